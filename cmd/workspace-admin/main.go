@@ -0,0 +1,266 @@
+// Package main contains administrative commands to maintain workspace database.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/SKorolchuk/dpio-workspace/internal/pkg/infra/database"
+	store "github.com/SKorolchuk/dpio-workspace/internal/pkg/store/database"
+
+	"github.com/jmoiron/sqlx"
+)
+
+const usage = `Usage: workspace-admin [flags] <command>
+
+Commands:
+  migrate up        apply all pending migrations
+  migrate down N    roll back N most recently applied migrations
+  migrate status    print applied version and checksum drift
+  migrate wait      block until the schema reaches the latest version
+  seed fixture NAME load named fixture set from the fixture directory
+  seed generate     load synthetic data set of -projects × -workspaces × -assets entities
+  reset             remove all data and load the seed preset, allowed in development and test
+                    environments or with -confirm equal to the database name
+  purge             permanently remove projects and workspaces deleted longer than -retention ago
+                    and print external asset refs which are no longer used by any asset
+
+Flags:
+`
+
+// DefaultCommandTimeout limits duration of a single administrative command.
+const DefaultCommandTimeout = 5 * time.Minute
+
+func main() {
+	if err := run(os.Args[1:]); err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string) error {
+	flags := flag.NewFlagSet("workspace-admin", flag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprint(flags.Output(), usage)
+		flags.PrintDefaults()
+	}
+
+	port, err := envNumber("DB_PORT", database.DefaultPort)
+	if err != nil {
+		return err
+	}
+	statementTimeout, err := envDuration("DB_STATEMENT_TIMEOUT", 0)
+	if err != nil {
+		return err
+	}
+
+	config := database.DbConfig{SearchPath: splitList(os.Getenv("DB_SEARCH_PATH"))}
+	flags.StringVar(&config.Driver, "db-driver", envOrDefault("DB_DRIVER", database.DriverPostgres),
+		"database driver: postgres or sqlite3")
+	flags.StringVar(&config.Host, "db-host", envOrDefault("DB_HOST", "localhost"), "database host")
+	flags.IntVar(&port, "db-port", port, "database port")
+	flags.StringVar(&config.User, "db-user", envOrDefault("DB_USER", "postgres"), "database user")
+	flags.StringVar(&config.Password, "db-password", os.Getenv("DB_PASSWORD"), "database password")
+	flags.StringVar(&config.DatabaseName, "db-name", envOrDefault("DB_NAME", "postgres"),
+		"database name or SQLite file path")
+	flags.BoolVar(&config.DisableTLS, "db-disable-tls", os.Getenv("DB_DISABLE_TLS") == "true", "disable TLS")
+	flags.StringVar(&config.SSLMode, "db-ssl-mode", os.Getenv("DB_SSL_MODE"),
+		"SSL mode: disable, require, verify-ca or verify-full")
+	flags.StringVar(&config.SSLRootCertPath, "db-ssl-root-cert", os.Getenv("DB_SSL_ROOT_CERT"),
+		"CA certificate file path, system root certificates are used if empty")
+	flags.StringVar(&config.SSLCertPath, "db-ssl-cert", os.Getenv("DB_SSL_CERT"), "client certificate file path")
+	flags.StringVar(&config.SSLKeyPath, "db-ssl-key", os.Getenv("DB_SSL_KEY"), "client private key file path")
+	flags.DurationVar(&config.StatementTimeout, "db-statement-timeout", statementTimeout,
+		"maximum duration of a single statement, zero disables the limit")
+	flags.StringVar(&config.ApplicationName, "db-application-name",
+		envOrDefault("DB_APPLICATION_NAME", "workspace-admin"), "application name reported to the database")
+	flags.Func("db-search-path", "comma-separated list of schemas in search path", func(value string) error {
+		config.SearchPath = splitList(value)
+		return nil
+	})
+	timeout := flags.Duration("timeout", DefaultCommandTimeout, "command timeout")
+	lockTimeout := flags.Duration("lock-timeout", store.DefaultMigrationLockTimeout,
+		"maximum time to wait for migration lock held by other replica")
+
+	guard := store.ResetGuard{}
+	flags.StringVar(&guard.Environment, "environment", os.Getenv("APP_ENVIRONMENT"),
+		"environment of the database: development, test, production, etc.")
+	flags.StringVar(&guard.ConfirmationToken, "confirm", "", "database name to confirm reset in other environments")
+	fixtureDir := flags.String("fixture-dir", envOrDefault("FIXTURE_DIR", "test/testdata"),
+		"directory of fixture sets in JSON or YAML format")
+	retention := flags.Duration("retention", store.DefaultDeletedRetention,
+		"period during which deleted projects and workspaces can be restored before purge")
+	generatorOptions := store.GeneratorOptions{}
+	flags.IntVar(&generatorOptions.Projects, "projects", 10, "amount of generated projects")
+	flags.IntVar(&generatorOptions.WorkspacesPerProject, "workspaces", 10, "amount of generated workspaces per project")
+	flags.IntVar(&generatorOptions.AssetsPerWorkspace, "assets", 10, "amount of generated assets per workspace")
+	flags.Int64Var(&generatorOptions.RandomSeed, "random-seed", 1, "random seed of generated data set")
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	config.Port = int32(port)
+
+	command := flags.Args()
+	if !isKnownCommand(command) {
+		flags.Usage()
+		return errors.New("unknown command")
+	}
+
+	connection, err := database.Open(config)
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+	defer database.Close(connection)
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	switch command[0] {
+	case "reset":
+		return store.Reset(ctx, connection, guard)
+	case "seed":
+		return seed(ctx, connection, command[1:], *fixtureDir, generatorOptions)
+	case "purge":
+		result, err := store.Purge(ctx, connection, *retention, time.Now().UTC())
+		if err != nil {
+			return err
+		}
+		return printJSON(result)
+	}
+
+	switch command[1] {
+	case "up":
+		return store.MigrateUp(ctx, connection, *lockTimeout)
+	case "down":
+		if len(command) != 3 {
+			return errors.New("number of migrations to roll back is not specified")
+		}
+		steps, err := strconv.Atoi(command[2])
+		if err != nil {
+			return fmt.Errorf("invalid number of migrations %q: %w", command[2], err)
+		}
+		return store.MigrateDown(ctx, connection, steps, *lockTimeout)
+	case "status":
+		status, err := store.QueryMigrationStatus(ctx, connection)
+		if err != nil {
+			return err
+		}
+		if err := printJSON(status); err != nil {
+			return err
+		}
+		if status.HasDrift() {
+			return errors.New("applied migrations drifted from the embedded set")
+		}
+		return nil
+	case "wait":
+		return store.WaitForMigrations(ctx, connection, store.DefaultMigrationPollInterval)
+	}
+
+	flags.Usage()
+	return fmt.Errorf("unknown migrate command %q", strings.Join(command[1:], " "))
+}
+
+// isKnownCommand reports whether command has a known name and enough arguments.
+func isKnownCommand(command []string) bool {
+	switch {
+	case len(command) == 1 && (command[0] == "reset" || command[0] == "purge"):
+		return true
+	case len(command) >= 2 && (command[0] == "migrate" || command[0] == "seed"):
+		return true
+	}
+
+	return false
+}
+
+// seed loads fixture set or synthetic data set into the database.
+func seed(ctx context.Context, connection *sqlx.DB, command []string, fixtureDir string,
+	options store.GeneratorOptions) error {
+	var fixture store.Fixture
+	var err error
+
+	switch {
+	case command[0] == "fixture" && len(command) == 2:
+		fixture, err = store.LoadFixtureSet(fixtureDir, command[1])
+	case command[0] == "generate" && len(command) == 1:
+		fixture, err = store.GenerateFixture(options)
+	default:
+		return fmt.Errorf("unknown seed command %q", strings.Join(command, " "))
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := store.SeedFixture(ctx, connection, fixture); err != nil {
+		return err
+	}
+
+	fmt.Printf("seeded %d projects, %d workspaces, %d assets\n", len(fixture.Projects), len(fixture.Workspaces),
+		len(fixture.Assets))
+	return nil
+}
+
+// envOrDefault returns value of environment variable or fallback if the variable is empty.
+func envOrDefault(key string, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+
+	return fallback
+}
+
+// envNumber returns numeric value of environment variable or fallback if the variable is empty.
+func envNumber(key string, fallback int) (int, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback, nil
+	}
+
+	number, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid number in environment variable %s: %w", key, err)
+	}
+
+	return number, nil
+}
+
+// envDuration returns duration value of environment variable or fallback if the variable is empty.
+func envDuration(key string, fallback time.Duration) (time.Duration, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback, nil
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration in environment variable %s: %w", key, err)
+	}
+
+	return duration, nil
+}
+
+// splitList splits comma-separated list of values, empty values are skipped.
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+
+	return items
+}
+
+// printJSON writes value to standard output in indented JSON format.
+func printJSON(value interface{}) error {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(value)
+}
@@ -1,4 +1,299 @@
+// Package main contains HTTP API service of workspace entities.
 package main
 
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/SKorolchuk/dpio-workspace/internal/pkg/handlers"
+	"github.com/SKorolchuk/dpio-workspace/internal/pkg/infra/auth"
+	"github.com/SKorolchuk/dpio-workspace/internal/pkg/infra/database"
+	"github.com/SKorolchuk/dpio-workspace/internal/pkg/store/project"
+	"github.com/SKorolchuk/dpio-workspace/internal/pkg/store/search"
+	"github.com/SKorolchuk/dpio-workspace/internal/pkg/store/workspace"
+
+	"github.com/golang-jwt/jwt/v4"
+	"go.uber.org/zap"
+)
+
+// Settings of HTTP server.
+const (
+	DefaultAddress         = ":8080"
+	DefaultShutdownTimeout = 20 * time.Second
+	DefaultDemoTokenTTL    = 24 * time.Hour
+)
+
+// demoUserID is the subject of the token issued in demo mode.
+const demoUserID = "00000000-0000-0000-0000-000000000001"
+
 func main() {
+	logger, err := zap.NewProduction()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(1)
+	}
+	defer func() { _ = logger.Sync() }()
+
+	if err := run(os.Args[1:], logger.Sugar()); err != nil {
+		logger.Sugar().Errorw("service.Stopped", "error", err)
+		_ = logger.Sync()
+		os.Exit(1)
+	}
+}
+
+func run(args []string, logger *zap.SugaredLogger) error {
+	flags := flag.NewFlagSet("workspace-api", flag.ContinueOnError)
+
+	port, err := envNumber("DB_PORT", database.DefaultPort)
+	if err != nil {
+		return err
+	}
+
+	config := database.DbConfig{ReplicaHosts: splitList(os.Getenv("DB_REPLICA_HOSTS"))}
+	flags.StringVar(&config.Driver, "db-driver", envOrDefault("DB_DRIVER", database.DriverPostgres),
+		"database driver: postgres or sqlite3")
+	flags.StringVar(&config.Host, "db-host", envOrDefault("DB_HOST", "localhost"), "database host")
+	flags.IntVar(&port, "db-port", port, "database port")
+	flags.StringVar(&config.User, "db-user", envOrDefault("DB_USER", "postgres"), "database user")
+	flags.StringVar(&config.Password, "db-password", os.Getenv("DB_PASSWORD"), "database password")
+	flags.StringVar(&config.DatabaseName, "db-name", envOrDefault("DB_NAME", "postgres"),
+		"database name or SQLite file path")
+	flags.BoolVar(&config.DisableTLS, "db-disable-tls", os.Getenv("DB_DISABLE_TLS") == "true", "disable TLS")
+	flags.StringVar(&config.SSLMode, "db-ssl-mode", os.Getenv("DB_SSL_MODE"),
+		"SSL mode: disable, require, verify-ca or verify-full")
+	flags.StringVar(&config.SSLRootCertPath, "db-ssl-root-cert", os.Getenv("DB_SSL_ROOT_CERT"),
+		"CA certificate file path, system root certificates are used if empty")
+	flags.StringVar(&config.ApplicationName, "db-application-name",
+		envOrDefault("DB_APPLICATION_NAME", "workspace-api"), "application name reported to the database")
+	flags.Func("db-replica-hosts", "comma-separated list of read replica hosts", func(value string) error {
+		config.ReplicaHosts = splitList(value)
+		return nil
+	})
+
+	address := flags.String("address", envOrDefault("APP_ADDRESS", DefaultAddress), "address of HTTP server")
+	keyPath := flags.String("auth-key", os.Getenv("AUTH_KEY"), "PEM file of RSA private key which signs tokens")
+	keyID := flags.String("auth-key-id", envOrDefault("AUTH_KEY_ID", "default"), "identifier of the signing key")
+	demo := flags.Bool("demo", os.Getenv("APP_DEMO") == "true",
+		"serve in-memory data without database, the signing key is generated if -auth-key is empty")
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	config.Port = int32(port)
+
+	keys, err := loadKeyStore(*keyID, *keyPath, *demo)
+	if err != nil {
+		return err
+	}
+	authContext, err := auth.NewAuthenticationContext(*keyID, keys)
+	if err != nil {
+		return fmt.Errorf("failed to create authentication context: %w", err)
+	}
+
+	apiConfig := handlers.Config{Logger: logger, Auth: authContext}
+	if *demo {
+		configureMemoryStores(&apiConfig)
+
+		token, err := demoToken(authContext, time.Now().UTC())
+		if err != nil {
+			return err
+		}
+		logger.Infow("service.DemoMode", "userid", demoUserID, "token", token)
+	} else {
+		cluster, err := database.OpenCluster(config, logger)
+		if err != nil {
+			return fmt.Errorf("failed to open database: %w", err)
+		}
+		defer cluster.Close()
+
+		configureStores(&apiConfig, logger, cluster)
+	}
+
+	return serve(logger, *address, handlers.API(apiConfig))
+}
+
+// configureMemoryStores sets in-memory repositories of demo mode in config, data is lost when the service stops.
+func configureMemoryStores(config *handlers.Config) {
+	projects := project.NewMemoryStore()
+	workspaces := workspace.NewMemoryStore(projects)
+	projects.WithProjectDeletionHooks(workspaces.FollowProjectDeletion)
+
+	config.Projects, config.Owners, config.Groups, config.Roles = projects, projects, projects, projects
+	config.Access, config.Invitations, config.AccessRequests = projects, projects, projects
+	config.Workspaces, config.Assets, config.Stems = workspaces, workspaces, workspaces
+	config.Search = search.NewMemoryStore(projects, workspaces)
+}
+
+// configureStores sets repositories of database cluster in config.
+func configureStores(config *handlers.Config, logger *zap.SugaredLogger, cluster *database.Cluster) {
+	projects := project.NewStore(logger, cluster)
+	workspaces := workspace.NewStore(logger, cluster)
+
+	config.Projects, config.Owners, config.Groups, config.Roles = projects, projects, projects, projects
+	config.Access, config.Invitations, config.AccessRequests = projects, projects, projects
+	config.Workspaces, config.Assets, config.Stems = workspaces, workspaces, workspaces
+	config.Search = search.NewStore(logger, cluster)
+}
+
+// serve handles requests until SIGINT or SIGTERM is received, then waits for active requests to complete.
+func serve(logger *zap.SugaredLogger, address string, handler http.Handler) error {
+	server := http.Server{Addr: address, Handler: handler}
+
+	serverErrors := make(chan error, 1)
+	go func() {
+		logger.Infow("service.Started", "address", address)
+		serverErrors <- server.ListenAndServe()
+	}()
+
+	shutdown := make(chan os.Signal, 1)
+	signal.Notify(shutdown, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(shutdown)
+
+	select {
+	case err := <-serverErrors:
+		return fmt.Errorf("server error: %w", err)
+	case sig := <-shutdown:
+		logger.Infow("service.Shutdown", "signal", sig.String())
+
+		ctx, cancel := context.WithTimeout(context.Background(), DefaultShutdownTimeout)
+		defer cancel()
+
+		if err := server.Shutdown(ctx); err != nil {
+			_ = server.Close()
+			return fmt.Errorf("failed to stop server gracefully: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// keyStore represents a KeyStore with a single RSA key.
+type keyStore struct {
+	keyID string
+	key   *rsa.PrivateKey
+}
+
+// GetPrivateKey returns the private key if keyId matches identifier of the stored key.
+func (store keyStore) GetPrivateKey(keyId string) (*rsa.PrivateKey, error) {
+	if keyId != store.keyID {
+		return nil, auth.ErrorKeyNotFound
+	}
+
+	return store.key, nil
+}
+
+// GetPublicKey returns the public key if keyId matches identifier of the stored key.
+func (store keyStore) GetPublicKey(keyId string) (*rsa.PublicKey, error) {
+	if keyId != store.keyID {
+		return nil, auth.ErrorKeyNotFound
+	}
+
+	return &store.key.PublicKey, nil
+}
+
+// loadKeyStore reads RSA private key in PKCS #1 or PKCS #8 PEM file.
+// A new key is generated in demo mode if path is empty.
+func loadKeyStore(keyID string, path string, demo bool) (keyStore, error) {
+	if path == "" {
+		if !demo {
+			return keyStore{}, errors.New("signing key file is not specified")
+		}
+
+		key, err := rsa.GenerateKey(rand.Reader, 2048)
+		if err != nil {
+			return keyStore{}, fmt.Errorf("failed to generate signing key: %w", err)
+		}
+		return keyStore{keyID: keyID, key: key}, nil
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return keyStore{}, fmt.Errorf("failed to read signing key: %w", err)
+	}
+
+	block, _ := pem.Decode(content)
+	if block == nil {
+		return keyStore{}, fmt.Errorf("signing key file %s is not in PEM format", path)
+	}
+
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return keyStore{keyID: keyID, key: key}, nil
+	}
+
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return keyStore{}, fmt.Errorf("failed to parse signing key: %w", err)
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return keyStore{}, fmt.Errorf("signing key file %s does not contain RSA key", path)
+	}
+
+	return keyStore{keyID: keyID, key: key}, nil
+}
+
+// demoToken issues a token of the demo user, so API of demo mode can be called without identity service.
+func demoToken(authContext *auth.AuthenticationContext, now time.Time) (string, error) {
+	token, err := authContext.GenerateToken(auth.Claims{
+		StandardClaims: jwt.StandardClaims{
+			Subject:   demoUserID,
+			IssuedAt:  now.Unix(),
+			ExpiresAt: now.Add(DefaultDemoTokenTTL).Unix(),
+		},
+		Roles: []string{auth.RoleUser},
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to issue demo token: %w", err)
+	}
+
+	return token, nil
+}
+
+// envOrDefault returns value of environment variable or fallback if the variable is empty.
+func envOrDefault(key string, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+
+	return fallback
+}
+
+// envNumber returns numeric value of environment variable or fallback if the variable is empty.
+func envNumber(key string, fallback int) (int, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback, nil
+	}
+
+	number, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid number in environment variable %s: %w", key, err)
+	}
+
+	return number, nil
+}
+
+// splitList splits comma-separated list of values, empty values are skipped.
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+
+	return items
 }
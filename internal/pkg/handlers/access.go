@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/SKorolchuk/dpio-workspace/internal/pkg/infra/server"
+	"github.com/SKorolchuk/dpio-workspace/internal/pkg/store/project"
+)
+
+// accessHandlers contains HTTP handlers of GroupAccess entities.
+type accessHandlers struct {
+	store project.AccessRepository
+}
+
+// queryProjects returns a page of Project entities which the caller can reach through group membership.
+// Archived projects are included only with "includeArchived" query parameter.
+func (h accessHandlers) queryProjects(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	_, claims, err := requestContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	skip, top, err := pageOf(r)
+	if err != nil {
+		return err
+	}
+
+	includeArchived, err := queryFlag(r, "includeArchived")
+	if err != nil {
+		return err
+	}
+
+	projects, err := h.store.QueryProjectsForUser(ctx, claims, includeArchived, skip, top)
+	if err != nil {
+		return requestError(err)
+	}
+
+	if projects == nil {
+		projects = []project.Project{}
+	}
+
+	return server.Respond(ctx, w, projects, http.StatusOK)
+}
+
+// queryGroups returns Group entities which have access to Project entity readable by the caller.
+func (h accessHandlers) queryGroups(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	_, claims, err := requestContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	groups, err := h.store.QueryProjectGroups(ctx, claims, server.Param(r, "project_id"))
+	if err != nil {
+		return requestError(err)
+	}
+
+	if groups == nil {
+		groups = []project.Group{}
+	}
+
+	return server.Respond(ctx, w, groups, http.StatusOK)
+}
+
+// grant gives Group entity access to Project entity.
+func (h accessHandlers) grant(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	info, claims, err := requestContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	var newAccess project.NewGroupAccess
+	if err := server.Decode(r, &newAccess); err != nil {
+		return err
+	}
+
+	access, err := h.store.GrantGroupAccess(ctx, claims, server.Param(r, "project_id"), newAccess, info.Now)
+	if err != nil {
+		return requestError(err)
+	}
+
+	return server.Respond(ctx, w, access, http.StatusCreated)
+}
+
+// revoke removes access of Group entity to Project entity.
+func (h accessHandlers) revoke(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	_, claims, err := requestContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = h.store.RevokeGroupAccess(ctx, claims, server.Param(r, "project_id"), server.Param(r, "group_id"))
+	if err != nil {
+		return requestError(err)
+	}
+
+	return server.Respond(ctx, w, nil, http.StatusNoContent)
+}
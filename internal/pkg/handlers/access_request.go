@@ -0,0 +1,122 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/SKorolchuk/dpio-workspace/internal/pkg/infra/server"
+	"github.com/SKorolchuk/dpio-workspace/internal/pkg/store/project"
+)
+
+// accessRequestHandlers contains HTTP handlers of AccessRequest entities.
+type accessRequestHandlers struct {
+	store project.AccessRequestRepository
+}
+
+// create queues a request of the caller for access to Project entity.
+func (h accessRequestHandlers) create(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	info, claims, err := requestContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	var newRequest project.NewAccessRequest
+	if err := server.Decode(r, &newRequest); err != nil {
+		return err
+	}
+
+	request, err := h.store.RequestAccess(ctx, claims, server.Param(r, "project_id"), newRequest, info.Now)
+	if err != nil {
+		return requestError(err)
+	}
+
+	return server.Respond(ctx, w, request, http.StatusCreated)
+}
+
+// queryPending returns a page of pending AccessRequest entities of Project entity.
+func (h accessRequestHandlers) queryPending(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	_, claims, err := requestContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	skip, top, err := pageOf(r)
+	if err != nil {
+		return err
+	}
+
+	requests, err := h.store.QueryPendingAccessRequests(ctx, claims, server.Param(r, "project_id"), skip, top)
+	if err != nil {
+		return requestError(err)
+	}
+
+	return respondAccessRequests(ctx, w, requests)
+}
+
+// queryMine returns a page of AccessRequest entities of the caller.
+func (h accessRequestHandlers) queryMine(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	_, claims, err := requestContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	skip, top, err := pageOf(r)
+	if err != nil {
+		return err
+	}
+
+	requests, err := h.store.QueryAccessRequestsByUser(ctx, claims, skip, top)
+	if err != nil {
+		return requestError(err)
+	}
+
+	return respondAccessRequests(ctx, w, requests)
+}
+
+// approve approves AccessRequest entity and adds the requester to the chosen group.
+func (h accessRequestHandlers) approve(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	info, claims, err := requestContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	var approval project.ApproveAccessRequest
+	if err := server.Decode(r, &approval); err != nil {
+		return err
+	}
+
+	groupUser, err := h.store.ApproveAccessRequest(ctx, claims, server.Param(r, "request_id"), approval, info.Now)
+	if err != nil {
+		return requestError(err)
+	}
+
+	return server.Respond(ctx, w, groupUser, http.StatusCreated)
+}
+
+// reject rejects AccessRequest entity with a reason.
+func (h accessRequestHandlers) reject(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	info, claims, err := requestContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	var rejection project.RejectAccessRequest
+	if err := server.Decode(r, &rejection); err != nil {
+		return err
+	}
+
+	if err := h.store.RejectAccessRequest(ctx, claims, server.Param(r, "request_id"), rejection, info.Now); err != nil {
+		return requestError(err)
+	}
+
+	return server.Respond(ctx, w, nil, http.StatusNoContent)
+}
+
+// respondAccessRequests writes AccessRequest entities, an empty page is written as an empty array.
+func respondAccessRequests(ctx context.Context, w http.ResponseWriter, requests []project.AccessRequest) error {
+	if requests == nil {
+		requests = []project.AccessRequest{}
+	}
+
+	return server.Respond(ctx, w, requests, http.StatusOK)
+}
@@ -0,0 +1,38 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/SKorolchuk/dpio-workspace/internal/pkg/infra/server"
+	"github.com/SKorolchuk/dpio-workspace/internal/pkg/store/workspace"
+)
+
+// assetHandlers contains HTTP handlers of Asset entities.
+type assetHandlers struct {
+	store workspace.AssetRepository
+}
+
+// queryByWorkspace returns a page of Asset entities of Workspace entity together with a cursor of the next page.
+func (h assetHandlers) queryByWorkspace(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	_, claims, err := requestContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	page, err := pageRequestOf(r)
+	if err != nil {
+		return err
+	}
+
+	assetPage, err := h.store.QueryAssetsByWorkspacePage(ctx, claims, server.Param(r, "workspace_id"), page)
+	if err != nil {
+		return requestError(err)
+	}
+
+	if assetPage.Items == nil {
+		assetPage.Items = []workspace.Asset{}
+	}
+
+	return server.Respond(ctx, w, assetPage, http.StatusOK)
+}
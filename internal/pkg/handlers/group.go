@@ -0,0 +1,165 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/SKorolchuk/dpio-workspace/internal/pkg/infra/server"
+	"github.com/SKorolchuk/dpio-workspace/internal/pkg/store/project"
+)
+
+// groupHandlers contains HTTP handlers of Group and GroupUser entities.
+type groupHandlers struct {
+	store project.GroupRepository
+}
+
+// create adds new Group entity.
+func (h groupHandlers) create(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	info, claims, err := requestContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	var newGroup project.NewGroup
+	if err := server.Decode(r, &newGroup); err != nil {
+		return err
+	}
+
+	groupData, err := h.store.CreateGroup(ctx, claims, newGroup, info.Now)
+	if err != nil {
+		return requestError(err)
+	}
+
+	return server.Respond(ctx, w, groupData, http.StatusCreated)
+}
+
+// queryByID returns Group entity.
+func (h groupHandlers) queryByID(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	_, claims, err := requestContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	groupData, err := h.store.QueryGroupByID(ctx, claims, server.Param(r, "group_id"))
+	if err != nil {
+		return requestError(err)
+	}
+
+	return server.Respond(ctx, w, groupData, http.StatusOK)
+}
+
+// update renames Group entity.
+func (h groupHandlers) update(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	info, claims, err := requestContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	var updateGroup project.UpdateGroup
+	if err := server.Decode(r, &updateGroup); err != nil {
+		return err
+	}
+
+	if err := h.store.UpdateGroup(ctx, claims, server.Param(r, "group_id"), updateGroup, info.Now); err != nil {
+		return requestError(err)
+	}
+
+	return server.Respond(ctx, w, nil, http.StatusNoContent)
+}
+
+// delete removes Group entity with all its assignments.
+func (h groupHandlers) delete(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	_, claims, err := requestContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := h.store.DeleteGroup(ctx, claims, server.Param(r, "group_id")); err != nil {
+		return requestError(err)
+	}
+
+	return server.Respond(ctx, w, nil, http.StatusNoContent)
+}
+
+// queryUsers returns a page of Group members.
+func (h groupHandlers) queryUsers(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	_, claims, err := requestContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	skip, top, err := pageOf(r)
+	if err != nil {
+		return err
+	}
+
+	groupUsers, err := h.store.QueryGroupUsers(ctx, claims, server.Param(r, "group_id"), skip, top)
+	if err != nil {
+		return requestError(err)
+	}
+
+	if groupUsers == nil {
+		groupUsers = []project.GroupUser{}
+	}
+
+	return server.Respond(ctx, w, groupUsers, http.StatusOK)
+}
+
+// addUser assigns a user to Group entity.
+func (h groupHandlers) addUser(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	info, claims, err := requestContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	var newGroupUser project.NewGroupUser
+	if err := server.Decode(r, &newGroupUser); err != nil {
+		return err
+	}
+
+	groupUser, err := h.store.AddGroupUser(ctx, claims, server.Param(r, "group_id"), newGroupUser, info.Now)
+	if err != nil {
+		return requestError(err)
+	}
+
+	return server.Respond(ctx, w, groupUser, http.StatusCreated)
+}
+
+// removeUser removes a user assignment from Group entity.
+func (h groupHandlers) removeUser(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	_, claims, err := requestContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = h.store.RemoveGroupUser(ctx, claims, server.Param(r, "group_id"), server.Param(r, "user_id"))
+	if err != nil {
+		return requestError(err)
+	}
+
+	return server.Respond(ctx, w, nil, http.StatusNoContent)
+}
+
+// queryByUser returns a page of Group entities which the user is a member of.
+func (h groupHandlers) queryByUser(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	_, claims, err := requestContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	skip, top, err := pageOf(r)
+	if err != nil {
+		return err
+	}
+
+	groups, err := h.store.QueryGroupsByUser(ctx, claims, server.Param(r, "user_id"), skip, top)
+	if err != nil {
+		return requestError(err)
+	}
+
+	if groups == nil {
+		groups = []project.Group{}
+	}
+
+	return server.Respond(ctx, w, groups, http.StatusOK)
+}
@@ -0,0 +1,282 @@
+// Package handlers contains HTTP API of workspace service.
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/SKorolchuk/dpio-workspace/internal/pkg/infra/auth"
+	"github.com/SKorolchuk/dpio-workspace/internal/pkg/infra/database"
+	"github.com/SKorolchuk/dpio-workspace/internal/pkg/infra/server"
+	"github.com/SKorolchuk/dpio-workspace/internal/pkg/infra/validation"
+	"github.com/SKorolchuk/dpio-workspace/internal/pkg/store/project"
+	"github.com/SKorolchuk/dpio-workspace/internal/pkg/store/search"
+	"github.com/SKorolchuk/dpio-workspace/internal/pkg/store/workspace"
+
+	"go.uber.org/zap"
+)
+
+// Paging settings of collection endpoints.
+const (
+	DefaultPageSize = 50
+	MaxPageSize     = 1000
+)
+
+// APIVersion is the version prefix of all API routes.
+const APIVersion = "v1"
+
+// Config describes dependencies of HTTP API.
+type Config struct {
+	Logger         *zap.SugaredLogger
+	Auth           *auth.AuthenticationContext
+	Projects       project.ProjectRepository
+	Owners         project.OwnerRepository
+	Groups         project.GroupRepository
+	Roles          project.RoleRepository
+	Access         project.AccessRepository
+	Invitations    project.InvitationRepository
+	AccessRequests project.AccessRequestRepository
+	Workspaces     workspace.WorkspaceRepository
+	Assets         workspace.AssetRepository
+	Stems          workspace.StemRepository
+	Search         search.Repository
+}
+
+// API creates HTTP handler with all routes of workspace service.
+func API(config Config) http.Handler {
+	app := server.NewApp(server.Logger(config.Logger), server.Errors(config.Logger), readYourWrites)
+	authenticate := server.Authenticate(config.Auth)
+
+	groups := groupHandlers{store: config.Groups}
+	app.Handle(http.MethodPost, APIVersion, "/groups", groups.create, authenticate)
+	app.Handle(http.MethodGet, APIVersion, "/groups/:group_id", groups.queryByID, authenticate)
+	app.Handle(http.MethodPut, APIVersion, "/groups/:group_id", groups.update, authenticate)
+	app.Handle(http.MethodDelete, APIVersion, "/groups/:group_id", groups.delete, authenticate)
+	app.Handle(http.MethodGet, APIVersion, "/groups/:group_id/users", groups.queryUsers, authenticate)
+	app.Handle(http.MethodPost, APIVersion, "/groups/:group_id/users", groups.addUser, authenticate)
+	app.Handle(http.MethodDelete, APIVersion, "/groups/:group_id/users/:user_id", groups.removeUser, authenticate)
+	app.Handle(http.MethodGet, APIVersion, "/users/:user_id/groups", groups.queryByUser, authenticate)
+
+	access := accessHandlers{store: config.Access}
+	app.Handle(http.MethodGet, APIVersion, "/projects", access.queryProjects, authenticate)
+	app.Handle(http.MethodGet, APIVersion, "/projects/:project_id/groups", access.queryGroups, authenticate)
+	app.Handle(http.MethodPost, APIVersion, "/projects/:project_id/groups", access.grant, authenticate)
+	app.Handle(http.MethodDelete, APIVersion, "/projects/:project_id/groups/:group_id", access.revoke, authenticate)
+
+	projects := projectHandlers{store: config.Projects}
+	app.Handle(http.MethodDelete, APIVersion, "/projects/:project_id", projects.delete, authenticate)
+	app.Handle(http.MethodPost, APIVersion, "/projects/:project_id/archive", projects.archive, authenticate)
+	app.Handle(http.MethodPost, APIVersion, "/projects/:project_id/restore", projects.restore, authenticate)
+
+	owners := ownerHandlers{store: config.Owners}
+	app.Handle(http.MethodGet, APIVersion, "/projects/:project_id/owners", owners.query, authenticate)
+	app.Handle(http.MethodPost, APIVersion, "/projects/:project_id/owners", owners.add, authenticate)
+	app.Handle(http.MethodDelete, APIVersion, "/projects/:project_id/owners/:user_id", owners.remove, authenticate)
+	app.Handle(http.MethodPost, APIVersion, "/projects/:project_id/transfer", owners.transfer, authenticate)
+
+	invitations := invitationHandlers{store: config.Invitations, authContext: config.Auth}
+	app.Handle(http.MethodGet, APIVersion, "/projects/:project_id/invitations", invitations.queryPending,
+		authenticate)
+	app.Handle(http.MethodPost, APIVersion, "/projects/:project_id/invitations", invitations.create, authenticate)
+	app.Handle(http.MethodDelete, APIVersion, "/projects/:project_id/invitations/:invitation_id", invitations.revoke,
+		authenticate)
+	app.Handle(http.MethodPost, APIVersion, "/invitations/accept", invitations.accept, authenticate)
+
+	requests := accessRequestHandlers{store: config.AccessRequests}
+	app.Handle(http.MethodGet, APIVersion, "/projects/:project_id/access-requests", requests.queryPending,
+		authenticate)
+	app.Handle(http.MethodPost, APIVersion, "/projects/:project_id/access-requests", requests.create, authenticate)
+	app.Handle(http.MethodGet, APIVersion, "/access-requests", requests.queryMine, authenticate)
+	app.Handle(http.MethodPost, APIVersion, "/access-requests/:request_id/approve", requests.approve, authenticate)
+	app.Handle(http.MethodPost, APIVersion, "/access-requests/:request_id/reject", requests.reject, authenticate)
+
+	roles := roleHandlers{store: config.Roles}
+	app.Handle(http.MethodGet, APIVersion, "/roles", roles.query, authenticate)
+	app.Handle(http.MethodPost, APIVersion, "/roles", roles.create, authenticate)
+	app.Handle(http.MethodGet, APIVersion, "/roles/:role_id", roles.queryByID, authenticate)
+	app.Handle(http.MethodPost, APIVersion, "/roles/:role_id/retire", roles.retire, authenticate)
+	app.Handle(http.MethodGet, APIVersion, "/groups/:group_id/roles", roles.queryByGroup, authenticate)
+	app.Handle(http.MethodPost, APIVersion, "/groups/:group_id/roles", roles.assign, authenticate)
+	app.Handle(http.MethodDelete, APIVersion, "/groups/:group_id/roles/:role_id", roles.unassign, authenticate)
+	app.Handle(http.MethodGet, APIVersion, "/projects/:project_id/users/:user_id/roles", roles.queryEffective,
+		authenticate)
+
+	workspaces := workspaceHandlers{store: config.Workspaces}
+	app.Handle(http.MethodGet, APIVersion, "/workspaces", workspaces.query, authenticate)
+	app.Handle(http.MethodPost, APIVersion, "/workspaces/:workspace_id/clone", workspaces.clone, authenticate)
+	app.Handle(http.MethodPost, APIVersion, "/workspaces/:workspace_id/purge", workspaces.purge, authenticate)
+	app.Handle(http.MethodGet, APIVersion, "/workspaces/:workspace_id/export", workspaces.export, authenticate)
+	app.Handle(http.MethodPost, APIVersion, "/projects/:project_id/workspaces/import", workspaces.importDocument,
+		authenticate)
+
+	assets := assetHandlers{store: config.Assets}
+	app.Handle(http.MethodGet, APIVersion, "/workspaces/:workspace_id/assets", assets.queryByWorkspace, authenticate)
+
+	stems := stemHandlers{store: config.Stems}
+	app.Handle(http.MethodGet, APIVersion, "/stems", stems.query, authenticate)
+
+	searches := searchHandlers{store: config.Search}
+	app.Handle(http.MethodGet, APIVersion, "/search", searches.query, authenticate)
+
+	return app
+}
+
+// readYourWrites routes all queries of mutating requests to the primary database, so data read during or right after
+// the change, e.g. by permission checks, never lags behind on a read replica.
+func readYourWrites(handler server.Handler) server.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		switch r.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+		default:
+			ctx = database.WithPrimary(ctx)
+		}
+
+		return handler(ctx, w, r)
+	}
+}
+
+// requestError converts store errors into RequestError with the matching status code.
+// Unknown errors are returned as is and reported with 500 status.
+func requestError(err error) error {
+	switch {
+	case errors.Is(err, database.ErrorNotFound):
+		return validation.NewRequestError(err, http.StatusNotFound)
+	case errors.Is(err, database.ErrorForbidden):
+		return validation.NewRequestError(err, http.StatusForbidden)
+	case errors.Is(err, database.ErrorConflict), errors.Is(err, project.ErrorAccessRequestClosed),
+		errors.Is(err, project.ErrorLastOwner), errors.Is(err, project.ErrorProjectHasWorkspaces):
+		return validation.NewRequestError(err, http.StatusConflict)
+	case errors.Is(err, project.ErrorInvitationClosed):
+		return validation.NewRequestError(err, http.StatusGone)
+	case errors.Is(err, database.ErrorInvalidReference), errors.Is(err, project.ErrorRoleRetired),
+		errors.Is(err, workspace.ErrorAssetLimitExceeded), errors.Is(err, workspace.ErrorFlatStem),
+		errors.Is(err, workspace.ErrorAssetOutOfBounds), errors.Is(err, workspace.ErrorDocumentVersion):
+		return validation.NewRequestError(err, http.StatusUnprocessableEntity)
+	case errors.Is(err, database.ErrorInvalidIdentifier), errors.Is(err, validation.ErrorInvalidIdentifier),
+		errors.Is(err, database.ErrorInvalidCursor):
+		return validation.NewRequestError(err, http.StatusBadRequest)
+	}
+
+	return err
+}
+
+// requestContext returns request information and user Claims of authenticated request.
+func requestContext(ctx context.Context) (*server.RequestInfo, auth.Claims, error) {
+	info, err := server.GetRequestInfo(ctx)
+	if err != nil {
+		return nil, auth.Claims{}, err
+	}
+
+	claims, err := auth.GetClaims(ctx)
+	if err != nil {
+		return nil, auth.Claims{}, err
+	}
+
+	return info, claims, nil
+}
+
+// pageOf reads "skip" and "top" query parameters of collection request.
+func pageOf(r *http.Request) (int32, int32, error) {
+	skip, err := queryNumber(r, "skip", 0)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	top, err := queryNumber(r, "top", DefaultPageSize)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if skip < 0 || top < 0 || top > MaxPageSize {
+		return 0, 0, validation.NewRequestError(errors.New("skip and top should be positive, top should not exceed "+
+			strconv.Itoa(MaxPageSize)), http.StatusBadRequest)
+	}
+
+	return skip, top, nil
+}
+
+// pageRequestOf reads "cursor", "top" and "withTotal" query parameters of collection request with keyset pagination.
+func pageRequestOf(r *http.Request) (workspace.PageRequest, error) {
+	top, err := queryTop(r)
+	if err != nil {
+		return workspace.PageRequest{}, err
+	}
+
+	withTotal, err := queryFlag(r, "withTotal")
+	if err != nil {
+		return workspace.PageRequest{}, err
+	}
+
+	return workspace.PageRequest{
+		Cursor:    r.URL.Query().Get("cursor"),
+		Top:       top,
+		WithTotal: withTotal,
+	}, nil
+}
+
+// queryTop reads "top" query parameter which limits amount of returned entities, DefaultPageSize is used if the
+// parameter is not specified.
+func queryTop(r *http.Request) (int32, error) {
+	top, err := queryNumber(r, "top", DefaultPageSize)
+	if err != nil {
+		return 0, err
+	}
+
+	if top < 1 || top > MaxPageSize {
+		return 0, validation.NewRequestError(errors.New("top should be positive and should not exceed "+
+			strconv.Itoa(MaxPageSize)), http.StatusBadRequest)
+	}
+
+	return top, nil
+}
+
+// queryNumber reads numeric query parameter or returns fallback if the parameter is not specified.
+func queryNumber(r *http.Request, name string, fallback int32) (int32, error) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return fallback, nil
+	}
+
+	number, err := strconv.ParseInt(value, 10, 32)
+	if err != nil {
+		return 0, validation.NewRequestError(errors.New("query parameter "+name+" should be a number"),
+			http.StatusBadRequest)
+	}
+
+	return int32(number), nil
+}
+
+// queryFlag reads boolean query parameter or returns false if the parameter is not specified.
+func queryFlag(r *http.Request, name string) (bool, error) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return false, nil
+	}
+
+	flag, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, validation.NewRequestError(errors.New("query parameter "+name+" should be a boolean"),
+			http.StatusBadRequest)
+	}
+
+	return flag, nil
+}
+
+// queryTime reads RFC 3339 time query parameter, nil is returned if the parameter is not specified.
+func queryTime(r *http.Request, name string) (*time.Time, error) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return nil, nil
+	}
+
+	moment, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, validation.NewRequestError(errors.New("query parameter "+name+" should be an RFC 3339 time"),
+			http.StatusBadRequest)
+	}
+
+	return &moment, nil
+}
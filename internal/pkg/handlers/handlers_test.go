@@ -0,0 +1,324 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/SKorolchuk/dpio-workspace/internal/pkg/infra/auth"
+	"github.com/SKorolchuk/dpio-workspace/internal/pkg/infra/database"
+	"github.com/SKorolchuk/dpio-workspace/internal/pkg/store/project"
+	"github.com/SKorolchuk/dpio-workspace/internal/pkg/store/search"
+	"github.com/SKorolchuk/dpio-workspace/internal/pkg/store/workspace"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/jmoiron/sqlx"
+	"go.uber.org/zap"
+)
+
+func TestReadYourWrites(t *testing.T) {
+	primary, err := sqlx.Open(database.DriverSQLite, ":memory:")
+	if err != nil {
+		t.Fatalf("sqlx.Open() error = %v", err)
+	}
+	replica, err := sqlx.Open(database.DriverSQLite, ":memory:")
+	if err != nil {
+		t.Fatalf("sqlx.Open() error = %v", err)
+	}
+	cluster := database.NewCluster(primary, replica)
+	defer cluster.Close()
+
+	tests := []struct {
+		method      string
+		wantPrimary bool
+	}{
+		{method: http.MethodGet},
+		{method: http.MethodHead},
+		{method: http.MethodPost, wantPrimary: true},
+		{method: http.MethodPut, wantPrimary: true},
+		{method: http.MethodDelete, wantPrimary: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.method, func(t *testing.T) {
+			handler := readYourWrites(func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+				if isPrimary := cluster.Reader(ctx) == primary; isPrimary != test.wantPrimary {
+					t.Errorf("queries are routed to the primary database = %t, want %t", isPrimary,
+						test.wantPrimary)
+				}
+
+				return nil
+			})
+
+			request := httptest.NewRequest(test.method, "/v1/workspaces", nil)
+			if err := handler(context.Background(), httptest.NewRecorder(), request); err != nil {
+				t.Errorf("handler error = %v", err)
+			}
+		})
+	}
+}
+
+// testKeyID identifies the signing key of test tokens.
+const testKeyID = "test"
+
+var (
+	testKeyOnce sync.Once
+	testKey     *rsa.PrivateKey
+)
+
+// testKeyStore represents a KeyStore with a single RSA key shared by all tests.
+type testKeyStore struct{}
+
+func (testKeyStore) GetPrivateKey(keyId string) (*rsa.PrivateKey, error) {
+	if keyId != testKeyID {
+		return nil, auth.ErrorKeyNotFound
+	}
+
+	return testKey, nil
+}
+
+func (testKeyStore) GetPublicKey(keyId string) (*rsa.PublicKey, error) {
+	if keyId != testKeyID {
+		return nil, auth.ErrorKeyNotFound
+	}
+
+	return &testKey.PublicKey, nil
+}
+
+// testAPI represents HTTP API served over in-memory stores.
+type testAPI struct {
+	handler    http.Handler
+	auth       *auth.AuthenticationContext
+	projects   *project.MemoryStore
+	workspaces *workspace.MemoryStore
+}
+
+// newTestAPI creates HTTP API with empty in-memory stores.
+func newTestAPI(t *testing.T) testAPI {
+	t.Helper()
+
+	testKeyOnce.Do(func() {
+		var err error
+		if testKey, err = rsa.GenerateKey(rand.Reader, 2048); err != nil {
+			panic(err)
+		}
+	})
+
+	authContext, err := auth.NewAuthenticationContext(testKeyID, testKeyStore{})
+	if err != nil {
+		t.Fatalf("NewAuthenticationContext() error = %v", err)
+	}
+
+	projects := project.NewMemoryStore()
+	workspaces := workspace.NewMemoryStore(projects)
+	projects.WithProjectDeletionHooks(workspaces.FollowProjectDeletion)
+	handler := API(Config{
+		Logger:         zap.NewNop().Sugar(),
+		Auth:           authContext,
+		Projects:       projects,
+		Owners:         projects,
+		Groups:         projects,
+		Roles:          projects,
+		Access:         projects,
+		Invitations:    projects,
+		AccessRequests: projects,
+		Workspaces:     workspaces,
+		Assets:         workspaces,
+		Stems:          workspaces,
+		Search:         search.NewMemoryStore(projects, workspaces),
+	})
+
+	return testAPI{handler: handler, auth: authContext, projects: projects, workspaces: workspaces}
+}
+
+// claims returns Claims of a regular user.
+func (api testAPI) claims(userID string) auth.Claims {
+	return auth.Claims{StandardClaims: jwt.StandardClaims{Subject: userID}, Roles: []string{auth.RoleUser}}
+}
+
+// call sends request on behalf of the user, the request is anonymous if userID is empty.
+// The body is encoded in JSON and the response is decoded into result if it is not nil.
+func (api testAPI) call(t *testing.T, userID string, method string, path string, body interface{},
+	result interface{}) int {
+	t.Helper()
+
+	var reader io.Reader
+	if body != nil {
+		content, err := json.Marshal(body)
+		if err != nil {
+			t.Fatalf("json.Marshal() error = %v", err)
+		}
+		reader = bytes.NewReader(content)
+	}
+
+	request := httptest.NewRequest(method, path, reader)
+	if userID != "" {
+		token, err := api.auth.GenerateToken(api.claims(userID))
+		if err != nil {
+			t.Fatalf("GenerateToken() error = %v", err)
+		}
+		request.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	recorder := httptest.NewRecorder()
+	api.handler.ServeHTTP(recorder, request)
+
+	if result != nil && recorder.Code < http.StatusBadRequest {
+		if err := json.NewDecoder(recorder.Body).Decode(result); err != nil {
+			t.Fatalf("%s %s response decode error = %v", method, path, err)
+		}
+	}
+
+	return recorder.Code
+}
+
+func TestAPIOverMemoryStores(t *testing.T) {
+	const owner, stranger = "user-1", "user-2"
+	api := newTestAPI(t)
+
+	if status := api.call(t, "", http.MethodGet, "/v1/stems", nil, nil); status != http.StatusUnauthorized {
+		t.Errorf("anonymous request status = %d, want %d", status, http.StatusUnauthorized)
+	}
+
+	var stems workspace.StemPage
+	if status := api.call(t, owner, http.MethodGet, "/v1/stems", nil, &stems); status != http.StatusOK {
+		t.Fatalf("GET /stems status = %d", status)
+	}
+	if len(stems.Items) == 0 {
+		t.Errorf("GET /stems returned no predefined stems")
+	}
+
+	var group project.Group
+	status := api.call(t, owner, http.MethodPost, "/v1/groups", project.NewGroup{Name: "Team"}, &group)
+	if status != http.StatusCreated || group.CreatedByUser != owner {
+		t.Fatalf("POST /groups status = %d, created by %q", status, group.CreatedByUser)
+	}
+
+	rename := project.UpdateGroup{Name: &group.Name}
+	status = api.call(t, stranger, http.MethodPut, "/v1/groups/"+group.ID, rename, nil)
+	if status != http.StatusForbidden {
+		t.Errorf("PUT /groups/:group_id of another user status = %d, want %d", status, http.StatusForbidden)
+	}
+
+	status = api.call(t, owner, http.MethodDelete, "/v1/groups/"+group.ID, nil, nil)
+	if status != http.StatusNoContent {
+		t.Errorf("DELETE /groups/:group_id status = %d, want %d", status, http.StatusNoContent)
+	}
+	status = api.call(t, owner, http.MethodDelete, "/v1/groups/"+group.ID, nil, nil)
+	if status != http.StatusNotFound {
+		t.Errorf("DELETE /groups/:group_id of removed group status = %d, want %d", status, http.StatusNotFound)
+	}
+}
+
+func TestAPIGroupVisibility(t *testing.T) {
+	const (
+		creator  = "2d9c3a41-7b5e-4f60-8a1b-9c0d1e2f3a01"
+		member   = "2d9c3a41-7b5e-4f60-8a1b-9c0d1e2f3a02"
+		stranger = "2d9c3a41-7b5e-4f60-8a1b-9c0d1e2f3a03"
+	)
+	api := newTestAPI(t)
+
+	var group project.Group
+	if status := api.call(t, creator, http.MethodPost, "/v1/groups", project.NewGroup{Name: "Shared"},
+		&group); status != http.StatusCreated {
+		t.Fatalf("POST /groups status = %d", status)
+	}
+	if status := api.call(t, creator, http.MethodPost, "/v1/groups/"+group.ID+"/users",
+		project.NewGroupUser{UserID: member}, nil); status != http.StatusCreated {
+		t.Fatalf("POST /groups/:group_id/users status = %d", status)
+	}
+
+	tests := []struct {
+		name       string
+		userID     string
+		path       string
+		wantStatus int
+		wantGroups int
+	}{
+		{name: "group by creator", userID: creator, path: "/v1/groups/" + group.ID, wantStatus: http.StatusOK},
+		{name: "group by member", userID: member, path: "/v1/groups/" + group.ID, wantStatus: http.StatusOK},
+		{name: "group by stranger", userID: stranger, path: "/v1/groups/" + group.ID,
+			wantStatus: http.StatusNotFound},
+		{name: "users by member", userID: member, path: "/v1/groups/" + group.ID + "/users",
+			wantStatus: http.StatusOK},
+		{name: "users by stranger", userID: stranger, path: "/v1/groups/" + group.ID + "/users",
+			wantStatus: http.StatusNotFound},
+		{name: "groups of member by themself", userID: member, path: "/v1/users/" + member + "/groups",
+			wantStatus: http.StatusOK, wantGroups: 1},
+		{name: "groups of member by creator", userID: creator, path: "/v1/users/" + member + "/groups",
+			wantStatus: http.StatusOK, wantGroups: 1},
+		{name: "groups of member by stranger", userID: stranger, path: "/v1/users/" + member + "/groups",
+			wantStatus: http.StatusOK},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var groups []project.Group
+			var result interface{}
+			if strings.HasSuffix(test.path, "/groups") {
+				result = &groups
+			}
+
+			if status := api.call(t, test.userID, http.MethodGet, test.path, nil, result); status != test.wantStatus {
+				t.Fatalf("GET %s status = %d, want %d", test.path, status, test.wantStatus)
+			}
+			if len(groups) != test.wantGroups {
+				t.Errorf("GET %s returned %d groups, want %d", test.path, len(groups), test.wantGroups)
+			}
+		})
+	}
+}
+
+func TestAPIPurgeWorkspace(t *testing.T) {
+	const (
+		owner    = "2d9c3a41-7b5e-4f60-8a1b-9c0d1e2f3a11"
+		stranger = "2d9c3a41-7b5e-4f60-8a1b-9c0d1e2f3a12"
+	)
+	api := newTestAPI(t)
+	ctx, claims := context.Background(), api.claims(owner)
+	now := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	projectData, err := api.projects.CreateProject(ctx, claims,
+		project.NewProject{ProjectTypeID: project.TeamCollaborationType, Name: "Team", Description: "Team"}, now)
+	if err != nil {
+		t.Fatalf("CreateProject() error = %v", err)
+	}
+	wsData, err := api.workspaces.CreateWorkspace(ctx, claims, workspace.NewWorkspace{ProjectID: projectData.ID,
+		StemID: workspace.StickerWorkspaceType, Name: "Purged", AssetAmountLimit: 10, MaxX: 100, MaxY: 100, MaxZ: 10},
+		now)
+	if err != nil {
+		t.Fatalf("CreateWorkspace() error = %v", err)
+	}
+	if _, err := api.workspaces.CreateAsset(ctx, claims, workspace.NewAsset{WorkspaceID: wsData.ID,
+		AssetRefID: "sticker", X: 1, Y: 1, Z: 1, Scale: 1, Height: 1, Width: 1, Length: 1}, now); err != nil {
+		t.Fatalf("CreateAsset() error = %v", err)
+	}
+
+	path := "/v1/workspaces/" + wsData.ID + "/purge"
+	if status := api.call(t, stranger, http.MethodPost, path, nil, nil); status != http.StatusForbidden {
+		t.Errorf("POST %s by a stranger status = %d, want %d", path, status, http.StatusForbidden)
+	}
+
+	var result struct {
+		AssetRefs []string `json:"assetRefs"`
+	}
+	if status := api.call(t, owner, http.MethodPost, path, nil, &result); status != http.StatusOK {
+		t.Fatalf("POST %s status = %d, want %d", path, status, http.StatusOK)
+	}
+	if len(result.AssetRefs) != 1 || result.AssetRefs[0] != "sticker" {
+		t.Errorf("POST %s returned %v asset references, want the purged one", path, result.AssetRefs)
+	}
+
+	if status := api.call(t, owner, http.MethodPost, path, nil, nil); status != http.StatusNotFound {
+		t.Errorf("POST %s of purged workspace status = %d, want %d", path, status, http.StatusNotFound)
+	}
+}
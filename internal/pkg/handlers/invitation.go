@@ -0,0 +1,134 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/SKorolchuk/dpio-workspace/internal/pkg/infra/auth"
+	"github.com/SKorolchuk/dpio-workspace/internal/pkg/infra/server"
+	"github.com/SKorolchuk/dpio-workspace/internal/pkg/infra/validation"
+	"github.com/SKorolchuk/dpio-workspace/internal/pkg/store/project"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// InvitationAudience is the audience of invitation tokens, it prevents use of other tokens as invitations.
+const InvitationAudience = "project-invitation"
+
+// invitationHandlers contains HTTP handlers of Invitation entities.
+type invitationHandlers struct {
+	store       project.InvitationRepository
+	authContext *auth.AuthenticationContext
+}
+
+// invitationResponse contains created Invitation entity and its signed token, the token is shown only once.
+type invitationResponse struct {
+	Invitation project.Invitation `json:"invitation"`
+	Token      string             `json:"token"`
+}
+
+// acceptRequest contains signed token of the accepted invitation.
+type acceptRequest struct {
+	Token string `json:"token" validate:"required"`
+}
+
+// create invites a user into Group entity of Project entity and returns the invitation token.
+func (h invitationHandlers) create(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	info, claims, err := requestContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	var newInvitation project.NewInvitation
+	if err := server.Decode(r, &newInvitation); err != nil {
+		return err
+	}
+
+	invitation, err := h.store.CreateInvitation(ctx, claims, server.Param(r, "project_id"), newInvitation, info.Now)
+	if err != nil {
+		return requestError(err)
+	}
+
+	token, err := h.authContext.GenerateSignedToken(jwt.StandardClaims{
+		Id:        invitation.ID,
+		Audience:  InvitationAudience,
+		Issuer:    claims.Subject,
+		IssuedAt:  invitation.DateCreated.Unix(),
+		ExpiresAt: invitation.DateExpires.Unix(),
+	})
+	if err != nil {
+		return fmt.Errorf("error during signing of Invitation token -> id={%q}: %w", invitation.ID, err)
+	}
+
+	return server.Respond(ctx, w, invitationResponse{Invitation: invitation, Token: token}, http.StatusCreated)
+}
+
+// queryPending returns pending Invitation entities of Project entity.
+func (h invitationHandlers) queryPending(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	info, claims, err := requestContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	invitations, err := h.store.QueryPendingInvitations(ctx, claims, server.Param(r, "project_id"), info.Now)
+	if err != nil {
+		return requestError(err)
+	}
+
+	if invitations == nil {
+		invitations = []project.Invitation{}
+	}
+
+	return server.Respond(ctx, w, invitations, http.StatusOK)
+}
+
+// revoke revokes pending Invitation entity of Project entity.
+func (h invitationHandlers) revoke(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	info, claims, err := requestContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = h.store.RevokeInvitation(ctx, claims, server.Param(r, "project_id"), server.Param(r, "invitation_id"),
+		info.Now)
+	if err != nil {
+		return requestError(err)
+	}
+
+	return server.Respond(ctx, w, nil, http.StatusNoContent)
+}
+
+// accept reads the invitation token and assigns the caller to the invitation group.
+func (h invitationHandlers) accept(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	info, claims, err := requestContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	var request acceptRequest
+	if err := server.Decode(r, &request); err != nil {
+		return err
+	}
+
+	if err := validation.Check(ctx, request); err != nil {
+		return err
+	}
+
+	var tokenClaims jwt.StandardClaims
+	if err := h.authContext.ReadSignedToken(request.Token, &tokenClaims); err != nil {
+		return validation.NewRequestError(fmt.Errorf("invitation token is invalid: %w", err), http.StatusBadRequest)
+	}
+
+	if !tokenClaims.VerifyAudience(InvitationAudience, true) {
+		return validation.NewRequestError(fmt.Errorf("invitation token is invalid: %w", auth.ErrorTokenAuthority),
+			http.StatusBadRequest)
+	}
+
+	groupUser, err := h.store.AcceptInvitation(ctx, claims, tokenClaims.Id, info.Now)
+	if err != nil {
+		return requestError(err)
+	}
+
+	return server.Respond(ctx, w, groupUser, http.StatusCreated)
+}
@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/SKorolchuk/dpio-workspace/internal/pkg/infra/server"
+	"github.com/SKorolchuk/dpio-workspace/internal/pkg/store/project"
+)
+
+// ownerHandlers contains HTTP handlers of ProjectOwner entities.
+type ownerHandlers struct {
+	store project.OwnerRepository
+}
+
+// query returns ProjectOwner entities of Project entity readable by the caller.
+func (h ownerHandlers) query(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	_, claims, err := requestContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	owners, err := h.store.QueryProjectOwners(ctx, claims, server.Param(r, "project_id"))
+	if err != nil {
+		return requestError(err)
+	}
+
+	if owners == nil {
+		owners = []project.ProjectOwner{}
+	}
+
+	return server.Respond(ctx, w, owners, http.StatusOK)
+}
+
+// add assigns a user as one more owner of Project entity.
+func (h ownerHandlers) add(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	info, claims, err := requestContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	var newOwner project.NewProjectOwner
+	if err := server.Decode(r, &newOwner); err != nil {
+		return err
+	}
+
+	owner, err := h.store.AddProjectOwner(ctx, claims, server.Param(r, "project_id"), newOwner, info.Now)
+	if err != nil {
+		return requestError(err)
+	}
+
+	return server.Respond(ctx, w, owner, http.StatusCreated)
+}
+
+// remove removes a user from owners of Project entity, the caller can remove itself to leave the project.
+func (h ownerHandlers) remove(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	_, claims, err := requestContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = h.store.RemoveProjectOwner(ctx, claims, server.Param(r, "project_id"), server.Param(r, "user_id"))
+	if err != nil {
+		return requestError(err)
+	}
+
+	return server.Respond(ctx, w, nil, http.StatusNoContent)
+}
+
+// transfer passes ownership of Project entity from the caller to another user.
+func (h ownerHandlers) transfer(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	info, claims, err := requestContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	var transfer project.TransferOwnership
+	if err := server.Decode(r, &transfer); err != nil {
+		return err
+	}
+
+	owner, err := h.store.TransferProjectOwnership(ctx, claims, server.Param(r, "project_id"), transfer, info.Now)
+	if err != nil {
+		return requestError(err)
+	}
+
+	return server.Respond(ctx, w, owner, http.StatusOK)
+}
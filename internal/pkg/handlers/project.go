@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/SKorolchuk/dpio-workspace/internal/pkg/infra/server"
+	"github.com/SKorolchuk/dpio-workspace/internal/pkg/store/project"
+)
+
+// projectHandlers contains HTTP handlers of Project entity lifecycle.
+type projectHandlers struct {
+	store project.ProjectRepository
+}
+
+// delete marks Project entity as deleted, the project can be restored until it is purged.
+// Project with workspaces is deleted only with "cascade" query parameter.
+func (h projectHandlers) delete(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	info, claims, err := requestContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	cascade, err := queryFlag(r, "cascade")
+	if err != nil {
+		return err
+	}
+
+	if err := h.store.DeleteProject(ctx, claims, server.Param(r, "project_id"), cascade, info.Now); err != nil {
+		return requestError(err)
+	}
+
+	return server.Respond(ctx, w, nil, http.StatusNoContent)
+}
+
+// archive hides Project entity from default queries.
+func (h projectHandlers) archive(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	info, claims, err := requestContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := h.store.ArchiveProject(ctx, claims, server.Param(r, "project_id"), info.Now); err != nil {
+		return requestError(err)
+	}
+
+	return server.Respond(ctx, w, nil, http.StatusNoContent)
+}
+
+// restore returns archived or deleted Project entity back to default queries.
+func (h projectHandlers) restore(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	info, claims, err := requestContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := h.store.RestoreProject(ctx, claims, server.Param(r, "project_id"), info.Now); err != nil {
+		return requestError(err)
+	}
+
+	return server.Respond(ctx, w, nil, http.StatusNoContent)
+}
@@ -0,0 +1,142 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/SKorolchuk/dpio-workspace/internal/pkg/infra/server"
+	"github.com/SKorolchuk/dpio-workspace/internal/pkg/store/project"
+)
+
+// roleHandlers contains HTTP handlers of Role and GroupRole entities.
+type roleHandlers struct {
+	store project.RoleRepository
+}
+
+// query returns a page of the role catalogue.
+func (h roleHandlers) query(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	skip, top, err := pageOf(r)
+	if err != nil {
+		return err
+	}
+
+	roles, err := h.store.QueryRoles(ctx, skip, top)
+	if err != nil {
+		return requestError(err)
+	}
+
+	return respondRoles(ctx, w, roles)
+}
+
+// create adds new Role entity to the catalogue.
+func (h roleHandlers) create(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	_, claims, err := requestContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	var newRole project.NewRole
+	if err := server.Decode(r, &newRole); err != nil {
+		return err
+	}
+
+	roleData, err := h.store.CreateRole(ctx, claims, newRole)
+	if err != nil {
+		return requestError(err)
+	}
+
+	return server.Respond(ctx, w, roleData, http.StatusCreated)
+}
+
+// queryByID returns Role entity.
+func (h roleHandlers) queryByID(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	roleData, err := h.store.QueryRoleByID(ctx, server.Param(r, "role_id"))
+	if err != nil {
+		return requestError(err)
+	}
+
+	return server.Respond(ctx, w, roleData, http.StatusOK)
+}
+
+// retire marks Role entity as retired.
+func (h roleHandlers) retire(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	info, claims, err := requestContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := h.store.RetireRole(ctx, claims, server.Param(r, "role_id"), info.Now); err != nil {
+		return requestError(err)
+	}
+
+	return server.Respond(ctx, w, nil, http.StatusNoContent)
+}
+
+// queryByGroup returns Role entities assigned to Group entity.
+func (h roleHandlers) queryByGroup(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	roles, err := h.store.QueryGroupRoles(ctx, server.Param(r, "group_id"))
+	if err != nil {
+		return requestError(err)
+	}
+
+	return respondRoles(ctx, w, roles)
+}
+
+// assign assigns Role entity to Group entity.
+func (h roleHandlers) assign(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	info, claims, err := requestContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	var newGroupRole project.NewGroupRole
+	if err := server.Decode(r, &newGroupRole); err != nil {
+		return err
+	}
+
+	groupRole, err := h.store.AssignGroupRole(ctx, claims, server.Param(r, "group_id"), newGroupRole, info.Now)
+	if err != nil {
+		return requestError(err)
+	}
+
+	return server.Respond(ctx, w, groupRole, http.StatusCreated)
+}
+
+// unassign removes Role entity assignment from Group entity.
+func (h roleHandlers) unassign(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	_, claims, err := requestContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = h.store.UnassignGroupRole(ctx, claims, server.Param(r, "group_id"), server.Param(r, "role_id"))
+	if err != nil {
+		return requestError(err)
+	}
+
+	return server.Respond(ctx, w, nil, http.StatusNoContent)
+}
+
+// queryEffective returns Role entities which the user effectively holds in Project entity readable by the caller.
+func (h roleHandlers) queryEffective(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	_, claims, err := requestContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	roles, err := h.store.QueryEffectiveRoles(ctx, claims, server.Param(r, "user_id"), server.Param(r, "project_id"))
+	if err != nil {
+		return requestError(err)
+	}
+
+	return respondRoles(ctx, w, roles)
+}
+
+// respondRoles writes Role entities as JSON array, the array is empty if no roles are found.
+func respondRoles(ctx context.Context, w http.ResponseWriter, roles []project.Role) error {
+	if roles == nil {
+		roles = []project.Role{}
+	}
+
+	return server.Respond(ctx, w, roles, http.StatusOK)
+}
@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/SKorolchuk/dpio-workspace/internal/pkg/infra/server"
+	"github.com/SKorolchuk/dpio-workspace/internal/pkg/store/search"
+)
+
+// searchHandlers contains HTTP handlers of search over Project and Workspace entities.
+type searchHandlers struct {
+	store search.Repository
+}
+
+// query returns projects and workspaces readable by the caller which match "q" query parameter, best matches go
+// first. Every result is typed with its kind, archived entities are included only with "includeArchived" query
+// parameter.
+func (h searchHandlers) query(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	_, claims, err := requestContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	top, err := queryTop(r)
+	if err != nil {
+		return err
+	}
+
+	includeArchived, err := queryFlag(r, "includeArchived")
+	if err != nil {
+		return err
+	}
+
+	query := search.Query{
+		Text:            r.URL.Query().Get("q"),
+		IncludeArchived: includeArchived,
+		Top:             top,
+	}
+
+	results, err := h.store.Search(ctx, claims, query)
+	if err != nil {
+		return requestError(err)
+	}
+
+	if results == nil {
+		results = []search.Result{}
+	}
+
+	return server.Respond(ctx, w, results, http.StatusOK)
+}
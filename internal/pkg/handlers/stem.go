@@ -0,0 +1,33 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/SKorolchuk/dpio-workspace/internal/pkg/infra/server"
+	"github.com/SKorolchuk/dpio-workspace/internal/pkg/store/workspace"
+)
+
+// stemHandlers contains HTTP handlers of Stem entities.
+type stemHandlers struct {
+	store workspace.StemRepository
+}
+
+// query returns a page of Stem entities together with a cursor of the next page.
+func (h stemHandlers) query(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	page, err := pageRequestOf(r)
+	if err != nil {
+		return err
+	}
+
+	stemPage, err := h.store.QueryStemsPage(ctx, page)
+	if err != nil {
+		return requestError(err)
+	}
+
+	if stemPage.Items == nil {
+		stemPage.Items = []workspace.Stem{}
+	}
+
+	return server.Respond(ctx, w, stemPage, http.StatusOK)
+}
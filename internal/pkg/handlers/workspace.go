@@ -0,0 +1,203 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/SKorolchuk/dpio-workspace/internal/pkg/infra/server"
+	"github.com/SKorolchuk/dpio-workspace/internal/pkg/store/workspace"
+)
+
+// workspaceHandlers contains HTTP handlers of Workspace entities.
+type workspaceHandlers struct {
+	store workspace.WorkspaceRepository
+}
+
+// query returns a page of Workspace entities readable by the caller together with a cursor of the next page.
+// Workspaces are filtered by repeatable "projectId" and "stemId" query parameters, by "createdBy", "namePrefix",
+// "nameContains" and by RFC 3339 "createdAfter", "createdBefore", "updatedAfter" and "updatedBefore" parameters.
+// The order is chosen by "sortBy" and "sortDirection" parameters, archived workspaces are included only with
+// "includeArchived" query parameter.
+func (h workspaceHandlers) query(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	_, claims, err := requestContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	page, err := pageRequestOf(r)
+	if err != nil {
+		return err
+	}
+
+	spec, err := workspaceQueryOf(r)
+	if err != nil {
+		return err
+	}
+
+	wsPage, err := h.store.QueryWorkspacesBySpec(ctx, claims, spec, page)
+	if err != nil {
+		return requestError(err)
+	}
+
+	if wsPage.Items == nil {
+		wsPage.Items = []workspace.Workspace{}
+	}
+
+	return server.Respond(ctx, w, wsPage, http.StatusOK)
+}
+
+// clone copies Workspace entity with all of its assets into the project and under the name specified in the body.
+func (h workspaceHandlers) clone(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	info, claims, err := requestContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	var target workspace.CloneTarget
+	if err := server.Decode(r, &target); err != nil {
+		return err
+	}
+
+	wsCopy, err := h.store.CloneWorkspace(ctx, claims, server.Param(r, "workspace_id"), target, info.Now)
+	if err != nil {
+		return requestError(err)
+	}
+
+	return server.Respond(ctx, w, wsCopy, http.StatusCreated)
+}
+
+// purgeResult contains external asset references which are no longer used after purge of Workspace entity.
+type purgeResult struct {
+	AssetRefs []string `json:"assetRefs"`
+}
+
+// purge permanently removes Workspace entity with all of its assets and reports external asset references which are
+// no longer used, so the asset storage can collect their binaries.
+func (h workspaceHandlers) purge(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	_, claims, err := requestContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	assetRefs, err := h.store.PurgeWorkspace(ctx, claims, server.Param(r, "workspace_id"))
+	if err != nil {
+		return requestError(err)
+	}
+
+	return server.Respond(ctx, w, purgeResult{AssetRefs: assetRefs}, http.StatusOK)
+}
+
+// export streams Document of Workspace entity with its stem and assets as a JSON attachment.
+func (h workspaceHandlers) export(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	_, claims, err := requestContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	wsId := server.Param(r, "workspace_id")
+	attachment := &attachmentWriter{ctx: ctx, w: w, fileName: "workspace-" + wsId + ".json"}
+	if err := h.store.ExportWorkspace(ctx, claims, wsId, attachment); err != nil {
+		if attachment.started {
+			return err
+		}
+
+		return requestError(err)
+	}
+
+	return nil
+}
+
+// importDocument creates Workspace entity from Document in the body inside the project of the path, the workspace
+// is renamed when "name" query parameter is specified. With "dryRun" query parameter nothing is created and only
+// conflicts of the document are reported. Document with conflicts is rejected with 409 status and the report.
+func (h workspaceHandlers) importDocument(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	info, claims, err := requestContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	options := workspace.ImportOptions{
+		ProjectID: server.Param(r, "project_id"),
+		Name:      r.URL.Query().Get("name"),
+	}
+	if options.DryRun, err = queryFlag(r, "dryRun"); err != nil {
+		return err
+	}
+
+	var doc workspace.Document
+	if err := server.Decode(r, &doc); err != nil {
+		return err
+	}
+
+	report, err := h.store.ImportWorkspace(ctx, claims, doc, options, info.Now)
+	if err != nil {
+		return requestError(err)
+	}
+
+	switch {
+	case report.DryRun:
+		return server.Respond(ctx, w, report, http.StatusOK)
+	case len(report.Conflicts) > 0:
+		return server.Respond(ctx, w, report, http.StatusConflict)
+	}
+
+	return server.Respond(ctx, w, report, http.StatusCreated)
+}
+
+// attachmentWriter writes JSON attachment into the response, status and headers are sent right before the first
+// write. Errors which occur before it can still be responded with an error status.
+type attachmentWriter struct {
+	ctx      context.Context
+	w        http.ResponseWriter
+	fileName string
+	started  bool
+}
+
+// Write sends status and headers of the attachment on the first call and writes content into the response.
+func (aw *attachmentWriter) Write(content []byte) (int, error) {
+	if !aw.started {
+		aw.started = true
+		if err := server.SetStatusCode(aw.ctx, http.StatusOK); err != nil {
+			return 0, err
+		}
+
+		aw.w.Header().Set("Content-Type", "application/json")
+		aw.w.Header().Set("Content-Disposition", `attachment; filename="`+aw.fileName+`"`)
+		aw.w.WriteHeader(http.StatusOK)
+	}
+
+	return aw.w.Write(content)
+}
+
+// workspaceQueryOf reads filters and order of Workspace entities from query parameters of the request.
+func workspaceQueryOf(r *http.Request) (workspace.WorkspaceQuery, error) {
+	values := r.URL.Query()
+	spec := workspace.WorkspaceQuery{
+		ProjectIDs:    values["projectId"],
+		StemIDs:       values["stemId"],
+		CreatedBy:     values.Get("createdBy"),
+		NamePrefix:    values.Get("namePrefix"),
+		NameContains:  values.Get("nameContains"),
+		SortBy:        values.Get("sortBy"),
+		SortDirection: values.Get("sortDirection"),
+	}
+
+	var err error
+	if spec.IncludeArchived, err = queryFlag(r, "includeArchived"); err != nil {
+		return workspace.WorkspaceQuery{}, err
+	}
+	if spec.CreatedAfter, err = queryTime(r, "createdAfter"); err != nil {
+		return workspace.WorkspaceQuery{}, err
+	}
+	if spec.CreatedBefore, err = queryTime(r, "createdBefore"); err != nil {
+		return workspace.WorkspaceQuery{}, err
+	}
+	if spec.UpdatedAfter, err = queryTime(r, "updatedAfter"); err != nil {
+		return workspace.WorkspaceQuery{}, err
+	}
+	if spec.UpdatedBefore, err = queryTime(r, "updatedBefore"); err != nil {
+		return workspace.WorkspaceQuery{}, err
+	}
+
+	return spec, nil
+}
@@ -16,6 +16,7 @@ var (
 	ErrorKIDNotFound          = errors.New("kid Header not found in token")
 	ErrorKIDInvalidString     = errors.New("kid Header value must be string")
 	ErrorTokenAuthority       = errors.New("token authority cannot be confirmed")
+	ErrorTokenPurpose         = errors.New("token is not a user session token")
 )
 
 // KeyStore declares interface for a set of methods to retrieve
@@ -77,6 +78,11 @@ func NewAuthenticationContext(activeKeyId string, keyStore KeyStore) (*Authentic
 
 // GenerateToken returns a signed JWT string with user Claims.
 func (ctx *AuthenticationContext) GenerateToken(claims Claims) (string, error) {
+	return ctx.GenerateSignedToken(claims)
+}
+
+// GenerateSignedToken returns a signed JWT string with arbitrary claims, e.g. claims of single-use invitation tokens.
+func (ctx *AuthenticationContext) GenerateSignedToken(claims jwt.Claims) (string, error) {
 	token := jwt.NewWithClaims(ctx.method, claims)
 	token.Header["kid"] = ctx.currentKeyId
 
@@ -94,18 +100,33 @@ func (ctx *AuthenticationContext) GenerateToken(claims Claims) (string, error) {
 }
 
 // ReadClaimsFromToken returns user Claims that stored in a target JWT token.
-// Token should be signed with a correct key.
+// Token should be signed with a correct key and should belong to a user session. Tokens with an audience, e.g.
+// invitation tokens, and tokens without a subject are rejected with ErrorTokenPurpose.
 func (ctx *AuthenticationContext) ReadClaimsFromToken(signedToken string) (Claims, error) {
 	var claims Claims
 
-	token, err := ctx.parser.ParseWithClaims(signedToken, &claims, ctx.getKey)
+	if err := ctx.ReadSignedToken(signedToken, &claims); err != nil {
+		return Claims{}, err
+	}
+
+	if claims.Audience != "" || claims.Subject == "" {
+		return Claims{}, ErrorTokenPurpose
+	}
+
+	return claims, nil
+}
+
+// ReadSignedToken reads arbitrary claims stored in a target JWT token into claims argument.
+// Token should be signed with a correct key, time-based claims such as expiry are verified as well.
+func (ctx *AuthenticationContext) ReadSignedToken(signedToken string, claims jwt.Claims) error {
+	token, err := ctx.parser.ParseWithClaims(signedToken, claims, ctx.getKey)
 	if err != nil {
-		return Claims{}, fmt.Errorf("error during parse of signed token: %w", err)
+		return fmt.Errorf("error during parse of signed token: %w", err)
 	}
 
 	if !token.Valid {
-		return Claims{}, ErrorTokenAuthority
+		return ErrorTokenAuthority
 	}
 
-	return claims, nil
+	return nil
 }
@@ -7,9 +7,16 @@ import (
 	"github.com/golang-jwt/jwt/v4"
 )
 
+// Roles of users which are stored in Claims.
+const (
+	RoleAdmin = "ADMIN"
+	RoleUser  = "USER"
+)
+
 // Claims represents the authorization claims in JWT format.
 type Claims struct {
 	jwt.StandardClaims
+	Email string   `json:"email,omitempty"`
 	Roles []string `json:"roles"`
 }
 
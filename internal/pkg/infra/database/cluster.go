@@ -0,0 +1,219 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"go.uber.org/zap"
+)
+
+const (
+	DefaultReplicaCheckInterval = 5 * time.Second
+	DefaultReplicaCheckTimeout  = time.Second
+	DefaultMaxReplicaLag        = 10 * time.Second
+)
+
+// replicaLagQuery returns replication lag of PostgreSQL standby in seconds. Standby which has replayed all received
+// WAL is not lagging regardless of the time of the last replayed transaction, since the primary can be idle.
+const replicaLagQuery = `
+	SELECT
+		CASE
+			WHEN pg_last_wal_receive_lsn() = pg_last_wal_replay_lsn() THEN 0
+			ELSE COALESCE(EXTRACT(EPOCH FROM now() - pg_last_xact_replay_timestamp()), 0)
+		END`
+
+// primaryContextKeyType represents the identifier of context key for primary database routing.
+type primaryContextKeyType int
+
+// primaryContextKey is used to mark context.Context whose queries should be served by the primary database.
+const primaryContextKey primaryContextKeyType = 481516
+
+// WithPrimary marks the context so all queries executed with it are routed to the primary database.
+// It is used for read-your-writes flows when data is read right after a mutation.
+func WithPrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, primaryContextKey, true)
+}
+
+// isPrimaryRequired reports whether the context was marked by WithPrimary.
+func isPrimaryRequired(ctx context.Context) bool {
+	required, _ := ctx.Value(primaryContextKey).(bool)
+	return required
+}
+
+// replica represents a read replica connection with its last known health state.
+type replica struct {
+	connection *sqlx.DB
+	healthy    int32
+}
+
+// Cluster routes database queries between the primary database and read replicas.
+// Mutations always use the primary database, query-only paths use a healthy replica if any exists.
+type Cluster struct {
+	primary     *sqlx.DB
+	replicas    []*replica
+	next        uint32
+	maxLag      time.Duration
+	replicaLag  func(ctx context.Context, connection *sqlx.DB) (time.Duration, error)
+	stopMonitor context.CancelFunc
+}
+
+// NewCluster constructs a Cluster from the primary connection and optional read replica connections.
+// All replicas are considered healthy until the first failed health check, replicas lagging behind the primary
+// longer than DefaultMaxReplicaLag fail the check.
+func NewCluster(primary *sqlx.DB, replicas ...*sqlx.DB) *Cluster {
+	cluster := Cluster{
+		primary:    primary,
+		replicas:   make([]*replica, 0, len(replicas)),
+		maxLag:     DefaultMaxReplicaLag,
+		replicaLag: queryReplicaLag,
+	}
+
+	for _, connection := range replicas {
+		cluster.replicas = append(cluster.replicas, &replica{connection: connection, healthy: 1})
+	}
+
+	return &cluster
+}
+
+// Primary returns connection to the primary database.
+func (cl *Cluster) Primary() *sqlx.DB {
+	return cl.primary
+}
+
+// Reader returns connection for query-only paths.
+// Healthy replicas are picked in round-robin order, the primary database is used as a fallback
+// or when the context was marked by WithPrimary.
+func (cl *Cluster) Reader(ctx context.Context) *sqlx.DB {
+	if len(cl.replicas) == 0 || isPrimaryRequired(ctx) {
+		return cl.primary
+	}
+
+	start := atomic.AddUint32(&cl.next, 1)
+	for offset := 0; offset < len(cl.replicas); offset++ {
+		candidate := cl.replicas[(int(start)+offset)%len(cl.replicas)]
+		if atomic.LoadInt32(&candidate.healthy) == 1 {
+			return candidate.connection
+		}
+	}
+
+	return cl.primary
+}
+
+// SetMaxReplicaLag changes the maximal replication lag of a healthy replica, zero value disables the lag check.
+func (cl *Cluster) SetMaxReplicaLag(maxLag time.Duration) {
+	cl.maxLag = maxLag
+}
+
+// CheckReplicas pings every read replica, compares its replication lag with the maximal one and updates its health
+// state. Returns the number of healthy replicas.
+func (cl *Cluster) CheckReplicas(ctx context.Context, logger *zap.SugaredLogger) int {
+	healthyCount := 0
+
+	for _, candidate := range cl.replicas {
+		if err := cl.checkReplica(ctx, candidate.connection); err != nil {
+			if atomic.SwapInt32(&candidate.healthy, 0) == 1 {
+				logger.Warnw("database.CheckReplicas", "status", "replica is unhealthy, falling back", "error", err)
+			}
+			continue
+		}
+
+		if atomic.SwapInt32(&candidate.healthy, 1) == 0 {
+			logger.Infow("database.CheckReplicas", "status", "replica is healthy again")
+		}
+		healthyCount++
+	}
+
+	return healthyCount
+}
+
+// checkReplica returns error if the replica connection is not available or the replica lags behind the primary
+// longer than the maximal replication lag.
+func (cl *Cluster) checkReplica(ctx context.Context, connection *sqlx.DB) error {
+	checkCtx, cancel := context.WithTimeout(ctx, DefaultReplicaCheckTimeout)
+	defer cancel()
+
+	if err := connection.PingContext(checkCtx); err != nil {
+		return err
+	}
+
+	if cl.maxLag <= 0 {
+		return nil
+	}
+
+	lag, err := cl.replicaLag(checkCtx, connection)
+	if err != nil {
+		return fmt.Errorf("failed to query replication lag: %w", err)
+	}
+
+	if lag > cl.maxLag {
+		return fmt.Errorf("replication lag %s exceeds %s", lag, cl.maxLag)
+	}
+
+	return nil
+}
+
+// queryReplicaLag returns replication lag of PostgreSQL standby.
+func queryReplicaLag(ctx context.Context, connection *sqlx.DB) (time.Duration, error) {
+	var seconds float64
+	if err := connection.QueryRowContext(ctx, replicaLagQuery).Scan(&seconds); err != nil {
+		return 0, err
+	}
+
+	return time.Duration(seconds * float64(time.Second)), nil
+}
+
+// MonitorReplicas runs CheckReplicas with the specified interval until the context is cancelled.
+func (cl *Cluster) MonitorReplicas(ctx context.Context, logger *zap.SugaredLogger, interval time.Duration) {
+	if len(cl.replicas) == 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			cl.CheckReplicas(ctx, logger)
+		}
+	}
+}
+
+// StartMonitor runs MonitorReplicas in background with DefaultReplicaCheckInterval until the Cluster is closed.
+func (cl *Cluster) StartMonitor(logger *zap.SugaredLogger) {
+	if len(cl.replicas) == 0 || cl.stopMonitor != nil {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cl.stopMonitor = cancel
+
+	go cl.MonitorReplicas(ctx, logger, DefaultReplicaCheckInterval)
+}
+
+// Close stops the replica monitor and pool metrics, then closes connections to the primary database and all read
+// replicas.
+func (cl *Cluster) Close() error {
+	var closeError error
+
+	if cl.stopMonitor != nil {
+		cl.stopMonitor()
+	}
+
+	for _, candidate := range cl.replicas {
+		if err := Close(candidate.connection); err != nil && closeError == nil {
+			closeError = err
+		}
+	}
+
+	if err := Close(cl.primary); err != nil && closeError == nil {
+		closeError = err
+	}
+
+	return closeError
+}
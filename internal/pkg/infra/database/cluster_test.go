@@ -0,0 +1,171 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"go.uber.org/zap"
+)
+
+// openTestConnections opens count of independent in-memory SQLite connections which are closed after the test.
+func openTestConnections(t *testing.T, count int) []*sqlx.DB {
+	t.Helper()
+
+	connections := make([]*sqlx.DB, 0, count)
+	for index := 0; index < count; index++ {
+		connection, err := sqlx.Open(DriverSQLite, ":memory:")
+		if err != nil {
+			t.Fatalf("sqlx.Open() error = %v", err)
+		}
+		t.Cleanup(func() { _ = connection.Close() })
+		connections = append(connections, connection)
+	}
+
+	return connections
+}
+
+func TestClusterReader(t *testing.T) {
+	connections := openTestConnections(t, 3)
+	primary, first, second := connections[0], connections[1], connections[2]
+
+	t.Run("without replicas", func(t *testing.T) {
+		cluster := NewCluster(primary)
+		if cluster.Reader(context.Background()) != primary {
+			t.Errorf("Reader() is not the primary database")
+		}
+	})
+
+	t.Run("round-robin over healthy replicas", func(t *testing.T) {
+		cluster := NewCluster(primary, first, second)
+		seen := map[*sqlx.DB]int{}
+		for index := 0; index < 4; index++ {
+			seen[cluster.Reader(context.Background())]++
+		}
+		if seen[first] != 2 || seen[second] != 2 {
+			t.Errorf("Reader() distribution = %d, %d, want 2, 2", seen[first], seen[second])
+		}
+	})
+
+	t.Run("primary is required by context", func(t *testing.T) {
+		cluster := NewCluster(primary, first, second)
+		if cluster.Reader(WithPrimary(context.Background())) != primary {
+			t.Errorf("Reader() of context marked by WithPrimary is not the primary database")
+		}
+	})
+
+	t.Run("unhealthy replicas are skipped", func(t *testing.T) {
+		cluster := NewCluster(primary, first, second)
+		cluster.replicas[0].healthy = 0
+		for index := 0; index < 2; index++ {
+			if cluster.Reader(context.Background()) != second {
+				t.Errorf("Reader() is not the healthy replica")
+			}
+		}
+
+		cluster.replicas[1].healthy = 0
+		if cluster.Reader(context.Background()) != primary {
+			t.Errorf("Reader() without healthy replicas is not the primary database")
+		}
+	})
+}
+
+func TestClusterCheckReplicas(t *testing.T) {
+	lagging := errors.New("lag is not available")
+
+	tests := []struct {
+		name        string
+		maxLag      time.Duration
+		lag         time.Duration
+		lagErr      error
+		closed      bool
+		wantHealthy int
+	}{
+		{name: "available replica", maxLag: time.Second, lag: time.Second, wantHealthy: 1},
+		{name: "closed replica", maxLag: time.Second, closed: true},
+		{name: "lagging replica", maxLag: time.Second, lag: time.Second + time.Millisecond},
+		{name: "unknown lag", maxLag: time.Second, lagErr: lagging},
+		{name: "lag check is disabled", lag: time.Hour, lagErr: lagging, wantHealthy: 1},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			connections := openTestConnections(t, 2)
+			cluster := NewCluster(connections[0], connections[1])
+			cluster.SetMaxReplicaLag(test.maxLag)
+			cluster.replicaLag = func(context.Context, *sqlx.DB) (time.Duration, error) {
+				return test.lag, test.lagErr
+			}
+			if test.closed {
+				_ = connections[1].Close()
+			}
+
+			healthy := cluster.CheckReplicas(context.Background(), zap.NewNop().Sugar())
+			if healthy != test.wantHealthy {
+				t.Errorf("CheckReplicas() = %d, want %d", healthy, test.wantHealthy)
+			}
+
+			wantReader := connections[1]
+			if test.wantHealthy == 0 {
+				wantReader = connections[0]
+			}
+			if cluster.Reader(context.Background()) != wantReader {
+				t.Errorf("Reader() does not match health of the replica")
+			}
+		})
+	}
+
+	t.Run("replica is healthy again", func(t *testing.T) {
+		connections := openTestConnections(t, 2)
+		cluster := NewCluster(connections[0], connections[1])
+		lag := time.Hour
+		cluster.replicaLag = func(context.Context, *sqlx.DB) (time.Duration, error) {
+			return lag, nil
+		}
+
+		if healthy := cluster.CheckReplicas(context.Background(), zap.NewNop().Sugar()); healthy != 0 {
+			t.Fatalf("CheckReplicas() = %d, want 0", healthy)
+		}
+
+		lag = 0
+		if healthy := cluster.CheckReplicas(context.Background(), zap.NewNop().Sugar()); healthy != 1 {
+			t.Errorf("CheckReplicas() = %d, want 1", healthy)
+		}
+	})
+}
+
+func TestClusterMonitor(t *testing.T) {
+	connections := openTestConnections(t, 2)
+	cluster := NewCluster(connections[0], connections[1])
+	checked := make(chan struct{}, 1)
+	cluster.replicaLag = func(context.Context, *sqlx.DB) (time.Duration, error) {
+		select {
+		case checked <- struct{}{}:
+		default:
+		}
+
+		return 0, nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan struct{})
+	go func() {
+		cluster.MonitorReplicas(ctx, zap.NewNop().Sugar(), time.Millisecond)
+		close(stopped)
+	}()
+
+	select {
+	case <-checked:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("MonitorReplicas() did not check replicas")
+	}
+
+	cancel()
+	select {
+	case <-stopped:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("MonitorReplicas() did not stop after the context was cancelled")
+	}
+}
@@ -0,0 +1,79 @@
+package database
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/SKorolchuk/dpio-workspace/internal/pkg/infra/uuid"
+)
+
+// ErrorInvalidCursor is returned when a page cursor token cannot be decoded.
+var ErrorInvalidCursor = errors.New("page cursor is not valid")
+
+// Cursor represents a position in a collection ordered by a date or a text field and by identifier as a tiebreaker.
+// The next page starts right after the row described by the cursor.
+// Sort names the order of the collection, so a cursor of one order is not applied to another one.
+// Collections ordered only by identifier keep neither Date nor Text.
+type Cursor struct {
+	Sort string    `json:"s,omitempty"`
+	Date time.Time `json:"u"`
+	Text string    `json:"t,omitempty"`
+	ID   string    `json:"i"`
+}
+
+// Keyset contains named query parameters which select rows after a Cursor.
+// Queries should compare with after_date, after_text and after_id parameters only when the cursor is specified,
+// since empty identifier is not a valid value of UUID columns.
+type Keyset struct {
+	AfterDate time.Time `db:"after_date"`
+	AfterText string    `db:"after_text"`
+	AfterID   string    `db:"after_id"`
+}
+
+// NewKeyset creates query parameters of the position described by cursor, nil cursor selects the first page.
+func NewKeyset(cursor *Cursor) Keyset {
+	if cursor == nil {
+		return Keyset{}
+	}
+
+	return Keyset{
+		AfterDate: cursor.Date.UTC(),
+		AfterText: cursor.Text,
+		AfterID:   cursor.ID,
+	}
+}
+
+// EncodeCursor converts cursor into an opaque URL-safe token.
+func EncodeCursor(cursor Cursor) string {
+	cursor.Date = cursor.Date.UTC()
+
+	data, err := json.Marshal(cursor)
+	if err != nil {
+		return ""
+	}
+
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeCursor converts token produced by EncodeCursor back into Cursor.
+// Empty token means the first page and is decoded into nil.
+// If the token is malformed or its identifier is not a UUID, the function returns ErrorInvalidCursor.
+func DecodeCursor(token string) (*Cursor, error) {
+	if token == "" {
+		return nil, nil
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, ErrorInvalidCursor
+	}
+
+	var cursor Cursor
+	if err := json.Unmarshal(data, &cursor); err != nil || uuid.Validate(cursor.ID) != nil {
+		return nil, ErrorInvalidCursor
+	}
+
+	return &cursor, nil
+}
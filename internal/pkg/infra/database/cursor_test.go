@@ -0,0 +1,87 @@
+package database
+
+import (
+	"encoding/base64"
+	"testing"
+	"time"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	date := time.Date(2021, 1, 1, 3, 0, 1, 1000, time.FixedZone("UTC+3", 3*60*60))
+
+	tests := []struct {
+		name   string
+		cursor Cursor
+	}{
+		{
+			name:   "identifier only",
+			cursor: Cursor{ID: "5b3ea10c-f6c6-4931-bbfc-ec20b190cca4"},
+		},
+		{
+			name:   "date order",
+			cursor: Cursor{Sort: "dateUpdated.desc", Date: date, ID: "5b3ea10c-f6c6-4931-bbfc-ec20b190cca4"},
+		},
+		{
+			name:   "text order",
+			cursor: Cursor{Sort: "name.asc", Text: "Workspace \"1\" / ü", ID: "5b3ea10c-f6c6-4931-bbfc-ec20b190cca4"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			token := EncodeCursor(test.cursor)
+			if token == "" {
+				t.Fatalf("EncodeCursor() returned empty token")
+			}
+
+			decoded, err := DecodeCursor(token)
+			if err != nil {
+				t.Fatalf("DecodeCursor() error = %v", err)
+			}
+			if decoded == nil {
+				t.Fatalf("DecodeCursor() returned nil cursor")
+			}
+
+			if decoded.Sort != test.cursor.Sort || decoded.Text != test.cursor.Text || decoded.ID != test.cursor.ID {
+				t.Errorf("DecodeCursor() = %+v, want %+v", *decoded, test.cursor)
+			}
+			if !decoded.Date.Equal(test.cursor.Date) {
+				t.Errorf("DecodeCursor() date = %v, want %v", decoded.Date, test.cursor.Date)
+			}
+		})
+	}
+}
+
+func TestDecodeCursor(t *testing.T) {
+	encode := func(content string) string {
+		return base64.RawURLEncoding.EncodeToString([]byte(content))
+	}
+
+	tests := []struct {
+		name    string
+		token   string
+		wantNil bool
+		wantErr error
+	}{
+		{name: "empty token is the first page", token: "", wantNil: true},
+		{name: "malformed base64", token: "not a cursor!", wantNil: true, wantErr: ErrorInvalidCursor},
+		{name: "malformed json", token: encode(`{"i":`), wantNil: true, wantErr: ErrorInvalidCursor},
+		{name: "missing identifier", token: encode(`{"s":"name.asc","t":"a"}`), wantNil: true,
+			wantErr: ErrorInvalidCursor},
+		{name: "identifier is not uuid", token: encode(`{"i":"1 OR 1=1"}`), wantNil: true,
+			wantErr: ErrorInvalidCursor},
+		{name: "valid token", token: encode(`{"i":"5b3ea10c-f6c6-4931-bbfc-ec20b190cca4"}`)},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cursor, err := DecodeCursor(test.token)
+			if err != test.wantErr {
+				t.Fatalf("DecodeCursor() error = %v, want %v", err, test.wantErr)
+			}
+			if (cursor == nil) != test.wantNil {
+				t.Errorf("DecodeCursor() cursor = %v, want nil %t", cursor, test.wantNil)
+			}
+		})
+	}
+}
@@ -4,15 +4,19 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net"
 	"net/url"
+	"os"
 	"reflect"
+	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/SKorolchuk/dpio-workspace/internal/pkg/infra/server"
 
 	"github.com/jmoiron/sqlx"
-	_ "github.com/lib/pq" // init function.
+	"github.com/lib/pq"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.uber.org/zap"
@@ -20,25 +24,75 @@ import (
 
 const (
 	DefaultRetryPeriodInMilliseconds = 100 * time.Millisecond
+	DefaultPort                      = 5432
+	DefaultTimeZone                  = "utc"
 )
 
+// Supported database drivers.
+const (
+	DriverPostgres = "postgres"
+	DriverSQLite   = "sqlite3"
+)
+
+// Supported SSL modes of PostgreSQL connection.
+const (
+	SSLModeDisable    = "disable"
+	SSLModeRequire    = "require"
+	SSLModeVerifyCA   = "verify-ca"
+	SSLModeVerifyFull = "verify-full"
+)
+
+// maxApplicationNameLength is a PostgreSQL limit for application_name parameter (NAMEDATALEN - 1).
+const maxApplicationNameLength = 63
+
+// searchPathSchemaRegex describes a schema name which can be specified in search_path parameter.
+var searchPathSchemaRegex = regexp.MustCompile(`^([A-Za-z_][A-Za-z0-9_$]*|"\$user")$`)
+
 // Common errors for CRUD operations.
 var (
 	ErrorNotFound          = errors.New("entity not found")
 	ErrorInvalidIdentifier = errors.New("specified ID format is not valid")
 	ErrorAuthFail          = errors.New("authentication error")
 	ErrorForbidden         = errors.New("action is not allowed")
+	ErrorConflict          = errors.New("entity conflicts with existing data")
+	ErrorInvalidReference  = errors.New("entity reference constraint is violated")
+)
+
+// PostgreSQL error codes translated into common errors.
+const (
+	pgUniqueViolation     = "23505"
+	pgForeignKeyViolation = "23503"
 )
 
 // DbConfig is used by PostgreSQL driver to build connection string and establish database connection.
+// If Driver is empty, DriverPostgres is used. DriverSQLite uses DatabaseName as a path to the database file and
+// ignores network and TLS settings.
+// If SSLMode is empty, "require" mode is used or "disable" mode if DisableTLS is set. Server certificate is verified
+// against SSLRootCertPath or system root certificates if the path is empty.
+// Host values can contain a port, otherwise Port or DefaultPort is used.
+// If MaxReplicaLag is zero, DefaultMaxReplicaLag is used.
 type DbConfig struct {
-	User               string
-	Password           string
-	Host               string
-	DatabaseName       string
-	MaxIdleConnections int32
-	MaxOpenConnections int32
-	DisableTLS         bool
+	Driver                string
+	User                  string
+	Password              string
+	Host                  string
+	Port                  int32
+	ReplicaHosts          []string
+	MaxReplicaLag         time.Duration
+	DatabaseName          string
+	MaxIdleConnections    int32
+	MaxOpenConnections    int32
+	ConnectionMaxLifetime time.Duration
+	ConnectionMaxIdleTime time.Duration
+	DisableTLS            bool
+	SSLMode               string
+	SSLRootCertPath       string
+	SSLCertPath           string
+	SSLKeyPath            string
+	StatementTimeout      time.Duration
+	ApplicationName       string
+	SearchPath            []string
+	SlowQueryThreshold    time.Duration
 }
 
 // Open use DbConfig settings to open database connection.
@@ -47,35 +101,132 @@ func Open(config DbConfig) (*sqlx.DB, error) {
 		return nil, err
 	}
 
-	sslMode := "require"
+	SetSlowQueryThreshold(config.SlowQueryThreshold)
 
-	if config.DisableTLS {
-		sslMode = "disable"
+	if config.Driver == DriverSQLite {
+		return openSQLite(config)
+	}
+
+	return openHost(config, config.Host, PoolPrimary)
+}
+
+// OpenCluster use DbConfig settings to open connections to the primary database and all read replicas.
+// Health of the replicas is monitored in background until the returned Cluster is closed.
+func OpenCluster(config DbConfig, logger *zap.SugaredLogger) (*Cluster, error) {
+	primary, err := Open(config)
+	if err != nil {
+		return nil, err
+	}
+
+	replicas := make([]*sqlx.DB, 0, len(config.ReplicaHosts))
+	for index, host := range config.ReplicaHosts {
+		replica, err := openHost(config, host, replicaPoolName(index))
+		if err != nil {
+			for _, opened := range append(replicas, primary) {
+				_ = Close(opened)
+			}
+			return nil, fmt.Errorf("failed to open read replica -> host={%q}: %w", host, err)
+		}
+		replicas = append(replicas, replica)
 	}
 
-	connectionParameters := make(url.Values)
-	connectionParameters.Set("sslmode", sslMode)
-	connectionParameters.Set("timezone", "utc")
+	cluster := NewCluster(primary, replicas...)
+	if config.MaxReplicaLag > 0 {
+		cluster.SetMaxReplicaLag(config.MaxReplicaLag)
+	}
+	cluster.StartMonitor(logger)
+
+	return cluster, nil
+}
+
+// Close stops export of pool metrics of connection opened by Open and closes the connection.
+func Close(connection *sqlx.DB) error {
+	UnregisterPoolMetrics(connection)
+
+	return connection.Close()
+}
 
+// openHost opens database connection to a specific host with DbConfig settings.
+// Statistics of the connection pool are exported under poolName label.
+func openHost(config DbConfig, host string, poolName string) (*sqlx.DB, error) {
 	connectionString := url.URL{
 		Scheme:   "postgres",
 		User:     url.UserPassword(config.User, config.Password),
-		Host:     config.Host,
+		Host:     hostWithPort(host, config.Port),
 		Path:     config.DatabaseName,
-		RawQuery: connectionParameters.Encode(),
+		RawQuery: connectionParameters(config).Encode(),
 	}
 
-	dbConnection, err := sqlx.Open("postgres", connectionString.String())
+	dbConnection, err := sqlx.Open(DriverPostgres, connectionString.String())
 	if err != nil {
 		return nil, err
 	}
 
 	dbConnection.SetMaxIdleConns(int(config.MaxIdleConnections))
 	dbConnection.SetMaxOpenConns(int(config.MaxOpenConnections))
+	dbConnection.SetConnMaxLifetime(config.ConnectionMaxLifetime)
+	dbConnection.SetConnMaxIdleTime(config.ConnectionMaxIdleTime)
+
+	if err := RegisterPoolMetrics(dbConnection, poolName); err != nil {
+		_ = dbConnection.Close()
+		return nil, fmt.Errorf("failed to register connection pool metrics: %w", err)
+	}
 
 	return dbConnection, nil
 }
 
+// connectionParameters builds the query part of connection string from DbConfig settings.
+func connectionParameters(config DbConfig) url.Values {
+	parameters := make(url.Values)
+	parameters.Set("sslmode", sslModeOf(config))
+	parameters.Set("timezone", DefaultTimeZone)
+
+	if config.SSLRootCertPath != "" {
+		parameters.Set("sslrootcert", config.SSLRootCertPath)
+	}
+	if config.SSLCertPath != "" {
+		parameters.Set("sslcert", config.SSLCertPath)
+		parameters.Set("sslkey", config.SSLKeyPath)
+	}
+	if config.StatementTimeout > 0 {
+		parameters.Set("statement_timeout", strconv.FormatInt(config.StatementTimeout.Milliseconds(), 10))
+	}
+	if config.ApplicationName != "" {
+		parameters.Set("application_name", config.ApplicationName)
+	}
+	if len(config.SearchPath) > 0 {
+		parameters.Set("search_path", strings.Join(config.SearchPath, ","))
+	}
+
+	return parameters
+}
+
+// sslModeOf returns SSL mode of connection with respect to the legacy DisableTLS flag.
+func sslModeOf(config DbConfig) string {
+	if config.SSLMode != "" {
+		return config.SSLMode
+	}
+
+	if config.DisableTLS {
+		return SSLModeDisable
+	}
+
+	return SSLModeRequire
+}
+
+// hostWithPort appends port to the host if the host does not contain it yet.
+func hostWithPort(host string, port int32) string {
+	if _, _, err := net.SplitHostPort(host); err == nil {
+		return host
+	}
+
+	if port == 0 {
+		port = DefaultPort
+	}
+
+	return net.JoinHostPort(host, strconv.Itoa(int(port)))
+}
+
 // StatusCheck returns error if issues exist with database connection.
 func StatusCheck(ctx context.Context, connection *sqlx.DB) error {
 	var connectivityError error
@@ -100,78 +251,142 @@ func StatusCheck(ctx context.Context, connection *sqlx.DB) error {
 	return connection.QueryRowContext(ctx, pingQuery).Scan(&output)
 }
 
+// WithTransaction executes operation in a transaction of the connection.
+// The transaction is committed if operation succeeds, otherwise it is rolled back and the operation error is returned.
+func WithTransaction(ctx context.Context, connection *sqlx.DB, operation func(transaction *sqlx.Tx) error) error {
+	transaction, err := connection.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	if err := operation(transaction); err != nil {
+		_ = transaction.Rollback()
+		return err
+	}
+
+	return translateError(transaction.Commit())
+}
+
 // NamedExecContext is a helper to execute a CRUD operation under logging and tracing features.
-func NamedExecContext(ctx context.Context, logger *zap.SugaredLogger, connection *sqlx.DB, sqlQuery string,
+// The connection can be a database or a transaction.
+func NamedExecContext(ctx context.Context, logger *zap.SugaredLogger, connection sqlx.ExtContext, sqlQuery string,
 	params interface{}) error {
 	query, err := queryString(sqlQuery, params)
 	if err != nil {
 		return err
 	}
-	logger.Infow("database.NameExecContext", "traceid", server.GetTraceID(ctx), "query", query)
+	queryName := queryNameOf(1)
+	logger.Infow("database.NameExecContext", "traceid", server.GetTraceID(ctx), "name", queryName, "query", query)
 
 	ctx, span := otel.GetTracerProvider().Tracer("").Start(ctx, "database.query")
-	span.SetAttributes(attribute.String("query", query))
+	span.SetAttributes(attribute.String("query", query), labelQueryName.String(queryName))
 	defer span.End()
 
-	if _, err := connection.NamedExecContext(ctx, query, params); err != nil {
-		return err
-	}
+	started := time.Now()
+	_, err = sqlx.NamedExecContext(ctx, connection, sqlQuery, params)
+	observeQuery(ctx, logger, OperationNamedExecContext, queryName, query, started, err)
 
-	return nil
+	return translateError(err)
 }
 
 // NamedQueryStruct is a helper to execute queries that return a single structured value.
-func NamedQueryStruct(ctx context.Context, logger *zap.SugaredLogger, connection *sqlx.DB, sqlQuery string,
+func NamedQueryStruct(ctx context.Context, logger *zap.SugaredLogger, connection sqlx.ExtContext, sqlQuery string,
 	params interface{}, target interface{}) error {
 	query, err := queryString(sqlQuery, params)
 	if err != nil {
 		return err
 	}
-	logger.Infow("database.NamedQueryStruct", "traceid", server.GetTraceID(ctx), "query", query)
+	queryName := queryNameOf(1)
+	logger.Infow("database.NamedQueryStruct", "traceid", server.GetTraceID(ctx), "name", queryName, "query", query)
 
 	ctx, span := otel.GetTracerProvider().Tracer("").Start(ctx, "database.query")
-	span.SetAttributes(attribute.String("query", query))
+	span.SetAttributes(attribute.String("query", query), labelQueryName.String(queryName))
 	defer span.End()
 
-	rows, err := connection.NamedQueryContext(ctx, query, params)
-	if err != nil {
-		return err
-	}
-	if !rows.Next() {
-		return ErrorNotFound
+	started := time.Now()
+	err = queryStruct(ctx, connection, sqlQuery, params, target)
+	observeQuery(ctx, logger, OperationNamedQueryStruct, queryName, query, started, err)
+
+	return err
+}
+
+// NamedQuerySlice is a helper to execute queries that return a collection of data.
+func NamedQuerySlice(ctx context.Context, logger *zap.SugaredLogger, connection sqlx.ExtContext, sqlQuery string,
+	params interface{}, target interface{}) error {
+	value := reflect.ValueOf(target)
+	if value.Kind() != reflect.Ptr || value.Elem().Kind() != reflect.Slice {
+		return errors.New("target object should be a pointer to a slice")
 	}
 
-	if err := rows.StructScan(target); err != nil {
+	query, err := queryString(sqlQuery, params)
+	if err != nil {
 		return err
 	}
+	queryName := queryNameOf(1)
+	logger.Infow("database.NamedQuerySlice", "traceid", server.GetTraceID(ctx), "name", queryName, "query", query)
 
-	return nil
+	ctx, span := otel.GetTracerProvider().Tracer("").Start(ctx, "database.query")
+	span.SetAttributes(attribute.String("query", query), labelQueryName.String(queryName))
+	defer span.End()
+
+	started := time.Now()
+	err = querySlice(ctx, connection, sqlQuery, params, value.Elem())
+	observeQuery(ctx, logger, OperationNamedQuerySlice, queryName, query, started, err)
+
+	return err
 }
 
-// NamedQuerySlice is a helper to execute queries that return a collection of data.
-func NamedQuerySlice(ctx context.Context, logger *zap.SugaredLogger, connection *sqlx.DB, sqlQuery string,
-	params interface{}, target interface{}) error {
+// NamedQueryEach is a helper to execute queries whose rows are processed one by one without loading the whole
+// collection. Every row is scanned into target before handle is called, error of handle stops the iteration and is
+// returned as is.
+func NamedQueryEach(ctx context.Context, logger *zap.SugaredLogger, connection sqlx.ExtContext, sqlQuery string,
+	params interface{}, target interface{}, handle func() error) error {
 	query, err := queryString(sqlQuery, params)
 	if err != nil {
 		return err
 	}
-	logger.Infow("database.NamedQuerySlice", "traceid", server.GetTraceID(ctx), "query", query)
+	queryName := queryNameOf(1)
+	logger.Infow("database.NamedQueryEach", "traceid", server.GetTraceID(ctx), "name", queryName, "query", query)
 
 	ctx, span := otel.GetTracerProvider().Tracer("").Start(ctx, "database.query")
-	span.SetAttributes(attribute.String("query", query))
+	span.SetAttributes(attribute.String("query", query), labelQueryName.String(queryName))
 	defer span.End()
 
-	value := reflect.ValueOf(target)
-	if value.Kind() != reflect.Ptr || value.Elem().Kind() != reflect.Slice {
-		return errors.New("target object should be a pointer to a slice")
+	started := time.Now()
+	err = queryEach(ctx, connection, sqlQuery, params, target, handle)
+	observeQuery(ctx, logger, OperationNamedQueryEach, queryName, query, started, err)
+
+	return err
+}
+
+// queryStruct executes the query and scans the first returned row into target.
+func queryStruct(ctx context.Context, connection sqlx.ExtContext, sqlQuery string, params interface{},
+	target interface{}) error {
+	rows, err := sqlx.NamedQueryContext(ctx, connection, sqlQuery, params)
+	if err != nil {
+		return err
 	}
+	defer rows.Close()
 
-	rows, err := connection.NamedQueryContext(ctx, query, params)
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return err
+		}
+		return ErrorNotFound
+	}
+
+	return rows.StructScan(target)
+}
+
+// querySlice executes the query and appends all returned rows to sliceRef.
+func querySlice(ctx context.Context, connection sqlx.ExtContext, sqlQuery string, params interface{},
+	sliceRef reflect.Value) error {
+	rows, err := sqlx.NamedQueryContext(ctx, connection, sqlQuery, params)
 	if err != nil {
 		return err
 	}
+	defer rows.Close()
 
-	sliceRef := value.Elem()
 	for rows.Next() {
 		sliceElement := reflect.New(sliceRef.Type().Elem())
 		if err := rows.StructScan(sliceElement.Interface()); err != nil {
@@ -180,7 +395,46 @@ func NamedQuerySlice(ctx context.Context, logger *zap.SugaredLogger, connection
 		sliceRef.Set(reflect.Append(sliceRef, sliceElement.Elem()))
 	}
 
-	return nil
+	return rows.Err()
+}
+
+// queryEach executes the query and calls handle after scan of every returned row into target.
+func queryEach(ctx context.Context, connection sqlx.ExtContext, sqlQuery string, params interface{},
+	target interface{}, handle func() error) error {
+	rows, err := sqlx.NamedQueryContext(ctx, connection, sqlQuery, params)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		if err := rows.StructScan(target); err != nil {
+			return err
+		}
+		if err := handle(); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+// translateError converts constraint violations reported by PostgreSQL or SQLite into common errors.
+// Original driver message is kept in the error chain.
+func translateError(err error) error {
+	var pgError *pq.Error
+	if !errors.As(err, &pgError) {
+		return translateSQLiteError(err)
+	}
+
+	switch pgError.Code {
+	case pgUniqueViolation:
+		return fmt.Errorf("%w: %s", ErrorConflict, pgError.Message)
+	case pgForeignKeyViolation:
+		return fmt.Errorf("%w: %s", ErrorInvalidReference, pgError.Message)
+	}
+
+	return err
 }
 
 // queryString formats SQL query and set specified arguments.
@@ -213,6 +467,14 @@ func queryString(sqlQuery string, args ...interface{}) (string, error) {
 }
 
 func validateDbConfig(config DbConfig) error {
+	switch config.Driver {
+	case "", DriverPostgres:
+	case DriverSQLite:
+		return validateSQLiteConfig(config)
+	default:
+		return fmt.Errorf("database driver %q is not supported in connection settings", config.Driver)
+	}
+
 	if len(config.User) <= 0 {
 		return errors.New("username is not specified in connection settings")
 	}
@@ -225,9 +487,93 @@ func validateDbConfig(config DbConfig) error {
 		return errors.New("database host is not specified in connection settings")
 	}
 
+	if config.Port < 0 || config.Port > 65535 {
+		return fmt.Errorf("database port %d is out of range in connection settings", config.Port)
+	}
+
+	for _, host := range config.ReplicaHosts {
+		if len(host) <= 0 {
+			return errors.New("read replica host is empty in connection settings")
+		}
+
+		if host == config.Host {
+			return errors.New("read replica host should differ from primary database host")
+		}
+	}
+
 	if len(config.DatabaseName) <= 0 {
 		return errors.New("database name is not specified in connection settings")
 	}
 
+	if config.MaxReplicaLag < 0 {
+		return errors.New("max replica lag cannot be negative in connection settings")
+	}
+
+	if config.ConnectionMaxLifetime < 0 {
+		return errors.New("connection max lifetime cannot be negative in connection settings")
+	}
+
+	if config.ConnectionMaxIdleTime < 0 {
+		return errors.New("connection max idle time cannot be negative in connection settings")
+	}
+
+	if config.StatementTimeout < 0 {
+		return errors.New("statement timeout cannot be negative in connection settings")
+	}
+
+	if err := validateSSLConfig(config); err != nil {
+		return err
+	}
+
+	if len(config.ApplicationName) > maxApplicationNameLength {
+		return fmt.Errorf("application name is longer than %d characters in connection settings",
+			maxApplicationNameLength)
+	}
+
+	for _, schema := range config.SearchPath {
+		if !searchPathSchemaRegex.MatchString(schema) {
+			return fmt.Errorf("search path schema %q is not valid in connection settings", schema)
+		}
+	}
+
+	return nil
+}
+
+// validateSSLConfig checks SSL mode and certificate settings.
+func validateSSLConfig(config DbConfig) error {
+	sslMode := sslModeOf(config)
+
+	switch sslMode {
+	case SSLModeDisable, SSLModeRequire, SSLModeVerifyCA, SSLModeVerifyFull:
+	default:
+		return fmt.Errorf("ssl mode %q is not supported in connection settings", sslMode)
+	}
+
+	if config.DisableTLS && sslMode != SSLModeDisable {
+		return fmt.Errorf("ssl mode %q conflicts with disabled TLS in connection settings", sslMode)
+	}
+
+	if sslMode == SSLModeDisable {
+		if config.SSLRootCertPath != "" || config.SSLCertPath != "" || config.SSLKeyPath != "" {
+			return errors.New("certificates cannot be specified when TLS is disabled in connection settings")
+		}
+
+		return nil
+	}
+
+	if (config.SSLCertPath == "") != (config.SSLKeyPath == "") {
+		return errors.New("client certificate and key should be specified together in connection settings")
+	}
+
+	for _, path := range []string{config.SSLRootCertPath, config.SSLCertPath, config.SSLKeyPath} {
+		if path == "" {
+			continue
+		}
+
+		if _, err := os.Stat(path); err != nil {
+			return fmt.Errorf("certificate file is not available in connection settings: %w", err)
+		}
+	}
+
 	return nil
 }
@@ -0,0 +1,142 @@
+package database
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestValidateDbConfig(t *testing.T) {
+	certPath := filepath.Join(t.TempDir(), "client.crt")
+	if err := os.WriteFile(certPath, []byte("certificate"), 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	missingPath := filepath.Join(t.TempDir(), "missing.crt")
+
+	valid := DbConfig{User: "postgres", Password: "secret", Host: "db", DatabaseName: "workspace"}
+	with := func(change func(config *DbConfig)) DbConfig {
+		config := valid
+		change(&config)
+		return config
+	}
+
+	tests := []struct {
+		name    string
+		config  DbConfig
+		wantErr string
+	}{
+		{name: "minimal settings", config: valid},
+		{name: "unknown driver", config: with(func(c *DbConfig) { c.Driver = "mysql" }), wantErr: "not supported"},
+		{name: "missing user", config: with(func(c *DbConfig) { c.User = "" }), wantErr: "username"},
+		{name: "missing password", config: with(func(c *DbConfig) { c.Password = "" }), wantErr: "password"},
+		{name: "missing host", config: with(func(c *DbConfig) { c.Host = "" }), wantErr: "host"},
+		{name: "port out of range", config: with(func(c *DbConfig) { c.Port = 65536 }), wantErr: "port"},
+		{name: "replica of the primary host", config: with(func(c *DbConfig) { c.ReplicaHosts = []string{"db"} }),
+			wantErr: "replica"},
+		{name: "negative replica lag", config: with(func(c *DbConfig) { c.MaxReplicaLag = -time.Second }),
+			wantErr: "lag"},
+		{name: "negative statement timeout", config: with(func(c *DbConfig) { c.StatementTimeout = -1 }),
+			wantErr: "statement timeout"},
+		{name: "unknown ssl mode", config: with(func(c *DbConfig) { c.SSLMode = "prefer" }), wantErr: "ssl mode"},
+		{name: "ssl mode with disabled TLS",
+			config: with(func(c *DbConfig) { c.DisableTLS, c.SSLMode = true, SSLModeRequire }), wantErr: "conflicts"},
+		{name: "certificate with disabled TLS",
+			config:  with(func(c *DbConfig) { c.DisableTLS, c.SSLRootCertPath = true, certPath }),
+			wantErr: "TLS is disabled"},
+		{name: "full verification with system roots",
+			config: with(func(c *DbConfig) { c.SSLMode = SSLModeVerifyFull })},
+		{name: "full verification with CA certificate",
+			config: with(func(c *DbConfig) { c.SSLMode, c.SSLRootCertPath = SSLModeVerifyFull, certPath })},
+		{name: "missing CA certificate file",
+			config:  with(func(c *DbConfig) { c.SSLMode, c.SSLRootCertPath = SSLModeVerifyCA, missingPath }),
+			wantErr: "not available"},
+		{name: "client certificate without key", config: with(func(c *DbConfig) { c.SSLCertPath = certPath }),
+			wantErr: "together"},
+		{name: "client certificate with key",
+			config: with(func(c *DbConfig) { c.SSLCertPath, c.SSLKeyPath = certPath, certPath })},
+		{name: "long application name",
+			config:  with(func(c *DbConfig) { c.ApplicationName = strings.Repeat("a", maxApplicationNameLength+1) }),
+			wantErr: "application name"},
+		{name: "valid search path",
+			config: with(func(c *DbConfig) { c.SearchPath = []string{"workspace", `"$user"`, "public"} })},
+		{name: "invalid search path", config: with(func(c *DbConfig) { c.SearchPath = []string{"public; DROP"} }),
+			wantErr: "search path"},
+		{name: "SQLite file", config: DbConfig{Driver: DriverSQLite, DatabaseName: "workspace.db"}},
+		{name: "SQLite without file", config: DbConfig{Driver: DriverSQLite}, wantErr: "file path"},
+		{name: "SQLite with replicas",
+			config:  DbConfig{Driver: DriverSQLite, DatabaseName: "workspace.db", ReplicaHosts: []string{"db"}},
+			wantErr: "not supported"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := validateDbConfig(test.config)
+			switch {
+			case test.wantErr == "" && err != nil:
+				t.Errorf("validateDbConfig() error = %v", err)
+			case test.wantErr != "" && (err == nil || !strings.Contains(err.Error(), test.wantErr)):
+				t.Errorf("validateDbConfig() error = %v, want error with %q", err, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestConnectionParameters(t *testing.T) {
+	tests := []struct {
+		name   string
+		config DbConfig
+		want   map[string]string
+	}{
+		{
+			name:   "defaults",
+			config: DbConfig{},
+			want:   map[string]string{"sslmode": SSLModeRequire, "timezone": DefaultTimeZone},
+		},
+		{
+			name:   "disabled TLS",
+			config: DbConfig{DisableTLS: true},
+			want:   map[string]string{"sslmode": SSLModeDisable},
+		},
+		{
+			name: "all settings",
+			config: DbConfig{SSLMode: SSLModeVerifyFull, SSLRootCertPath: "ca.crt", SSLCertPath: "client.crt",
+				SSLKeyPath: "client.key", StatementTimeout: 1500 * time.Millisecond, ApplicationName: "admin",
+				SearchPath: []string{"workspace", "public"}},
+			want: map[string]string{"sslmode": SSLModeVerifyFull, "sslrootcert": "ca.crt", "sslcert": "client.crt",
+				"sslkey": "client.key", "statement_timeout": "1500", "application_name": "admin",
+				"search_path": "workspace,public"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			parameters := connectionParameters(test.config)
+			for name, want := range test.want {
+				if got := parameters.Get(name); got != want {
+					t.Errorf("parameter %s = %q, want %q", name, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestHostWithPort(t *testing.T) {
+	tests := []struct {
+		host string
+		port int32
+		want string
+	}{
+		{host: "db", want: "db:5432"},
+		{host: "db", port: 6432, want: "db:6432"},
+		{host: "db:7432", port: 6432, want: "db:7432"},
+		{host: "::1", port: 6432, want: "[::1]:6432"},
+	}
+
+	for _, test := range tests {
+		if got := hostWithPort(test.host, test.port); got != test.want {
+			t.Errorf("hostWithPort(%q, %d) = %q, want %q", test.host, test.port, got, test.want)
+		}
+	}
+}
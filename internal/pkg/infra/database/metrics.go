@@ -0,0 +1,272 @@
+package database
+
+import (
+	"context"
+	"reflect"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+	"unicode"
+
+	"github.com/SKorolchuk/dpio-workspace/internal/pkg/infra/server"
+
+	"github.com/jmoiron/sqlx"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/global"
+	"go.opentelemetry.io/otel/unit"
+	"go.uber.org/zap"
+)
+
+// Names of query helpers used as operation label of database metrics.
+const (
+	OperationNamedExecContext = "NamedExecContext"
+	OperationNamedQueryStruct = "NamedQueryStruct"
+	OperationNamedQuerySlice  = "NamedQuerySlice"
+	OperationNamedQueryEach   = "NamedQueryEach"
+)
+
+// Label keys attached to database metrics.
+const (
+	labelOperation = attribute.Key("db.operation")
+	labelQueryName = attribute.Key("db.query.name")
+	labelSuccess   = attribute.Key("db.success")
+	labelPool      = attribute.Key("db.pool")
+)
+
+// unknownQueryName is used when a query helper caller cannot be resolved.
+const unknownQueryName = "unknown"
+
+// meter is a source of all instruments declared by database package.
+// Global provider delegates to the SDK as soon as it is configured, so instruments can be created on package init.
+var meter = global.Meter("github.com/SKorolchuk/dpio-workspace/internal/pkg/infra/database")
+
+// queryDuration is a histogram of query execution time labelled by operation and query name.
+var queryDuration = metric.Must(meter).NewFloat64ValueRecorder(
+	"db.query.duration",
+	metric.WithDescription("Duration of database queries executed by query helpers."),
+	metric.WithUnit(unit.Milliseconds),
+)
+
+// slowQueryThreshold contains the minimal query duration in nanoseconds which is reported to the slow query log.
+// Zero value disables slow query logging.
+var slowQueryThreshold int64
+
+// SetSlowQueryThreshold changes the minimal duration of query to be reported in the slow query log.
+// Zero or negative value disables slow query logging.
+func SetSlowQueryThreshold(threshold time.Duration) {
+	if threshold < 0 {
+		threshold = 0
+	}
+	atomic.StoreInt64(&slowQueryThreshold, int64(threshold))
+}
+
+// observedPool is a connection pool whose statistics are exported as metrics under the pool name.
+type observedPool struct {
+	name       string
+	connection *sqlx.DB
+}
+
+// Registry of connection pools observed by pool metrics.
+// Instruments are registered once, since OpenTelemetry returns the first instruments for repeated names and drops
+// callbacks of later registrations.
+var (
+	poolsMutex         sync.Mutex
+	observedPools      []observedPool
+	poolInstruments    sync.Once
+	poolInstrumentsErr error
+)
+
+// Names of connection pools used as pool label of pool metrics.
+const (
+	PoolPrimary       = "primary"
+	poolReplicaPrefix = "replica-"
+)
+
+// replicaPoolName returns the pool label of read replica with the index in DbConfig.ReplicaHosts.
+func replicaPoolName(index int) string {
+	return poolReplicaPrefix + strconv.Itoa(index+1)
+}
+
+// RegisterPoolMetrics exports connection pool statistics of connection as OpenTelemetry metrics.
+// The poolName value is used as a label to distinguish several pools, e.g. primary and replica.
+// The pool is observed until UnregisterPoolMetrics is called for the connection.
+func RegisterPoolMetrics(connection *sqlx.DB, poolName string) error {
+	poolInstruments.Do(func() {
+		poolInstrumentsErr = registerPoolInstruments()
+	})
+	if poolInstrumentsErr != nil {
+		return poolInstrumentsErr
+	}
+
+	poolsMutex.Lock()
+	defer poolsMutex.Unlock()
+
+	observedPools = append(observedPools, observedPool{name: poolName, connection: connection})
+
+	return nil
+}
+
+// UnregisterPoolMetrics stops export of connection pool statistics of connection.
+// Connections which are not observed are ignored.
+func UnregisterPoolMetrics(connection *sqlx.DB) {
+	poolsMutex.Lock()
+	defer poolsMutex.Unlock()
+
+	pools := observedPools[:0]
+	for _, pool := range observedPools {
+		if pool.connection != connection {
+			pools = append(pools, pool)
+		}
+	}
+	for index := len(pools); index < len(observedPools); index++ {
+		observedPools[index] = observedPool{}
+	}
+	observedPools = pools
+}
+
+// registerPoolInstruments creates instruments of connection pool statistics with a single callback which observes
+// all registered pools.
+func registerPoolInstruments() error {
+	var (
+		openConnections metric.Int64ValueObserver
+		inUse           metric.Int64ValueObserver
+		idle            metric.Int64ValueObserver
+		waitCount       metric.Int64SumObserver
+		waitDuration    metric.Float64SumObserver
+	)
+
+	batch := meter.NewBatchObserver(func(_ context.Context, result metric.BatchObserverResult) {
+		poolsMutex.Lock()
+		pools := make([]observedPool, len(observedPools))
+		copy(pools, observedPools)
+		poolsMutex.Unlock()
+
+		for _, pool := range pools {
+			stats := pool.connection.Stats()
+			result.Observe(
+				[]attribute.KeyValue{labelPool.String(pool.name)},
+				openConnections.Observation(int64(stats.OpenConnections)),
+				inUse.Observation(int64(stats.InUse)),
+				idle.Observation(int64(stats.Idle)),
+				waitCount.Observation(stats.WaitCount),
+				waitDuration.Observation(float64(stats.WaitDuration)/float64(time.Millisecond)),
+			)
+		}
+	})
+
+	var err error
+	if openConnections, err = batch.NewInt64ValueObserver("db.pool.connections.open",
+		metric.WithDescription("Number of established connections both in use and idle.")); err != nil {
+		return err
+	}
+	if inUse, err = batch.NewInt64ValueObserver("db.pool.connections.in_use",
+		metric.WithDescription("Number of connections currently in use.")); err != nil {
+		return err
+	}
+	if idle, err = batch.NewInt64ValueObserver("db.pool.connections.idle",
+		metric.WithDescription("Number of idle connections.")); err != nil {
+		return err
+	}
+	if waitCount, err = batch.NewInt64SumObserver("db.pool.wait.count",
+		metric.WithDescription("Total number of connections waited for.")); err != nil {
+		return err
+	}
+	if waitDuration, err = batch.NewFloat64SumObserver("db.pool.wait.duration",
+		metric.WithDescription("Total time blocked waiting for a new connection."),
+		metric.WithUnit(unit.Milliseconds)); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// observeQuery records the duration of executed query and reports it to the slow query log if threshold is exceeded.
+func observeQuery(ctx context.Context, logger *zap.SugaredLogger, operation string, queryName string, query string,
+	started time.Time, err error) {
+	duration := time.Since(started)
+
+	queryDuration.Record(ctx, float64(duration)/float64(time.Millisecond),
+		labelOperation.String(operation),
+		labelQueryName.String(queryName),
+		labelSuccess.Bool(err == nil || err == ErrorNotFound),
+	)
+
+	threshold := time.Duration(atomic.LoadInt64(&slowQueryThreshold))
+	if threshold > 0 && duration >= threshold {
+		logger.Warnw("database.SlowQuery", "traceid", server.GetTraceID(ctx), "operation", operation,
+			"name", queryName, "duration", duration, "threshold", threshold, "query", query)
+	}
+}
+
+// maxQueryNameDepth limits the number of stack frames inspected to resolve a query name.
+const maxQueryNameDepth = 32
+
+// packagePath is the import path of database package whose frames are skipped during query name resolution.
+var packagePath = reflect.TypeOf(Cursor{}).PkgPath()
+
+// queryNameOf returns a stable query name based on the method that called a query helper,
+// e.g. "workspace.Store.QueryWorkspaces".
+// The skip argument is the number of stack frames to ascend, with 0 identifying the caller of queryNameOf.
+func queryNameOf(skip int) string {
+	pcs := make([]uintptr, maxQueryNameDepth)
+	count := runtime.Callers(skip+2, pcs)
+	frames := runtime.CallersFrames(pcs[:count])
+
+	functions := make([]string, 0, count)
+	for {
+		frame, more := frames.Next()
+		functions = append(functions, frame.Function)
+		if !more {
+			break
+		}
+	}
+
+	return queryNameFrom(functions)
+}
+
+// queryNameFrom picks the query name from fully qualified function names of the call stack ordered from the callee.
+// Frames of database package, closures and unexported helpers are skipped, so queries of shared helpers and
+// transactions are named after the exported method which runs them. The nearest caller outside of database package
+// is used if there is no exported one.
+func queryNameFrom(functions []string) string {
+	fallback := ""
+
+	for _, function := range functions {
+		functionPackage, name := splitFunctionName(function)
+		if name == "" || functionPackage == packagePath {
+			continue
+		}
+
+		shortName := functionPackage[strings.LastIndex(functionPackage, "/")+1:] + "." + name
+		if fallback == "" {
+			fallback = shortName
+		}
+
+		method := name[strings.LastIndex(name, ".")+1:]
+		if method != "" && unicode.IsUpper([]rune(method)[0]) {
+			return shortName
+		}
+	}
+
+	if fallback == "" {
+		return unknownQueryName
+	}
+
+	return fallback
+}
+
+// splitFunctionName splits fully qualified function name into the import path of its package and the name inside
+// of the package, e.g. "workspace.Store.QueryWorkspaces" is split into ".../workspace" and "Store.QueryWorkspaces".
+func splitFunctionName(function string) (string, string) {
+	start := strings.LastIndex(function, "/") + 1
+	index := strings.Index(function[start:], ".")
+	if index < 0 {
+		return function, ""
+	}
+
+	return function[:start+index], function[start+index+1:]
+}
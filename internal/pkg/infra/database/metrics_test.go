@@ -0,0 +1,112 @@
+package database
+
+import (
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+)
+
+func TestQueryNameFrom(t *testing.T) {
+	const module = "github.com/SKorolchuk/dpio-workspace/internal/pkg/"
+
+	tests := []struct {
+		name      string
+		functions []string
+		want      string
+	}{
+		{
+			name:      "store method",
+			functions: []string{packagePath + ".NamedQuerySlice", module + "store/workspace.Store.QueryWorkspaces"},
+			want:      "workspace.Store.QueryWorkspaces",
+		},
+		{
+			name: "unexported helper of store method",
+			functions: []string{packagePath + ".NamedQueryStruct", module + "store/workspace.Store.queryWorkspace",
+				module + "store/workspace.Store.queryWorkspaceByID", module + "store/workspace.Store.UpdateWorkspace"},
+			want: "workspace.Store.UpdateWorkspace",
+		},
+		{
+			name: "transaction closure",
+			functions: []string{packagePath + ".NamedExecContext",
+				module + "store/workspace.Store.CloneWorkspace.func1", packagePath + ".WithTransaction",
+				module + "store/workspace.Store.CloneWorkspace"},
+			want: "workspace.Store.CloneWorkspace",
+		},
+		{
+			name:      "pointer receiver",
+			functions: []string{packagePath + ".NamedExecContext", module + "store/project.(*Store).DeleteGroup"},
+			want:      "project.(*Store).DeleteGroup",
+		},
+		{
+			name: "package function of another database package",
+			functions: []string{packagePath + ".NamedExecContext", module + "store/database.applyMigration",
+				module + "store/database.MigrateUp"},
+			want: "database.MigrateUp",
+		},
+		{
+			name:      "no exported caller",
+			functions: []string{packagePath + ".NamedExecContext", "main.run.func2", "main.run", "runtime.main"},
+			want:      "main.run.func2",
+		},
+		{
+			name:      "no caller outside of database package",
+			functions: []string{packagePath + ".NamedExecContext", ""},
+			want:      unknownQueryName,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := queryNameFrom(test.functions); got != test.want {
+				t.Errorf("queryNameFrom() = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestPoolMetricsRegistration(t *testing.T) {
+	isObserved := func(connection *sqlx.DB) (string, bool) {
+		poolsMutex.Lock()
+		defer poolsMutex.Unlock()
+
+		for _, pool := range observedPools {
+			if pool.connection == connection {
+				return pool.name, true
+			}
+		}
+
+		return "", false
+	}
+
+	primary, err := Open(DbConfig{Driver: DriverSQLite, DatabaseName: ":memory:"})
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	replica, err := sqlx.Open(DriverSQLite, ":memory:")
+	if err != nil {
+		t.Fatalf("sqlx.Open() error = %v", err)
+	}
+	if err := RegisterPoolMetrics(replica, replicaPoolName(0)); err != nil {
+		t.Fatalf("RegisterPoolMetrics() error = %v", err)
+	}
+
+	if name, found := isObserved(primary); !found || name != PoolPrimary {
+		t.Errorf("primary pool is observed = %t under %q, want %q", found, name, PoolPrimary)
+	}
+	if name, found := isObserved(replica); !found || name != "replica-1" {
+		t.Errorf("replica pool is observed = %t under %q, want %q", found, name, "replica-1")
+	}
+
+	cluster := NewCluster(primary, replica)
+	if err := cluster.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	for _, connection := range []*sqlx.DB{primary, replica} {
+		if name, found := isObserved(connection); found {
+			t.Errorf("pool %q is observed after Close()", name)
+		}
+	}
+
+	UnregisterPoolMetrics(primary)
+}
@@ -0,0 +1,13 @@
+package database
+
+import "strings"
+
+// likeEscaper escapes wildcard characters of LIKE patterns with a backslash.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// LikePattern converts text into lower case LIKE pattern body which matches the text literally.
+// Queries should compare it with lower case values and declare a backslash as the escape character:
+// LOWER(name) LIKE :pattern ESCAPE '\'.
+func LikePattern(text string) string {
+	return likeEscaper.Replace(strings.ToLower(text))
+}
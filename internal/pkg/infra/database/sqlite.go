@@ -0,0 +1,75 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/mattn/go-sqlite3"
+)
+
+// DefaultSQLiteBusyTimeout is a time in milliseconds a connection waits for a lock held by other writer.
+const DefaultSQLiteBusyTimeout = 5000
+
+// openSQLite opens embedded SQLite database file with DbConfig settings.
+// Foreign keys are enforced and parsed timestamps are returned in UTC to match PostgreSQL connections.
+func openSQLite(config DbConfig) (*sqlx.DB, error) {
+	connectionParameters := make(url.Values)
+	connectionParameters.Set("_foreign_keys", "on")
+	connectionParameters.Set("_journal_mode", "WAL")
+	connectionParameters.Set("_busy_timeout", fmt.Sprint(DefaultSQLiteBusyTimeout))
+	connectionParameters.Set("_loc", "UTC")
+
+	connectionString := fmt.Sprintf("file:%s?%s", config.DatabaseName, connectionParameters.Encode())
+
+	dbConnection, err := sqlx.Open(DriverSQLite, connectionString)
+	if err != nil {
+		return nil, err
+	}
+
+	dbConnection.SetMaxIdleConns(int(config.MaxIdleConnections))
+	dbConnection.SetMaxOpenConns(int(config.MaxOpenConnections))
+	dbConnection.SetConnMaxLifetime(config.ConnectionMaxLifetime)
+	dbConnection.SetConnMaxIdleTime(config.ConnectionMaxIdleTime)
+
+	if err := RegisterPoolMetrics(dbConnection, PoolPrimary); err != nil {
+		_ = dbConnection.Close()
+		return nil, fmt.Errorf("failed to register connection pool metrics: %w", err)
+	}
+
+	return dbConnection, nil
+}
+
+// translateSQLiteError converts constraint violations reported by SQLite into common errors.
+func translateSQLiteError(err error) error {
+	var sqliteError sqlite3.Error
+	if !errors.As(err, &sqliteError) {
+		return err
+	}
+
+	switch sqliteError.ExtendedCode {
+	case sqlite3.ErrConstraintUnique, sqlite3.ErrConstraintPrimaryKey:
+		return fmt.Errorf("%w: %s", ErrorConflict, sqliteError.Error())
+	case sqlite3.ErrConstraintForeignKey:
+		return fmt.Errorf("%w: %s", ErrorInvalidReference, sqliteError.Error())
+	}
+
+	return err
+}
+
+func validateSQLiteConfig(config DbConfig) error {
+	if len(config.DatabaseName) <= 0 {
+		return errors.New("database file path is not specified in connection settings")
+	}
+
+	if len(config.ReplicaHosts) > 0 {
+		return errors.New("read replicas are not supported by SQLite driver")
+	}
+
+	if config.MaxIdleConnections < 0 || config.MaxOpenConnections < 0 {
+		return errors.New("connection limits cannot be negative in connection settings")
+	}
+
+	return nil
+}
@@ -0,0 +1,65 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/SKorolchuk/dpio-workspace/internal/pkg/infra/uuid"
+
+	"github.com/dimfeld/httptreemux/v5"
+)
+
+// Handler represents a function which handles HTTP request inside the App.
+type Handler func(ctx context.Context, w http.ResponseWriter, r *http.Request) error
+
+// Middleware represents a function which wraps Handler with additional logic.
+type Middleware func(handler Handler) Handler
+
+// App is an entrypoint of HTTP API, it routes requests to handlers wrapped with middlewares.
+type App struct {
+	mux         *httptreemux.ContextMux
+	middlewares []Middleware
+}
+
+// NewApp creates an instance of App with middlewares applied to every handler.
+func NewApp(middlewares ...Middleware) *App {
+	return &App{
+		mux:         httptreemux.NewContextMux(),
+		middlewares: middlewares,
+	}
+}
+
+// ServeHTTP implements http.Handler interface.
+func (app *App) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	app.mux.ServeHTTP(w, r)
+}
+
+// Handle binds handler to the HTTP method and path of API version, e.g. "/v1/groups/:group_id".
+// Handler specific middlewares are applied inside of App middlewares.
+func (app *App) Handle(method string, version string, path string, handler Handler, middlewares ...Middleware) {
+	handler = wrapMiddlewares(handler, middlewares)
+	handler = wrapMiddlewares(handler, app.middlewares)
+
+	app.mux.Handle(method, "/"+version+path, func(w http.ResponseWriter, r *http.Request) {
+		info := RequestInfo{
+			TraceID: uuid.Generate(),
+			Now:     time.Now().UTC(),
+		}
+		ctx := context.WithValue(r.Context(), key, &info)
+
+		// Errors should be handled by middlewares, so nothing can be done here.
+		_ = handler(ctx, w, r.WithContext(ctx))
+	})
+}
+
+// wrapMiddlewares wraps handler with middlewares, the first middleware is executed first.
+func wrapMiddlewares(handler Handler, middlewares []Middleware) Handler {
+	for index := len(middlewares) - 1; index >= 0; index-- {
+		if middlewares[index] != nil {
+			handler = middlewares[index](handler)
+		}
+	}
+
+	return handler
+}
@@ -0,0 +1,100 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/SKorolchuk/dpio-workspace/internal/pkg/infra/auth"
+	"github.com/SKorolchuk/dpio-workspace/internal/pkg/infra/validation"
+
+	"go.uber.org/zap"
+)
+
+// Logger writes information about every request and its completion.
+func Logger(logger *zap.SugaredLogger) Middleware {
+	return func(handler Handler) Handler {
+		return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+			info, err := GetRequestInfo(ctx)
+			if err != nil {
+				return err
+			}
+
+			logger.Infow("server.RequestStarted", "traceid", info.TraceID, "method", r.Method,
+				"path", r.URL.Path, "remoteaddr", r.RemoteAddr)
+
+			err = handler(ctx, w, r)
+
+			logger.Infow("server.RequestCompleted", "traceid", info.TraceID, "method", r.Method,
+				"path", r.URL.Path, "statuscode", info.StatusCode, "duration", time.Since(info.Now))
+
+			return err
+		}
+	}
+}
+
+// Errors converts handler errors into ResponseError responses.
+// RequestError defines the status code, validation errors are reported with 400 status and the others with 500.
+func Errors(logger *zap.SugaredLogger) Middleware {
+	return func(handler Handler) Handler {
+		return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+			err := handler(ctx, w, r)
+			if err == nil {
+				return nil
+			}
+
+			logger.Errorw("server.RequestError", "traceid", GetTraceID(ctx), "error", err)
+
+			var fieldErrors validation.FieldErrors
+			var requestError *validation.RequestError
+			var response validation.ResponseError
+			var statusCode int
+
+			switch {
+			case errors.As(err, &fieldErrors):
+				response = validation.ResponseError{
+					Error:            "data validation error",
+					FieldsValidation: fieldErrors.Error(),
+				}
+				statusCode = http.StatusBadRequest
+			case errors.As(err, &requestError):
+				response = validation.ResponseError{Error: requestError.Error()}
+				if requestError.Fields != nil {
+					response.FieldsValidation = requestError.Fields.Error()
+				}
+				statusCode = int(requestError.Status)
+			default:
+				response = validation.ResponseError{Error: http.StatusText(http.StatusInternalServerError)}
+				statusCode = http.StatusInternalServerError
+			}
+
+			return Respond(ctx, w, response, statusCode)
+		}
+	}
+}
+
+// Authenticate reads user Claims from the bearer token of Authorization header and saves them in the context.
+// Requests without a valid user session token are rejected with 401 status, tokens issued for other purposes such as
+// project invitations are not accepted.
+func Authenticate(authContext *auth.AuthenticationContext) Middleware {
+	return func(handler Handler) Handler {
+		return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+			const prefix = "bearer "
+
+			header := r.Header.Get("Authorization")
+			if len(header) <= len(prefix) || !strings.EqualFold(header[:len(prefix)], prefix) {
+				return validation.NewRequestError(errors.New("expected authorization header format: bearer <token>"),
+					http.StatusUnauthorized)
+			}
+
+			claims, err := authContext.ReadClaimsFromToken(header[len(prefix):])
+			if err != nil {
+				return validation.NewRequestError(err, http.StatusUnauthorized)
+			}
+
+			return handler(auth.SetClaims(ctx, claims), w, r)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/SKorolchuk/dpio-workspace/internal/pkg/infra/validation"
+
+	"github.com/dimfeld/httptreemux/v5"
+)
+
+// Respond writes data to the client in JSON format with specified status code.
+// Nothing except the status code is written if data equals to nil or status code is 204.
+func Respond(ctx context.Context, w http.ResponseWriter, data interface{}, statusCode int) error {
+	if err := SetStatusCode(ctx, statusCode); err != nil {
+		return err
+	}
+
+	if data == nil || statusCode == http.StatusNoContent {
+		w.WriteHeader(statusCode)
+		return nil
+	}
+
+	content, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	_, err = w.Write(content)
+
+	return err
+}
+
+// Decode reads JSON body of the request into target, unknown fields are rejected.
+// If error occurs, the method returns RequestError with 400 status.
+func Decode(r *http.Request, target interface{}) error {
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+
+	if err := decoder.Decode(target); err != nil {
+		return validation.NewRequestError(fmt.Errorf("unable to decode request body: %w", err),
+			http.StatusBadRequest)
+	}
+
+	return nil
+}
+
+// Param returns value of the path parameter, e.g. "group_id" of "/v1/groups/:group_id".
+func Param(r *http.Request, name string) string {
+	return httptreemux.ContextParams(r.Context())[name]
+}
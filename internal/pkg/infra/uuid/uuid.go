@@ -1,6 +1,8 @@
 package uuid
 
 import (
+	"io"
+
 	"github.com/SKorolchuk/dpio-workspace/internal/pkg/infra/validation"
 
 	"github.com/google/uuid"
@@ -11,6 +13,16 @@ func Generate() string {
 	return uuid.NewString()
 }
 
+// GenerateFromReader forms new unique identifier in GUID format using random bytes of reader.
+// It allows to produce reproducible identifiers, e.g. for generated test data.
+func GenerateFromReader(reader io.Reader) (string, error) {
+	id, err := uuid.NewRandomFromReader(reader)
+	if err != nil {
+		return "", err
+	}
+	return id.String(), nil
+}
+
 // Validate checks GUID format of a target id.
 func Validate(id string) error {
 	if _, err := uuid.Parse(id); err != nil {
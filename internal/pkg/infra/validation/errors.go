@@ -34,6 +34,11 @@ func (err *RequestError) Error() string {
 	return err.CustomError.Error()
 }
 
+// Unwrap returns the wrapped error, so errors.Is and errors.As can inspect it.
+func (err *RequestError) Unwrap() error {
+	return err.CustomError
+}
+
 // FieldError represents error message for a specific field.
 type FieldError struct {
 	FieldName    string `json:"fieldName"`
@@ -2,76 +2,147 @@ package database
 
 import (
 	"context"
+	_ "embed" // embed seed and drop scripts.
 	"fmt"
+	"strings"
 
 	"github.com/SKorolchuk/dpio-workspace/internal/pkg/infra/database"
 
-	"github.com/ardanlabs/darwin"
 	"github.com/jmoiron/sqlx"
 )
 
 var (
-	// go:embed sql/schema.sql
-	workspaceSchemaScript string
-
-	// go:embed sql/seed.sql
+	//go:embed sql/seed.sql
 	workspaceSeedScript string
 
-	// go:embed sql/drop.sql
+	//go:embed sql/drop.sql
 	workspaceDropScript string
 )
 
-// Migrate will do the schema migration of workspace database.
+// Migrate will do the schema migration of workspace database up to the latest embedded version.
+// SQLite connections use own migration set with the same schema.
+// It is safe to call from several replicas at once, only one of them applies the migrations.
 func Migrate(ctx context.Context, connection *sqlx.DB) error {
-	if err := database.StatusCheck(ctx, connection); err != nil {
-		return fmt.Errorf("database is not available: %w", err)
-	}
-
-	driver, err := darwin.NewGenericDriver(connection.DB, darwin.PostgresDialect{})
-	if err != nil {
-		return fmt.Errorf("failed to initialize migration driver: %w", err)
-	}
-
-	processor := darwin.New(driver, darwin.ParseMigrations(workspaceSchemaScript))
-	return processor.Migrate()
+	return MigrateUp(ctx, connection, DefaultMigrationLockTimeout)
 }
 
 // Seed will generate initial data useful for development and testing purposes.
+// If a statement of the seed script fails, the transaction is rolled back and the original error is returned.
 func Seed(ctx context.Context, connection *sqlx.DB) error {
 	if err := database.StatusCheck(ctx, connection); err != nil {
 		return fmt.Errorf("database is not available: %w", err)
 	}
 
-	transaction, err := connection.Begin()
+	return executeScript(ctx, connection, "seed", workspaceSeedScript)
+}
+
+// Drop removes all data from workspace database.
+// The drop is refused with ErrorResetForbidden unless guard allows it for the connected database.
+// If a statement of the drop script fails, the transaction is rolled back and the original error is returned.
+func Drop(ctx context.Context, connection *sqlx.DB, guard ResetGuard) error {
+	if err := checkResetGuard(ctx, connection, guard, "drop"); err != nil {
+		return err
+	}
+
+	return executeScript(ctx, connection, "drop", workspaceDropScript)
+}
+
+// executeScript runs all statements of the script in a single transaction.
+// The failed statement is reported with its number and text.
+func executeScript(ctx context.Context, connection *sqlx.DB, scriptName string, script string) error {
+	transaction, err := connection.BeginTxx(ctx, nil)
 	if err != nil {
 		return err
 	}
 
-	if _, err := transaction.Exec(workspaceSeedScript); err != nil {
-		if err := transaction.Rollback(); err != nil {
-			return err
+	for index, statement := range splitStatements(script) {
+		if _, err := transaction.ExecContext(ctx, statement); err != nil {
+			if rollbackErr := transaction.Rollback(); rollbackErr != nil {
+				err = fmt.Errorf("%w (rollback failed: %v)", err, rollbackErr)
+			}
+			return fmt.Errorf("error during execution of %s script statement #%d -> statement={%q}: %w",
+				scriptName, index+1, statement, err)
 		}
 	}
 
 	return transaction.Commit()
 }
 
-// Drop cleans workspace database.
-func Drop(ctx context.Context, connection *sqlx.DB) error {
-	if err := database.StatusCheck(ctx, connection); err != nil {
-		return fmt.Errorf("database is not available: %w", err)
+// splitStatements splits SQL script into separate statements by semicolons outside of string literals, quoted
+// identifiers, dollar-quoted strings and comments. Comments are left out of the statements.
+func splitStatements(script string) []string {
+	var statements []string
+	var current strings.Builder
+
+	flush := func() {
+		if statement := strings.TrimSpace(current.String()); statement != "" {
+			statements = append(statements, statement)
+		}
+		current.Reset()
 	}
 
-	transaction, err := connection.Begin()
-	if err != nil {
-		return err
+	for index := 0; index < len(script); {
+		rest := script[index:]
+		switch {
+		case strings.HasPrefix(rest, "--"):
+			end := strings.IndexByte(rest, '\n')
+			if end < 0 {
+				end = len(rest)
+			}
+			index += end
+		case strings.HasPrefix(rest, "/*"):
+			end := strings.Index(rest[2:], "*/")
+			if end < 0 {
+				end = len(rest) - 4
+			}
+			current.WriteByte(' ')
+			index += end + 4
+		case rest[0] == '\'' || rest[0] == '"':
+			length := quotedLength(rest, rest[:1])
+			current.WriteString(rest[:length])
+			index += length
+		case rest[0] == '$' && dollarTag(rest) != "":
+			length := quotedLength(rest, dollarTag(rest))
+			current.WriteString(rest[:length])
+			index += length
+		case rest[0] == ';':
+			flush()
+			index++
+		default:
+			current.WriteByte(rest[0])
+			index++
+		}
 	}
+	flush()
 
-	if _, err := transaction.Exec(workspaceDropScript); err != nil {
-		if err := transaction.Rollback(); err != nil {
-			return err
+	return statements
+}
+
+// quotedLength returns length of the quoted text at the start of script including both delimiters, the rest of the
+// script is quoted if the closing delimiter is missing. Doubled quotes inside of literals are read as two literals.
+func quotedLength(script string, delimiter string) int {
+	end := strings.Index(script[len(delimiter):], delimiter)
+	if end < 0 {
+		return len(script)
+	}
+
+	return len(delimiter) + end + len(delimiter)
+}
+
+// dollarTag returns the opening delimiter of dollar-quoted string at the start of script, e.g. "$$" or "$body$".
+// Positional parameters such as "$1" are not delimiters.
+func dollarTag(script string) string {
+	for index := 1; index < len(script); index++ {
+		char := script[index]
+		switch {
+		case char == '$':
+			return script[:index+1]
+		case char == '_' || char >= 'a' && char <= 'z' || char >= 'A' && char <= 'Z':
+		case char >= '0' && char <= '9' && index > 1:
+		default:
+			return ""
 		}
 	}
 
-	return transaction.Commit()
+	return ""
 }
@@ -0,0 +1,100 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestSplitStatements(t *testing.T) {
+	tests := []struct {
+		name   string
+		script string
+		want   []string
+	}{
+		{name: "statements", script: "SELECT 1;\n SELECT 2 ;\n\n", want: []string{"SELECT 1", "SELECT 2"}},
+		{name: "semicolon in string literal", script: "SELECT 'a;b', 'it''s;';SELECT 2",
+			want: []string{"SELECT 'a;b', 'it''s;'", "SELECT 2"}},
+		{name: "semicolon in quoted identifier", script: `SELECT 1 AS "a;b";`, want: []string{`SELECT 1 AS "a;b"`}},
+		{name: "line comments", script: "-- first; 'unclosed\nSELECT 1; -- trailing;\nSELECT 2;\n-- only comment",
+			want: []string{"SELECT 1", "SELECT 2"}},
+		{name: "block comments", script: "SELECT /* ; ' */ 1;/* only comment; */",
+			want: []string{"SELECT   1"}},
+		{name: "dollar quotes",
+			script: "CREATE FUNCTION f() RETURNS int AS $$ SELECT 1; $$ LANGUAGE sql;\nDO $body$ BEGIN; END $body$;",
+			want: []string{"CREATE FUNCTION f() RETURNS int AS $$ SELECT 1; $$ LANGUAGE sql",
+				"DO $body$ BEGIN; END $body$"}},
+		{name: "positional parameters", script: "SELECT $1; SELECT $2", want: []string{"SELECT $1", "SELECT $2"}},
+		{name: "empty", script: " ;\n; "},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := splitStatements(test.script); !reflect.DeepEqual(got, test.want) {
+				t.Errorf("splitStatements() = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestResetSQLite(t *testing.T) {
+	connection := openTestDatabase(t)
+	ctx := context.Background()
+
+	if err := Migrate(ctx, connection); err != nil {
+		t.Fatalf("Migrate() error = %v", err)
+	}
+
+	const projectQuery = `
+	INSERT INTO PROJECT (project_id, project_collaboration_type_id, name, description, date_created,
+	                     created_by_user_id, date_updated, updated_by_user_id)
+	VALUES ('4a1f7d2e-8c3b-4e5a-9f60-718293a4b5c6', 'f4a284cd-5a65-4468-8800-e0d8762933a9', 'Kept Project', '',
+	        CURRENT_TIMESTAMP, '92eded9e-979c-4e94-afc5-2333fcc920f6', CURRENT_TIMESTAMP,
+	        '92eded9e-979c-4e94-afc5-2333fcc920f6')`
+	if err := Seed(ctx, connection); err != nil {
+		t.Fatalf("Seed() error = %v", err)
+	}
+	if _, err := connection.ExecContext(ctx, projectQuery); err != nil {
+		t.Fatalf("ExecContext() error = %v", err)
+	}
+
+	projectCount := func() int {
+		var count int
+		if err := connection.GetContext(ctx, &count, `SELECT COUNT(*) FROM PROJECT`); err != nil {
+			t.Fatalf("GetContext() error = %v", err)
+		}
+		return count
+	}
+
+	forbidden := []ResetGuard{
+		{Environment: "production"},
+		{Environment: "production", ConfirmationToken: "other.db"},
+	}
+	for _, guard := range forbidden {
+		if err := Reset(ctx, connection, guard); !errors.Is(err, ErrorResetForbidden) {
+			t.Errorf("Reset(%+v) error = %v, want %v", guard, err, ErrorResetForbidden)
+		}
+		if err := Drop(ctx, connection, guard); !errors.Is(err, ErrorResetForbidden) {
+			t.Errorf("Drop(%+v) error = %v, want %v", guard, err, ErrorResetForbidden)
+		}
+	}
+	if count := projectCount(); count != 2 {
+		t.Fatalf("refused reset changed projects, count = %d, want 2", count)
+	}
+
+	confirmed := ResetGuard{Environment: "production", ConfirmationToken: "workspace.db"}
+	if err := Reset(ctx, connection, confirmed); err != nil {
+		t.Fatalf("Reset() error = %v", err)
+	}
+	if count := projectCount(); count != 1 {
+		t.Errorf("project count after Reset() = %d, want 1 of the seed preset", count)
+	}
+
+	if err := Drop(ctx, connection, ResetGuard{Environment: EnvironmentTest}); err != nil {
+		t.Fatalf("Drop() error = %v", err)
+	}
+	if count := projectCount(); count != 0 {
+		t.Errorf("project count after Drop() = %d, want 0", count)
+	}
+}
@@ -0,0 +1,260 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/SKorolchuk/dpio-workspace/internal/pkg/infra/database"
+	"github.com/SKorolchuk/dpio-workspace/internal/pkg/infra/uuid"
+	"github.com/SKorolchuk/dpio-workspace/internal/pkg/store/project"
+	"github.com/SKorolchuk/dpio-workspace/internal/pkg/store/workspace"
+
+	"github.com/jmoiron/sqlx"
+	"gopkg.in/yaml.v3"
+)
+
+// fixtureExtensions lists supported fixture file formats in lookup order.
+var fixtureExtensions = []string{".json", ".yaml", ".yml"}
+
+// ErrorFixtureNotFound is returned when no file of a named fixture set exists.
+var ErrorFixtureNotFound = errors.New("fixture set is not found")
+
+// Fixture represents a set of entities in the shape of store models which can be loaded into workspace database.
+type Fixture struct {
+	CollaborationTypes []project.CollaborationType `json:"collaborationTypes"`
+	Projects           []project.Project           `json:"projects"`
+	Roles              []project.Role              `json:"roles"`
+	Groups             []project.Group             `json:"groups"`
+	GroupRoles         []project.GroupRole         `json:"groupRoles"`
+	GroupUsers         []project.GroupUser         `json:"groupUsers"`
+	GroupAccesses      []project.GroupAccess       `json:"groupAccesses"`
+	Stems              []workspace.Stem            `json:"stems"`
+	Workspaces         []workspace.Workspace       `json:"workspaces"`
+	Assets             []workspace.Asset           `json:"assets"`
+}
+
+// LoadFixtureSet looking for fixture set with specified name in directory, e.g. "<dir>/<name>.json".
+// JSON and YAML formats are supported.
+func LoadFixtureSet(dir string, name string) (Fixture, error) {
+	for _, extension := range fixtureExtensions {
+		path := filepath.Join(dir, name+extension)
+		if _, err := os.Stat(path); err == nil {
+			return LoadFixture(path)
+		}
+	}
+
+	return Fixture{}, fmt.Errorf("error during lookup of fixture set -> name={%q}: %w", name, ErrorFixtureNotFound)
+}
+
+// LoadFixture reads fixture file, the format is detected by file extension.
+// YAML documents use the same field names as JSON documents.
+func LoadFixture(path string) (Fixture, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return Fixture{}, fmt.Errorf("error during read of fixture -> path={%q}: %w", path, err)
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		// Models declare only JSON field names, so YAML document is converted to JSON first.
+		var document interface{}
+		if err := yaml.Unmarshal(content, &document); err != nil {
+			return Fixture{}, fmt.Errorf("error during parse of fixture -> path={%q}: %w", path, err)
+		}
+
+		if content, err = json.Marshal(document); err != nil {
+			return Fixture{}, fmt.Errorf("error during parse of fixture -> path={%q}: %w", path, err)
+		}
+	case ".json":
+	default:
+		return Fixture{}, fmt.Errorf("unsupported format of fixture -> path={%q}", path)
+	}
+
+	var fixture Fixture
+	if err := json.Unmarshal(content, &fixture); err != nil {
+		return Fixture{}, fmt.Errorf("error during parse of fixture -> path={%q}: %w", path, err)
+	}
+
+	return fixture, nil
+}
+
+// SeedFixture inserts all fixture entities in a single transaction.
+// Entities whose identifiers already exist are skipped, so the same fixture can be loaded several times. Entities
+// which clash with other existing entities, e.g. by a unique name, fail the seed instead of being skipped silently.
+func SeedFixture(ctx context.Context, connection *sqlx.DB, fixture Fixture) error {
+	if err := database.StatusCheck(ctx, connection); err != nil {
+		return fmt.Errorf("database is not available: %w", err)
+	}
+
+	transaction, err := connection.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	if err := seedFixture(ctx, transaction, fixture); err != nil {
+		_ = transaction.Rollback()
+		return err
+	}
+
+	return transaction.Commit()
+}
+
+// seedFixture inserts fixture entities in order of their references.
+func seedFixture(ctx context.Context, transaction *sqlx.Tx, fixture Fixture) error {
+	const collaborationTypeQuery = `
+	INSERT INTO PROJECT_COLLABORATION_TYPE (project_collaboration_type_id, name)
+	VALUES (:project_collaboration_type_id, :name)
+	ON CONFLICT (project_collaboration_type_id) DO NOTHING`
+
+	for _, entity := range fixture.CollaborationTypes {
+		if err := seedEntity(ctx, transaction, collaborationTypeQuery, "CollaborationType", entity.ID,
+			entity); err != nil {
+			return err
+		}
+	}
+
+	const projectQuery = `
+	INSERT INTO PROJECT (project_id, project_collaboration_type_id, name, description, date_created,
+	                     created_by_user_id, date_updated, updated_by_user_id)
+	VALUES (:project_id, :project_collaboration_type_id, :name, :description, :date_created,
+	        :created_by_user_id, :date_updated, :updated_by_user_id)
+	ON CONFLICT (project_id) DO NOTHING`
+
+	// Creators of fixture projects become their owners, as it happens with projects created through the store.
+	const projectOwnerQuery = `
+	INSERT INTO PROJECT_OWNER (project_owner_id, project_id, user_id, date_created, created_by_user_id)
+	VALUES (:project_owner_id, :project_id, :user_id, :date_created, :created_by_user_id)
+	ON CONFLICT (project_id, user_id) DO NOTHING`
+
+	for _, entity := range fixture.Projects {
+		if err := seedEntity(ctx, transaction, projectQuery, "Project", entity.ID, entity); err != nil {
+			return err
+		}
+
+		owner := project.ProjectOwner{
+			ID:            uuid.Generate(),
+			ProjectID:     entity.ID,
+			UserID:        entity.CreatedByUser,
+			DateCreated:   entity.DateCreated,
+			CreatedByUser: entity.CreatedByUser,
+		}
+		if err := seedEntity(ctx, transaction, projectOwnerQuery, "ProjectOwner", owner.ID, owner); err != nil {
+			return err
+		}
+	}
+
+	const roleQuery = `
+	INSERT INTO PROJECT_ROLE (project_role_id, name)
+	VALUES (:project_role_id, :name)
+	ON CONFLICT (project_role_id) DO NOTHING`
+
+	for _, entity := range fixture.Roles {
+		if err := seedEntity(ctx, transaction, roleQuery, "Role", entity.ID, entity); err != nil {
+			return err
+		}
+	}
+
+	const groupQuery = `
+	INSERT INTO PROJECT_GROUP (project_group_id, name, date_created, created_by_user_id, date_updated,
+	                           updated_by_user_id)
+	VALUES (:project_group_id, :name, :date_created, :created_by_user_id, :date_updated, :updated_by_user_id)
+	ON CONFLICT (project_group_id) DO NOTHING`
+
+	for _, entity := range fixture.Groups {
+		if err := seedEntity(ctx, transaction, groupQuery, "Group", entity.ID, entity); err != nil {
+			return err
+		}
+	}
+
+	const groupRoleQuery = `
+	INSERT INTO PROJECT_GROUP_ROLE (project_group_role_id, project_group_id, project_role_id, date_created,
+	                                created_by_user_id)
+	VALUES (:project_group_role_id, :project_group_id, :project_role_id, :date_created, :created_by_user_id)
+	ON CONFLICT (project_group_role_id) DO NOTHING`
+
+	for _, entity := range fixture.GroupRoles {
+		if err := seedEntity(ctx, transaction, groupRoleQuery, "GroupRole", entity.ID, entity); err != nil {
+			return err
+		}
+	}
+
+	const groupUserQuery = `
+	INSERT INTO PROJECT_GROUP_USER (project_group_user_id, project_group_id, user_id, date_created,
+	                                created_by_user_id)
+	VALUES (:project_group_user_id, :project_group_id, :user_id, :date_created, :created_by_user_id)
+	ON CONFLICT (project_group_user_id) DO NOTHING`
+
+	for _, entity := range fixture.GroupUsers {
+		if err := seedEntity(ctx, transaction, groupUserQuery, "GroupUser", entity.ID, entity); err != nil {
+			return err
+		}
+	}
+
+	const groupAccessQuery = `
+	INSERT INTO PROJECT_GROUP_ACCESS (project_group_access_id, project_id, project_group_id, date_created,
+	                                  created_by_user_id)
+	VALUES (:project_group_access_id, :project_id, :project_group_id, :date_created, :created_by_user_id)
+	ON CONFLICT (project_group_access_id) DO NOTHING`
+
+	for _, entity := range fixture.GroupAccesses {
+		if err := seedEntity(ctx, transaction, groupAccessQuery, "GroupAccess", entity.ID, entity); err != nil {
+			return err
+		}
+	}
+
+	const stemQuery = `
+	INSERT INTO STEM (stem_id, name)
+	VALUES (:stem_id, :name)
+	ON CONFLICT (stem_id) DO NOTHING`
+
+	for _, entity := range fixture.Stems {
+		if err := seedEntity(ctx, transaction, stemQuery, "Stem", entity.ID, entity); err != nil {
+			return err
+		}
+	}
+
+	const workspaceQuery = `
+	INSERT INTO WORKSPACE (workspace_id, project_id, stem_id, name, description, asset_amount_limit,
+	                       x_max, y_max, z_max, date_created, created_by_user_id, date_updated, updated_by_user_id)
+	VALUES (:workspace_id, :project_id, :stem_id, :name, :description, :asset_amount_limit,
+	        :x_max, :y_max, :z_max, :date_created, :created_by_user_id, :date_updated, :updated_by_user_id)
+	ON CONFLICT (workspace_id) DO NOTHING`
+
+	for _, entity := range fixture.Workspaces {
+		if err := seedEntity(ctx, transaction, workspaceQuery, "Workspace", entity.ID, entity); err != nil {
+			return err
+		}
+	}
+
+	const assetQuery = `
+	INSERT INTO ASSET (asset_id, workspace_id, asset_external_ref_id, position_x, position_y, position_z, scale,
+	                   height_by_y, width_by_x, length_by_z, date_created, created_by_user_id, date_updated,
+	                   updated_by_user_id)
+	VALUES (:asset_id, :workspace_id, :asset_external_ref_id, :position_x, :position_y, :position_z, :scale,
+	        :height_by_y, :width_by_x, :length_by_z, :date_created, :created_by_user_id, :date_updated,
+	        :updated_by_user_id)
+	ON CONFLICT (asset_id) DO NOTHING`
+
+	for _, entity := range fixture.Assets {
+		if err := seedEntity(ctx, transaction, assetQuery, "Asset", entity.ID, entity); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// seedEntity inserts a single fixture entity using named query.
+func seedEntity(ctx context.Context, transaction *sqlx.Tx, query string, kind string, id string,
+	entity interface{}) error {
+	if _, err := transaction.NamedExecContext(ctx, query, entity); err != nil {
+		return fmt.Errorf("error during seed of %s entity -> id={%q}: %w", kind, id, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,117 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// sampleFixtureDir is the directory of fixture sets shared with the admin tool.
+const sampleFixtureDir = "../../../../test/testdata"
+
+func TestLoadFixtureSet(t *testing.T) {
+	fixture, err := LoadFixtureSet(sampleFixtureDir, "_sample_entities")
+	if err != nil {
+		t.Fatalf("LoadFixtureSet() error = %v", err)
+	}
+	if len(fixture.CollaborationTypes) != 3 || len(fixture.Projects) == 0 {
+		t.Errorf("LoadFixtureSet() = %d collaboration types and %d projects, want 3 and at least 1",
+			len(fixture.CollaborationTypes), len(fixture.Projects))
+	}
+
+	if _, err := LoadFixtureSet(sampleFixtureDir, "missing"); !errors.Is(err, ErrorFixtureNotFound) {
+		t.Errorf("LoadFixtureSet() of missing set error = %v, want %v", err, ErrorFixtureNotFound)
+	}
+}
+
+func TestLoadFixture(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name string, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatalf("WriteFile() error = %v", err)
+		}
+		return path
+	}
+
+	const projectID = "1f3a8a6e-5b0c-4d2e-9f41-7c6b5a4d3e21"
+
+	tests := []struct {
+		name         string
+		path         string
+		wantErr      bool
+		wantProjects int
+	}{
+		{name: "json", wantProjects: 1,
+			path: write("set.json", `{"projects": [{"id": "`+projectID+`", "name": "From JSON"}]}`)},
+		{name: "yaml", wantProjects: 1,
+			path: write("set.yaml", "projects:\n  - id: "+projectID+"\n    name: From YAML\n")},
+		{name: "yml", path: write("empty.yml", "stems: []\n")},
+		{name: "unsupported format", path: write("set.txt", "{}"), wantErr: true},
+		{name: "invalid json", path: write("broken.json", "{"), wantErr: true},
+		{name: "missing file", path: filepath.Join(dir, "missing.json"), wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fixture, err := LoadFixture(test.path)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("LoadFixture() error = %v, wantErr %v", err, test.wantErr)
+			}
+			if len(fixture.Projects) != test.wantProjects {
+				t.Fatalf("LoadFixture() = %d projects, want %d", len(fixture.Projects), test.wantProjects)
+			}
+			if test.wantProjects > 0 && fixture.Projects[0].ID != projectID {
+				t.Errorf("LoadFixture() project id = %q, want %q", fixture.Projects[0].ID, projectID)
+			}
+		})
+	}
+}
+
+func TestSeedFixtureSQLite(t *testing.T) {
+	connection := openTestDatabase(t)
+	ctx := context.Background()
+
+	if err := Migrate(ctx, connection); err != nil {
+		t.Fatalf("Migrate() error = %v", err)
+	}
+
+	fixture, err := GenerateFixture(GeneratorOptions{Projects: 2, WorkspacesPerProject: 2, AssetsPerWorkspace: 3,
+		RandomSeed: 7})
+	if err != nil {
+		t.Fatalf("GenerateFixture() error = %v", err)
+	}
+
+	// The second seed of the same fixture skips existing entities.
+	for attempt := 1; attempt <= 2; attempt++ {
+		if err := SeedFixture(ctx, connection, fixture); err != nil {
+			t.Fatalf("SeedFixture() attempt %d error = %v", attempt, err)
+		}
+	}
+
+	counts := map[string]int{
+		"SELECT COUNT(*) FROM PROJECT WHERE name LIKE 'Generated Project 7-%'":   2,
+		"SELECT COUNT(*) FROM WORKSPACE WHERE name LIKE 'Generated Project 7-%'": 4,
+		"SELECT COUNT(*) FROM ASSET": 12,
+	}
+	for query, want := range counts {
+		var count int
+		if err := connection.GetContext(ctx, &count, query); err != nil {
+			t.Fatalf("GetContext(%q) error = %v", query, err)
+		}
+		if count != want {
+			t.Errorf("%s = %d, want %d", query, count, want)
+		}
+	}
+
+	t.Run("clash of unique name fails the seed", func(t *testing.T) {
+		clash := Fixture{Projects: fixture.Projects[:1]}
+		clash.Projects[0].ID = "1f3a8a6e-5b0c-4d2e-9f41-7c6b5a4d3e22"
+
+		if err := SeedFixture(ctx, connection, clash); err == nil {
+			t.Errorf("SeedFixture() error = nil, want error of duplicated project name")
+		}
+	})
+}
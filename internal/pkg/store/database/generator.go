@@ -0,0 +1,249 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/SKorolchuk/dpio-workspace/internal/pkg/infra/uuid"
+	"github.com/SKorolchuk/dpio-workspace/internal/pkg/store/project"
+	"github.com/SKorolchuk/dpio-workspace/internal/pkg/store/workspace"
+)
+
+// generatedHistory is a period before GeneratorOptions.Now where dates of generated entities are spread.
+const generatedHistory = 365 * 24 * time.Hour
+
+// GeneratorOptions describes the volume and shape of synthetic data set.
+type GeneratorOptions struct {
+	Projects             int
+	WorkspacesPerProject int
+	AssetsPerWorkspace   int
+
+	// UserID is used as author of generated entities, a random one is generated if empty.
+	UserID string
+
+	// RandomSeed makes generated data set reproducible, the same seed produces the same entities.
+	// Names of generated projects and workspaces include the seed, so data sets of different seeds can be loaded
+	// into the same database.
+	RandomSeed int64
+
+	// Now is the latest date of generated entities, current time is used if zero.
+	Now time.Time
+}
+
+// generator holds the state of a single synthetic data set generation.
+type generator struct {
+	random  *rand.Rand
+	options GeneratorOptions
+	fixture Fixture
+}
+
+// GenerateFixture creates synthetic Fixture with Projects × WorkspacesPerProject × AssetsPerWorkspace entities.
+// Workspaces use all known stems, assets are placed and sized inside MaxX/MaxY/MaxZ bounds of their workspace.
+// Collaboration types and stems are included, so the fixture can be loaded into an empty database.
+func GenerateFixture(options GeneratorOptions) (Fixture, error) {
+	if options.Projects < 0 || options.WorkspacesPerProject < 0 || options.AssetsPerWorkspace < 0 {
+		return Fixture{}, errors.New("amount of generated entities should not be negative")
+	}
+
+	if options.Now.IsZero() {
+		options.Now = time.Now()
+	}
+	options.Now = options.Now.UTC()
+
+	gen := generator{
+		random:  rand.New(rand.NewSource(options.RandomSeed)),
+		options: options,
+		fixture: Fixture{
+			CollaborationTypes: []project.CollaborationType{
+				{ID: project.PublicCollaborationType, Name: "Public"},
+				{ID: project.TeamCollaborationType, Name: "Team"},
+				{ID: project.PrivateCollaborationType, Name: "Private"},
+			},
+			Stems: []workspace.Stem{
+				{ID: workspace.StickerWorkspaceType, Name: "Sticker Pane"},
+				{ID: workspace.Environment2DWorkspaceType, Name: "2D Environment"},
+				{ID: workspace.Environment3DWorkspaceType, Name: "3D Environment"},
+			},
+			Projects:   make([]project.Project, 0, options.Projects),
+			Workspaces: make([]workspace.Workspace, 0, options.Projects*options.WorkspacesPerProject),
+			Assets: make([]workspace.Asset, 0,
+				options.Projects*options.WorkspacesPerProject*options.AssetsPerWorkspace),
+		},
+	}
+
+	if gen.options.UserID == "" {
+		userID, err := gen.id()
+		if err != nil {
+			return Fixture{}, err
+		}
+		gen.options.UserID = userID
+	}
+
+	for projectIndex := 0; projectIndex < options.Projects; projectIndex++ {
+		if err := gen.addProject(projectIndex); err != nil {
+			return Fixture{}, err
+		}
+	}
+
+	return gen.fixture, nil
+}
+
+// addProject generates Project entity with all its workspaces and assets.
+func (gen *generator) addProject(projectIndex int) error {
+	id, err := gen.id()
+	if err != nil {
+		return err
+	}
+
+	collaborationTypes := gen.fixture.CollaborationTypes
+	dateCreated := gen.dateAfter(gen.options.Now.Add(-generatedHistory))
+	projectData := project.Project{
+		ID:            id,
+		ProjectTypeID: collaborationTypes[gen.random.Intn(len(collaborationTypes))].ID,
+		Name:          fmt.Sprintf("Generated Project %d-%d", gen.options.RandomSeed, projectIndex+1),
+		Description:   "This project generated by synthetic data generator.",
+		DateCreated:   dateCreated,
+		CreatedByUser: gen.options.UserID,
+		DateUpdated:   gen.dateAfter(dateCreated),
+		UpdatedByUser: gen.options.UserID,
+	}
+	gen.fixture.Projects = append(gen.fixture.Projects, projectData)
+
+	for workspaceIndex := 0; workspaceIndex < gen.options.WorkspacesPerProject; workspaceIndex++ {
+		if err := gen.addWorkspace(projectData, workspaceIndex); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// addWorkspace generates Workspace entity of a random stem with all its assets.
+func (gen *generator) addWorkspace(projectData project.Project, workspaceIndex int) error {
+	id, err := gen.id()
+	if err != nil {
+		return err
+	}
+
+	stem := gen.fixture.Stems[gen.random.Intn(len(gen.fixture.Stems))]
+
+	// Flat stems have a single layer, so only 3D environments have depth.
+	maxZ := int32(1)
+	if stem.ID == workspace.Environment3DWorkspaceType {
+		maxZ = gen.between(100, 2000)
+	}
+
+	assetAmountLimit := int32(gen.options.AssetsPerWorkspace)
+	if assetAmountLimit < 1 {
+		assetAmountLimit = 1
+	}
+
+	dateCreated := gen.dateAfter(projectData.DateCreated)
+	workspaceData := workspace.Workspace{
+		ID:               id,
+		ProjectID:        projectData.ID,
+		StemID:           stem.ID,
+		Name:             fmt.Sprintf("%s %s %d", projectData.Name, stem.Name, workspaceIndex+1),
+		Description:      "This workspace generated by synthetic data generator.",
+		AssetAmountLimit: assetAmountLimit,
+		MaxX:             gen.between(500, 4000),
+		MaxY:             gen.between(500, 4000),
+		MaxZ:             maxZ,
+		DateCreated:      dateCreated,
+		CreatedByUser:    gen.options.UserID,
+		DateUpdated:      gen.dateAfter(dateCreated),
+		UpdatedByUser:    gen.options.UserID,
+	}
+	gen.fixture.Workspaces = append(gen.fixture.Workspaces, workspaceData)
+
+	for assetIndex := 0; assetIndex < gen.options.AssetsPerWorkspace; assetIndex++ {
+		if err := gen.addAsset(workspaceData); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// addAsset generates Asset entity which fits into bounds of the workspace.
+func (gen *generator) addAsset(workspaceData workspace.Workspace) error {
+	id, err := gen.id()
+	if err != nil {
+		return err
+	}
+
+	refID, err := gen.id()
+	if err != nil {
+		return err
+	}
+
+	width := gen.between(1, maxInt32(workspaceData.MaxX/4, 1))
+	height := gen.between(1, maxInt32(workspaceData.MaxY/4, 1))
+
+	// Assets of flat workspaces have no depth and are placed on one of the layers.
+	length := int32(0)
+	if workspaceData.StemID == workspace.Environment3DWorkspaceType {
+		length = gen.between(1, maxInt32(workspaceData.MaxZ/4, 1))
+	}
+
+	dateCreated := gen.dateAfter(workspaceData.DateCreated)
+	gen.fixture.Assets = append(gen.fixture.Assets, workspace.Asset{
+		ID:            id,
+		WorkspaceID:   workspaceData.ID,
+		AssetRefID:    refID,
+		X:             gen.between(0, workspaceData.MaxX-width),
+		Y:             gen.between(0, workspaceData.MaxY-height),
+		Z:             gen.between(0, maxInt32(workspaceData.MaxZ-length, 0)),
+		Scale:         gen.between(1, 4),
+		Height:        height,
+		Width:         width,
+		Length:        length,
+		DateCreated:   dateCreated,
+		CreatedByUser: gen.options.UserID,
+		DateUpdated:   gen.dateAfter(dateCreated),
+		UpdatedByUser: gen.options.UserID,
+	})
+
+	return nil
+}
+
+// id generates reproducible identifier from the generator random source.
+func (gen *generator) id() (string, error) {
+	id, err := uuid.GenerateFromReader(gen.random)
+	if err != nil {
+		return "", fmt.Errorf("error during identifier generation: %w", err)
+	}
+
+	return id, nil
+}
+
+// between returns random number in the closed range [min, max].
+func (gen *generator) between(min int32, max int32) int32 {
+	if max <= min {
+		return min
+	}
+
+	return min + gen.random.Int31n(max-min+1)
+}
+
+// dateAfter returns random date between since and GeneratorOptions.Now, rounded to microseconds
+// to survive a round trip through the database.
+func (gen *generator) dateAfter(since time.Time) time.Time {
+	period := gen.options.Now.Sub(since)
+	if period <= 0 {
+		return gen.options.Now.Truncate(time.Microsecond)
+	}
+
+	return since.Add(time.Duration(gen.random.Int63n(int64(period)))).Truncate(time.Microsecond)
+}
+
+// maxInt32 returns the largest of two numbers.
+func maxInt32(a int32, b int32) int32 {
+	if a > b {
+		return a
+	}
+
+	return b
+}
@@ -0,0 +1,85 @@
+package database
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/SKorolchuk/dpio-workspace/internal/pkg/store/workspace"
+)
+
+func TestGenerateFixture(t *testing.T) {
+	now := time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+	options := GeneratorOptions{Projects: 3, WorkspacesPerProject: 4, AssetsPerWorkspace: 5, RandomSeed: 42,
+		UserID: "6c1e2f3a-4b5c-4d6e-8f70-8192a3b4c5d6", Now: now}
+
+	fixture, err := GenerateFixture(options)
+	if err != nil {
+		t.Fatalf("GenerateFixture() error = %v", err)
+	}
+
+	if len(fixture.Projects) != 3 || len(fixture.Workspaces) != 12 || len(fixture.Assets) != 60 {
+		t.Fatalf("GenerateFixture() = %d projects, %d workspaces, %d assets, want 3, 12, 60",
+			len(fixture.Projects), len(fixture.Workspaces), len(fixture.Assets))
+	}
+	if len(fixture.CollaborationTypes) != 3 || len(fixture.Stems) != 3 {
+		t.Errorf("GenerateFixture() = %d collaboration types and %d stems, want 3 and 3",
+			len(fixture.CollaborationTypes), len(fixture.Stems))
+	}
+
+	t.Run("same seed produces the same entities", func(t *testing.T) {
+		again, err := GenerateFixture(options)
+		if err != nil {
+			t.Fatalf("GenerateFixture() error = %v", err)
+		}
+		if !reflect.DeepEqual(fixture, again) {
+			t.Errorf("GenerateFixture() is not reproducible for the same seed")
+		}
+	})
+
+	t.Run("entities use options", func(t *testing.T) {
+		for _, projectData := range fixture.Projects {
+			if projectData.CreatedByUser != options.UserID {
+				t.Errorf("project %s author = %q, want %q", projectData.ID, projectData.CreatedByUser, options.UserID)
+			}
+			if projectData.DateCreated.After(now) || projectData.DateUpdated.Before(projectData.DateCreated) {
+				t.Errorf("project %s dates are out of order", projectData.ID)
+			}
+		}
+	})
+
+	t.Run("assets fit into workspace bounds", func(t *testing.T) {
+		workspaces := make(map[string]workspace.Workspace, len(fixture.Workspaces))
+		for _, workspaceData := range fixture.Workspaces {
+			workspaces[workspaceData.ID] = workspaceData
+		}
+
+		for _, asset := range fixture.Assets {
+			workspaceData, ok := workspaces[asset.WorkspaceID]
+			if !ok {
+				t.Fatalf("asset %s references unknown workspace %s", asset.ID, asset.WorkspaceID)
+			}
+			if asset.X < 0 || asset.X+asset.Width > workspaceData.MaxX ||
+				asset.Y < 0 || asset.Y+asset.Height > workspaceData.MaxY ||
+				asset.Z < 0 || asset.Z+asset.Length > workspaceData.MaxZ {
+				t.Errorf("asset %s is outside bounds of workspace %s", asset.ID, workspaceData.ID)
+			}
+		}
+	})
+
+	t.Run("generated author if empty", func(t *testing.T) {
+		generated, err := GenerateFixture(GeneratorOptions{Projects: 1, RandomSeed: 1, Now: now})
+		if err != nil {
+			t.Fatalf("GenerateFixture() error = %v", err)
+		}
+		if generated.Projects[0].CreatedByUser == "" {
+			t.Errorf("GenerateFixture() project author is empty")
+		}
+	})
+
+	t.Run("negative amount", func(t *testing.T) {
+		if _, err := GenerateFixture(GeneratorOptions{Projects: -1}); err == nil {
+			t.Errorf("GenerateFixture() error = nil, want error of negative amount")
+		}
+	})
+}
@@ -0,0 +1,133 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/SKorolchuk/dpio-workspace/internal/pkg/infra/database"
+
+	"github.com/jmoiron/sqlx"
+)
+
+const (
+	DefaultMigrationLockTimeout  = time.Minute
+	DefaultMigrationPollInterval = 500 * time.Millisecond
+)
+
+// migrationLockID is a key of PostgreSQL advisory lock which serializes migrations between service replicas.
+const migrationLockID int64 = 7305118452093317
+
+// ErrorMigrationLockTimeout is returned when the migration lock is held by other replica longer than allowed.
+var ErrorMigrationLockTimeout = errors.New("timeout while waiting for migration lock")
+
+// migrationLock represents a dedicated connection holding the migration lock. Migrations should run on this
+// connection, so a pool limited to a single connection is not exhausted by the lock.
+type migrationLock struct {
+	conn   *sqlx.Conn
+	locked bool
+}
+
+// acquireMigrationLock takes the session-level advisory lock on a dedicated connection, polling until the lock is
+// free or timeout is reached.
+// SQLite serves a single node and serializes writers itself, so only the connection is taken for it.
+func acquireMigrationLock(ctx context.Context, connection *sqlx.DB, timeout time.Duration) (*migrationLock, error) {
+	conn, err := connection.Connx(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if connection.DriverName() != database.DriverPostgres {
+		return &migrationLock{conn: conn}, nil
+	}
+
+	deadline := time.Now().Add(timeout)
+	for {
+		var acquired bool
+		err := conn.QueryRowxContext(ctx, `SELECT pg_try_advisory_lock($1)`, migrationLockID).Scan(&acquired)
+		if err != nil {
+			_ = conn.Close()
+			return nil, fmt.Errorf("failed to acquire migration lock: %w", err)
+		}
+
+		if acquired {
+			return &migrationLock{conn: conn, locked: true}, nil
+		}
+
+		if time.Now().After(deadline) {
+			_ = conn.Close()
+			return nil, ErrorMigrationLockTimeout
+		}
+
+		select {
+		case <-ctx.Done():
+			_ = conn.Close()
+			return nil, ctx.Err()
+		case <-time.After(DefaultMigrationPollInterval):
+		}
+	}
+}
+
+// release unlocks the advisory lock and returns the dedicated connection to the pool.
+// The connection is closed even if the unlock fails, the lock of a session is freed together with it.
+func (lock *migrationLock) release() error {
+	var unlockErr error
+	if lock.locked {
+		_, unlockErr = lock.conn.ExecContext(context.Background(), `SELECT pg_advisory_unlock($1)`, migrationLockID)
+		lock.locked = false
+	}
+
+	if err := lock.conn.Close(); err != nil && unlockErr == nil {
+		return fmt.Errorf("failed to return migration connection: %w", err)
+	}
+	if unlockErr != nil {
+		return fmt.Errorf("failed to release migration lock: %w", unlockErr)
+	}
+
+	return nil
+}
+
+// releaseMigrationLock releases the lock and reports the release error through err unless the migration already
+// failed with its own error.
+func releaseMigrationLock(lock *migrationLock, err *error) {
+	if releaseErr := lock.release(); releaseErr != nil && *err == nil {
+		*err = releaseErr
+	}
+}
+
+// WaitForMigrations blocks until the database schema reaches the latest embedded migration version.
+// It is used by replicas which do not run migrations themselves and should not start serving earlier.
+func WaitForMigrations(ctx context.Context, connection *sqlx.DB, pollInterval time.Duration) error {
+	migrations, err := Migrations(connection.DriverName())
+	if err != nil {
+		return err
+	}
+
+	expectedVersion := 0
+	if len(migrations) > 0 {
+		expectedVersion = migrations[len(migrations)-1].Version
+	}
+
+	for {
+		status, err := QueryMigrationStatus(ctx, connection)
+		if err != nil {
+			return err
+		}
+
+		if status.HasDrift() {
+			return fmt.Errorf("schema cannot be used: %w", driftError(status))
+		}
+
+		if status.CurrentVersion >= expectedVersion {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("schema version %d is not reached, current version %d: %w", expectedVersion,
+				status.CurrentVersion, ctx.Err())
+		case <-time.After(pollInterval):
+		}
+	}
+}
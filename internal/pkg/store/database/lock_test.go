@@ -0,0 +1,70 @@
+package database
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/SKorolchuk/dpio-workspace/internal/pkg/infra/database"
+)
+
+func TestMigrateWithSingleConnection(t *testing.T) {
+	connection, err := database.Open(database.DbConfig{Driver: database.DriverSQLite, MaxOpenConnections: 1,
+		DatabaseName: filepath.Join(t.TempDir(), "workspace.db")})
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	defer database.Close(connection)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	if err := MigrateUp(ctx, connection, DefaultMigrationLockTimeout); err != nil {
+		t.Fatalf("MigrateUp() error = %v", err)
+	}
+	if err := MigrateDown(ctx, connection, 1, DefaultMigrationLockTimeout); err != nil {
+		t.Fatalf("MigrateDown() error = %v", err)
+	}
+
+	status, err := QueryMigrationStatus(ctx, connection)
+	if err != nil {
+		t.Fatalf("QueryMigrationStatus() error = %v", err)
+	}
+	if status.CurrentVersion != status.LatestVersion-1 {
+		t.Errorf("CurrentVersion = %d, want %d", status.CurrentVersion, status.LatestVersion-1)
+	}
+}
+
+func TestMigrationLockRelease(t *testing.T) {
+	connection := openTestDatabase(t)
+
+	lock, err := acquireMigrationLock(context.Background(), connection, DefaultMigrationLockTimeout)
+	if err != nil {
+		t.Fatalf("acquireMigrationLock() error = %v", err)
+	}
+	if err := lock.release(); err != nil {
+		t.Errorf("release() error = %v", err)
+	}
+
+	t.Run("release error is reported", func(t *testing.T) {
+		err = lock.release()
+		if err == nil {
+			t.Fatalf("second release() error = nil, want error of closed connection")
+		}
+
+		var migrationErr error
+		releaseMigrationLock(lock, &migrationErr)
+		if migrationErr == nil {
+			t.Errorf("releaseMigrationLock() did not report the release error")
+		}
+	})
+
+	t.Run("migration error is kept", func(t *testing.T) {
+		migrationErr := ErrorMigrationLockTimeout
+		releaseMigrationLock(lock, &migrationErr)
+		if migrationErr != ErrorMigrationLockTimeout {
+			t.Errorf("releaseMigrationLock() replaced migration error with %v", migrationErr)
+		}
+	})
+}
@@ -0,0 +1,502 @@
+package database
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"embed"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/SKorolchuk/dpio-workspace/internal/pkg/infra/database"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// migrationsRoot is a directory of embedded migration sets, one sub-directory per database driver name.
+const migrationsRoot = "sql/migrations"
+
+// migrationFileRegex describes migration file name, e.g. "0001_initial_schema.up.sql".
+var migrationFileRegex = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+//go:embed sql/migrations
+var migrationFiles embed.FS
+
+// Errors of migration processing.
+var (
+	ErrorMigrationDrift   = errors.New("applied migration differs from the embedded one")
+	ErrorMigrationMissing = errors.New("applied migration is missing from the embedded set")
+	ErrorNoDownMigration  = errors.New("migration cannot be rolled back")
+)
+
+// Migration represents a single versioned schema change with its rollback script.
+type Migration struct {
+	Version     int
+	Description string
+	Up          string
+	Down        string
+}
+
+// Checksum returns SHA-256 hash of the migration Up script.
+func (m Migration) Checksum() string {
+	hash := sha256.Sum256([]byte(m.Up))
+	return hex.EncodeToString(hash[:])
+}
+
+// AppliedMigration represents a migration record stored in the database.
+type AppliedMigration struct {
+	Version     int       `db:"version"`
+	Description string    `db:"description"`
+	Checksum    string    `db:"checksum"`
+	DateApplied time.Time `db:"date_applied"`
+}
+
+// MigrationState describes a migration from the embedded set in relation to the database.
+type MigrationState struct {
+	Version         int        `json:"version"`
+	Description     string     `json:"description"`
+	Applied         bool       `json:"applied"`
+	DateApplied     *time.Time `json:"dateApplied,omitempty"`
+	Checksum        string     `json:"checksum"`
+	AppliedChecksum string     `json:"appliedChecksum,omitempty"`
+	Drift           bool       `json:"drift"`
+}
+
+// MigrationStatus describes the schema version of the database and the state of every known migration.
+type MigrationStatus struct {
+	CurrentVersion int              `json:"currentVersion"`
+	LatestVersion  int              `json:"latestVersion"`
+	Migrations     []MigrationState `json:"migrations"`
+	Missing        []int            `json:"missing,omitempty"`
+}
+
+// IsCurrent reports whether all embedded migrations are applied.
+func (ms MigrationStatus) IsCurrent() bool {
+	for _, state := range ms.Migrations {
+		if !state.Applied {
+			return false
+		}
+	}
+
+	return true
+}
+
+// HasDrift reports whether applied migrations differ from the embedded set.
+func (ms MigrationStatus) HasDrift() bool {
+	if len(ms.Missing) > 0 {
+		return true
+	}
+
+	for _, state := range ms.Migrations {
+		if state.Drift {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Migrations returns the embedded migration set for the database driver ordered by version.
+func Migrations(driverName string) ([]Migration, error) {
+	switch driverName {
+	case database.DriverPostgres, database.DriverSQLite:
+	default:
+		return nil, fmt.Errorf("migrations for database driver %q are not available", driverName)
+	}
+
+	return loadMigrations(migrationFiles, path.Join(migrationsRoot, driverName))
+}
+
+// loadMigrations reads numbered up/down migration files from the directory.
+func loadMigrations(fsys fs.FS, directory string) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, directory)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations directory %q: %w", directory, err)
+	}
+
+	byVersion := make(map[int]*Migration)
+	for _, entry := range entries {
+		match := migrationFileRegex.FindStringSubmatch(entry.Name())
+		if entry.IsDir() || match == nil {
+			continue
+		}
+
+		version, err := strconv.Atoi(match[1])
+		if err != nil || version <= 0 {
+			return nil, fmt.Errorf("migration %q has invalid version", entry.Name())
+		}
+
+		script, err := fs.ReadFile(fsys, path.Join(directory, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read migration %q: %w", entry.Name(), err)
+		}
+
+		migration, found := byVersion[version]
+		if !found {
+			migration = &Migration{Version: version, Description: match[2]}
+			byVersion[version] = migration
+		}
+		if migration.Description != match[2] {
+			return nil, fmt.Errorf("migration version %d has several descriptions", version)
+		}
+
+		if match[3] == "up" {
+			migration.Up = string(script)
+		} else {
+			migration.Down = string(script)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.Up == "" {
+			return nil, fmt.Errorf("migration version %d has no up script", migration.Version)
+		}
+		migrations = append(migrations, *migration)
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
+
+// MigrateUp applies all pending migrations in version order, each one in its own transaction.
+// Concurrent replicas are serialized by an advisory lock awaited up to lockTimeout; the work is skipped when the
+// schema is already current. Migration refuses to run if applied migrations drifted from the embedded set.
+func MigrateUp(ctx context.Context, connection *sqlx.DB, lockTimeout time.Duration) (err error) {
+	status, err := QueryMigrationStatus(ctx, connection)
+	if err != nil {
+		return err
+	}
+	if status.HasDrift() {
+		return fmt.Errorf("migration is stopped: %w", driftError(status))
+	}
+	if status.IsCurrent() {
+		return nil
+	}
+
+	lock, err := acquireMigrationLock(ctx, connection, lockTimeout)
+	if err != nil {
+		return err
+	}
+	defer releaseMigrationLock(lock, &err)
+
+	// Other replica could finish the migration while the lock was awaited.
+	migrations, applied, err := prepareMigrations(ctx, lock.conn, connection.DriverName(), true)
+	if err != nil {
+		return err
+	}
+
+	status = buildMigrationStatus(migrations, applied)
+	if status.HasDrift() {
+		return fmt.Errorf("migration is stopped: %w", driftError(status))
+	}
+
+	for index, migration := range migrations {
+		if status.Migrations[index].Applied {
+			continue
+		}
+
+		if err := applyMigration(ctx, lock.conn, connection.DriverName(), migration); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// MigrateDown rolls back the specified number of most recently applied migrations.
+// Concurrent replicas are serialized by an advisory lock awaited up to lockTimeout.
+func MigrateDown(ctx context.Context, connection *sqlx.DB, steps int, lockTimeout time.Duration) (err error) {
+	if steps <= 0 {
+		return errors.New("number of migrations to roll back should be positive")
+	}
+
+	if err := database.StatusCheck(ctx, connection); err != nil {
+		return fmt.Errorf("database is not available: %w", err)
+	}
+
+	lock, err := acquireMigrationLock(ctx, connection, lockTimeout)
+	if err != nil {
+		return err
+	}
+	defer releaseMigrationLock(lock, &err)
+
+	migrations, applied, err := prepareMigrations(ctx, lock.conn, connection.DriverName(), true)
+	if err != nil {
+		return err
+	}
+
+	if steps > len(applied) {
+		return fmt.Errorf("cannot roll back %d migrations, only %d applied", steps, len(applied))
+	}
+
+	byVersion := make(map[int]Migration, len(migrations))
+	for _, migration := range migrations {
+		byVersion[migration.Version] = migration
+	}
+
+	for index := len(applied) - 1; index >= len(applied)-steps; index-- {
+		migration, found := byVersion[applied[index].Version]
+		if !found {
+			return fmt.Errorf("migration version %d: %w", applied[index].Version, ErrorMigrationMissing)
+		}
+		if migration.Down == "" {
+			return fmt.Errorf("migration version %d: %w", migration.Version, ErrorNoDownMigration)
+		}
+
+		if err := revertMigration(ctx, lock.conn, connection.DriverName(), migration); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// QueryMigrationStatus reports the applied schema version and checksum drift of applied migrations.
+// The method does not change the database, so it is safe to call while other replica migrates.
+func QueryMigrationStatus(ctx context.Context, connection *sqlx.DB) (MigrationStatus, error) {
+	if err := database.StatusCheck(ctx, connection); err != nil {
+		return MigrationStatus{}, fmt.Errorf("database is not available: %w", err)
+	}
+
+	migrations, applied, err := prepareMigrations(ctx, connection, connection.DriverName(), false)
+	if err != nil {
+		return MigrationStatus{}, err
+	}
+
+	return buildMigrationStatus(migrations, applied), nil
+}
+
+// migrationQueryer is implemented by a connection pool and by a dedicated connection of the migration lock.
+type migrationQueryer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryRowxContext(ctx context.Context, query string, args ...interface{}) *sqlx.Row
+	SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+}
+
+// prepareMigrations returns embedded and applied migrations of the driver.
+// The migration history table is created if createHistory is set, otherwise its absence means no applied migrations.
+func prepareMigrations(ctx context.Context, connection migrationQueryer, driverName string, createHistory bool) (
+	[]Migration, []AppliedMigration, error) {
+	migrations, err := Migrations(driverName)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if createHistory {
+		const createQuery = `
+		CREATE TABLE IF NOT EXISTS SCHEMA_MIGRATION
+		(
+			version      INTEGER,
+			description  VARCHAR(255) NOT NULL,
+			checksum     VARCHAR(64)  NOT NULL,
+			date_applied TIMESTAMP    NOT NULL,
+
+			PRIMARY KEY (version)
+		)`
+
+		if _, err := connection.ExecContext(ctx, createQuery); err != nil {
+			return nil, nil, fmt.Errorf("failed to create migration history table: %w", err)
+		}
+	} else {
+		exists, err := historyTableExists(ctx, connection, driverName)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to check migration history table: %w", err)
+		}
+		if !exists {
+			return migrations, nil, nil
+		}
+	}
+
+	const selectQuery = `
+	SELECT
+		m.version,
+		m.description,
+		m.checksum,
+		m.date_applied
+	FROM
+		SCHEMA_MIGRATION AS m
+	ORDER BY m.version`
+
+	var applied []AppliedMigration
+	if err := connection.SelectContext(ctx, &applied, selectQuery); err != nil {
+		return nil, nil, fmt.Errorf("failed to read migration history: %w", err)
+	}
+
+	return migrations, applied, nil
+}
+
+// historyTableExists reports whether the migration history table was created.
+func historyTableExists(ctx context.Context, connection migrationQueryer, driverName string) (bool, error) {
+	query := `SELECT to_regclass('schema_migration') IS NOT NULL`
+	if driverName == database.DriverSQLite {
+		query = `SELECT COUNT(*) > 0 FROM sqlite_master WHERE type = 'table' AND name = 'SCHEMA_MIGRATION'`
+	}
+
+	var exists bool
+	if err := connection.QueryRowxContext(ctx, query).Scan(&exists); err != nil {
+		return false, err
+	}
+
+	return exists, nil
+}
+
+// applyMigration executes Up script and records the migration in one transaction.
+func applyMigration(ctx context.Context, conn *sqlx.Conn, driverName string, migration Migration) error {
+	record := AppliedMigration{
+		Version:     migration.Version,
+		Description: migration.Description,
+		Checksum:    migration.Checksum(),
+		DateApplied: time.Now().UTC(),
+	}
+
+	const query = `
+	INSERT INTO SCHEMA_MIGRATION
+		(version, description, checksum, date_applied)
+	VALUES
+		(:version, :description, :checksum, :date_applied)`
+
+	if err := executeMigration(ctx, conn, driverName, migration.Up, query, record); err != nil {
+		return fmt.Errorf("failed to apply migration version %d %q: %w", migration.Version,
+			migration.Description, err)
+	}
+
+	return nil
+}
+
+// revertMigration executes Down script and removes the migration record in one transaction.
+func revertMigration(ctx context.Context, conn *sqlx.Conn, driverName string, migration Migration) error {
+	const query = `
+	DELETE FROM
+		SCHEMA_MIGRATION
+	WHERE
+		version = :version`
+
+	record := AppliedMigration{Version: migration.Version}
+	if err := executeMigration(ctx, conn, driverName, migration.Down, query, record); err != nil {
+		return fmt.Errorf("failed to roll back migration version %d %q: %w", migration.Version,
+			migration.Description, err)
+	}
+
+	return nil
+}
+
+// executeMigration runs the migration script together with the history query in one transaction on the connection
+// of the migration lock.
+// SQLite cannot rebuild tables referenced by foreign keys while they are enforced, so for SQLite the enforcement is
+// suspended on the connection and integrity is verified before commit.
+func executeMigration(ctx context.Context, conn *sqlx.Conn, driverName string, script string, historyQuery string,
+	record AppliedMigration) error {
+	isSQLite := driverName == database.DriverSQLite
+	if isSQLite {
+		if _, err := conn.ExecContext(ctx, "PRAGMA foreign_keys = OFF"); err != nil {
+			return err
+		}
+		defer conn.ExecContext(context.Background(), "PRAGMA foreign_keys = ON")
+	}
+
+	transaction, err := conn.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	if _, err := transaction.ExecContext(ctx, script); err != nil {
+		_ = transaction.Rollback()
+		return err
+	}
+
+	if isSQLite {
+		var violations []struct {
+			Table  string        `db:"table"`
+			RowID  sql.NullInt64 `db:"rowid"`
+			Parent string        `db:"parent"`
+			FKID   int           `db:"fkid"`
+		}
+		if err := transaction.SelectContext(ctx, &violations, "PRAGMA foreign_key_check"); err != nil {
+			_ = transaction.Rollback()
+			return err
+		}
+		if len(violations) > 0 {
+			_ = transaction.Rollback()
+			return fmt.Errorf("%w: table %s references missing %s", database.ErrorInvalidReference,
+				violations[0].Table, violations[0].Parent)
+		}
+	}
+
+	if _, err := transaction.NamedExecContext(ctx, historyQuery, record); err != nil {
+		_ = transaction.Rollback()
+		return fmt.Errorf("failed to update migration history: %w", err)
+	}
+
+	return transaction.Commit()
+}
+
+// buildMigrationStatus compares embedded migrations with applied ones.
+func buildMigrationStatus(migrations []Migration, applied []AppliedMigration) MigrationStatus {
+	appliedByVersion := make(map[int]AppliedMigration, len(applied))
+	for _, record := range applied {
+		appliedByVersion[record.Version] = record
+	}
+
+	status := MigrationStatus{
+		Migrations: make([]MigrationState, 0, len(migrations)),
+	}
+
+	known := make(map[int]bool, len(migrations))
+	for _, migration := range migrations {
+		known[migration.Version] = true
+		status.LatestVersion = migration.Version
+
+		state := MigrationState{
+			Version:     migration.Version,
+			Description: migration.Description,
+			Checksum:    migration.Checksum(),
+		}
+
+		if record, found := appliedByVersion[migration.Version]; found {
+			dateApplied := record.DateApplied
+			state.Applied = true
+			state.DateApplied = &dateApplied
+			state.AppliedChecksum = record.Checksum
+			state.Drift = record.Checksum != state.Checksum
+		}
+
+		status.Migrations = append(status.Migrations, state)
+	}
+
+	for _, record := range applied {
+		if record.Version > status.CurrentVersion {
+			status.CurrentVersion = record.Version
+		}
+		if !known[record.Version] {
+			status.Missing = append(status.Missing, record.Version)
+		}
+	}
+
+	return status
+}
+
+// driftError describes the first inconsistency between applied and embedded migrations.
+func driftError(status MigrationStatus) error {
+	if len(status.Missing) > 0 {
+		return fmt.Errorf("migration version %d: %w", status.Missing[0], ErrorMigrationMissing)
+	}
+
+	for _, state := range status.Migrations {
+		if state.Drift {
+			return fmt.Errorf("migration version %d: %w", state.Version, ErrorMigrationDrift)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,397 @@
+package database
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/SKorolchuk/dpio-workspace/internal/pkg/infra/database"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// openTestDatabase opens an empty SQLite database file which is removed after the test.
+func openTestDatabase(t *testing.T) *sqlx.DB {
+	t.Helper()
+
+	connection, err := database.Open(database.DbConfig{Driver: database.DriverSQLite,
+		DatabaseName: filepath.Join(t.TempDir(), "workspace.db")})
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	t.Cleanup(func() { _ = database.Close(connection) })
+
+	return connection
+}
+
+func TestLoadMigrations(t *testing.T) {
+	file := func(content string) *fstest.MapFile {
+		return &fstest.MapFile{Data: []byte(content)}
+	}
+
+	tests := []struct {
+		name         string
+		files        fstest.MapFS
+		wantVersions []int
+		wantErr      bool
+	}{
+		{
+			name: "ordered by version",
+			files: fstest.MapFS{
+				"m/0010_later.up.sql":   file("CREATE TABLE B (id TEXT);"),
+				"m/0002_first.up.sql":   file("CREATE TABLE A (id TEXT);"),
+				"m/0002_first.down.sql": file("DROP TABLE A;"),
+			},
+			wantVersions: []int{2, 10},
+		},
+		{
+			name: "unrelated files are skipped",
+			files: fstest.MapFS{
+				"m/0001_initial.up.sql":  file("CREATE TABLE A (id TEXT);"),
+				"m/README.md":            file("# migrations"),
+				"m/0002_draft.sql":       file("SELECT 1;"),
+				"m/nested/0003_x.up.sql": file("SELECT 1;"),
+			},
+			wantVersions: []int{1},
+		},
+		{
+			name: "missing up script",
+			files: fstest.MapFS{
+				"m/0001_initial.down.sql": file("DROP TABLE A;"),
+			},
+			wantErr: true,
+		},
+		{
+			name: "several descriptions of a version",
+			files: fstest.MapFS{
+				"m/0001_initial.up.sql": file("CREATE TABLE A (id TEXT);"),
+				"m/0001_other.down.sql": file("DROP TABLE A;"),
+			},
+			wantErr: true,
+		},
+		{
+			name: "zero version",
+			files: fstest.MapFS{
+				"m/0000_initial.up.sql": file("CREATE TABLE A (id TEXT);"),
+			},
+			wantErr: true,
+		},
+		{
+			name:    "missing directory",
+			files:   fstest.MapFS{},
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			migrations, err := loadMigrations(test.files, "m")
+			if (err != nil) != test.wantErr {
+				t.Fatalf("loadMigrations() error = %v, want error %t", err, test.wantErr)
+			}
+
+			var versions []int
+			for _, migration := range migrations {
+				versions = append(versions, migration.Version)
+			}
+			if len(versions) != len(test.wantVersions) {
+				t.Fatalf("loadMigrations() versions = %v, want %v", versions, test.wantVersions)
+			}
+			for i := range versions {
+				if versions[i] != test.wantVersions[i] {
+					t.Errorf("loadMigrations() versions = %v, want %v", versions, test.wantVersions)
+				}
+			}
+		})
+	}
+}
+
+func TestMigrations(t *testing.T) {
+	tests := []struct {
+		driverName string
+		wantErr    bool
+	}{
+		{driverName: database.DriverPostgres},
+		{driverName: database.DriverSQLite},
+		{driverName: "mysql", wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.driverName, func(t *testing.T) {
+			migrations, err := Migrations(test.driverName)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("Migrations() error = %v, want error %t", err, test.wantErr)
+			}
+			if test.wantErr {
+				return
+			}
+
+			if len(migrations) == 0 {
+				t.Fatalf("Migrations() returned no migrations")
+			}
+			for i, migration := range migrations {
+				if migration.Version != i+1 {
+					t.Errorf("migration %q has version %d, want %d", migration.Description, migration.Version, i+1)
+				}
+				if migration.Down == "" {
+					t.Errorf("migration version %d has no down script", migration.Version)
+				}
+			}
+		})
+	}
+}
+
+func TestMigrationChecksum(t *testing.T) {
+	migration := Migration{Version: 1, Description: "initial", Up: "CREATE TABLE A (id TEXT);", Down: "DROP TABLE A;"}
+	hash := sha256.Sum256([]byte(migration.Up))
+
+	if got, want := migration.Checksum(), hex.EncodeToString(hash[:]); got != want {
+		t.Errorf("Checksum() = %q, want %q", got, want)
+	}
+
+	changedDown := migration
+	changedDown.Down = "DROP TABLE IF EXISTS A;"
+	if changedDown.Checksum() != migration.Checksum() {
+		t.Errorf("Checksum() depends on the down script")
+	}
+
+	changedUp := migration
+	changedUp.Up = "CREATE TABLE A (id TEXT NOT NULL);"
+	if changedUp.Checksum() == migration.Checksum() {
+		t.Errorf("Checksum() does not depend on the up script")
+	}
+}
+
+func TestBuildMigrationStatus(t *testing.T) {
+	migrations := []Migration{
+		{Version: 1, Description: "initial", Up: "CREATE TABLE A (id TEXT);"},
+		{Version: 2, Description: "second", Up: "CREATE TABLE B (id TEXT);"},
+	}
+	applied := func(version int, checksum string) AppliedMigration {
+		return AppliedMigration{Version: version, Checksum: checksum, DateApplied: time.Now()}
+	}
+
+	tests := []struct {
+		name        string
+		applied     []AppliedMigration
+		wantCurrent int
+		wantIsCur   bool
+		wantDrift   bool
+		wantErr     error
+	}{
+		{
+			name:        "empty database",
+			wantCurrent: 0,
+		},
+		{
+			name:        "pending migration",
+			applied:     []AppliedMigration{applied(1, migrations[0].Checksum())},
+			wantCurrent: 1,
+		},
+		{
+			name:        "all applied",
+			applied:     []AppliedMigration{applied(1, migrations[0].Checksum()), applied(2, migrations[1].Checksum())},
+			wantCurrent: 2,
+			wantIsCur:   true,
+		},
+		{
+			name:        "changed script",
+			applied:     []AppliedMigration{applied(1, "changed"), applied(2, migrations[1].Checksum())},
+			wantCurrent: 2,
+			wantIsCur:   true,
+			wantDrift:   true,
+			wantErr:     ErrorMigrationDrift,
+		},
+		{
+			name: "unknown applied migration",
+			applied: []AppliedMigration{applied(1, migrations[0].Checksum()), applied(2, migrations[1].Checksum()),
+				applied(3, "removed")},
+			wantCurrent: 3,
+			wantIsCur:   true,
+			wantDrift:   true,
+			wantErr:     ErrorMigrationMissing,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			status := buildMigrationStatus(migrations, test.applied)
+
+			if status.CurrentVersion != test.wantCurrent {
+				t.Errorf("CurrentVersion = %d, want %d", status.CurrentVersion, test.wantCurrent)
+			}
+			if status.LatestVersion != 2 {
+				t.Errorf("LatestVersion = %d, want 2", status.LatestVersion)
+			}
+			if status.IsCurrent() != test.wantIsCur {
+				t.Errorf("IsCurrent() = %t, want %t", status.IsCurrent(), test.wantIsCur)
+			}
+			if status.HasDrift() != test.wantDrift {
+				t.Errorf("HasDrift() = %t, want %t", status.HasDrift(), test.wantDrift)
+			}
+			if err := driftError(status); !errors.Is(err, test.wantErr) {
+				t.Errorf("driftError() = %v, want %v", err, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestMigrateSQLite(t *testing.T) {
+	ctx := context.Background()
+	connection := openTestDatabase(t)
+
+	migrations, err := Migrations(database.DriverSQLite)
+	if err != nil {
+		t.Fatalf("Migrations() error = %v", err)
+	}
+
+	for _, step := range []string{"up", "down", "up again"} {
+		if step == "down" {
+			err = MigrateDown(ctx, connection, len(migrations), DefaultMigrationLockTimeout)
+		} else {
+			err = MigrateUp(ctx, connection, DefaultMigrationLockTimeout)
+		}
+		if err != nil {
+			t.Fatalf("migrate %s error = %v", step, err)
+		}
+	}
+
+	status, err := QueryMigrationStatus(ctx, connection)
+	if err != nil {
+		t.Fatalf("QueryMigrationStatus() error = %v", err)
+	}
+	if !status.IsCurrent() || status.HasDrift() {
+		t.Errorf("migration status = %+v, want current version without drift", status)
+	}
+}
+
+func TestMigrateProjectOwnerIdentifiers(t *testing.T) {
+	const (
+		projectID = "0a1b2c3d-4e5f-4a6b-8c7d-8e9fa0b1c2d3"
+		creatorID = "11111111-2222-4333-8444-555555555555"
+		ownerID   = "66666666-7777-4888-8999-aaaaaaaaaaaa"
+	)
+	ctx := context.Background()
+	connection := openTestDatabase(t)
+
+	if err := MigrateUp(ctx, connection, DefaultMigrationLockTimeout); err != nil {
+		t.Fatalf("MigrateUp() error = %v", err)
+	}
+	migrations, err := Migrations(database.DriverSQLite)
+	if err != nil {
+		t.Fatalf("Migrations() error = %v", err)
+	}
+	// Owners are inserted right before migration 0011 which re-keys them.
+	if err := MigrateDown(ctx, connection, len(migrations)-10, DefaultMigrationLockTimeout); err != nil {
+		t.Fatalf("MigrateDown() error = %v", err)
+	}
+
+	insertQueries := []string{
+		`INSERT INTO PROJECT_COLLABORATION_TYPE (project_collaboration_type_id, name) VALUES ('t', 'Type')`,
+		`INSERT INTO PROJECT (project_id, project_collaboration_type_id, name, description, date_created,
+			created_by_user_id, date_updated, updated_by_user_id)
+		VALUES (?1, 't', 'Project', '', CURRENT_TIMESTAMP, ?2, CURRENT_TIMESTAMP, ?2)`,
+		`INSERT INTO PROJECT_OWNER (project_owner_id, project_id, user_id, date_created, created_by_user_id)
+		VALUES (lower(hex(randomblob(16))), ?1, ?2, CURRENT_TIMESTAMP, ?2)`,
+		`INSERT INTO PROJECT_OWNER (project_owner_id, project_id, user_id, date_created, created_by_user_id)
+		VALUES (?3, ?1, ?3, CURRENT_TIMESTAMP, ?2)`,
+	}
+	for index, query := range insertQueries {
+		if _, err := connection.ExecContext(ctx, query, projectID, creatorID, ownerID); err != nil {
+			t.Fatalf("ExecContext() of statement #%d error = %v", index+1, err)
+		}
+	}
+
+	if err := MigrateUp(ctx, connection, DefaultMigrationLockTimeout); err != nil {
+		t.Fatalf("MigrateUp() error = %v", err)
+	}
+
+	var creatorOwnerID string
+	const ownerQuery = `SELECT project_owner_id FROM PROJECT_OWNER WHERE user_id = $1`
+	if err := connection.GetContext(ctx, &creatorOwnerID, ownerQuery, creatorID); err != nil {
+		t.Fatalf("GetContext() error = %v", err)
+	}
+	if want := "8e9fa0b1-c2d3-8c7d-4a6b-0a1b2c3d4e5f"; creatorOwnerID != want {
+		t.Errorf("identifier of creator owner = %q, want %q", creatorOwnerID, want)
+	}
+
+	var otherOwnerID string
+	if err := connection.GetContext(ctx, &otherOwnerID, ownerQuery, ownerID); err != nil {
+		t.Fatalf("GetContext() error = %v", err)
+	}
+	if otherOwnerID != ownerID {
+		t.Errorf("identifier of other owner = %q, want unchanged %q", otherOwnerID, ownerID)
+	}
+}
+
+func TestMigrateCascadeDeletes(t *testing.T) {
+	ctx := context.Background()
+	connection := openTestDatabase(t)
+
+	if err := MigrateUp(ctx, connection, DefaultMigrationLockTimeout); err != nil {
+		t.Fatalf("MigrateUp() error = %v", err)
+	}
+
+	insertQueries := []string{
+		`INSERT INTO PROJECT_COLLABORATION_TYPE (project_collaboration_type_id, name) VALUES ('t', 'Type')`,
+		`INSERT INTO STEM (stem_id, name) VALUES ('s', 'Stem')`,
+		`INSERT INTO PROJECT (project_id, project_collaboration_type_id, name, description, date_created,
+			created_by_user_id, date_updated, updated_by_user_id)
+		VALUES ('p', 't', 'Project', '', CURRENT_TIMESTAMP, 'u', CURRENT_TIMESTAMP, 'u')`,
+		`INSERT INTO PROJECT_OWNER (project_owner_id, project_id, user_id, date_created, created_by_user_id)
+		VALUES ('o', 'p', 'u', CURRENT_TIMESTAMP, 'u')`,
+		`INSERT INTO PROJECT_GROUP (project_group_id, name, date_created, created_by_user_id, date_updated,
+			updated_by_user_id)
+		VALUES ('g', 'Group', CURRENT_TIMESTAMP, 'u', CURRENT_TIMESTAMP, 'u')`,
+		`INSERT INTO PROJECT_GROUP_ACCESS (project_group_access_id, project_id, project_group_id, date_created,
+			created_by_user_id)
+		VALUES ('ga', 'p', 'g', CURRENT_TIMESTAMP, 'u')`,
+		`INSERT INTO PROJECT_ACCESS_REQUEST (project_access_request_id, project_id, user_id, status,
+			project_group_id, date_created)
+		VALUES ('r', 'p', 'u2', 'APPROVED', 'g', CURRENT_TIMESTAMP)`,
+		`INSERT INTO WORKSPACE (workspace_id, project_id, stem_id, name, description, asset_amount_limit, x_max, y_max,
+			z_max, date_created, created_by_user_id, date_updated, updated_by_user_id)
+		VALUES ('w', 'p', 's', 'Workspace', '', 1, 1, 1, 0, CURRENT_TIMESTAMP, 'u', CURRENT_TIMESTAMP, 'u')`,
+		`INSERT INTO ASSET (asset_id, workspace_id, asset_external_ref_id, position_x, position_y, position_z, scale,
+			height_by_y, width_by_x, length_by_z, date_created, created_by_user_id, date_updated, updated_by_user_id)
+		VALUES ('a', 'w', 'ref', 0, 0, 0, 1, 1, 1, 1, CURRENT_TIMESTAMP, 'u', CURRENT_TIMESTAMP, 'u')`,
+	}
+	for index, query := range insertQueries {
+		if _, err := connection.ExecContext(ctx, query); err != nil {
+			t.Fatalf("ExecContext() of statement #%d error = %v", index+1, err)
+		}
+	}
+
+	count := func(query string) int {
+		var value int
+		if err := connection.GetContext(ctx, &value, query); err != nil {
+			t.Fatalf("GetContext() error = %v", err)
+		}
+		return value
+	}
+
+	if _, err := connection.ExecContext(ctx, `DELETE FROM PROJECT_GROUP WHERE project_group_id = 'g'`); err != nil {
+		t.Fatalf("delete of group error = %v", err)
+	}
+	if got := count(`SELECT COUNT(*) FROM PROJECT_GROUP_ACCESS`); got != 0 {
+		t.Errorf("group accesses after delete of group = %d, want 0", got)
+	}
+	if got := count(`SELECT COUNT(*) FROM PROJECT_ACCESS_REQUEST WHERE project_group_id IS NULL`); got != 1 {
+		t.Errorf("access requests without group after delete of group = %d, want 1", got)
+	}
+
+	if _, err := connection.ExecContext(ctx, `DELETE FROM PROJECT WHERE project_id = 'p'`); err != nil {
+		t.Fatalf("delete of project error = %v", err)
+	}
+	tables := []string{"PROJECT_OWNER", "PROJECT_ACCESS_REQUEST", "WORKSPACE", "ASSET", "WORKSPACE_SEARCH"}
+	for _, table := range tables {
+		if got := count(`SELECT COUNT(*) FROM ` + table); got != 0 {
+			t.Errorf("rows of %s after delete of project = %d, want 0", table, got)
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/SKorolchuk/dpio-workspace/internal/pkg/infra/database"
+	"github.com/SKorolchuk/dpio-workspace/internal/pkg/store/workspace"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// DefaultDeletedRetention is the period during which soft-deleted projects and workspaces can be restored before
+// Purge removes them.
+const DefaultDeletedRetention = 30 * 24 * time.Hour
+
+// PurgeResult contains amounts of entities removed by Purge and external asset references which are no longer used
+// by any Asset entity, so the asset storage can collect their binaries.
+type PurgeResult struct {
+	Projects   int64    `json:"projects"`
+	Workspaces int64    `json:"workspaces"`
+	Assets     int64    `json:"assets"`
+	AssetRefs  []string `json:"assetRefs"`
+}
+
+// Purge permanently removes projects and workspaces which were deleted before now minus retention, together with
+// their assets. Purged projects take along all their workspaces, owners, group accesses, invitations and access
+// requests.
+// All entities are removed in a single transaction, so a failed purge leaves the database unchanged. Orphaned
+// external asset references are taken from the removed asset rows, so the report matches the removal.
+func Purge(ctx context.Context, connection *sqlx.DB, retention time.Duration, now time.Time) (PurgeResult, error) {
+	if err := database.StatusCheck(ctx, connection); err != nil {
+		return PurgeResult{}, fmt.Errorf("database is not available: %w", err)
+	}
+
+	if retention < 0 {
+		return PurgeResult{}, fmt.Errorf("retention period should not be negative -> retention={%s}", retention)
+	}
+
+	queryParams := struct {
+		Before time.Time `db:"before"`
+	}{
+		Before: now.Add(-retention),
+	}
+
+	const purgedWorkspaces = `
+		SELECT w.workspace_id
+		FROM WORKSPACE AS w
+		JOIN PROJECT AS p ON p.project_id = w.project_id
+		WHERE w.date_deleted < :before OR p.date_deleted < :before`
+
+	const purgedProjects = `
+		SELECT p.project_id
+		FROM PROJECT AS p
+		WHERE p.date_deleted < :before`
+
+	const deleteAssetsQuery = `
+	DELETE FROM ASSET
+	WHERE workspace_id IN (` + purgedWorkspaces + `)
+	RETURNING asset_external_ref_id`
+
+	const deleteWorkspacesQuery = `
+	DELETE FROM WORKSPACE
+	WHERE workspace_id IN (` + purgedWorkspaces + `)`
+
+	var result PurgeResult
+	err := database.WithTransaction(ctx, connection, func(transaction *sqlx.Tx) error {
+		removedRefs, err := purgeAssets(ctx, transaction, deleteAssetsQuery, queryParams)
+		if err != nil {
+			return err
+		}
+		result.Assets = int64(len(removedRefs))
+		if result.AssetRefs, err = workspace.UnusedAssetRefs(ctx, transaction, removedRefs); err != nil {
+			return err
+		}
+		if result.Workspaces, err = purgeRows(ctx, transaction, deleteWorkspacesQuery, queryParams); err != nil {
+			return err
+		}
+
+		for _, table := range []string{"PROJECT_OWNER", "PROJECT_INVITATION", "PROJECT_ACCESS_REQUEST",
+			"PROJECT_GROUP_ACCESS"} {
+			query := `
+			DELETE FROM ` + table + `
+			WHERE project_id IN (` + purgedProjects + `)`
+			if _, err := purgeRows(ctx, transaction, query, queryParams); err != nil {
+				return err
+			}
+		}
+
+		const deleteProjectsQuery = `
+		DELETE FROM PROJECT
+		WHERE date_deleted < :before`
+
+		result.Projects, err = purgeRows(ctx, transaction, deleteProjectsQuery, queryParams)
+		return err
+	})
+	if err != nil {
+		return PurgeResult{}, fmt.Errorf("error during purge of deleted entities -> before={%s}: %w",
+			queryParams.Before.Format(time.RFC3339), err)
+	}
+
+	return result, nil
+}
+
+// purgeRows executes delete query and returns amount of removed rows.
+func purgeRows(ctx context.Context, transaction *sqlx.Tx, query string, queryParams interface{}) (int64, error) {
+	result, err := transaction.NamedExecContext(ctx, query, queryParams)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
+
+// purgeAssets executes delete query which returns external asset references of removed assets.
+func purgeAssets(ctx context.Context, transaction *sqlx.Tx, query string, queryParams interface{}) ([]string,
+	error) {
+	statement, err := transaction.PrepareNamedContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer statement.Close()
+
+	removedRefs := []string{}
+	if err := statement.SelectContext(ctx, &removedRefs, queryParams); err != nil {
+		return nil, err
+	}
+
+	return removedRefs, nil
+}
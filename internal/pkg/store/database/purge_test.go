@@ -0,0 +1,82 @@
+package database
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestPurgeSQLite(t *testing.T) {
+	connection := openTestDatabase(t)
+	ctx := context.Background()
+	now := time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	if err := Migrate(ctx, connection); err != nil {
+		t.Fatalf("Migrate() error = %v", err)
+	}
+
+	fixture, err := GenerateFixture(GeneratorOptions{Projects: 2, WorkspacesPerProject: 1, AssetsPerWorkspace: 2,
+		RandomSeed: 3, Now: now.Add(-60 * 24 * time.Hour)})
+	if err != nil {
+		t.Fatalf("GenerateFixture() error = %v", err)
+	}
+
+	// The first workspace uses "unique" and "shared" references, the workspace of the second project uses "shared"
+	// and "other" ones.
+	assetRefs := map[string][]string{
+		fixture.Workspaces[0].ID: {"unique", "shared"},
+		fixture.Workspaces[1].ID: {"shared", "other"},
+	}
+	for i := range fixture.Assets {
+		wsId := fixture.Assets[i].WorkspaceID
+		fixture.Assets[i].AssetRefID, assetRefs[wsId] = assetRefs[wsId][0], assetRefs[wsId][1:]
+	}
+	if err := SeedFixture(ctx, connection, fixture); err != nil {
+		t.Fatalf("SeedFixture() error = %v", err)
+	}
+
+	deleted := []struct {
+		query string
+		id    string
+		date  time.Time
+	}{
+		{query: `UPDATE WORKSPACE SET date_deleted = $1 WHERE workspace_id = $2`, id: fixture.Workspaces[0].ID,
+			date: now.Add(-40 * 24 * time.Hour)},
+		{query: `UPDATE PROJECT SET date_deleted = $1 WHERE project_id = $2`, id: fixture.Workspaces[1].ProjectID,
+			date: now.Add(-10 * 24 * time.Hour)},
+	}
+	for _, entity := range deleted {
+		if _, err := connection.ExecContext(ctx, entity.query, entity.date, entity.id); err != nil {
+			t.Fatalf("ExecContext() error = %v", err)
+		}
+	}
+
+	tests := []struct {
+		name      string
+		retention time.Duration
+		want      PurgeResult
+	}{
+		{name: "workspace deleted before retention", retention: DefaultDeletedRetention,
+			want: PurgeResult{Workspaces: 1, Assets: 2, AssetRefs: []string{"unique"}}},
+		{name: "project with its workspace", retention: 0,
+			want: PurgeResult{Projects: 1, Workspaces: 1, Assets: 2, AssetRefs: []string{"other", "shared"}}},
+		{name: "nothing to purge", retention: 0, want: PurgeResult{AssetRefs: []string{}}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result, err := Purge(ctx, connection, test.retention, now)
+			if err != nil {
+				t.Fatalf("Purge() error = %v", err)
+			}
+			if !reflect.DeepEqual(result, test.want) {
+				t.Errorf("Purge() = %+v, want %+v", result, test.want)
+			}
+		})
+	}
+
+	if _, err := Purge(ctx, connection, -time.Hour, now); err == nil {
+		t.Errorf("Purge() error = nil, want error of negative retention")
+	}
+}
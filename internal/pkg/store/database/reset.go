@@ -0,0 +1,92 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/SKorolchuk/dpio-workspace/internal/pkg/infra/database"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// Environments where database reset is allowed without confirmation.
+const (
+	EnvironmentDevelopment = "development"
+	EnvironmentTest        = "test"
+)
+
+// ErrorResetForbidden is returned when reset or drop is requested outside of development or test environment without a
+// valid confirmation token.
+var ErrorResetForbidden = errors.New("database reset is not allowed in this environment")
+
+// ResetGuard describes conditions which allow destructive reset of the database.
+type ResetGuard struct {
+	// Environment is the name of the environment the database belongs to, e.g. "development" or "production".
+	Environment string
+
+	// ConfirmationToken should match the name of the connected database to reset it in other environments.
+	ConfirmationToken string
+}
+
+// Reset removes all data from workspace database and loads the seed preset again.
+// The reset is refused with ErrorResetForbidden unless guard allows it for the connected database.
+// If a statement fails, the whole reset is rolled back and the original error is returned.
+func Reset(ctx context.Context, connection *sqlx.DB, guard ResetGuard) error {
+	if err := checkResetGuard(ctx, connection, guard, "reset"); err != nil {
+		return err
+	}
+
+	return executeScript(ctx, connection, "reset", workspaceDropScript+"\n"+workspaceSeedScript)
+}
+
+// checkResetGuard returns ErrorResetForbidden if guard does not allow destructive operation on connected database.
+func checkResetGuard(ctx context.Context, connection *sqlx.DB, guard ResetGuard, operation string) error {
+	if err := database.StatusCheck(ctx, connection); err != nil {
+		return fmt.Errorf("database is not available: %w", err)
+	}
+
+	databaseName, err := databaseNameOf(ctx, connection)
+	if err != nil {
+		return fmt.Errorf("error during lookup of database name: %w", err)
+	}
+
+	if !guard.allows(databaseName) {
+		return fmt.Errorf("error during %s of database -> name={%q}, environment={%q}: %w", operation, databaseName,
+			guard.Environment, ErrorResetForbidden)
+	}
+
+	return nil
+}
+
+// allows reports whether reset of the database with specified name is allowed.
+func (guard ResetGuard) allows(databaseName string) bool {
+	switch strings.ToLower(strings.TrimSpace(guard.Environment)) {
+	case EnvironmentDevelopment, "dev", EnvironmentTest:
+		return true
+	}
+
+	return guard.ConfirmationToken != "" && guard.ConfirmationToken == databaseName
+}
+
+// databaseNameOf returns the name of connected database.
+// For SQLite the name is the database file name without directory.
+func databaseNameOf(ctx context.Context, connection *sqlx.DB) (string, error) {
+	var name string
+
+	if connection.DriverName() == database.DriverSQLite {
+		const query = `SELECT file FROM pragma_database_list WHERE name = 'main'`
+		if err := connection.QueryRowxContext(ctx, query).Scan(&name); err != nil {
+			return "", err
+		}
+		return filepath.Base(name), nil
+	}
+
+	if err := connection.QueryRowxContext(ctx, `SELECT current_database()`).Scan(&name); err != nil {
+		return "", err
+	}
+
+	return name, nil
+}
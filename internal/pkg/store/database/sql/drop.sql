@@ -1,20 +1,26 @@
 DELETE
-FROM PROJECT_COLLABORATION_TYPE;
+FROM ASSET;
 DELETE
-FROM PROJECT;
+FROM WORKSPACE;
 DELETE
-FROM PROJECT_ROLE;
+FROM STEM;
 DELETE
-FROM PROJECT_GROUP;
+FROM PROJECT_OWNER;
 DELETE
-FROM PROJECT_GROUP_ROLE;
+FROM PROJECT_ACCESS_REQUEST;
 DELETE
-FROM PROJECT_GROUP_USER;
+FROM PROJECT_INVITATION;
 DELETE
 FROM PROJECT_GROUP_ACCESS;
 DELETE
-FROM STEM;
+FROM PROJECT_GROUP_USER;
 DELETE
-FROM WORKSPACE;
+FROM PROJECT_GROUP_ROLE;
 DELETE
-FROM ASSET;
+FROM PROJECT_GROUP;
+DELETE
+FROM PROJECT_ROLE;
+DELETE
+FROM PROJECT;
+DELETE
+FROM PROJECT_COLLABORATION_TYPE;
@@ -0,0 +1,116 @@
+DROP INDEX IF EXISTS ix_asset_workspace_date_updated;
+DROP INDEX IF EXISTS ix_workspace_project_stem_date_updated;
+DROP INDEX IF EXISTS ix_workspace_date_updated;
+
+ALTER TABLE ASSET
+    DROP CONSTRAINT IF EXISTS ck_asset_length_by_z,
+    DROP CONSTRAINT IF EXISTS ck_asset_width_by_x,
+    DROP CONSTRAINT IF EXISTS ck_asset_height_by_y,
+    DROP CONSTRAINT IF EXISTS ck_asset_scale,
+    DROP CONSTRAINT IF EXISTS ck_asset_position_z,
+    DROP CONSTRAINT IF EXISTS ck_asset_position_y,
+    DROP CONSTRAINT IF EXISTS ck_asset_position_x,
+    ALTER COLUMN workspace_id DROP NOT NULL,
+    ALTER COLUMN asset_external_ref_id DROP NOT NULL,
+    ALTER COLUMN position_x DROP NOT NULL,
+    ALTER COLUMN position_x TYPE numeric,
+    ALTER COLUMN position_y DROP NOT NULL,
+    ALTER COLUMN position_y TYPE numeric,
+    ALTER COLUMN position_z DROP NOT NULL,
+    ALTER COLUMN position_z TYPE numeric,
+    ALTER COLUMN scale DROP NOT NULL,
+    ALTER COLUMN scale TYPE numeric,
+    ALTER COLUMN height_by_y DROP NOT NULL,
+    ALTER COLUMN height_by_y TYPE numeric,
+    ALTER COLUMN width_by_x DROP NOT NULL,
+    ALTER COLUMN width_by_x TYPE numeric,
+    ALTER COLUMN length_by_z DROP NOT NULL,
+    ALTER COLUMN length_by_z TYPE numeric,
+    ALTER COLUMN date_created DROP NOT NULL,
+    ALTER COLUMN date_created TYPE timestamp USING date_created AT TIME ZONE 'UTC',
+    ALTER COLUMN created_by_user_id DROP NOT NULL,
+    ALTER COLUMN date_updated DROP NOT NULL,
+    ALTER COLUMN date_updated TYPE timestamp USING date_updated AT TIME ZONE 'UTC',
+    ALTER COLUMN updated_by_user_id DROP NOT NULL;
+
+ALTER TABLE WORKSPACE
+    ALTER COLUMN description DROP DEFAULT,
+    DROP CONSTRAINT IF EXISTS ck_workspace_z_max,
+    DROP CONSTRAINT IF EXISTS ck_workspace_y_max,
+    DROP CONSTRAINT IF EXISTS ck_workspace_x_max,
+    DROP CONSTRAINT IF EXISTS ck_workspace_asset_amount_limit,
+    DROP CONSTRAINT IF EXISTS ck_workspace_name,
+    ALTER COLUMN project_id DROP NOT NULL,
+    ALTER COLUMN stem_id DROP NOT NULL,
+    ALTER COLUMN name DROP NOT NULL,
+    ALTER COLUMN description DROP NOT NULL,
+    ALTER COLUMN asset_amount_limit DROP NOT NULL,
+    ALTER COLUMN asset_amount_limit TYPE numeric,
+    ALTER COLUMN x_max DROP NOT NULL,
+    ALTER COLUMN x_max TYPE numeric,
+    ALTER COLUMN y_max DROP NOT NULL,
+    ALTER COLUMN y_max TYPE numeric,
+    ALTER COLUMN z_max DROP NOT NULL,
+    ALTER COLUMN z_max TYPE numeric,
+    ALTER COLUMN date_created DROP NOT NULL,
+    ALTER COLUMN date_created TYPE timestamp USING date_created AT TIME ZONE 'UTC',
+    ALTER COLUMN created_by_user_id DROP NOT NULL,
+    ALTER COLUMN date_updated DROP NOT NULL,
+    ALTER COLUMN date_updated TYPE timestamp USING date_updated AT TIME ZONE 'UTC',
+    ALTER COLUMN updated_by_user_id DROP NOT NULL;
+
+ALTER TABLE STEM
+    DROP CONSTRAINT IF EXISTS ck_stem_name,
+    ALTER COLUMN name DROP NOT NULL;
+
+ALTER TABLE PROJECT_GROUP_ACCESS
+    ALTER COLUMN project_id DROP NOT NULL,
+    ALTER COLUMN project_group_id DROP NOT NULL,
+    ALTER COLUMN date_created DROP NOT NULL,
+    ALTER COLUMN date_created TYPE timestamp USING date_created AT TIME ZONE 'UTC',
+    ALTER COLUMN created_by_user_id DROP NOT NULL;
+
+ALTER TABLE PROJECT_GROUP_USER
+    ALTER COLUMN project_group_id DROP NOT NULL,
+    ALTER COLUMN user_id DROP NOT NULL,
+    ALTER COLUMN date_created DROP NOT NULL,
+    ALTER COLUMN date_created TYPE timestamp USING date_created AT TIME ZONE 'UTC',
+    ALTER COLUMN created_by_user_id DROP NOT NULL;
+
+ALTER TABLE PROJECT_GROUP_ROLE
+    ALTER COLUMN project_group_id DROP NOT NULL,
+    ALTER COLUMN project_role_id DROP NOT NULL,
+    ALTER COLUMN date_created DROP NOT NULL,
+    ALTER COLUMN date_created TYPE timestamp USING date_created AT TIME ZONE 'UTC',
+    ALTER COLUMN created_by_user_id DROP NOT NULL;
+
+ALTER TABLE PROJECT_GROUP
+    DROP CONSTRAINT IF EXISTS ck_project_group_name,
+    ALTER COLUMN name DROP NOT NULL,
+    ALTER COLUMN date_created DROP NOT NULL,
+    ALTER COLUMN date_created TYPE timestamp USING date_created AT TIME ZONE 'UTC',
+    ALTER COLUMN created_by_user_id DROP NOT NULL,
+    ALTER COLUMN date_updated DROP NOT NULL,
+    ALTER COLUMN date_updated TYPE timestamp USING date_updated AT TIME ZONE 'UTC',
+    ALTER COLUMN updated_by_user_id DROP NOT NULL;
+
+ALTER TABLE PROJECT_ROLE
+    DROP CONSTRAINT IF EXISTS ck_project_role_name,
+    ALTER COLUMN name DROP NOT NULL;
+
+ALTER TABLE PROJECT
+    ALTER COLUMN description DROP DEFAULT,
+    DROP CONSTRAINT IF EXISTS ck_project_name,
+    ALTER COLUMN project_collaboration_type_id DROP NOT NULL,
+    ALTER COLUMN name DROP NOT NULL,
+    ALTER COLUMN description DROP NOT NULL,
+    ALTER COLUMN date_created DROP NOT NULL,
+    ALTER COLUMN date_created TYPE timestamp USING date_created AT TIME ZONE 'UTC',
+    ALTER COLUMN created_by_user_id DROP NOT NULL,
+    ALTER COLUMN date_updated DROP NOT NULL,
+    ALTER COLUMN date_updated TYPE timestamp USING date_updated AT TIME ZONE 'UTC',
+    ALTER COLUMN updated_by_user_id DROP NOT NULL;
+
+ALTER TABLE PROJECT_COLLABORATION_TYPE
+    DROP CONSTRAINT IF EXISTS ck_project_collaboration_type_name,
+    ALTER COLUMN name DROP NOT NULL;
@@ -0,0 +1,119 @@
+UPDATE PROJECT SET description = '' WHERE description IS NULL;
+UPDATE WORKSPACE SET description = '' WHERE description IS NULL;
+
+ALTER TABLE PROJECT_COLLABORATION_TYPE
+    ALTER COLUMN name SET NOT NULL,
+    ADD CONSTRAINT ck_project_collaboration_type_name CHECK (name <> '');
+
+ALTER TABLE PROJECT
+    ALTER COLUMN project_collaboration_type_id SET NOT NULL,
+    ALTER COLUMN name SET NOT NULL,
+    ALTER COLUMN description SET NOT NULL,
+    ALTER COLUMN date_created TYPE timestamptz USING date_created AT TIME ZONE 'UTC',
+    ALTER COLUMN date_created SET NOT NULL,
+    ALTER COLUMN created_by_user_id SET NOT NULL,
+    ALTER COLUMN date_updated TYPE timestamptz USING date_updated AT TIME ZONE 'UTC',
+    ALTER COLUMN date_updated SET NOT NULL,
+    ALTER COLUMN updated_by_user_id SET NOT NULL,
+    ADD CONSTRAINT ck_project_name CHECK (name <> ''),
+    ALTER COLUMN description SET DEFAULT '';
+
+ALTER TABLE PROJECT_ROLE
+    ALTER COLUMN name SET NOT NULL,
+    ADD CONSTRAINT ck_project_role_name CHECK (name <> '');
+
+ALTER TABLE PROJECT_GROUP
+    ALTER COLUMN name SET NOT NULL,
+    ALTER COLUMN date_created TYPE timestamptz USING date_created AT TIME ZONE 'UTC',
+    ALTER COLUMN date_created SET NOT NULL,
+    ALTER COLUMN created_by_user_id SET NOT NULL,
+    ALTER COLUMN date_updated TYPE timestamptz USING date_updated AT TIME ZONE 'UTC',
+    ALTER COLUMN date_updated SET NOT NULL,
+    ALTER COLUMN updated_by_user_id SET NOT NULL,
+    ADD CONSTRAINT ck_project_group_name CHECK (name <> '');
+
+ALTER TABLE PROJECT_GROUP_ROLE
+    ALTER COLUMN project_group_id SET NOT NULL,
+    ALTER COLUMN project_role_id SET NOT NULL,
+    ALTER COLUMN date_created TYPE timestamptz USING date_created AT TIME ZONE 'UTC',
+    ALTER COLUMN date_created SET NOT NULL,
+    ALTER COLUMN created_by_user_id SET NOT NULL;
+
+ALTER TABLE PROJECT_GROUP_USER
+    ALTER COLUMN project_group_id SET NOT NULL,
+    ALTER COLUMN user_id SET NOT NULL,
+    ALTER COLUMN date_created TYPE timestamptz USING date_created AT TIME ZONE 'UTC',
+    ALTER COLUMN date_created SET NOT NULL,
+    ALTER COLUMN created_by_user_id SET NOT NULL;
+
+ALTER TABLE PROJECT_GROUP_ACCESS
+    ALTER COLUMN project_id SET NOT NULL,
+    ALTER COLUMN project_group_id SET NOT NULL,
+    ALTER COLUMN date_created TYPE timestamptz USING date_created AT TIME ZONE 'UTC',
+    ALTER COLUMN date_created SET NOT NULL,
+    ALTER COLUMN created_by_user_id SET NOT NULL;
+
+ALTER TABLE STEM
+    ALTER COLUMN name SET NOT NULL,
+    ADD CONSTRAINT ck_stem_name CHECK (name <> '');
+
+ALTER TABLE WORKSPACE
+    ALTER COLUMN project_id SET NOT NULL,
+    ALTER COLUMN stem_id SET NOT NULL,
+    ALTER COLUMN name SET NOT NULL,
+    ALTER COLUMN description SET NOT NULL,
+    ALTER COLUMN asset_amount_limit TYPE integer USING asset_amount_limit::integer,
+    ALTER COLUMN asset_amount_limit SET NOT NULL,
+    ALTER COLUMN x_max TYPE integer USING x_max::integer,
+    ALTER COLUMN x_max SET NOT NULL,
+    ALTER COLUMN y_max TYPE integer USING y_max::integer,
+    ALTER COLUMN y_max SET NOT NULL,
+    ALTER COLUMN z_max TYPE integer USING z_max::integer,
+    ALTER COLUMN z_max SET NOT NULL,
+    ALTER COLUMN date_created TYPE timestamptz USING date_created AT TIME ZONE 'UTC',
+    ALTER COLUMN date_created SET NOT NULL,
+    ALTER COLUMN created_by_user_id SET NOT NULL,
+    ALTER COLUMN date_updated TYPE timestamptz USING date_updated AT TIME ZONE 'UTC',
+    ALTER COLUMN date_updated SET NOT NULL,
+    ALTER COLUMN updated_by_user_id SET NOT NULL,
+    ADD CONSTRAINT ck_workspace_name CHECK (name <> ''),
+    ADD CONSTRAINT ck_workspace_asset_amount_limit CHECK (asset_amount_limit >= 1),
+    ADD CONSTRAINT ck_workspace_x_max CHECK (x_max >= 1),
+    ADD CONSTRAINT ck_workspace_y_max CHECK (y_max >= 1),
+    ADD CONSTRAINT ck_workspace_z_max CHECK (z_max >= 0),
+    ALTER COLUMN description SET DEFAULT '';
+
+ALTER TABLE ASSET
+    ALTER COLUMN workspace_id SET NOT NULL,
+    ALTER COLUMN asset_external_ref_id SET NOT NULL,
+    ALTER COLUMN position_x TYPE integer USING position_x::integer,
+    ALTER COLUMN position_x SET NOT NULL,
+    ALTER COLUMN position_y TYPE integer USING position_y::integer,
+    ALTER COLUMN position_y SET NOT NULL,
+    ALTER COLUMN position_z TYPE integer USING position_z::integer,
+    ALTER COLUMN position_z SET NOT NULL,
+    ALTER COLUMN scale TYPE integer USING scale::integer,
+    ALTER COLUMN scale SET NOT NULL,
+    ALTER COLUMN height_by_y TYPE integer USING height_by_y::integer,
+    ALTER COLUMN height_by_y SET NOT NULL,
+    ALTER COLUMN width_by_x TYPE integer USING width_by_x::integer,
+    ALTER COLUMN width_by_x SET NOT NULL,
+    ALTER COLUMN length_by_z TYPE integer USING length_by_z::integer,
+    ALTER COLUMN length_by_z SET NOT NULL,
+    ALTER COLUMN date_created TYPE timestamptz USING date_created AT TIME ZONE 'UTC',
+    ALTER COLUMN date_created SET NOT NULL,
+    ALTER COLUMN created_by_user_id SET NOT NULL,
+    ALTER COLUMN date_updated TYPE timestamptz USING date_updated AT TIME ZONE 'UTC',
+    ALTER COLUMN date_updated SET NOT NULL,
+    ALTER COLUMN updated_by_user_id SET NOT NULL,
+    ADD CONSTRAINT ck_asset_position_x CHECK (position_x >= 0),
+    ADD CONSTRAINT ck_asset_position_y CHECK (position_y >= 0),
+    ADD CONSTRAINT ck_asset_position_z CHECK (position_z >= 0),
+    ADD CONSTRAINT ck_asset_scale CHECK (scale >= 0),
+    ADD CONSTRAINT ck_asset_height_by_y CHECK (height_by_y >= 0),
+    ADD CONSTRAINT ck_asset_width_by_x CHECK (width_by_x >= 0),
+    ADD CONSTRAINT ck_asset_length_by_z CHECK (length_by_z >= 0);
+
+CREATE INDEX ix_workspace_date_updated ON WORKSPACE (date_updated DESC);
+CREATE INDEX ix_workspace_project_stem_date_updated ON WORKSPACE (project_id, stem_id, date_updated DESC);
+CREATE INDEX ix_asset_workspace_date_updated ON ASSET (workspace_id, date_updated DESC);
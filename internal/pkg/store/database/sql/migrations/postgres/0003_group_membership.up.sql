@@ -0,0 +1,10 @@
+DELETE
+FROM PROJECT_GROUP_USER AS u
+WHERE EXISTS(SELECT 1
+             FROM PROJECT_GROUP_USER AS d
+             WHERE d.project_group_id = u.project_group_id
+               AND d.user_id = u.user_id
+               AND d.project_group_user_id < u.project_group_user_id);
+
+CREATE UNIQUE INDEX ux_project_group_user_group_user ON PROJECT_GROUP_USER (project_group_id, user_id);
+CREATE INDEX ix_project_group_user_user ON PROJECT_GROUP_USER (user_id);
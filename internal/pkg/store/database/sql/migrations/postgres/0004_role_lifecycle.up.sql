@@ -0,0 +1,13 @@
+ALTER TABLE PROJECT_ROLE
+    ADD COLUMN date_retired timestamptz NULL;
+
+DELETE
+FROM PROJECT_GROUP_ROLE AS r
+WHERE EXISTS(SELECT 1
+             FROM PROJECT_GROUP_ROLE AS d
+             WHERE d.project_group_id = r.project_group_id
+               AND d.project_role_id = r.project_role_id
+               AND d.project_group_role_id < r.project_group_role_id);
+
+CREATE UNIQUE INDEX ux_project_group_role_group_role ON PROJECT_GROUP_ROLE (project_group_id, project_role_id);
+CREATE INDEX ix_project_group_access_project_group ON PROJECT_GROUP_ACCESS (project_id, project_group_id);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS ix_project_group_access_group;
+DROP INDEX IF EXISTS ux_project_group_access_project_group;
+CREATE INDEX ix_project_group_access_project_group ON PROJECT_GROUP_ACCESS (project_id, project_group_id);
@@ -0,0 +1,11 @@
+DELETE
+FROM PROJECT_GROUP_ACCESS AS a
+WHERE EXISTS(SELECT 1
+             FROM PROJECT_GROUP_ACCESS AS d
+             WHERE d.project_id = a.project_id
+               AND d.project_group_id = a.project_group_id
+               AND d.project_group_access_id < a.project_group_access_id);
+
+DROP INDEX IF EXISTS ix_project_group_access_project_group;
+CREATE UNIQUE INDEX ux_project_group_access_project_group ON PROJECT_GROUP_ACCESS (project_id, project_group_id);
+CREATE INDEX ix_project_group_access_group ON PROJECT_GROUP_ACCESS (project_group_id);
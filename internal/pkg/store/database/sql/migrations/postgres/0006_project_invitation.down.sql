@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS ix_project_invitation_group;
+DROP INDEX IF EXISTS ix_project_invitation_project;
+DROP TABLE IF EXISTS PROJECT_INVITATION;
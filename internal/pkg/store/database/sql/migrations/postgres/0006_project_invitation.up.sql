@@ -0,0 +1,22 @@
+CREATE TABLE PROJECT_INVITATION
+(
+    project_invitation_id UUID,
+    project_id            UUID         NOT NULL,
+    project_group_id      UUID         NOT NULL,
+    invitee_user_id       UUID         NULL,
+    invitee_email         varchar(320) NULL,
+    date_expires          timestamptz  NOT NULL,
+    date_accepted         timestamptz  NULL,
+    accepted_by_user_id   UUID         NULL,
+    date_revoked          timestamptz  NULL,
+    date_created          timestamptz  NOT NULL,
+    created_by_user_id    UUID         NOT NULL,
+
+    PRIMARY KEY (project_invitation_id),
+    FOREIGN KEY (project_id) REFERENCES PROJECT (project_id),
+    FOREIGN KEY (project_group_id) REFERENCES PROJECT_GROUP (project_group_id),
+    CHECK (invitee_user_id IS NOT NULL OR invitee_email IS NOT NULL)
+);
+
+CREATE INDEX ix_project_invitation_project ON PROJECT_INVITATION (project_id);
+CREATE INDEX ix_project_invitation_group ON PROJECT_INVITATION (project_group_id);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS ix_project_access_request_user;
+DROP INDEX IF EXISTS ux_project_access_request_pending;
+DROP TABLE IF EXISTS PROJECT_ACCESS_REQUEST;
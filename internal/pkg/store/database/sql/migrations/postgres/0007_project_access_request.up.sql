@@ -0,0 +1,22 @@
+CREATE TABLE PROJECT_ACCESS_REQUEST
+(
+    project_access_request_id UUID,
+    project_id                UUID         NOT NULL,
+    user_id                   UUID         NOT NULL,
+    message                   varchar(500) NULL,
+    status                    varchar(16)  NOT NULL,
+    project_group_id          UUID         NULL,
+    reason                    varchar(500) NULL,
+    date_created              timestamptz  NOT NULL,
+    date_decided              timestamptz  NULL,
+    decided_by_user_id        UUID         NULL,
+
+    PRIMARY KEY (project_access_request_id),
+    FOREIGN KEY (project_id) REFERENCES PROJECT (project_id),
+    FOREIGN KEY (project_group_id) REFERENCES PROJECT_GROUP (project_group_id),
+    CHECK (status IN ('PENDING', 'APPROVED', 'REJECTED'))
+);
+
+CREATE UNIQUE INDEX ux_project_access_request_pending ON PROJECT_ACCESS_REQUEST (project_id, user_id)
+    WHERE status = 'PENDING';
+CREATE INDEX ix_project_access_request_user ON PROJECT_ACCESS_REQUEST (user_id);
@@ -0,0 +1,21 @@
+CREATE TABLE PROJECT_OWNER
+(
+    project_owner_id   UUID,
+    project_id         UUID NOT NULL,
+    user_id            UUID NOT NULL,
+    date_created       timestamptz NOT NULL,
+    created_by_user_id UUID        NOT NULL,
+
+    PRIMARY KEY (project_owner_id),
+    FOREIGN KEY (project_id) REFERENCES PROJECT (project_id)
+);
+
+CREATE UNIQUE INDEX ux_project_owner_project_user ON PROJECT_OWNER (project_id, user_id);
+CREATE INDEX ix_project_owner_user ON PROJECT_OWNER (user_id);
+
+-- Identifiers of backfilled owners are derived from their projects, so the backfill is reproducible.
+INSERT INTO PROJECT_OWNER (project_owner_id, project_id, user_id, date_created, created_by_user_id)
+SELECT md5('project-owner:' || p.project_id::text)::uuid, p.project_id, p.created_by_user_id,
+       coalesce(p.date_created, now()), p.created_by_user_id
+FROM PROJECT AS p
+WHERE p.created_by_user_id IS NOT NULL;
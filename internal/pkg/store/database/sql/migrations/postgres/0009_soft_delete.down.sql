@@ -0,0 +1,10 @@
+DROP INDEX IF EXISTS ix_workspace_date_deleted;
+DROP INDEX IF EXISTS ix_project_date_deleted;
+
+ALTER TABLE WORKSPACE
+    DROP COLUMN IF EXISTS date_deleted,
+    DROP COLUMN IF EXISTS date_archived;
+
+ALTER TABLE PROJECT
+    DROP COLUMN IF EXISTS date_deleted,
+    DROP COLUMN IF EXISTS date_archived;
@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS ix_workspace_search_vector;
+DROP INDEX IF EXISTS ix_project_search_vector;
+
+ALTER TABLE WORKSPACE
+    DROP COLUMN IF EXISTS search_vector;
+
+ALTER TABLE PROJECT
+    DROP COLUMN IF EXISTS search_vector;
@@ -0,0 +1,12 @@
+ALTER TABLE PROJECT
+    ADD COLUMN search_vector tsvector GENERATED ALWAYS AS (
+        setweight(to_tsvector('english', coalesce(name, '')), 'A') ||
+        setweight(to_tsvector('english', coalesce(description, '')), 'B')) STORED;
+
+ALTER TABLE WORKSPACE
+    ADD COLUMN search_vector tsvector GENERATED ALWAYS AS (
+        setweight(to_tsvector('english', coalesce(name, '')), 'A') ||
+        setweight(to_tsvector('english', coalesce(description, '')), 'B')) STORED;
+
+CREATE INDEX ix_project_search_vector ON PROJECT USING GIN (search_vector);
+CREATE INDEX ix_workspace_search_vector ON WORKSPACE USING GIN (search_vector);
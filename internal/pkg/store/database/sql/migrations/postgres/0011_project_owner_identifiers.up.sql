@@ -0,0 +1,11 @@
+-- Owners backfilled by 0008 got md5-based identifiers on Postgres and random ones on SQLite. Owner rows of project
+-- creators are re-keyed with an identifier made of rearranged hex groups of the project identifier, so both
+-- dialects derive the same value.
+UPDATE PROJECT_OWNER AS o
+SET project_owner_id = (substr(p.project_id::text, 25, 8) || '-' || substr(p.project_id::text, 33, 4) || '-' ||
+                        substr(p.project_id::text, 20, 4) || '-' || substr(p.project_id::text, 15, 4) || '-' ||
+                        substr(p.project_id::text, 1, 8) || substr(p.project_id::text, 10, 4))::uuid
+FROM PROJECT AS p
+WHERE p.project_id = o.project_id
+  AND o.user_id = p.created_by_user_id
+  AND o.created_by_user_id = p.created_by_user_id;
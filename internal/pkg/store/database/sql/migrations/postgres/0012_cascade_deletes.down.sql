@@ -0,0 +1,57 @@
+DROP INDEX IF EXISTS ix_project_access_request_project;
+DROP INDEX IF EXISTS ix_asset_workspace_date_updated_id;
+DROP INDEX IF EXISTS ix_workspace_project_stem_date_updated_id;
+DROP INDEX IF EXISTS ix_workspace_date_updated_id;
+
+CREATE INDEX ix_workspace_date_updated ON WORKSPACE (date_updated DESC);
+CREATE INDEX ix_workspace_project_stem_date_updated ON WORKSPACE (project_id, stem_id, date_updated DESC);
+CREATE INDEX ix_asset_workspace_date_updated ON ASSET (workspace_id, date_updated DESC);
+
+ALTER TABLE WORKSPACE
+    DROP CONSTRAINT workspace_project_id_fkey,
+    ADD CONSTRAINT workspace_project_id_fkey
+        FOREIGN KEY (project_id) REFERENCES PROJECT (project_id);
+
+ALTER TABLE ASSET
+    DROP CONSTRAINT asset_workspace_id_fkey,
+    ADD CONSTRAINT asset_workspace_id_fkey
+        FOREIGN KEY (workspace_id) REFERENCES WORKSPACE (workspace_id);
+
+ALTER TABLE PROJECT_GROUP_ROLE
+    DROP CONSTRAINT project_group_role_project_group_id_fkey,
+    ADD CONSTRAINT project_group_role_project_group_id_fkey
+        FOREIGN KEY (project_group_id) REFERENCES PROJECT_GROUP (project_group_id);
+
+ALTER TABLE PROJECT_GROUP_USER
+    DROP CONSTRAINT project_group_user_project_group_id_fkey,
+    ADD CONSTRAINT project_group_user_project_group_id_fkey
+        FOREIGN KEY (project_group_id) REFERENCES PROJECT_GROUP (project_group_id);
+
+ALTER TABLE PROJECT_GROUP_ACCESS
+    DROP CONSTRAINT project_group_access_project_id_fkey,
+    ADD CONSTRAINT project_group_access_project_id_fkey
+        FOREIGN KEY (project_id) REFERENCES PROJECT (project_id),
+    DROP CONSTRAINT project_group_access_project_group_id_fkey,
+    ADD CONSTRAINT project_group_access_project_group_id_fkey
+        FOREIGN KEY (project_group_id) REFERENCES PROJECT_GROUP (project_group_id);
+
+ALTER TABLE PROJECT_INVITATION
+    DROP CONSTRAINT project_invitation_project_id_fkey,
+    ADD CONSTRAINT project_invitation_project_id_fkey
+        FOREIGN KEY (project_id) REFERENCES PROJECT (project_id),
+    DROP CONSTRAINT project_invitation_project_group_id_fkey,
+    ADD CONSTRAINT project_invitation_project_group_id_fkey
+        FOREIGN KEY (project_group_id) REFERENCES PROJECT_GROUP (project_group_id);
+
+ALTER TABLE PROJECT_ACCESS_REQUEST
+    DROP CONSTRAINT project_access_request_project_id_fkey,
+    ADD CONSTRAINT project_access_request_project_id_fkey
+        FOREIGN KEY (project_id) REFERENCES PROJECT (project_id),
+    DROP CONSTRAINT project_access_request_project_group_id_fkey,
+    ADD CONSTRAINT project_access_request_project_group_id_fkey
+        FOREIGN KEY (project_group_id) REFERENCES PROJECT_GROUP (project_group_id);
+
+ALTER TABLE PROJECT_OWNER
+    DROP CONSTRAINT project_owner_project_id_fkey,
+    ADD CONSTRAINT project_owner_project_id_fkey
+        FOREIGN KEY (project_id) REFERENCES PROJECT (project_id);
@@ -0,0 +1,10 @@
+DROP TABLE IF EXISTS ASSET;
+DROP TABLE IF EXISTS WORKSPACE;
+DROP TABLE IF EXISTS STEM;
+DROP TABLE IF EXISTS PROJECT_GROUP_ACCESS;
+DROP TABLE IF EXISTS PROJECT_GROUP_USER;
+DROP TABLE IF EXISTS PROJECT_GROUP_ROLE;
+DROP TABLE IF EXISTS PROJECT_GROUP;
+DROP TABLE IF EXISTS PROJECT_ROLE;
+DROP TABLE IF EXISTS PROJECT;
+DROP TABLE IF EXISTS PROJECT_COLLABORATION_TYPE;
@@ -0,0 +1,130 @@
+CREATE TABLE PROJECT_COLLABORATION_TYPE
+(
+    project_collaboration_type_id TEXT,
+    name                          TEXT UNIQUE,
+
+    PRIMARY KEY (project_collaboration_type_id)
+);
+
+CREATE TABLE PROJECT
+(
+    project_id                    TEXT,
+    project_collaboration_type_id TEXT,
+    name                          TEXT UNIQUE,
+    description                   TEXT,
+    date_created                  TIMESTAMP,
+    created_by_user_id            TEXT,
+    date_updated                  TIMESTAMP,
+    updated_by_user_id            TEXT,
+
+    PRIMARY KEY (project_id),
+    FOREIGN KEY (project_collaboration_type_id) REFERENCES PROJECT_COLLABORATION_TYPE (project_collaboration_type_id)
+);
+
+CREATE TABLE PROJECT_ROLE
+(
+    project_role_id TEXT,
+    name            TEXT UNIQUE,
+
+    PRIMARY KEY (project_role_id)
+);
+
+CREATE TABLE PROJECT_GROUP
+(
+    project_group_id   TEXT,
+    name               TEXT UNIQUE,
+    date_created       TIMESTAMP,
+    created_by_user_id TEXT,
+    date_updated       TIMESTAMP,
+    updated_by_user_id TEXT,
+
+    PRIMARY KEY (project_group_id)
+);
+
+CREATE TABLE PROJECT_GROUP_ROLE
+(
+    project_group_role_id TEXT,
+    project_group_id      TEXT,
+    project_role_id       TEXT,
+    date_created          TIMESTAMP,
+    created_by_user_id    TEXT,
+
+    PRIMARY KEY (project_group_role_id),
+    FOREIGN KEY (project_group_id) REFERENCES PROJECT_GROUP (project_group_id),
+    FOREIGN KEY (project_role_id) REFERENCES PROJECT_ROLE (project_role_id)
+);
+
+CREATE TABLE PROJECT_GROUP_USER
+(
+    project_group_user_id TEXT,
+    project_group_id      TEXT,
+    user_id               TEXT,
+    date_created          TIMESTAMP,
+    created_by_user_id    TEXT,
+
+    PRIMARY KEY (project_group_user_id),
+    FOREIGN KEY (project_group_id) REFERENCES PROJECT_GROUP (project_group_id)
+);
+
+CREATE TABLE PROJECT_GROUP_ACCESS
+(
+    project_group_access_id TEXT,
+    project_id              TEXT,
+    project_group_id        TEXT,
+    date_created            TIMESTAMP,
+    created_by_user_id      TEXT,
+
+    PRIMARY KEY (project_group_access_id),
+    FOREIGN KEY (project_id) REFERENCES PROJECT (project_id),
+    FOREIGN KEY (project_group_id) REFERENCES PROJECT_GROUP (project_group_id)
+);
+
+CREATE TABLE STEM
+(
+    stem_id TEXT,
+    name    TEXT UNIQUE,
+
+    PRIMARY KEY (stem_id)
+);
+
+CREATE TABLE WORKSPACE
+(
+    workspace_id       TEXT,
+    project_id         TEXT,
+    stem_id            TEXT,
+    name               TEXT UNIQUE,
+    description        TEXT,
+    asset_amount_limit INTEGER,
+    x_max              INTEGER,
+    y_max              INTEGER,
+    z_max              INTEGER,
+    date_created       TIMESTAMP,
+    created_by_user_id TEXT,
+    date_updated       TIMESTAMP,
+    updated_by_user_id TEXT,
+
+    PRIMARY KEY (workspace_id),
+    FOREIGN KEY (project_id) REFERENCES PROJECT (project_id),
+    FOREIGN KEY (stem_id) REFERENCES STEM (stem_id)
+);
+
+CREATE TABLE ASSET
+(
+    asset_id              TEXT,
+    workspace_id          TEXT,
+    asset_external_ref_id TEXT,
+    position_x            INTEGER,
+    position_y            INTEGER,
+    position_z            INTEGER,
+    scale                 INTEGER,
+    height_by_y           INTEGER,
+    width_by_x            INTEGER,
+    length_by_z           INTEGER,
+    date_created          TIMESTAMP,
+    created_by_user_id    TEXT,
+    date_updated          TIMESTAMP,
+    updated_by_user_id    TEXT,
+
+    PRIMARY KEY (asset_id),
+    FOREIGN KEY (workspace_id) REFERENCES WORKSPACE (workspace_id)
+);
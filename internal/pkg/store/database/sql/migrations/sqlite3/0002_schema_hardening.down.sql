@@ -0,0 +1,204 @@
+DROP INDEX IF EXISTS ix_asset_workspace_date_updated;
+DROP INDEX IF EXISTS ix_workspace_project_stem_date_updated;
+DROP INDEX IF EXISTS ix_workspace_date_updated;
+
+CREATE TABLE PROJECT_COLLABORATION_TYPE_NEW
+(
+    project_collaboration_type_id TEXT,
+    name                          TEXT UNIQUE,
+
+    PRIMARY KEY (project_collaboration_type_id)
+);
+
+INSERT INTO PROJECT_COLLABORATION_TYPE_NEW (project_collaboration_type_id, name)
+SELECT project_collaboration_type_id, name
+FROM PROJECT_COLLABORATION_TYPE;
+
+DROP TABLE PROJECT_COLLABORATION_TYPE;
+ALTER TABLE PROJECT_COLLABORATION_TYPE_NEW RENAME TO PROJECT_COLLABORATION_TYPE;
+
+CREATE TABLE PROJECT_NEW
+(
+    project_id                    TEXT,
+    project_collaboration_type_id TEXT,
+    name                          TEXT UNIQUE,
+    description                   TEXT,
+    date_created                  TIMESTAMP,
+    created_by_user_id            TEXT,
+    date_updated                  TIMESTAMP,
+    updated_by_user_id            TEXT,
+
+    PRIMARY KEY (project_id),
+    FOREIGN KEY (project_collaboration_type_id) REFERENCES PROJECT_COLLABORATION_TYPE (project_collaboration_type_id)
+);
+
+INSERT INTO PROJECT_NEW (project_id, project_collaboration_type_id, name, description, date_created, created_by_user_id, date_updated, updated_by_user_id)
+SELECT project_id, project_collaboration_type_id, name, description, date_created, created_by_user_id, date_updated, updated_by_user_id
+FROM PROJECT;
+
+DROP TABLE PROJECT;
+ALTER TABLE PROJECT_NEW RENAME TO PROJECT;
+
+CREATE TABLE PROJECT_ROLE_NEW
+(
+    project_role_id TEXT,
+    name            TEXT UNIQUE,
+
+    PRIMARY KEY (project_role_id)
+);
+
+INSERT INTO PROJECT_ROLE_NEW (project_role_id, name)
+SELECT project_role_id, name
+FROM PROJECT_ROLE;
+
+DROP TABLE PROJECT_ROLE;
+ALTER TABLE PROJECT_ROLE_NEW RENAME TO PROJECT_ROLE;
+
+CREATE TABLE PROJECT_GROUP_NEW
+(
+    project_group_id   TEXT,
+    name               TEXT UNIQUE,
+    date_created       TIMESTAMP,
+    created_by_user_id TEXT,
+    date_updated       TIMESTAMP,
+    updated_by_user_id TEXT,
+
+    PRIMARY KEY (project_group_id)
+);
+
+INSERT INTO PROJECT_GROUP_NEW (project_group_id, name, date_created, created_by_user_id, date_updated, updated_by_user_id)
+SELECT project_group_id, name, date_created, created_by_user_id, date_updated, updated_by_user_id
+FROM PROJECT_GROUP;
+
+DROP TABLE PROJECT_GROUP;
+ALTER TABLE PROJECT_GROUP_NEW RENAME TO PROJECT_GROUP;
+
+CREATE TABLE PROJECT_GROUP_ROLE_NEW
+(
+    project_group_role_id TEXT,
+    project_group_id      TEXT,
+    project_role_id       TEXT,
+    date_created          TIMESTAMP,
+    created_by_user_id    TEXT,
+
+    PRIMARY KEY (project_group_role_id),
+    FOREIGN KEY (project_group_id) REFERENCES PROJECT_GROUP (project_group_id),
+    FOREIGN KEY (project_role_id) REFERENCES PROJECT_ROLE (project_role_id)
+);
+
+INSERT INTO PROJECT_GROUP_ROLE_NEW (project_group_role_id, project_group_id, project_role_id, date_created, created_by_user_id)
+SELECT project_group_role_id, project_group_id, project_role_id, date_created, created_by_user_id
+FROM PROJECT_GROUP_ROLE;
+
+DROP TABLE PROJECT_GROUP_ROLE;
+ALTER TABLE PROJECT_GROUP_ROLE_NEW RENAME TO PROJECT_GROUP_ROLE;
+
+CREATE TABLE PROJECT_GROUP_USER_NEW
+(
+    project_group_user_id TEXT,
+    project_group_id      TEXT,
+    user_id               TEXT,
+    date_created          TIMESTAMP,
+    created_by_user_id    TEXT,
+
+    PRIMARY KEY (project_group_user_id),
+    FOREIGN KEY (project_group_id) REFERENCES PROJECT_GROUP (project_group_id)
+);
+
+INSERT INTO PROJECT_GROUP_USER_NEW (project_group_user_id, project_group_id, user_id, date_created, created_by_user_id)
+SELECT project_group_user_id, project_group_id, user_id, date_created, created_by_user_id
+FROM PROJECT_GROUP_USER;
+
+DROP TABLE PROJECT_GROUP_USER;
+ALTER TABLE PROJECT_GROUP_USER_NEW RENAME TO PROJECT_GROUP_USER;
+
+CREATE TABLE PROJECT_GROUP_ACCESS_NEW
+(
+    project_group_access_id TEXT,
+    project_id              TEXT,
+    project_group_id        TEXT,
+    date_created            TIMESTAMP,
+    created_by_user_id      TEXT,
+
+    PRIMARY KEY (project_group_access_id),
+    FOREIGN KEY (project_id) REFERENCES PROJECT (project_id),
+    FOREIGN KEY (project_group_id) REFERENCES PROJECT_GROUP (project_group_id)
+);
+
+INSERT INTO PROJECT_GROUP_ACCESS_NEW (project_group_access_id, project_id, project_group_id, date_created, created_by_user_id)
+SELECT project_group_access_id, project_id, project_group_id, date_created, created_by_user_id
+FROM PROJECT_GROUP_ACCESS;
+
+DROP TABLE PROJECT_GROUP_ACCESS;
+ALTER TABLE PROJECT_GROUP_ACCESS_NEW RENAME TO PROJECT_GROUP_ACCESS;
+
+CREATE TABLE STEM_NEW
+(
+    stem_id TEXT,
+    name    TEXT UNIQUE,
+
+    PRIMARY KEY (stem_id)
+);
+
+INSERT INTO STEM_NEW (stem_id, name)
+SELECT stem_id, name
+FROM STEM;
+
+DROP TABLE STEM;
+ALTER TABLE STEM_NEW RENAME TO STEM;
+
+CREATE TABLE WORKSPACE_NEW
+(
+    workspace_id       TEXT,
+    project_id         TEXT,
+    stem_id            TEXT,
+    name               TEXT UNIQUE,
+    description        TEXT,
+    asset_amount_limit INTEGER,
+    x_max              INTEGER,
+    y_max              INTEGER,
+    z_max              INTEGER,
+    date_created       TIMESTAMP,
+    created_by_user_id TEXT,
+    date_updated       TIMESTAMP,
+    updated_by_user_id TEXT,
+
+    PRIMARY KEY (workspace_id),
+    FOREIGN KEY (project_id) REFERENCES PROJECT (project_id),
+    FOREIGN KEY (stem_id) REFERENCES STEM (stem_id)
+);
+
+INSERT INTO WORKSPACE_NEW (workspace_id, project_id, stem_id, name, description, asset_amount_limit, x_max, y_max, z_max, date_created, created_by_user_id, date_updated, updated_by_user_id)
+SELECT workspace_id, project_id, stem_id, name, description, asset_amount_limit, x_max, y_max, z_max, date_created, created_by_user_id, date_updated, updated_by_user_id
+FROM WORKSPACE;
+
+DROP TABLE WORKSPACE;
+ALTER TABLE WORKSPACE_NEW RENAME TO WORKSPACE;
+
+CREATE TABLE ASSET_NEW
+(
+    asset_id              TEXT,
+    workspace_id          TEXT,
+    asset_external_ref_id TEXT,
+    position_x            INTEGER,
+    position_y            INTEGER,
+    position_z            INTEGER,
+    scale                 INTEGER,
+    height_by_y           INTEGER,
+    width_by_x            INTEGER,
+    length_by_z           INTEGER,
+    date_created          TIMESTAMP,
+    created_by_user_id    TEXT,
+    date_updated          TIMESTAMP,
+    updated_by_user_id    TEXT,
+
+    PRIMARY KEY (asset_id),
+    FOREIGN KEY (workspace_id) REFERENCES WORKSPACE (workspace_id)
+);
+
+INSERT INTO ASSET_NEW (asset_id, workspace_id, asset_external_ref_id, position_x, position_y, position_z, scale, height_by_y, width_by_x, length_by_z, date_created, created_by_user_id, date_updated, updated_by_user_id)
+SELECT asset_id, workspace_id, asset_external_ref_id, position_x, position_y, position_z, scale, height_by_y, width_by_x, length_by_z, date_created, created_by_user_id, date_updated, updated_by_user_id
+FROM ASSET;
+
+DROP TABLE ASSET;
+ALTER TABLE ASSET_NEW RENAME TO ASSET;
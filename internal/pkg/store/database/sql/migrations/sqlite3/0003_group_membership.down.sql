@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS ix_project_group_user_user;
+DROP INDEX IF EXISTS ux_project_group_user_group_user;
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS ix_project_group_access_project_group;
+DROP INDEX IF EXISTS ux_project_group_role_group_role;
+
+ALTER TABLE PROJECT_ROLE
+    DROP COLUMN date_retired;
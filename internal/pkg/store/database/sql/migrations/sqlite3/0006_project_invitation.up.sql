@@ -0,0 +1,22 @@
+CREATE TABLE PROJECT_INVITATION
+(
+    project_invitation_id TEXT,
+    project_id            TEXT      NOT NULL,
+    project_group_id      TEXT      NOT NULL,
+    invitee_user_id       TEXT      NULL,
+    invitee_email         TEXT      NULL,
+    date_expires          TIMESTAMP NOT NULL,
+    date_accepted         TIMESTAMP NULL,
+    accepted_by_user_id   TEXT      NULL,
+    date_revoked          TIMESTAMP NULL,
+    date_created          TIMESTAMP NOT NULL,
+    created_by_user_id    TEXT      NOT NULL,
+
+    PRIMARY KEY (project_invitation_id),
+    FOREIGN KEY (project_id) REFERENCES PROJECT (project_id),
+    FOREIGN KEY (project_group_id) REFERENCES PROJECT_GROUP (project_group_id),
+    CHECK (invitee_user_id IS NOT NULL OR invitee_email IS NOT NULL)
+);
+
+CREATE INDEX ix_project_invitation_project ON PROJECT_INVITATION (project_id);
+CREATE INDEX ix_project_invitation_group ON PROJECT_INVITATION (project_group_id);
@@ -0,0 +1,22 @@
+CREATE TABLE PROJECT_ACCESS_REQUEST
+(
+    project_access_request_id TEXT,
+    project_id                TEXT      NOT NULL,
+    user_id                   TEXT      NOT NULL,
+    message                   TEXT      NULL,
+    status                    TEXT      NOT NULL,
+    project_group_id          TEXT      NULL,
+    reason                    TEXT      NULL,
+    date_created              TIMESTAMP NOT NULL,
+    date_decided              TIMESTAMP NULL,
+    decided_by_user_id        TEXT      NULL,
+
+    PRIMARY KEY (project_access_request_id),
+    FOREIGN KEY (project_id) REFERENCES PROJECT (project_id),
+    FOREIGN KEY (project_group_id) REFERENCES PROJECT_GROUP (project_group_id),
+    CHECK (status IN ('PENDING', 'APPROVED', 'REJECTED'))
+);
+
+CREATE UNIQUE INDEX ux_project_access_request_pending ON PROJECT_ACCESS_REQUEST (project_id, user_id)
+    WHERE status = 'PENDING';
+CREATE INDEX ix_project_access_request_user ON PROJECT_ACCESS_REQUEST (user_id);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS ix_project_owner_user;
+DROP INDEX IF EXISTS ux_project_owner_project_user;
+DROP TABLE IF EXISTS PROJECT_OWNER;
@@ -0,0 +1,23 @@
+CREATE TABLE PROJECT_OWNER
+(
+    project_owner_id   TEXT,
+    project_id         TEXT NOT NULL,
+    user_id            TEXT NOT NULL,
+    date_created       TIMESTAMP NOT NULL,
+    created_by_user_id TEXT      NOT NULL,
+
+    PRIMARY KEY (project_owner_id),
+    FOREIGN KEY (project_id) REFERENCES PROJECT (project_id)
+);
+
+CREATE UNIQUE INDEX ux_project_owner_project_user ON PROJECT_OWNER (project_id, user_id);
+CREATE INDEX ix_project_owner_user ON PROJECT_OWNER (user_id);
+
+-- Backfilled owners get random version 4 identifiers, SQLite has no built-in UUID function.
+INSERT INTO PROJECT_OWNER (project_owner_id, project_id, user_id, date_created, created_by_user_id)
+SELECT lower(hex(randomblob(4)) || '-' || hex(randomblob(2)) || '-4' || substr(hex(randomblob(2)), 2) || '-' ||
+             substr('89ab', 1 + (abs(random()) % 4), 1) || substr(hex(randomblob(2)), 2) || '-' ||
+             hex(randomblob(6))),
+       p.project_id, p.created_by_user_id, coalesce(p.date_created, CURRENT_TIMESTAMP), p.created_by_user_id
+FROM PROJECT AS p
+WHERE p.created_by_user_id IS NOT NULL;
@@ -0,0 +1,12 @@
+DROP INDEX IF EXISTS ix_workspace_date_deleted;
+DROP INDEX IF EXISTS ix_project_date_deleted;
+
+ALTER TABLE WORKSPACE
+    DROP COLUMN date_deleted;
+ALTER TABLE WORKSPACE
+    DROP COLUMN date_archived;
+
+ALTER TABLE PROJECT
+    DROP COLUMN date_deleted;
+ALTER TABLE PROJECT
+    DROP COLUMN date_archived;
@@ -0,0 +1,12 @@
+ALTER TABLE PROJECT
+    ADD COLUMN date_archived TIMESTAMP NULL;
+ALTER TABLE PROJECT
+    ADD COLUMN date_deleted TIMESTAMP NULL;
+
+ALTER TABLE WORKSPACE
+    ADD COLUMN date_archived TIMESTAMP NULL;
+ALTER TABLE WORKSPACE
+    ADD COLUMN date_deleted TIMESTAMP NULL;
+
+CREATE INDEX ix_project_date_deleted ON PROJECT (date_deleted) WHERE date_deleted IS NOT NULL;
+CREATE INDEX ix_workspace_date_deleted ON WORKSPACE (date_deleted) WHERE date_deleted IS NOT NULL;
@@ -0,0 +1,9 @@
+DROP TRIGGER IF EXISTS tr_workspace_search_delete;
+DROP TRIGGER IF EXISTS tr_workspace_search_update;
+DROP TRIGGER IF EXISTS tr_workspace_search_insert;
+DROP TRIGGER IF EXISTS tr_project_search_delete;
+DROP TRIGGER IF EXISTS tr_project_search_update;
+DROP TRIGGER IF EXISTS tr_project_search_insert;
+
+DROP TABLE IF EXISTS WORKSPACE_SEARCH;
+DROP TABLE IF EXISTS PROJECT_SEARCH;
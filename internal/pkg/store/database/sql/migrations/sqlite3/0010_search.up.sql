@@ -0,0 +1,63 @@
+-- Full-text indexes of names and descriptions. Rows are keyed by entity identifiers rather than rowids, because
+-- VACUUM may renumber rowids of tables without INTEGER PRIMARY KEY.
+CREATE VIRTUAL TABLE PROJECT_SEARCH USING fts4(project_id, name, description, notindexed=project_id,
+    tokenize=unicode61 "remove_diacritics=0");
+
+CREATE VIRTUAL TABLE WORKSPACE_SEARCH USING fts4(workspace_id, name, description, notindexed=workspace_id,
+    tokenize=unicode61 "remove_diacritics=0");
+
+CREATE TRIGGER tr_project_search_insert
+    AFTER INSERT
+    ON PROJECT
+BEGIN
+    INSERT INTO PROJECT_SEARCH (project_id, name, description)
+    VALUES (new.project_id, coalesce(new.name, ''), coalesce(new.description, ''));
+END;
+
+CREATE TRIGGER tr_project_search_update
+    AFTER UPDATE OF project_id, name, description
+    ON PROJECT
+BEGIN
+    DELETE FROM PROJECT_SEARCH WHERE project_id = old.project_id;
+    INSERT INTO PROJECT_SEARCH (project_id, name, description)
+    VALUES (new.project_id, coalesce(new.name, ''), coalesce(new.description, ''));
+END;
+
+CREATE TRIGGER tr_project_search_delete
+    AFTER DELETE
+    ON PROJECT
+BEGIN
+    DELETE FROM PROJECT_SEARCH WHERE project_id = old.project_id;
+END;
+
+CREATE TRIGGER tr_workspace_search_insert
+    AFTER INSERT
+    ON WORKSPACE
+BEGIN
+    INSERT INTO WORKSPACE_SEARCH (workspace_id, name, description)
+    VALUES (new.workspace_id, coalesce(new.name, ''), coalesce(new.description, ''));
+END;
+
+CREATE TRIGGER tr_workspace_search_update
+    AFTER UPDATE OF workspace_id, name, description
+    ON WORKSPACE
+BEGIN
+    DELETE FROM WORKSPACE_SEARCH WHERE workspace_id = old.workspace_id;
+    INSERT INTO WORKSPACE_SEARCH (workspace_id, name, description)
+    VALUES (new.workspace_id, coalesce(new.name, ''), coalesce(new.description, ''));
+END;
+
+CREATE TRIGGER tr_workspace_search_delete
+    AFTER DELETE
+    ON WORKSPACE
+BEGIN
+    DELETE FROM WORKSPACE_SEARCH WHERE workspace_id = old.workspace_id;
+END;
+
+INSERT INTO PROJECT_SEARCH (project_id, name, description)
+SELECT p.project_id, coalesce(p.name, ''), coalesce(p.description, '')
+FROM PROJECT AS p;
+
+INSERT INTO WORKSPACE_SEARCH (workspace_id, name, description)
+SELECT w.workspace_id, coalesce(w.name, ''), coalesce(w.description, '')
+FROM WORKSPACE AS w;
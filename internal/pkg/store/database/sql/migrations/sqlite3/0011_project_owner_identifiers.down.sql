@@ -0,0 +1,2 @@
+-- Previous identifiers are not kept, re-keyed owners stay valid with the new ones.
+SELECT 1;
@@ -0,0 +1,15 @@
+-- Owners backfilled by 0008 got md5-based identifiers on Postgres and random ones on SQLite. Owner rows of project
+-- creators are re-keyed with an identifier made of rearranged hex groups of the project identifier, so both
+-- dialects derive the same value.
+UPDATE PROJECT_OWNER
+SET project_owner_id = (SELECT substr(lower(p.project_id), 25, 8) || '-' || substr(lower(p.project_id), 33, 4) ||
+                               '-' || substr(lower(p.project_id), 20, 4) || '-' ||
+                               substr(lower(p.project_id), 15, 4) || '-' || substr(lower(p.project_id), 1, 8) ||
+                               substr(lower(p.project_id), 10, 4)
+                        FROM PROJECT AS p
+                        WHERE p.project_id = PROJECT_OWNER.project_id)
+WHERE EXISTS(SELECT 1
+             FROM PROJECT AS p
+             WHERE p.project_id = PROJECT_OWNER.project_id
+               AND PROJECT_OWNER.user_id = p.created_by_user_id
+               AND PROJECT_OWNER.created_by_user_id = p.created_by_user_id);
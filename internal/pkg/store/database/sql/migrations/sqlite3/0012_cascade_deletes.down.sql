@@ -0,0 +1,259 @@
+CREATE TABLE WORKSPACE_NEW
+(
+    workspace_id       TEXT,
+    project_id         TEXT NOT NULL,
+    stem_id            TEXT NOT NULL,
+    name               TEXT UNIQUE NOT NULL,
+    description        TEXT NOT NULL DEFAULT '',
+    asset_amount_limit INTEGER NOT NULL,
+    x_max              INTEGER NOT NULL,
+    y_max              INTEGER NOT NULL,
+    z_max              INTEGER NOT NULL,
+    date_created       TIMESTAMP NOT NULL,
+    created_by_user_id TEXT NOT NULL,
+    date_updated       TIMESTAMP NOT NULL,
+    updated_by_user_id TEXT NOT NULL,
+    date_archived      TIMESTAMP NULL,
+    date_deleted       TIMESTAMP NULL,
+
+    PRIMARY KEY (workspace_id),
+    FOREIGN KEY (project_id) REFERENCES PROJECT (project_id),
+    FOREIGN KEY (stem_id) REFERENCES STEM (stem_id),
+    CONSTRAINT ck_workspace_name CHECK (name <> ''),
+    CONSTRAINT ck_workspace_asset_amount_limit CHECK (asset_amount_limit >= 1),
+    CONSTRAINT ck_workspace_x_max CHECK (x_max >= 1),
+    CONSTRAINT ck_workspace_y_max CHECK (y_max >= 1),
+    CONSTRAINT ck_workspace_z_max CHECK (z_max >= 0)
+);
+
+INSERT INTO WORKSPACE_NEW (workspace_id, project_id, stem_id, name, description, asset_amount_limit, x_max, y_max,
+    z_max, date_created, created_by_user_id, date_updated, updated_by_user_id, date_archived, date_deleted)
+SELECT workspace_id, project_id, stem_id, name, description, asset_amount_limit, x_max, y_max, z_max, date_created,
+       created_by_user_id, date_updated, updated_by_user_id, date_archived, date_deleted
+FROM WORKSPACE;
+
+DROP TABLE WORKSPACE;
+ALTER TABLE WORKSPACE_NEW RENAME TO WORKSPACE;
+
+CREATE INDEX ix_workspace_date_updated ON WORKSPACE (date_updated DESC);
+CREATE INDEX ix_workspace_project_stem_date_updated ON WORKSPACE (project_id, stem_id, date_updated DESC);
+CREATE INDEX ix_workspace_date_deleted ON WORKSPACE (date_deleted) WHERE date_deleted IS NOT NULL;
+
+CREATE TRIGGER tr_workspace_search_insert
+    AFTER INSERT
+    ON WORKSPACE
+BEGIN
+    INSERT INTO WORKSPACE_SEARCH (workspace_id, name, description)
+    VALUES (new.workspace_id, coalesce(new.name, ''), coalesce(new.description, ''));
+END;
+
+CREATE TRIGGER tr_workspace_search_update
+    AFTER UPDATE OF workspace_id, name, description
+    ON WORKSPACE
+BEGIN
+    DELETE FROM WORKSPACE_SEARCH WHERE workspace_id = old.workspace_id;
+    INSERT INTO WORKSPACE_SEARCH (workspace_id, name, description)
+    VALUES (new.workspace_id, coalesce(new.name, ''), coalesce(new.description, ''));
+END;
+
+CREATE TRIGGER tr_workspace_search_delete
+    AFTER DELETE
+    ON WORKSPACE
+BEGIN
+    DELETE FROM WORKSPACE_SEARCH WHERE workspace_id = old.workspace_id;
+END;
+
+CREATE TABLE ASSET_NEW
+(
+    asset_id              TEXT,
+    workspace_id          TEXT NOT NULL,
+    asset_external_ref_id TEXT NOT NULL,
+    position_x            INTEGER NOT NULL,
+    position_y            INTEGER NOT NULL,
+    position_z            INTEGER NOT NULL,
+    scale                 INTEGER NOT NULL,
+    height_by_y           INTEGER NOT NULL,
+    width_by_x            INTEGER NOT NULL,
+    length_by_z           INTEGER NOT NULL,
+    date_created          TIMESTAMP NOT NULL,
+    created_by_user_id    TEXT NOT NULL,
+    date_updated          TIMESTAMP NOT NULL,
+    updated_by_user_id    TEXT NOT NULL,
+
+    PRIMARY KEY (asset_id),
+    FOREIGN KEY (workspace_id) REFERENCES WORKSPACE (workspace_id),
+    CONSTRAINT ck_asset_position_x CHECK (position_x >= 0),
+    CONSTRAINT ck_asset_position_y CHECK (position_y >= 0),
+    CONSTRAINT ck_asset_position_z CHECK (position_z >= 0),
+    CONSTRAINT ck_asset_scale CHECK (scale >= 0),
+    CONSTRAINT ck_asset_height_by_y CHECK (height_by_y >= 0),
+    CONSTRAINT ck_asset_width_by_x CHECK (width_by_x >= 0),
+    CONSTRAINT ck_asset_length_by_z CHECK (length_by_z >= 0)
+);
+
+INSERT INTO ASSET_NEW (asset_id, workspace_id, asset_external_ref_id, position_x, position_y, position_z, scale,
+    height_by_y, width_by_x, length_by_z, date_created, created_by_user_id, date_updated, updated_by_user_id)
+SELECT asset_id, workspace_id, asset_external_ref_id, position_x, position_y, position_z, scale, height_by_y,
+       width_by_x, length_by_z, date_created, created_by_user_id, date_updated, updated_by_user_id
+FROM ASSET;
+
+DROP TABLE ASSET;
+ALTER TABLE ASSET_NEW RENAME TO ASSET;
+
+CREATE INDEX ix_asset_workspace_date_updated ON ASSET (workspace_id, date_updated DESC);
+
+CREATE TABLE PROJECT_GROUP_ROLE_NEW
+(
+    project_group_role_id TEXT,
+    project_group_id      TEXT NOT NULL,
+    project_role_id       TEXT NOT NULL,
+    date_created          TIMESTAMP NOT NULL,
+    created_by_user_id    TEXT NOT NULL,
+
+    PRIMARY KEY (project_group_role_id),
+    FOREIGN KEY (project_group_id) REFERENCES PROJECT_GROUP (project_group_id),
+    FOREIGN KEY (project_role_id) REFERENCES PROJECT_ROLE (project_role_id)
+);
+
+INSERT INTO PROJECT_GROUP_ROLE_NEW (project_group_role_id, project_group_id, project_role_id, date_created,
+    created_by_user_id)
+SELECT project_group_role_id, project_group_id, project_role_id, date_created, created_by_user_id
+FROM PROJECT_GROUP_ROLE;
+
+DROP TABLE PROJECT_GROUP_ROLE;
+ALTER TABLE PROJECT_GROUP_ROLE_NEW RENAME TO PROJECT_GROUP_ROLE;
+
+CREATE UNIQUE INDEX ux_project_group_role_group_role ON PROJECT_GROUP_ROLE (project_group_id, project_role_id);
+
+CREATE TABLE PROJECT_GROUP_USER_NEW
+(
+    project_group_user_id TEXT,
+    project_group_id      TEXT NOT NULL,
+    user_id               TEXT NOT NULL,
+    date_created          TIMESTAMP NOT NULL,
+    created_by_user_id    TEXT NOT NULL,
+
+    PRIMARY KEY (project_group_user_id),
+    FOREIGN KEY (project_group_id) REFERENCES PROJECT_GROUP (project_group_id)
+);
+
+INSERT INTO PROJECT_GROUP_USER_NEW (project_group_user_id, project_group_id, user_id, date_created,
+    created_by_user_id)
+SELECT project_group_user_id, project_group_id, user_id, date_created, created_by_user_id
+FROM PROJECT_GROUP_USER;
+
+DROP TABLE PROJECT_GROUP_USER;
+ALTER TABLE PROJECT_GROUP_USER_NEW RENAME TO PROJECT_GROUP_USER;
+
+CREATE UNIQUE INDEX ux_project_group_user_group_user ON PROJECT_GROUP_USER (project_group_id, user_id);
+CREATE INDEX ix_project_group_user_user ON PROJECT_GROUP_USER (user_id);
+
+CREATE TABLE PROJECT_GROUP_ACCESS_NEW
+(
+    project_group_access_id TEXT,
+    project_id              TEXT NOT NULL,
+    project_group_id        TEXT NOT NULL,
+    date_created            TIMESTAMP NOT NULL,
+    created_by_user_id      TEXT NOT NULL,
+
+    PRIMARY KEY (project_group_access_id),
+    FOREIGN KEY (project_id) REFERENCES PROJECT (project_id),
+    FOREIGN KEY (project_group_id) REFERENCES PROJECT_GROUP (project_group_id)
+);
+
+INSERT INTO PROJECT_GROUP_ACCESS_NEW (project_group_access_id, project_id, project_group_id, date_created,
+    created_by_user_id)
+SELECT project_group_access_id, project_id, project_group_id, date_created, created_by_user_id
+FROM PROJECT_GROUP_ACCESS;
+
+DROP TABLE PROJECT_GROUP_ACCESS;
+ALTER TABLE PROJECT_GROUP_ACCESS_NEW RENAME TO PROJECT_GROUP_ACCESS;
+
+CREATE UNIQUE INDEX ux_project_group_access_project_group ON PROJECT_GROUP_ACCESS (project_id, project_group_id);
+CREATE INDEX ix_project_group_access_group ON PROJECT_GROUP_ACCESS (project_group_id);
+
+CREATE TABLE PROJECT_INVITATION_NEW
+(
+    project_invitation_id TEXT,
+    project_id            TEXT      NOT NULL,
+    project_group_id      TEXT      NOT NULL,
+    invitee_user_id       TEXT      NULL,
+    invitee_email         TEXT      NULL,
+    date_expires          TIMESTAMP NOT NULL,
+    date_accepted         TIMESTAMP NULL,
+    accepted_by_user_id   TEXT      NULL,
+    date_revoked          TIMESTAMP NULL,
+    date_created          TIMESTAMP NOT NULL,
+    created_by_user_id    TEXT      NOT NULL,
+
+    PRIMARY KEY (project_invitation_id),
+    FOREIGN KEY (project_id) REFERENCES PROJECT (project_id),
+    FOREIGN KEY (project_group_id) REFERENCES PROJECT_GROUP (project_group_id),
+    CHECK (invitee_user_id IS NOT NULL OR invitee_email IS NOT NULL)
+);
+
+INSERT INTO PROJECT_INVITATION_NEW (project_invitation_id, project_id, project_group_id, invitee_user_id,
+    invitee_email, date_expires, date_accepted, accepted_by_user_id, date_revoked, date_created, created_by_user_id)
+SELECT project_invitation_id, project_id, project_group_id, invitee_user_id, invitee_email, date_expires,
+       date_accepted, accepted_by_user_id, date_revoked, date_created, created_by_user_id
+FROM PROJECT_INVITATION;
+
+DROP TABLE PROJECT_INVITATION;
+ALTER TABLE PROJECT_INVITATION_NEW RENAME TO PROJECT_INVITATION;
+
+CREATE INDEX ix_project_invitation_project ON PROJECT_INVITATION (project_id);
+CREATE INDEX ix_project_invitation_group ON PROJECT_INVITATION (project_group_id);
+
+CREATE TABLE PROJECT_ACCESS_REQUEST_NEW
+(
+    project_access_request_id TEXT,
+    project_id                TEXT      NOT NULL,
+    user_id                   TEXT      NOT NULL,
+    message                   TEXT      NULL,
+    status                    TEXT      NOT NULL,
+    project_group_id          TEXT      NULL,
+    reason                    TEXT      NULL,
+    date_created              TIMESTAMP NOT NULL,
+    date_decided              TIMESTAMP NULL,
+    decided_by_user_id        TEXT      NULL,
+
+    PRIMARY KEY (project_access_request_id),
+    FOREIGN KEY (project_id) REFERENCES PROJECT (project_id),
+    FOREIGN KEY (project_group_id) REFERENCES PROJECT_GROUP (project_group_id),
+    CHECK (status IN ('PENDING', 'APPROVED', 'REJECTED'))
+);
+
+INSERT INTO PROJECT_ACCESS_REQUEST_NEW (project_access_request_id, project_id, user_id, message, status,
+    project_group_id, reason, date_created, date_decided, decided_by_user_id)
+SELECT project_access_request_id, project_id, user_id, message, status, project_group_id, reason, date_created,
+       date_decided, decided_by_user_id
+FROM PROJECT_ACCESS_REQUEST;
+
+DROP TABLE PROJECT_ACCESS_REQUEST;
+ALTER TABLE PROJECT_ACCESS_REQUEST_NEW RENAME TO PROJECT_ACCESS_REQUEST;
+
+CREATE UNIQUE INDEX ux_project_access_request_pending ON PROJECT_ACCESS_REQUEST (project_id, user_id)
+    WHERE status = 'PENDING';
+CREATE INDEX ix_project_access_request_user ON PROJECT_ACCESS_REQUEST (user_id);
+
+CREATE TABLE PROJECT_OWNER_NEW
+(
+    project_owner_id   TEXT,
+    project_id         TEXT NOT NULL,
+    user_id            TEXT NOT NULL,
+    date_created       TIMESTAMP NOT NULL,
+    created_by_user_id TEXT      NOT NULL,
+
+    PRIMARY KEY (project_owner_id),
+    FOREIGN KEY (project_id) REFERENCES PROJECT (project_id)
+);
+
+INSERT INTO PROJECT_OWNER_NEW (project_owner_id, project_id, user_id, date_created, created_by_user_id)
+SELECT project_owner_id, project_id, user_id, date_created, created_by_user_id
+FROM PROJECT_OWNER;
+
+DROP TABLE PROJECT_OWNER;
+ALTER TABLE PROJECT_OWNER_NEW RENAME TO PROJECT_OWNER;
+
+CREATE UNIQUE INDEX ux_project_owner_project_user ON PROJECT_OWNER (project_id, user_id);
+CREATE INDEX ix_project_owner_user ON PROJECT_OWNER (user_id);
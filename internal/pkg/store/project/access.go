@@ -0,0 +1,215 @@
+package project
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/SKorolchuk/dpio-workspace/internal/pkg/infra/auth"
+	"github.com/SKorolchuk/dpio-workspace/internal/pkg/infra/database"
+	"github.com/SKorolchuk/dpio-workspace/internal/pkg/infra/uuid"
+	"github.com/SKorolchuk/dpio-workspace/internal/pkg/infra/validation"
+)
+
+// GrantGroupAccess gives members of Group entity access to Project entity.
+// If grant is successful, the method returns GroupAccess entity.
+// Both the project and the group should be managed by the claims subject, i.e. the subject owns the project and
+// created the group, so members cannot be added to projects without consent of the group creator.
+// If error occurs, the method can return database.ErrorConflict for repeated grant, database.ErrorInvalidReference
+// for unknown group, database.ErrorForbidden or other database errors.
+func (str Store) GrantGroupAccess(ctx context.Context, claims auth.Claims, projectId string, access NewGroupAccess,
+	now time.Time) (GroupAccess, error) {
+	if err := uuid.Validate(projectId); err != nil {
+		return GroupAccess{}, database.ErrorInvalidIdentifier
+	}
+
+	if err := validation.Check(ctx, access); err != nil {
+		return GroupAccess{}, fmt.Errorf("error during data validation of GroupAccess entity: %w", err)
+	}
+
+	if _, err := str.ownedProject(ctx, claims, projectId); err != nil {
+		return GroupAccess{}, err
+	}
+
+	groupData, err := str.queryGroup(ctx, access.GroupID)
+	if err != nil {
+		if err == database.ErrorNotFound {
+			err = database.ErrorInvalidReference
+		}
+		return GroupAccess{}, fmt.Errorf("error during search of Group entity -> id={%q}: %w", access.GroupID, err)
+	}
+
+	if groupData.CreatedByUser != claims.Subject {
+		return GroupAccess{}, database.ErrorForbidden
+	}
+
+	accessData := GroupAccess{
+		ID:            uuid.Generate(),
+		ProjectID:     projectId,
+		GroupID:       access.GroupID,
+		DateCreated:   now,
+		CreatedByUser: claims.Subject,
+	}
+
+	const query = `
+	INSERT INTO PROJECT_GROUP_ACCESS
+		(project_group_access_id, project_id, project_group_id, date_created, created_by_user_id)
+	VALUES
+		(:project_group_access_id, :project_id, :project_group_id, :date_created, :created_by_user_id)`
+
+	if err := database.NamedExecContext(ctx, str.logger, str.cluster.Primary(), query, accessData); err != nil {
+		return GroupAccess{}, fmt.Errorf("error during create of new GroupAccess entity -> project_id={%q}: %w",
+			projectId, err)
+	}
+
+	return accessData, nil
+}
+
+// RevokeGroupAccess removes access of Group entity members to Project entity.
+// If the group has no access to the project, the method returns database.ErrorNotFound.
+func (str Store) RevokeGroupAccess(ctx context.Context, claims auth.Claims, projectId string, groupId string) error {
+	if err := uuid.Validate(projectId); err != nil {
+		return database.ErrorInvalidIdentifier
+	}
+	if err := uuid.Validate(groupId); err != nil {
+		return database.ErrorInvalidIdentifier
+	}
+
+	if _, err := str.ownedProject(ctx, claims, projectId); err != nil {
+		return err
+	}
+
+	queryParams := struct {
+		ProjectID string `db:"project_id"`
+		GroupID   string `db:"project_group_id"`
+	}{
+		ProjectID: projectId,
+		GroupID:   groupId,
+	}
+
+	const query = `
+	DELETE FROM
+		PROJECT_GROUP_ACCESS
+	WHERE
+		project_id = :project_id AND project_group_id = :project_group_id
+	RETURNING project_group_access_id`
+
+	var removed struct {
+		ID string `db:"project_group_access_id"`
+	}
+	connection := str.cluster.Primary()
+	if err := database.NamedQueryStruct(ctx, str.logger, connection, query, queryParams, &removed); err != nil {
+		if err == database.ErrorNotFound {
+			return database.ErrorNotFound
+		}
+
+		return fmt.Errorf("error during delete of GroupAccess entity -> project_id={%q}, group_id={%q}: %w",
+			projectId, groupId, err)
+	}
+
+	return nil
+}
+
+// QueryProjectGroups looking for Group entities which have access to Project entity with ascending order by name
+// field. Groups of a project which is not readable by the claims subject are not returned.
+func (str Store) QueryProjectGroups(ctx context.Context, claims auth.Claims, projectId string) ([]Group, error) {
+	if err := uuid.Validate(projectId); err != nil {
+		return nil, err
+	}
+
+	queryParams := struct {
+		Visibility
+		ProjectID string `db:"project_id"`
+	}{
+		Visibility: NewVisibility(claims),
+		ProjectID:  projectId,
+	}
+
+	const query = `
+	SELECT
+		g.project_group_id,
+		g.name,
+		g.date_created,
+		g.created_by_user_id,
+		g.date_updated,
+		g.updated_by_user_id
+	FROM
+		PROJECT_GROUP AS g
+		JOIN PROJECT_GROUP_ACCESS AS ga ON ga.project_group_id = g.project_group_id
+		JOIN PROJECT AS p ON p.project_id = ga.project_id
+	WHERE
+		ga.project_id = :project_id AND ` + ReadableCondition + `
+	ORDER BY g.name`
+
+	var groups []Group
+	connection := str.cluster.Reader(ctx)
+	if err := database.NamedQuerySlice(ctx, str.logger, connection, query, queryParams, &groups); err != nil {
+		if err == database.ErrorNotFound {
+			return nil, database.ErrorNotFound
+		}
+
+		return nil, fmt.Errorf("error during search of Group entities -> project_id={%q}: %w", projectId, err)
+	}
+
+	return groups, nil
+}
+
+// QueryProjectsForUser looking for Project entities which the claims subject reaches through membership in a group
+// with access to the project, using skip/top mechanics with descending order by update date field.
+// Private projects of other users are excluded even when the subject has group access to them.
+// Archived projects are returned only if includeArchived is set.
+func (str Store) QueryProjectsForUser(ctx context.Context, claims auth.Claims, includeArchived bool, skip int32,
+	top int32) ([]Project, error) {
+	queryParams := struct {
+		Visibility
+		UserID          string `db:"user_id"`
+		IncludeArchived bool   `db:"include_archived"`
+		Skip            int32  `db:"offset"`
+		Top             int32  `db:"top"`
+	}{
+		Visibility:      NewVisibility(claims),
+		UserID:          claims.Subject,
+		IncludeArchived: includeArchived,
+		Skip:            skip,
+		Top:             top,
+	}
+
+	const query = `
+	SELECT
+		p.project_id,
+		p.project_collaboration_type_id,
+		p.name,
+		p.description,
+		p.date_created,
+		p.created_by_user_id,
+		p.date_updated,
+		p.updated_by_user_id,
+		p.date_archived,
+		p.date_deleted
+	FROM
+		PROJECT AS p
+	WHERE
+		EXISTS(SELECT 1
+			FROM
+				PROJECT_GROUP_ACCESS AS ga
+				JOIN PROJECT_GROUP_USER AS gu ON gu.project_group_id = ga.project_group_id
+			WHERE
+				ga.project_id = p.project_id AND gu.user_id = :user_id)
+		AND ` + ReadableCondition + `
+		AND (:include_archived OR p.date_archived IS NULL)
+	ORDER BY p.date_updated DESC
+	LIMIT :top OFFSET :offset`
+
+	var projectCollection []Project
+	connection := str.cluster.Reader(ctx)
+	if err := database.NamedQuerySlice(ctx, str.logger, connection, query, queryParams,
+		&projectCollection); err != nil {
+		if err == database.ErrorNotFound {
+			return nil, database.ErrorNotFound
+		}
+
+		return nil, fmt.Errorf("error during search of Project entities -> user_id={%q}: %w", claims.Subject, err)
+	}
+
+	return projectCollection, nil
+}
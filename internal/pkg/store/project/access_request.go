@@ -0,0 +1,430 @@
+package project
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/SKorolchuk/dpio-workspace/internal/pkg/infra/auth"
+	"github.com/SKorolchuk/dpio-workspace/internal/pkg/infra/database"
+	"github.com/SKorolchuk/dpio-workspace/internal/pkg/infra/uuid"
+	"github.com/SKorolchuk/dpio-workspace/internal/pkg/infra/validation"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// ErrorAccessRequestClosed is returned when AccessRequest entity is already approved or rejected.
+var ErrorAccessRequestClosed = errors.New("access request is already decided")
+
+// AccessRequestEvent describes a change of AccessRequest entity, Status field of the request tells which change
+// happened. OwnerIDs contains users who decide the request.
+type AccessRequestEvent struct {
+	Request  AccessRequest
+	OwnerIDs []string
+}
+
+// AccessRequestHook is called after a change of AccessRequest entity is stored, e.g. to notify project owners
+// about new requests or requesters about decisions. Hooks should not block for a long time.
+type AccessRequestHook func(ctx context.Context, event AccessRequestEvent)
+
+// RequestAccess adds new pending AccessRequest entity of the claims subject to Team Project entity.
+// If error occurs, the method can return database.ErrorNotFound for a project hidden from the subject,
+// database.ErrorConflict when the subject can already read the project or has a pending request,
+// validation or other database errors.
+func (str Store) RequestAccess(ctx context.Context, claims auth.Claims, projectId string, request NewAccessRequest,
+	now time.Time) (AccessRequest, error) {
+	if err := uuid.Validate(projectId); err != nil {
+		return AccessRequest{}, database.ErrorInvalidIdentifier
+	}
+
+	if err := validation.Check(ctx, request); err != nil {
+		return AccessRequest{}, fmt.Errorf("error during data validation of AccessRequest entity: %w", err)
+	}
+
+	projectData, err := str.queryProjectByID(ctx, projectId)
+	if err != nil {
+		return AccessRequest{}, fmt.Errorf("error during search of Project entity -> id={%q}: %w", projectId, err)
+	}
+
+	readable, err := str.CanReadProject(ctx, claims, projectId)
+	if err != nil {
+		return AccessRequest{}, fmt.Errorf("error during search of Project entity -> id={%q}: %w", projectId, err)
+	}
+
+	if err := checkAccessRequestAllowed(projectData, readable); err != nil {
+		return AccessRequest{}, fmt.Errorf("error during create of new AccessRequest entity -> project_id={%q}: %w",
+			projectId, err)
+	}
+
+	requestData := newAccessRequest(claims, projectId, request, now)
+
+	const query = `
+	INSERT INTO PROJECT_ACCESS_REQUEST
+		(project_access_request_id, project_id, user_id, message, status, date_created)
+	VALUES
+		(:project_access_request_id, :project_id, :user_id, :message, :status, :date_created)`
+
+	if err := database.NamedExecContext(ctx, str.logger, str.cluster.Primary(), query, requestData); err != nil {
+		return AccessRequest{}, fmt.Errorf("error during create of new AccessRequest entity -> project_id={%q}: %w",
+			projectId, err)
+	}
+
+	str.notify(ctx, requestData)
+
+	return requestData, nil
+}
+
+// ApproveAccessRequest approves pending AccessRequest entity and assigns the requester to Group entity which has
+// access to the project. Only the project owner who also manages the group, i.e. created it, can approve requests.
+// If approval is successful, the method returns GroupUser entity.
+// If error occurs, the method can return ErrorAccessRequestClosed, database.ErrorInvalidReference for a group
+// without access to the project, database.ErrorForbidden for a group managed by another user,
+// database.ErrorConflict for a requester who is already a group member, validation or other database errors.
+func (str Store) ApproveAccessRequest(ctx context.Context, claims auth.Claims, requestId string,
+	approval ApproveAccessRequest, now time.Time) (GroupUser, error) {
+	if err := uuid.Validate(requestId); err != nil {
+		return GroupUser{}, database.ErrorInvalidIdentifier
+	}
+
+	if err := validation.Check(ctx, approval); err != nil {
+		return GroupUser{}, fmt.Errorf("error during data validation of AccessRequest entity: %w", err)
+	}
+
+	requestData, err := str.ownedAccessRequest(ctx, claims, requestId)
+	if err != nil {
+		return GroupUser{}, err
+	}
+
+	connection := str.cluster.Primary()
+	if err := str.checkGroupAccess(ctx, connection, requestData.ProjectID, approval.GroupID); err != nil {
+		return GroupUser{}, fmt.Errorf("error during search of GroupAccess entity -> project_id={%q}, "+
+			"group_id={%q}: %w", requestData.ProjectID, approval.GroupID, err)
+	}
+
+	if err := str.checkGroupManager(ctx, connection, approval.GroupID, claims.Subject); err != nil {
+		return GroupUser{}, fmt.Errorf("error during search of Group entity -> id={%q}: %w", approval.GroupID, err)
+	}
+
+	decideAccessRequest(&requestData, claims, AccessRequestApproved, now)
+	requestData.GroupID = &approval.GroupID
+
+	groupUserData := GroupUser{
+		ID:            uuid.Generate(),
+		GroupID:       approval.GroupID,
+		UserID:        requestData.UserID,
+		DateCreated:   now,
+		CreatedByUser: claims.Subject,
+	}
+
+	const insertQuery = `
+	INSERT INTO PROJECT_GROUP_USER
+		(project_group_user_id, project_group_id, user_id, date_created, created_by_user_id)
+	VALUES
+		(:project_group_user_id, :project_group_id, :user_id, :date_created, :created_by_user_id)`
+
+	err = database.WithTransaction(ctx, connection, func(transaction *sqlx.Tx) error {
+		if err := str.storeDecision(ctx, transaction, requestData); err != nil {
+			return err
+		}
+
+		return database.NamedExecContext(ctx, str.logger, transaction, insertQuery, groupUserData)
+	})
+	if err != nil {
+		return GroupUser{}, fmt.Errorf("error during approval of AccessRequest entity -> id={%q}: %w", requestId, err)
+	}
+
+	str.notify(ctx, requestData)
+
+	return groupUserData, nil
+}
+
+// RejectAccessRequest rejects pending AccessRequest entity with a reason.
+// Only the project owner can decide requests.
+// If error occurs, the method can return ErrorAccessRequestClosed, validation or database errors.
+func (str Store) RejectAccessRequest(ctx context.Context, claims auth.Claims, requestId string,
+	rejection RejectAccessRequest, now time.Time) error {
+	if err := uuid.Validate(requestId); err != nil {
+		return database.ErrorInvalidIdentifier
+	}
+
+	if err := validation.Check(ctx, rejection); err != nil {
+		return fmt.Errorf("error during data validation of AccessRequest entity: %w", err)
+	}
+
+	requestData, err := str.ownedAccessRequest(ctx, claims, requestId)
+	if err != nil {
+		return err
+	}
+
+	decideAccessRequest(&requestData, claims, AccessRequestRejected, now)
+	requestData.Reason = &rejection.Reason
+
+	if err := str.storeDecision(ctx, str.cluster.Primary(), requestData); err != nil {
+		return fmt.Errorf("error during rejection of AccessRequest entity -> id={%q}: %w", requestId, err)
+	}
+
+	str.notify(ctx, requestData)
+
+	return nil
+}
+
+// QueryPendingAccessRequests looking for pending AccessRequest entities of Project entity using skip/top mechanics
+// with ascending order by creation date field, so the oldest requests go first.
+// Only the project owner can see requests.
+func (str Store) QueryPendingAccessRequests(ctx context.Context, claims auth.Claims, projectId string, skip int32,
+	top int32) ([]AccessRequest, error) {
+	if err := uuid.Validate(projectId); err != nil {
+		return nil, database.ErrorInvalidIdentifier
+	}
+
+	if _, err := str.ownedProject(ctx, claims, projectId); err != nil {
+		return nil, err
+	}
+
+	queryParams := struct {
+		ProjectID string `db:"project_id"`
+		Status    string `db:"status"`
+		Skip      int32  `db:"offset"`
+		Top       int32  `db:"top"`
+	}{
+		ProjectID: projectId,
+		Status:    AccessRequestPending,
+		Skip:      skip,
+		Top:       top,
+	}
+
+	const query = `
+	SELECT
+		r.project_access_request_id,
+		r.project_id,
+		r.user_id,
+		r.message,
+		r.status,
+		r.project_group_id,
+		r.reason,
+		r.date_created,
+		r.date_decided,
+		r.decided_by_user_id
+	FROM
+		PROJECT_ACCESS_REQUEST AS r
+	WHERE
+		r.project_id = :project_id AND r.status = :status
+	ORDER BY r.date_created
+	LIMIT :top OFFSET :offset`
+
+	var requests []AccessRequest
+	connection := str.cluster.Reader(ctx)
+	if err := database.NamedQuerySlice(ctx, str.logger, connection, query, queryParams, &requests); err != nil {
+		if err == database.ErrorNotFound {
+			return nil, database.ErrorNotFound
+		}
+
+		return nil, fmt.Errorf("error during search of AccessRequest entities -> project_id={%q}: %w", projectId,
+			err)
+	}
+
+	return requests, nil
+}
+
+// QueryAccessRequestsByUser looking for AccessRequest entities of the claims subject using skip/top mechanics with
+// descending order by creation date field.
+func (str Store) QueryAccessRequestsByUser(ctx context.Context, claims auth.Claims, skip int32,
+	top int32) ([]AccessRequest, error) {
+	queryParams := struct {
+		UserID string `db:"user_id"`
+		Skip   int32  `db:"offset"`
+		Top    int32  `db:"top"`
+	}{
+		UserID: claims.Subject,
+		Skip:   skip,
+		Top:    top,
+	}
+
+	const query = `
+	SELECT
+		r.project_access_request_id,
+		r.project_id,
+		r.user_id,
+		r.message,
+		r.status,
+		r.project_group_id,
+		r.reason,
+		r.date_created,
+		r.date_decided,
+		r.decided_by_user_id
+	FROM
+		PROJECT_ACCESS_REQUEST AS r
+	WHERE
+		r.user_id = :user_id
+	ORDER BY r.date_created DESC
+	LIMIT :top OFFSET :offset`
+
+	var requests []AccessRequest
+	connection := str.cluster.Reader(ctx)
+	if err := database.NamedQuerySlice(ctx, str.logger, connection, query, queryParams, &requests); err != nil {
+		if err == database.ErrorNotFound {
+			return nil, database.ErrorNotFound
+		}
+
+		return nil, fmt.Errorf("error during search of AccessRequest entities -> user_id={%q}: %w", claims.Subject,
+			err)
+	}
+
+	return requests, nil
+}
+
+// ownedAccessRequest returns pending AccessRequest entity if the claims subject owns its project.
+func (str Store) ownedAccessRequest(ctx context.Context, claims auth.Claims, requestId string) (AccessRequest,
+	error) {
+	queryParams := struct {
+		RequestID string `db:"project_access_request_id"`
+	}{
+		RequestID: requestId,
+	}
+
+	const query = `
+	SELECT
+		r.project_access_request_id,
+		r.project_id,
+		r.user_id,
+		r.message,
+		r.status,
+		r.project_group_id,
+		r.reason,
+		r.date_created,
+		r.date_decided,
+		r.decided_by_user_id
+	FROM
+		PROJECT_ACCESS_REQUEST AS r
+	WHERE
+		r.project_access_request_id = :project_access_request_id`
+
+	var requestData AccessRequest
+	connection := str.cluster.Primary()
+	if err := database.NamedQueryStruct(ctx, str.logger, connection, query, queryParams, &requestData); err != nil {
+		return AccessRequest{}, fmt.Errorf("error during search of AccessRequest entity -> id={%q}: %w",
+			requestId, err)
+	}
+
+	if _, err := str.ownedProject(ctx, claims, requestData.ProjectID); err != nil {
+		return AccessRequest{}, err
+	}
+
+	if requestData.Status != AccessRequestPending {
+		return AccessRequest{}, fmt.Errorf("error during decision of AccessRequest entity -> id={%q}: %w",
+			requestId, ErrorAccessRequestClosed)
+	}
+
+	return requestData, nil
+}
+
+// storeDecision saves decision of pending AccessRequest entity.
+// If the request was decided concurrently, the method returns ErrorAccessRequestClosed.
+func (str Store) storeDecision(ctx context.Context, connection sqlx.ExtContext, requestData AccessRequest) error {
+	queryParams := struct {
+		AccessRequest
+		PendingStatus string `db:"pending_status"`
+	}{
+		AccessRequest: requestData,
+		PendingStatus: AccessRequestPending,
+	}
+
+	const query = `
+	UPDATE
+		PROJECT_ACCESS_REQUEST
+	SET
+		"status" = :status,
+		"project_group_id" = :project_group_id,
+		"reason" = :reason,
+		"date_decided" = :date_decided,
+		"decided_by_user_id" = :decided_by_user_id
+	WHERE
+		project_access_request_id = :project_access_request_id AND status = :pending_status
+	RETURNING project_access_request_id`
+
+	var decided struct {
+		ID string `db:"project_access_request_id"`
+	}
+	if err := database.NamedQueryStruct(ctx, str.logger, connection, query, queryParams, &decided); err != nil {
+		if err == database.ErrorNotFound {
+			return ErrorAccessRequestClosed
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+// notify calls AccessRequestHook functions of Store with the changed request.
+// Failed search of the project owners is logged, the hooks are called without them.
+func (str Store) notify(ctx context.Context, requestData AccessRequest) {
+	if len(str.hooks) == 0 {
+		return
+	}
+
+	owners, err := str.queryProjectOwners(ctx, str.cluster.Primary(), requestData.ProjectID)
+	if err != nil && err != database.ErrorNotFound {
+		str.logger.Warnw("project.NotifyAccessRequest", "id", requestData.ID, "error", err)
+	}
+
+	callAccessRequestHooks(ctx, str.hooks, requestData, owners)
+}
+
+// callAccessRequestHooks calls hooks with AccessRequestEvent of the changed request.
+func callAccessRequestHooks(ctx context.Context, hooks []AccessRequestHook, requestData AccessRequest,
+	owners []ProjectOwner) {
+	event := AccessRequestEvent{
+		Request:  requestData,
+		OwnerIDs: make([]string, 0, len(owners)),
+	}
+	for _, owner := range owners {
+		event.OwnerIDs = append(event.OwnerIDs, owner.UserID)
+	}
+
+	for _, hook := range hooks {
+		hook(ctx, event)
+	}
+}
+
+// checkAccessRequestAllowed confirms that access to the project can be requested.
+// Private projects stay hidden, so requests to them are reported with database.ErrorNotFound.
+func checkAccessRequestAllowed(projectData Project, readable bool) error {
+	if readable {
+		return database.ErrorConflict
+	}
+
+	if projectData.ProjectTypeID != TeamCollaborationType {
+		return database.ErrorNotFound
+	}
+
+	return nil
+}
+
+// newAccessRequest creates pending AccessRequest entity from the request data.
+func newAccessRequest(claims auth.Claims, projectId string, request NewAccessRequest, now time.Time) AccessRequest {
+	requestData := AccessRequest{
+		ID:          uuid.Generate(),
+		ProjectID:   projectId,
+		UserID:      claims.Subject,
+		Status:      AccessRequestPending,
+		DateCreated: now,
+	}
+
+	if request.Message != "" {
+		message := request.Message
+		requestData.Message = &message
+	}
+
+	return requestData
+}
+
+// decideAccessRequest fills decision fields of AccessRequest entity.
+func decideAccessRequest(requestData *AccessRequest, claims auth.Claims, status string, now time.Time) {
+	decidedBy := claims.Subject
+
+	requestData.Status = status
+	requestData.DateDecided = &now
+	requestData.DecidedByUser = &decidedBy
+}
@@ -0,0 +1,142 @@
+package project
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/SKorolchuk/dpio-workspace/internal/pkg/infra/database"
+)
+
+func TestMemoryStoreRequestAccess(t *testing.T) {
+	str := NewMemoryStore()
+	ctx := context.Background()
+	team := mustCreateProject(t, str, "Team", TeamCollaborationType, baseDate)
+	public := mustCreateProject(t, str, "Public", PublicCollaborationType, baseDate)
+	private := mustCreateProject(t, str, "Private", PrivateCollaborationType, baseDate)
+
+	tests := []struct {
+		name      string
+		userId    string
+		projectId string
+		wantErr   error
+	}{
+		{name: "team project", userId: strangerID, projectId: team.ID},
+		{name: "repeated request", userId: strangerID, projectId: team.ID, wantErr: database.ErrorConflict},
+		{name: "readable project", userId: strangerID, projectId: public.ID, wantErr: database.ErrorConflict},
+		{name: "own project", userId: ownerID, projectId: team.ID, wantErr: database.ErrorConflict},
+		{name: "private project", userId: strangerID, projectId: private.ID, wantErr: database.ErrorNotFound},
+		{name: "missing project", userId: strangerID, projectId: missingID, wantErr: database.ErrorNotFound},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := str.RequestAccess(ctx, claimsOf(test.userId), test.projectId, NewAccessRequest{}, baseDate)
+			if !errors.Is(err, test.wantErr) {
+				t.Errorf("RequestAccess() error = %v, want %v", err, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestMemoryStoreApproveAccessRequest(t *testing.T) {
+	str := NewMemoryStore()
+	ctx := context.Background()
+	projectData := mustCreateProject(t, str, "Team", TeamCollaborationType, baseDate)
+	if _, err := str.AddProjectOwner(ctx, claimsOf(ownerID), projectData.ID, NewProjectOwner{UserID: memberID},
+		baseDate); err != nil {
+		t.Fatalf("AddProjectOwner() error = %v", err)
+	}
+
+	grantedGroup := func(userId string, name string) Group {
+		groupData, err := str.CreateGroup(ctx, claimsOf(userId), NewGroup{Name: name}, baseDate)
+		if err != nil {
+			t.Fatalf("CreateGroup() error = %v", err)
+		}
+		if _, err := str.GrantGroupAccess(ctx, claimsOf(userId), projectData.ID,
+			NewGroupAccess{GroupID: groupData.ID}, baseDate); err != nil {
+			t.Fatalf("GrantGroupAccess() error = %v", err)
+		}
+
+		return groupData
+	}
+	ownGroup, coOwnerGroup := grantedGroup(ownerID, "Own"), grantedGroup(memberID, "Co-owner")
+
+	requestData, err := str.RequestAccess(ctx, claimsOf(strangerID), projectData.ID,
+		NewAccessRequest{Message: "Let me in"}, baseDate)
+	if err != nil {
+		t.Fatalf("RequestAccess() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		userId  string
+		groupId string
+		wantErr error
+	}{
+		{name: "requester", userId: strangerID, groupId: ownGroup.ID, wantErr: database.ErrorForbidden},
+		{name: "group without access", userId: ownerID, groupId: missingID,
+			wantErr: database.ErrorInvalidReference},
+		{name: "group of another owner", userId: ownerID, groupId: coOwnerGroup.ID,
+			wantErr: database.ErrorForbidden},
+		{name: "own group", userId: ownerID, groupId: ownGroup.ID},
+		{name: "decided request", userId: memberID, groupId: coOwnerGroup.ID, wantErr: ErrorAccessRequestClosed},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := str.ApproveAccessRequest(ctx, claimsOf(test.userId), requestData.ID,
+				ApproveAccessRequest{GroupID: test.groupId}, baseDate.Add(time.Hour))
+			if !errors.Is(err, test.wantErr) {
+				t.Errorf("ApproveAccessRequest() error = %v, want %v", err, test.wantErr)
+			}
+		})
+	}
+
+	canRead, err := str.CanReadProject(ctx, claimsOf(strangerID), projectData.ID)
+	if err != nil || !canRead {
+		t.Errorf("CanReadProject() of approved requester = %v, %v, want true", canRead, err)
+	}
+}
+
+func TestMemoryStoreRejectAccessRequest(t *testing.T) {
+	str := NewMemoryStore()
+	ctx := context.Background()
+	projectData := mustCreateProject(t, str, "Team", TeamCollaborationType, baseDate)
+
+	requestData, err := str.RequestAccess(ctx, claimsOf(strangerID), projectData.ID, NewAccessRequest{}, baseDate)
+	if err != nil {
+		t.Fatalf("RequestAccess() error = %v", err)
+	}
+
+	rejection := RejectAccessRequest{Reason: "Not now"}
+	if err := str.RejectAccessRequest(ctx, claimsOf(strangerID), requestData.ID, rejection,
+		baseDate); !errors.Is(err, database.ErrorForbidden) {
+		t.Errorf("RejectAccessRequest() by requester error = %v, want %v", err, database.ErrorForbidden)
+	}
+	if err := str.RejectAccessRequest(ctx, claimsOf(ownerID), requestData.ID, rejection, baseDate); err != nil {
+		t.Fatalf("RejectAccessRequest() error = %v", err)
+	}
+
+	pending, err := str.QueryPendingAccessRequests(ctx, claimsOf(ownerID), projectData.ID, 0, 10)
+	if err != nil && !errors.Is(err, database.ErrorNotFound) {
+		t.Fatalf("QueryPendingAccessRequests() error = %v", err)
+	}
+	if len(pending) != 0 {
+		t.Errorf("QueryPendingAccessRequests() returned %d requests, want 0", len(pending))
+	}
+
+	requests, err := str.QueryAccessRequestsByUser(ctx, claimsOf(strangerID), 0, 10)
+	if err != nil {
+		t.Fatalf("QueryAccessRequestsByUser() error = %v", err)
+	}
+	if len(requests) != 1 || requests[0].Status != AccessRequestRejected || *requests[0].Reason != rejection.Reason {
+		t.Errorf("QueryAccessRequestsByUser() = %+v, want the rejected request", requests)
+	}
+
+	if _, err := str.RequestAccess(ctx, claimsOf(strangerID), projectData.ID, NewAccessRequest{},
+		baseDate.Add(time.Hour)); err != nil {
+		t.Errorf("RequestAccess() after rejection error = %v", err)
+	}
+}
@@ -0,0 +1,48 @@
+package project
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/SKorolchuk/dpio-workspace/internal/pkg/infra/database"
+)
+
+func TestMemoryStoreGrantGroupAccess(t *testing.T) {
+	str := NewMemoryStore()
+	ctx := context.Background()
+	projectData := mustCreateProject(t, str, "Team", TeamCollaborationType, baseDate)
+
+	ownGroup, err := str.CreateGroup(ctx, claimsOf(ownerID), NewGroup{Name: "Own"}, baseDate)
+	if err != nil {
+		t.Fatalf("CreateGroup() error = %v", err)
+	}
+	foreignGroup, err := str.CreateGroup(ctx, claimsOf(strangerID), NewGroup{Name: "Foreign"}, baseDate)
+	if err != nil {
+		t.Fatalf("CreateGroup() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		userId  string
+		groupId string
+		wantErr error
+	}{
+		{name: "project of another user", userId: strangerID, groupId: foreignGroup.ID,
+			wantErr: database.ErrorForbidden},
+		{name: "unknown group", userId: ownerID, groupId: missingID, wantErr: database.ErrorInvalidReference},
+		{name: "group of another user", userId: ownerID, groupId: foreignGroup.ID, wantErr: database.ErrorForbidden},
+		{name: "own group", userId: ownerID, groupId: ownGroup.ID},
+		{name: "repeated grant", userId: ownerID, groupId: ownGroup.ID, wantErr: database.ErrorConflict},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := str.GrantGroupAccess(ctx, claimsOf(test.userId), projectData.ID,
+				NewGroupAccess{GroupID: test.groupId}, baseDate)
+			if !errors.Is(err, test.wantErr) {
+				t.Errorf("GrantGroupAccess() error = %v, want %v", err, test.wantErr)
+			}
+		})
+	}
+}
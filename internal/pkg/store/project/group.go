@@ -1 +1,438 @@
 package project
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/SKorolchuk/dpio-workspace/internal/pkg/infra/auth"
+	"github.com/SKorolchuk/dpio-workspace/internal/pkg/infra/database"
+	"github.com/SKorolchuk/dpio-workspace/internal/pkg/infra/uuid"
+	"github.com/SKorolchuk/dpio-workspace/internal/pkg/infra/validation"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// CreateGroup adds new Group entity to the database.
+// If creation is successful, the method returns Group entity.
+// Can return validation or database errors.
+func (str Store) CreateGroup(ctx context.Context, claims auth.Claims, group NewGroup, now time.Time) (Group, error) {
+	if err := validation.Check(ctx, group); err != nil {
+		return Group{}, fmt.Errorf("error during data validation of Group entity: %w", err)
+	}
+
+	groupData := Group{
+		ID:            uuid.Generate(),
+		Name:          group.Name,
+		DateCreated:   now,
+		CreatedByUser: claims.Subject,
+		DateUpdated:   now,
+		UpdatedByUser: claims.Subject,
+	}
+
+	const query = `
+	INSERT INTO PROJECT_GROUP
+		(project_group_id, name, date_created, created_by_user_id, date_updated, updated_by_user_id)
+	VALUES
+		(:project_group_id, :name, :date_created, :created_by_user_id, :date_updated, :updated_by_user_id)`
+
+	if err := database.NamedExecContext(ctx, str.logger, str.cluster.Primary(), query, groupData); err != nil {
+		return Group{}, fmt.Errorf("error during create of new Group entity: %w", err)
+	}
+
+	return groupData, nil
+}
+
+// UpdateGroup renames existing Group entity in the database.
+// If error occurs, the method can return validation or database errors.
+func (str Store) UpdateGroup(ctx context.Context, claims auth.Claims, groupId string, group UpdateGroup,
+	now time.Time) error {
+	if err := uuid.Validate(groupId); err != nil {
+		return database.ErrorInvalidIdentifier
+	}
+
+	if err := validation.Check(ctx, group); err != nil {
+		return fmt.Errorf("error during data validation of Group entity: %w", err)
+	}
+
+	groupData, err := str.queryGroup(ctx, groupId)
+	if err != nil {
+		return fmt.Errorf("error during search of Group entity -> id={%q}: %w", groupId, err)
+	}
+
+	if groupData.CreatedByUser != claims.Subject {
+		return database.ErrorForbidden
+	}
+
+	groupData.Name = *group.Name
+	groupData.DateUpdated = now
+	groupData.UpdatedByUser = claims.Subject
+
+	const query = `
+	UPDATE
+		PROJECT_GROUP
+	SET
+		"name" = :name,
+		"date_updated" = :date_updated,
+		"updated_by_user_id" = :updated_by_user_id
+	WHERE
+		project_group_id = :project_group_id`
+
+	if err := database.NamedExecContext(ctx, str.logger, str.cluster.Primary(), query, groupData); err != nil {
+		return fmt.Errorf("error during update of Group entity -> id={%q}: %w", groupId, err)
+	}
+
+	return nil
+}
+
+// DeleteGroup removes existing Group entity in the database together with its user, role and project assignments
+// and invitations. Decided access requests keep their history without reference to the group.
+// If error occurs, the method can return database errors.
+func (str Store) DeleteGroup(ctx context.Context, claims auth.Claims, groupId string) error {
+	if err := uuid.Validate(groupId); err != nil {
+		return database.ErrorInvalidIdentifier
+	}
+
+	groupData, err := str.queryGroup(ctx, groupId)
+	if err != nil {
+		return fmt.Errorf("error during search of Group entity -> id={%q}: %w", groupId, err)
+	}
+
+	if groupData.CreatedByUser != claims.Subject {
+		return database.ErrorForbidden
+	}
+
+	queryParams := struct {
+		GroupID string `db:"project_group_id"`
+	}{
+		GroupID: groupId,
+	}
+
+	const deleteUsersQuery = `
+	DELETE FROM
+		PROJECT_GROUP_USER
+	WHERE
+		project_group_id = :project_group_id`
+
+	const deleteRolesQuery = `
+	DELETE FROM
+		PROJECT_GROUP_ROLE
+	WHERE
+		project_group_id = :project_group_id`
+
+	const deleteAccessQuery = `
+	DELETE FROM
+		PROJECT_GROUP_ACCESS
+	WHERE
+		project_group_id = :project_group_id`
+
+	const deleteInvitationsQuery = `
+	DELETE FROM
+		PROJECT_INVITATION
+	WHERE
+		project_group_id = :project_group_id`
+
+	const detachAccessRequestsQuery = `
+	UPDATE
+		PROJECT_ACCESS_REQUEST
+	SET
+		"project_group_id" = NULL
+	WHERE
+		project_group_id = :project_group_id`
+
+	const deleteGroupQuery = `
+	DELETE FROM
+		PROJECT_GROUP
+	WHERE
+		project_group_id = :project_group_id`
+
+	err = database.WithTransaction(ctx, str.cluster.Primary(), func(transaction *sqlx.Tx) error {
+		for _, query := range []string{deleteUsersQuery, deleteRolesQuery, deleteAccessQuery, deleteInvitationsQuery,
+			detachAccessRequestsQuery, deleteGroupQuery} {
+			if err := database.NamedExecContext(ctx, str.logger, transaction, query, queryParams); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("error during delete of Group entity -> id={%q}: %w", groupId, err)
+	}
+
+	return nil
+}
+
+// AddGroupUser assigns a user to existing Group entity.
+// If assignment is successful, the method returns GroupUser entity.
+// Repeated assignment of the same user returns database.ErrorConflict.
+func (str Store) AddGroupUser(ctx context.Context, claims auth.Claims, groupId string, user NewGroupUser,
+	now time.Time) (GroupUser, error) {
+	if err := uuid.Validate(groupId); err != nil {
+		return GroupUser{}, database.ErrorInvalidIdentifier
+	}
+
+	if err := validation.Check(ctx, user); err != nil {
+		return GroupUser{}, fmt.Errorf("error during data validation of GroupUser entity: %w", err)
+	}
+
+	groupData, err := str.queryGroup(ctx, groupId)
+	if err != nil {
+		return GroupUser{}, fmt.Errorf("error during search of Group entity -> id={%q}: %w", groupId, err)
+	}
+
+	if groupData.CreatedByUser != claims.Subject {
+		return GroupUser{}, database.ErrorForbidden
+	}
+
+	groupUserData := GroupUser{
+		ID:            uuid.Generate(),
+		GroupID:       groupId,
+		UserID:        user.UserID,
+		DateCreated:   now,
+		CreatedByUser: claims.Subject,
+	}
+
+	const query = `
+	INSERT INTO PROJECT_GROUP_USER
+		(project_group_user_id, project_group_id, user_id, date_created, created_by_user_id)
+	VALUES
+		(:project_group_user_id, :project_group_id, :user_id, :date_created, :created_by_user_id)`
+
+	if err := database.NamedExecContext(ctx, str.logger, str.cluster.Primary(), query, groupUserData); err != nil {
+		return GroupUser{}, fmt.Errorf("error during create of new GroupUser entity -> group_id={%q}: %w", groupId,
+			err)
+	}
+
+	return groupUserData, nil
+}
+
+// RemoveGroupUser removes a user assignment from existing Group entity.
+// If the user is not a member of the group, the method returns database.ErrorNotFound.
+func (str Store) RemoveGroupUser(ctx context.Context, claims auth.Claims, groupId string, userId string) error {
+	if err := uuid.Validate(groupId); err != nil {
+		return database.ErrorInvalidIdentifier
+	}
+
+	groupData, err := str.queryGroup(ctx, groupId)
+	if err != nil {
+		return fmt.Errorf("error during search of Group entity -> id={%q}: %w", groupId, err)
+	}
+
+	if groupData.CreatedByUser != claims.Subject {
+		return database.ErrorForbidden
+	}
+
+	queryParams := struct {
+		GroupID string `db:"project_group_id"`
+		UserID  string `db:"user_id"`
+	}{
+		GroupID: groupId,
+		UserID:  userId,
+	}
+
+	const query = `
+	DELETE FROM
+		PROJECT_GROUP_USER
+	WHERE
+		project_group_id = :project_group_id AND user_id = :user_id
+	RETURNING project_group_user_id`
+
+	var removed struct {
+		ID string `db:"project_group_user_id"`
+	}
+	connection := str.cluster.Primary()
+	if err := database.NamedQueryStruct(ctx, str.logger, connection, query, queryParams, &removed); err != nil {
+		if err == database.ErrorNotFound {
+			return database.ErrorNotFound
+		}
+
+		return fmt.Errorf("error during delete of GroupUser entity -> group_id={%q}, user_id={%q}: %w", groupId,
+			userId, err)
+	}
+
+	return nil
+}
+
+// groupVisibleCondition is SQL condition of Group entities visible to the claims subject, i.e. created by the subject
+// or having the subject as a member. It expects alias g of PROJECT_GROUP and :subject_id parameter.
+const groupVisibleCondition = `(g.created_by_user_id = :subject_id OR EXISTS (
+		SELECT 1 FROM PROJECT_GROUP_USER AS m
+		WHERE m.project_group_id = g.project_group_id AND m.user_id = :subject_id))`
+
+// QueryGroupUsers looking for members of Group entity visible to the claims subject using skip/top mechanics with
+// descending order by creation date field. A group is visible to its creator and members.
+// If error occurs, the method can return database.ErrorNotFound for a group hidden from the subject or other
+// database errors.
+func (str Store) QueryGroupUsers(ctx context.Context, claims auth.Claims, groupId string, skip int32,
+	top int32) ([]GroupUser, error) {
+	if err := uuid.Validate(groupId); err != nil {
+		return nil, err
+	}
+
+	if _, err := str.QueryGroupByID(ctx, claims, groupId); err != nil {
+		return nil, err
+	}
+
+	queryParams := struct {
+		GroupID string `db:"project_group_id"`
+		Skip    int32  `db:"offset"`
+		Top     int32  `db:"top"`
+	}{
+		GroupID: groupId,
+		Skip:    skip,
+		Top:     top,
+	}
+
+	const query = `
+	SELECT
+		u.project_group_user_id,
+		u.project_group_id,
+		u.user_id,
+		u.date_created,
+		u.created_by_user_id
+	FROM
+		PROJECT_GROUP_USER AS u
+	WHERE
+		u.project_group_id = :project_group_id
+	ORDER BY u.date_created DESC
+	LIMIT :top OFFSET :offset`
+
+	var groupUsers []GroupUser
+	connection := str.cluster.Reader(ctx)
+	if err := database.NamedQuerySlice(ctx, str.logger, connection, query, queryParams, &groupUsers); err != nil {
+		if err == database.ErrorNotFound {
+			return nil, database.ErrorNotFound
+		}
+
+		return nil, fmt.Errorf("error during search of GroupUser entities -> group_id={%q}: %w", groupId, err)
+	}
+
+	return groupUsers, nil
+}
+
+// QueryGroupsByUser looking for Group entities which the user is a member of, using skip/top mechanics with
+// ascending order by name field. Only groups visible to the claims subject are returned, so users see all their
+// own groups and only the shared ones of other users.
+func (str Store) QueryGroupsByUser(ctx context.Context, claims auth.Claims, userId string, skip int32,
+	top int32) ([]Group, error) {
+	queryParams := struct {
+		UserID    string `db:"user_id"`
+		SubjectID string `db:"subject_id"`
+		Skip      int32  `db:"offset"`
+		Top       int32  `db:"top"`
+	}{
+		UserID:    userId,
+		SubjectID: claims.Subject,
+		Skip:      skip,
+		Top:       top,
+	}
+
+	const query = `
+	SELECT
+		g.project_group_id,
+		g.name,
+		g.date_created,
+		g.created_by_user_id,
+		g.date_updated,
+		g.updated_by_user_id
+	FROM
+		PROJECT_GROUP AS g
+		JOIN PROJECT_GROUP_USER AS u ON u.project_group_id = g.project_group_id
+	WHERE
+		u.user_id = :user_id AND ` + groupVisibleCondition + `
+	ORDER BY g.name
+	LIMIT :top OFFSET :offset`
+
+	var groups []Group
+	connection := str.cluster.Reader(ctx)
+	if err := database.NamedQuerySlice(ctx, str.logger, connection, query, queryParams, &groups); err != nil {
+		if err == database.ErrorNotFound {
+			return nil, database.ErrorNotFound
+		}
+
+		return nil, fmt.Errorf("error during search of Group entities -> user_id={%q}: %w", userId, err)
+	}
+
+	return groups, nil
+}
+
+// QueryGroupByID looking for Group entity with groupId identifier visible to the claims subject.
+// A group is visible to its creator and members, others receive database.ErrorNotFound.
+func (str Store) QueryGroupByID(ctx context.Context, claims auth.Claims, groupId string) (Group, error) {
+	if err := uuid.Validate(groupId); err != nil {
+		return Group{}, err
+	}
+
+	queryParams := struct {
+		GroupID   string `db:"project_group_id"`
+		SubjectID string `db:"subject_id"`
+	}{
+		GroupID:   groupId,
+		SubjectID: claims.Subject,
+	}
+
+	const query = `
+	SELECT
+		g.project_group_id,
+		g.name,
+		g.date_created,
+		g.created_by_user_id,
+		g.date_updated,
+		g.updated_by_user_id
+	FROM
+		PROJECT_GROUP AS g
+	WHERE
+		g.project_group_id = :project_group_id AND ` + groupVisibleCondition
+
+	var groupData Group
+	connection := str.cluster.Primary()
+	if err := database.NamedQueryStruct(ctx, str.logger, connection, query, queryParams, &groupData); err != nil {
+		if err == database.ErrorNotFound {
+			return Group{}, database.ErrorNotFound
+		}
+
+		return Group{}, fmt.Errorf("error during search of Group entity -> id={%q}: %w", groupId, err)
+	}
+
+	return groupData, nil
+}
+
+// queryGroup looking for Group entity with groupId identifier regardless of its visibility, so the caller can
+// decide whether the claims subject manages the group.
+func (str Store) queryGroup(ctx context.Context, groupId string) (Group, error) {
+	if err := uuid.Validate(groupId); err != nil {
+		return Group{}, err
+	}
+
+	queryParams := struct {
+		GroupID string `db:"project_group_id"`
+	}{
+		GroupID: groupId,
+	}
+
+	const query = `
+	SELECT
+		g.project_group_id,
+		g.name,
+		g.date_created,
+		g.created_by_user_id,
+		g.date_updated,
+		g.updated_by_user_id
+	FROM
+		PROJECT_GROUP AS g
+	WHERE
+		g.project_group_id = :project_group_id`
+
+	var groupData Group
+	connection := str.cluster.Primary()
+	if err := database.NamedQueryStruct(ctx, str.logger, connection, query, queryParams, &groupData); err != nil {
+		if err == database.ErrorNotFound {
+			return Group{}, database.ErrorNotFound
+		}
+
+		return Group{}, fmt.Errorf("error during search of Group entity -> id={%q}: %w", groupId, err)
+	}
+
+	return groupData, nil
+}
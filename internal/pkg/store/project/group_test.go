@@ -0,0 +1,85 @@
+package project
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/SKorolchuk/dpio-workspace/internal/pkg/infra/database"
+)
+
+func TestMemoryStoreQueryGroupByID(t *testing.T) {
+	str := NewMemoryStore()
+	groupData := mustJoinProject(t, str, mustCreateProject(t, str, "Shared", TeamCollaborationType, baseDate))
+
+	tests := []struct {
+		name    string
+		userId  string
+		groupId string
+		wantErr error
+	}{
+		{name: "missing group", userId: ownerID, groupId: missingID, wantErr: database.ErrorNotFound},
+		{name: "creator", userId: ownerID, groupId: groupData.ID},
+		{name: "member", userId: memberID, groupId: groupData.ID},
+		{name: "stranger", userId: strangerID, groupId: groupData.ID, wantErr: database.ErrorNotFound},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx, claims := context.Background(), claimsOf(test.userId)
+
+			_, err := str.QueryGroupByID(ctx, claims, test.groupId)
+			if !errors.Is(err, test.wantErr) {
+				t.Errorf("QueryGroupByID() error = %v, want %v", err, test.wantErr)
+			}
+
+			groupUsers, err := str.QueryGroupUsers(ctx, claims, test.groupId, 0, 10)
+			if !errors.Is(err, test.wantErr) {
+				t.Errorf("QueryGroupUsers() error = %v, want %v", err, test.wantErr)
+			}
+			if err == nil && len(groupUsers) != 1 {
+				t.Errorf("QueryGroupUsers() returned %d users, want 1", len(groupUsers))
+			}
+		})
+	}
+}
+
+func TestMemoryStoreQueryGroupsByUser(t *testing.T) {
+	const otherID = "7e8f9a0b-1c2d-4e3f-8a4b-5c6d7e8f9a0b"
+	str := NewMemoryStore()
+	mustJoinProject(t, str, mustCreateProject(t, str, "Shared", TeamCollaborationType, baseDate))
+
+	// The member joins another group created by strangerID, who can see only that one.
+	ctx, byStranger := context.Background(), claimsOf(strangerID)
+	groupData, err := str.CreateGroup(ctx, byStranger, NewGroup{Name: "Stranger's"}, baseDate)
+	if err != nil {
+		t.Fatalf("CreateGroup() error = %v", err)
+	}
+	if _, err := str.AddGroupUser(ctx, byStranger, groupData.ID, NewGroupUser{UserID: memberID},
+		baseDate); err != nil {
+		t.Fatalf("AddGroupUser() error = %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		userId     string
+		wantGroups int
+	}{
+		{name: "the user themself", userId: memberID, wantGroups: 2},
+		{name: "creator of a shared group", userId: ownerID, wantGroups: 1},
+		{name: "creator of another shared group", userId: strangerID, wantGroups: 1},
+		{name: "user without shared groups", userId: otherID},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			groups, err := str.QueryGroupsByUser(ctx, claimsOf(test.userId), memberID, 0, 10)
+			if err != nil {
+				t.Fatalf("QueryGroupsByUser() error = %v", err)
+			}
+			if len(groups) != test.wantGroups {
+				t.Errorf("QueryGroupsByUser() returned %d groups, want %d", len(groups), test.wantGroups)
+			}
+		})
+	}
+}
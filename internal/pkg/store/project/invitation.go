@@ -0,0 +1,397 @@
+package project
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/SKorolchuk/dpio-workspace/internal/pkg/infra/auth"
+	"github.com/SKorolchuk/dpio-workspace/internal/pkg/infra/database"
+	"github.com/SKorolchuk/dpio-workspace/internal/pkg/infra/uuid"
+	"github.com/SKorolchuk/dpio-workspace/internal/pkg/infra/validation"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// DefaultInvitationLifetime is used when NewInvitation does not specify its lifetime.
+const DefaultInvitationLifetime = 7 * 24 * time.Hour
+
+// ErrorInvitationClosed is returned when Invitation entity is already accepted, revoked or expired.
+var ErrorInvitationClosed = errors.New("invitation is accepted, revoked or expired")
+
+// CreateInvitation adds new Invitation entity of a user into Group entity which has access to Project entity.
+// Only the project owner who also manages the group, i.e. created it, can invite users.
+// If error occurs, the method can return database.ErrorInvalidReference for a group without access to the project,
+// database.ErrorForbidden for a group managed by another user, validation or other database errors.
+func (str Store) CreateInvitation(ctx context.Context, claims auth.Claims, projectId string,
+	invitation NewInvitation, now time.Time) (Invitation, error) {
+	if err := uuid.Validate(projectId); err != nil {
+		return Invitation{}, database.ErrorInvalidIdentifier
+	}
+
+	if err := validation.Check(ctx, invitation); err != nil {
+		return Invitation{}, fmt.Errorf("error during data validation of Invitation entity: %w", err)
+	}
+
+	if _, err := str.ownedProject(ctx, claims, projectId); err != nil {
+		return Invitation{}, err
+	}
+
+	connection := str.cluster.Primary()
+	if err := str.checkGroupAccess(ctx, connection, projectId, invitation.GroupID); err != nil {
+		return Invitation{}, fmt.Errorf("error during search of GroupAccess entity -> project_id={%q}, "+
+			"group_id={%q}: %w", projectId, invitation.GroupID, err)
+	}
+
+	if err := str.checkGroupManager(ctx, connection, invitation.GroupID, claims.Subject); err != nil {
+		return Invitation{}, fmt.Errorf("error during search of Group entity -> id={%q}: %w", invitation.GroupID, err)
+	}
+
+	invitationData := newInvitation(claims, projectId, invitation, now)
+
+	const query = `
+	INSERT INTO PROJECT_INVITATION
+		(project_invitation_id, project_id, project_group_id, invitee_user_id, invitee_email, date_expires,
+			date_created, created_by_user_id)
+	VALUES
+		(:project_invitation_id, :project_id, :project_group_id, :invitee_user_id, :invitee_email, :date_expires,
+			:date_created, :created_by_user_id)`
+
+	if err := database.NamedExecContext(ctx, str.logger, connection, query, invitationData); err != nil {
+		return Invitation{}, fmt.Errorf("error during create of new Invitation entity -> project_id={%q}: %w",
+			projectId, err)
+	}
+
+	return invitationData, nil
+}
+
+// RevokeInvitation marks pending Invitation entity of Project entity as revoked at now.
+// Only the project owner can revoke invitations.
+// If the invitation is not pending, the method returns database.ErrorNotFound.
+func (str Store) RevokeInvitation(ctx context.Context, claims auth.Claims, projectId string, invitationId string,
+	now time.Time) error {
+	if err := uuid.Validate(projectId); err != nil {
+		return database.ErrorInvalidIdentifier
+	}
+	if err := uuid.Validate(invitationId); err != nil {
+		return database.ErrorInvalidIdentifier
+	}
+
+	if _, err := str.ownedProject(ctx, claims, projectId); err != nil {
+		return err
+	}
+
+	queryParams := struct {
+		ProjectID    string    `db:"project_id"`
+		InvitationID string    `db:"project_invitation_id"`
+		Now          time.Time `db:"now"`
+	}{
+		ProjectID:    projectId,
+		InvitationID: invitationId,
+		Now:          now,
+	}
+
+	const query = `
+	UPDATE
+		PROJECT_INVITATION
+	SET
+		"date_revoked" = :now
+	WHERE
+		project_invitation_id = :project_invitation_id AND project_id = :project_id
+		AND date_accepted IS NULL AND date_revoked IS NULL AND date_expires > :now
+	RETURNING project_invitation_id`
+
+	var revoked struct {
+		ID string `db:"project_invitation_id"`
+	}
+	connection := str.cluster.Primary()
+	if err := database.NamedQueryStruct(ctx, str.logger, connection, query, queryParams, &revoked); err != nil {
+		if err == database.ErrorNotFound {
+			return database.ErrorNotFound
+		}
+
+		return fmt.Errorf("error during revocation of Invitation entity -> id={%q}: %w", invitationId, err)
+	}
+
+	return nil
+}
+
+// AcceptInvitation accepts pending Invitation entity on behalf of the claims subject and assigns the subject to
+// the invitation group. The invitation can be accepted only once by the invited user.
+// If acceptance is successful, the method returns GroupUser entity.
+// The group should still have access to the project and be managed by the inviter.
+// If error occurs, the method can return ErrorInvitationClosed, database.ErrorForbidden for another user or a group
+// no longer managed by the inviter, database.ErrorInvalidReference for a group without access to the project,
+// database.ErrorConflict for a user who is already a group member or other database errors.
+func (str Store) AcceptInvitation(ctx context.Context, claims auth.Claims, invitationId string,
+	now time.Time) (GroupUser, error) {
+	if err := uuid.Validate(invitationId); err != nil {
+		return GroupUser{}, database.ErrorInvalidIdentifier
+	}
+
+	queryParams := struct {
+		InvitationID string    `db:"project_invitation_id"`
+		UserID       string    `db:"accepted_by_user_id"`
+		Now          time.Time `db:"now"`
+	}{
+		InvitationID: invitationId,
+		UserID:       claims.Subject,
+		Now:          now,
+	}
+
+	const selectQuery = `
+	SELECT
+		i.project_invitation_id,
+		i.project_id,
+		i.project_group_id,
+		i.invitee_user_id,
+		i.invitee_email,
+		i.date_expires,
+		i.date_accepted,
+		i.accepted_by_user_id,
+		i.date_revoked,
+		i.date_created,
+		i.created_by_user_id
+	FROM
+		PROJECT_INVITATION AS i
+	WHERE
+		i.project_invitation_id = :project_invitation_id`
+
+	const acceptQuery = `
+	UPDATE
+		PROJECT_INVITATION
+	SET
+		"date_accepted" = :now,
+		"accepted_by_user_id" = :accepted_by_user_id
+	WHERE
+		project_invitation_id = :project_invitation_id AND date_accepted IS NULL AND date_revoked IS NULL
+	RETURNING project_invitation_id`
+
+	const insertQuery = `
+	INSERT INTO PROJECT_GROUP_USER
+		(project_group_user_id, project_group_id, user_id, date_created, created_by_user_id)
+	VALUES
+		(:project_group_user_id, :project_group_id, :user_id, :date_created, :created_by_user_id)`
+
+	var groupUserData GroupUser
+	err := database.WithTransaction(ctx, str.cluster.Primary(), func(transaction *sqlx.Tx) error {
+		var invitationData Invitation
+		if err := database.NamedQueryStruct(ctx, str.logger, transaction, selectQuery, queryParams,
+			&invitationData); err != nil {
+			return err
+		}
+
+		if !invitationData.IsPending(now) {
+			return ErrorInvitationClosed
+		}
+
+		if !invitationData.IsAddressedTo(claims) {
+			return database.ErrorForbidden
+		}
+
+		// The group could lose access to the project or its manager since the invitation was created.
+		if err := str.checkGroupAccess(ctx, transaction, invitationData.ProjectID, invitationData.GroupID); err != nil {
+			return err
+		}
+		if err := str.checkGroupManager(ctx, transaction, invitationData.GroupID,
+			invitationData.CreatedByUser); err != nil {
+			return err
+		}
+
+		var accepted struct {
+			ID string `db:"project_invitation_id"`
+		}
+		if err := database.NamedQueryStruct(ctx, str.logger, transaction, acceptQuery, queryParams,
+			&accepted); err != nil {
+			if err == database.ErrorNotFound {
+				return ErrorInvitationClosed
+			}
+
+			return err
+		}
+
+		groupUserData = GroupUser{
+			ID:            uuid.Generate(),
+			GroupID:       invitationData.GroupID,
+			UserID:        claims.Subject,
+			DateCreated:   now,
+			CreatedByUser: invitationData.CreatedByUser,
+		}
+
+		return database.NamedExecContext(ctx, str.logger, transaction, insertQuery, groupUserData)
+	})
+	if err != nil {
+		return GroupUser{}, fmt.Errorf("error during acceptance of Invitation entity -> id={%q}: %w", invitationId,
+			err)
+	}
+
+	return groupUserData, nil
+}
+
+// QueryPendingInvitations looking for Invitation entities of Project entity which are pending at now with
+// descending order by creation date field.
+// Only the project owner can see invitations.
+func (str Store) QueryPendingInvitations(ctx context.Context, claims auth.Claims, projectId string,
+	now time.Time) ([]Invitation, error) {
+	if err := uuid.Validate(projectId); err != nil {
+		return nil, database.ErrorInvalidIdentifier
+	}
+
+	if _, err := str.ownedProject(ctx, claims, projectId); err != nil {
+		return nil, err
+	}
+
+	queryParams := struct {
+		ProjectID string    `db:"project_id"`
+		Now       time.Time `db:"now"`
+	}{
+		ProjectID: projectId,
+		Now:       now,
+	}
+
+	const query = `
+	SELECT
+		i.project_invitation_id,
+		i.project_id,
+		i.project_group_id,
+		i.invitee_user_id,
+		i.invitee_email,
+		i.date_expires,
+		i.date_accepted,
+		i.accepted_by_user_id,
+		i.date_revoked,
+		i.date_created,
+		i.created_by_user_id
+	FROM
+		PROJECT_INVITATION AS i
+	WHERE
+		i.project_id = :project_id AND i.date_accepted IS NULL AND i.date_revoked IS NULL AND i.date_expires > :now
+	ORDER BY i.date_created DESC`
+
+	var invitations []Invitation
+	connection := str.cluster.Reader(ctx)
+	if err := database.NamedQuerySlice(ctx, str.logger, connection, query, queryParams, &invitations); err != nil {
+		if err == database.ErrorNotFound {
+			return nil, database.ErrorNotFound
+		}
+
+		return nil, fmt.Errorf("error during search of Invitation entities -> project_id={%q}: %w", projectId, err)
+	}
+
+	return invitations, nil
+}
+
+// checkGroupAccess confirms that Group entity has access to Project entity.
+// If it has not, the method returns database.ErrorInvalidReference.
+func (str Store) checkGroupAccess(ctx context.Context, connection sqlx.ExtContext, projectId string,
+	groupId string) error {
+	queryParams := struct {
+		ProjectID string `db:"project_id"`
+		GroupID   string `db:"project_group_id"`
+	}{
+		ProjectID: projectId,
+		GroupID:   groupId,
+	}
+
+	const query = `
+	SELECT
+		ga.project_group_access_id
+	FROM
+		PROJECT_GROUP_ACCESS AS ga
+	WHERE
+		ga.project_id = :project_id AND ga.project_group_id = :project_group_id`
+
+	var access struct {
+		ID string `db:"project_group_access_id"`
+	}
+	if err := database.NamedQueryStruct(ctx, str.logger, connection, query, queryParams, &access); err != nil {
+		if err == database.ErrorNotFound {
+			return database.ErrorInvalidReference
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+// checkGroupManager confirms that Group entity is managed by the user with userId identifier, i.e. the user created
+// the group. It reports database.ErrorInvalidReference for a missing group and database.ErrorForbidden for a group
+// managed by another user.
+func (str Store) checkGroupManager(ctx context.Context, connection sqlx.ExtContext, groupId string,
+	userId string) error {
+	queryParams := struct {
+		GroupID string `db:"project_group_id"`
+	}{
+		GroupID: groupId,
+	}
+
+	const query = `
+	SELECT
+		g.created_by_user_id
+	FROM
+		PROJECT_GROUP AS g
+	WHERE
+		g.project_group_id = :project_group_id`
+
+	var groupData struct {
+		CreatedByUser string `db:"created_by_user_id"`
+	}
+	if err := database.NamedQueryStruct(ctx, str.logger, connection, query, queryParams, &groupData); err != nil {
+		if err == database.ErrorNotFound {
+			return database.ErrorInvalidReference
+		}
+
+		return err
+	}
+
+	if groupData.CreatedByUser != userId {
+		return database.ErrorForbidden
+	}
+
+	return nil
+}
+
+// IsPending reports whether Invitation is neither accepted, revoked nor expired at now.
+func (invitation Invitation) IsPending(now time.Time) bool {
+	return invitation.DateAccepted == nil && invitation.DateRevoked == nil && now.Before(invitation.DateExpires)
+}
+
+// IsAddressedTo reports whether Invitation is addressed to the claims subject by identifier or email.
+func (invitation Invitation) IsAddressedTo(claims auth.Claims) bool {
+	if invitation.UserID != nil && *invitation.UserID == claims.Subject {
+		return true
+	}
+
+	return invitation.Email != nil && claims.Email != "" && strings.EqualFold(*invitation.Email, claims.Email)
+}
+
+// newInvitation creates Invitation entity from the request data, email is stored in lower case.
+func newInvitation(claims auth.Claims, projectId string, invitation NewInvitation, now time.Time) Invitation {
+	lifetime := DefaultInvitationLifetime
+	if invitation.ExpiresInHours > 0 {
+		lifetime = time.Duration(invitation.ExpiresInHours) * time.Hour
+	}
+
+	invitationData := Invitation{
+		ID:            uuid.Generate(),
+		ProjectID:     projectId,
+		GroupID:       invitation.GroupID,
+		DateExpires:   now.Add(lifetime),
+		DateCreated:   now,
+		CreatedByUser: claims.Subject,
+	}
+
+	if invitation.UserID != "" {
+		userId := invitation.UserID
+		invitationData.UserID = &userId
+	}
+	if invitation.Email != "" {
+		email := strings.ToLower(invitation.Email)
+		invitationData.Email = &email
+	}
+
+	return invitationData
+}
@@ -0,0 +1,179 @@
+package project
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/SKorolchuk/dpio-workspace/internal/pkg/infra/auth"
+	"github.com/SKorolchuk/dpio-workspace/internal/pkg/infra/database"
+)
+
+func TestInvitationIsPending(t *testing.T) {
+	now := baseDate.Add(time.Hour)
+	before := now.Add(-time.Minute)
+
+	tests := []struct {
+		name       string
+		invitation Invitation
+		want       bool
+	}{
+		{name: "open", invitation: Invitation{DateExpires: now.Add(time.Minute)}, want: true},
+		{name: "expires right now", invitation: Invitation{DateExpires: now}},
+		{name: "expired", invitation: Invitation{DateExpires: before}},
+		{name: "accepted", invitation: Invitation{DateExpires: now.Add(time.Minute), DateAccepted: &before}},
+		{name: "revoked", invitation: Invitation{DateExpires: now.Add(time.Minute), DateRevoked: &before}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := test.invitation.IsPending(now); got != test.want {
+				t.Errorf("IsPending() = %t, want %t", got, test.want)
+			}
+		})
+	}
+}
+
+func TestInvitationIsAddressedTo(t *testing.T) {
+	userId, email := memberID, "member@example.com"
+	withEmail := func(userId string, email string) auth.Claims {
+		claims := claimsOf(userId)
+		claims.Email = email
+
+		return claims
+	}
+
+	tests := []struct {
+		name       string
+		invitation Invitation
+		claims     auth.Claims
+		want       bool
+	}{
+		{name: "invited user", invitation: Invitation{UserID: &userId}, claims: claimsOf(memberID), want: true},
+		{name: "another user", invitation: Invitation{UserID: &userId}, claims: claimsOf(strangerID)},
+		{name: "invited email", invitation: Invitation{Email: &email},
+			claims: withEmail(strangerID, "member@example.com"), want: true},
+		{name: "invited email in another case", invitation: Invitation{Email: &email},
+			claims: withEmail(strangerID, "Member@Example.COM"), want: true},
+		{name: "another email", invitation: Invitation{Email: &email},
+			claims: withEmail(strangerID, "stranger@example.com")},
+		{name: "token without email", invitation: Invitation{Email: &email}, claims: claimsOf(strangerID)},
+		{name: "another user with invited email", invitation: Invitation{UserID: &userId, Email: &email},
+			claims: withEmail(strangerID, "member@example.com"), want: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := test.invitation.IsAddressedTo(test.claims); got != test.want {
+				t.Errorf("IsAddressedTo() = %t, want %t", got, test.want)
+			}
+		})
+	}
+}
+
+func TestNewInvitation(t *testing.T) {
+	tests := []struct {
+		name        string
+		invitation  NewInvitation
+		wantExpires time.Time
+		wantEmail   string
+	}{
+		{
+			name:        "default lifetime",
+			invitation:  NewInvitation{UserID: memberID},
+			wantExpires: baseDate.Add(DefaultInvitationLifetime),
+		},
+		{
+			name:        "requested lifetime",
+			invitation:  NewInvitation{UserID: memberID, ExpiresInHours: 2},
+			wantExpires: baseDate.Add(2 * time.Hour),
+		},
+		{
+			name:        "email in lower case",
+			invitation:  NewInvitation{Email: "Member@Example.COM"},
+			wantExpires: baseDate.Add(DefaultInvitationLifetime),
+			wantEmail:   "member@example.com",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			invitationData := newInvitation(claimsOf(ownerID), missingID, test.invitation, baseDate)
+
+			if !invitationData.DateExpires.Equal(test.wantExpires) {
+				t.Errorf("DateExpires = %v, want %v", invitationData.DateExpires, test.wantExpires)
+			}
+			if test.invitation.UserID != "" && (invitationData.UserID == nil ||
+				*invitationData.UserID != test.invitation.UserID) {
+				t.Errorf("UserID = %v, want %q", invitationData.UserID, test.invitation.UserID)
+			}
+			if test.wantEmail != "" && (invitationData.Email == nil || *invitationData.Email != test.wantEmail) {
+				t.Errorf("Email = %v, want %q", invitationData.Email, test.wantEmail)
+			}
+			if !invitationData.IsPending(baseDate) {
+				t.Errorf("new invitation is not pending")
+			}
+		})
+	}
+}
+
+func TestMemoryStoreInvitationGroupManager(t *testing.T) {
+	str := NewMemoryStore()
+	ctx := context.Background()
+	projectData := mustCreateProject(t, str, "Team", TeamCollaborationType, baseDate)
+	if _, err := str.AddProjectOwner(ctx, claimsOf(ownerID), projectData.ID, NewProjectOwner{UserID: memberID},
+		baseDate); err != nil {
+		t.Fatalf("AddProjectOwner() error = %v", err)
+	}
+
+	grantedGroup := func(userId string, name string) Group {
+		groupData, err := str.CreateGroup(ctx, claimsOf(userId), NewGroup{Name: name}, baseDate)
+		if err != nil {
+			t.Fatalf("CreateGroup() error = %v", err)
+		}
+		if _, err := str.GrantGroupAccess(ctx, claimsOf(userId), projectData.ID,
+			NewGroupAccess{GroupID: groupData.ID}, baseDate); err != nil {
+			t.Fatalf("GrantGroupAccess() error = %v", err)
+		}
+
+		return groupData
+	}
+	ownGroup, coOwnerGroup := grantedGroup(ownerID, "Own"), grantedGroup(memberID, "Co-owner")
+
+	tests := []struct {
+		name    string
+		groupId string
+		wantErr error
+	}{
+		{name: "own group", groupId: ownGroup.ID},
+		{name: "group of another owner", groupId: coOwnerGroup.ID, wantErr: database.ErrorForbidden},
+		{name: "group without access", groupId: missingID, wantErr: database.ErrorInvalidReference},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := str.CreateInvitation(ctx, claimsOf(ownerID), projectData.ID,
+				NewInvitation{GroupID: test.groupId, UserID: strangerID}, baseDate)
+			if !errors.Is(err, test.wantErr) {
+				t.Errorf("CreateInvitation() error = %v, want %v", err, test.wantErr)
+			}
+		})
+	}
+
+	t.Run("group lost access before acceptance", func(t *testing.T) {
+		invitation, err := str.CreateInvitation(ctx, claimsOf(ownerID), projectData.ID,
+			NewInvitation{GroupID: ownGroup.ID, UserID: strangerID}, baseDate)
+		if err != nil {
+			t.Fatalf("CreateInvitation() error = %v", err)
+		}
+		if err := str.RevokeGroupAccess(ctx, claimsOf(ownerID), projectData.ID, ownGroup.ID); err != nil {
+			t.Fatalf("RevokeGroupAccess() error = %v", err)
+		}
+
+		_, err = str.AcceptInvitation(ctx, claimsOf(strangerID), invitation.ID, baseDate.Add(time.Hour))
+		if !errors.Is(err, database.ErrorInvalidReference) {
+			t.Errorf("AcceptInvitation() error = %v, want %v", err, database.ErrorInvalidReference)
+		}
+	})
+}
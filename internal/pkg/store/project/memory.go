@@ -0,0 +1,1754 @@
+package project
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/SKorolchuk/dpio-workspace/internal/pkg/infra/auth"
+	"github.com/SKorolchuk/dpio-workspace/internal/pkg/infra/database"
+	"github.com/SKorolchuk/dpio-workspace/internal/pkg/infra/uuid"
+	"github.com/SKorolchuk/dpio-workspace/internal/pkg/infra/validation"
+)
+
+// MemoryStore represents a concurrency-safe in-memory point of access to CollaborationType, Project, Role, Group,
+// GroupRole, GroupUser, GroupAccess, Invitation, AccessRequest and ProjectOwner entities.
+// It follows the semantics of Store and is intended for unit tests and offline demo mode.
+type MemoryStore struct {
+	mutex              sync.RWMutex
+	collaborationTypes map[string]CollaborationType
+	projects           map[string]Project
+	owners             map[string]ProjectOwner
+	groups             map[string]Group
+	groupUsers         map[string]GroupUser
+	roles              map[string]Role
+	groupRoles         map[string]GroupRole
+	groupAccesses      map[string]GroupAccess
+	invitations        map[string]Invitation
+	accessRequests     map[string]AccessRequest
+	hooks              []AccessRequestHook
+	deletionHooks      []ProjectDeletionHook
+}
+
+// NewMemoryStore creates an instance of MemoryStore with predefined CollaborationType entities and ReadWriteRole.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		collaborationTypes: map[string]CollaborationType{
+			PublicCollaborationType:  {ID: PublicCollaborationType, Name: "Public"},
+			TeamCollaborationType:    {ID: TeamCollaborationType, Name: "Team"},
+			PrivateCollaborationType: {ID: PrivateCollaborationType, Name: "Private"},
+		},
+		projects:   make(map[string]Project),
+		owners:     make(map[string]ProjectOwner),
+		groups:     make(map[string]Group),
+		groupUsers: make(map[string]GroupUser),
+		roles: map[string]Role{
+			ReadWriteRole: {ID: ReadWriteRole, Name: "ProjectReadWriteAll"},
+		},
+		groupRoles:     make(map[string]GroupRole),
+		groupAccesses:  make(map[string]GroupAccess),
+		invitations:    make(map[string]Invitation),
+		accessRequests: make(map[string]AccessRequest),
+	}
+}
+
+// CreateProject adds new Project entity to the memory, the claims subject becomes its first owner.
+// If creation is successful, the method returns Project entity.
+// Can return validation or database errors.
+func (str *MemoryStore) CreateProject(ctx context.Context, claims auth.Claims, project NewProject,
+	now time.Time) (Project, error) {
+	if err := validation.Check(ctx, project); err != nil {
+		return Project{}, fmt.Errorf("error during data validation of Project entity: %w", err)
+	}
+
+	projectData := Project{
+		ID:            uuid.Generate(),
+		ProjectTypeID: project.ProjectTypeID,
+		Name:          project.Name,
+		Description:   project.Description,
+		DateCreated:   now,
+		DateUpdated:   now,
+		CreatedByUser: claims.Subject,
+		UpdatedByUser: claims.Subject,
+	}
+
+	str.mutex.Lock()
+	defer str.mutex.Unlock()
+
+	if _, found := str.collaborationTypes[projectData.ProjectTypeID]; !found {
+		return Project{}, fmt.Errorf("error during create of new Project entity: %w", database.ErrorInvalidReference)
+	}
+
+	if str.projectNameTaken(projectData.Name, "") {
+		return Project{}, fmt.Errorf("error during create of new Project entity: %w", database.ErrorConflict)
+	}
+
+	str.projects[projectData.ID] = projectData
+
+	ownerData := newProjectOwner(claims, projectData.ID, claims.Subject, now)
+	str.owners[ownerData.ID] = ownerData
+
+	return projectData, nil
+}
+
+// UpdateProject change existing Project entity in the memory.
+// Only specified fields of project are changed, audit fields are filled with now and claims subject.
+// If error occurs, the method can return validation or database errors.
+func (str *MemoryStore) UpdateProject(ctx context.Context, claims auth.Claims, projectId string,
+	project UpdateProject, now time.Time) error {
+	if err := uuid.Validate(projectId); err != nil {
+		return database.ErrorInvalidIdentifier
+	}
+
+	if err := validation.Check(ctx, project); err != nil {
+		return fmt.Errorf("error during data validation of Project entity: %w", err)
+	}
+
+	str.mutex.Lock()
+	defer str.mutex.Unlock()
+
+	projectData, err := str.ownedProject(claims, projectId)
+	if err != nil {
+		return err
+	}
+
+	applyProjectUpdate(&projectData, project)
+	projectData.DateUpdated = now
+	projectData.UpdatedByUser = claims.Subject
+
+	if _, found := str.collaborationTypes[projectData.ProjectTypeID]; !found {
+		return fmt.Errorf("error during update of Project entity -> id={%q}: %w", projectId,
+			database.ErrorInvalidReference)
+	}
+
+	if str.projectNameTaken(projectData.Name, projectData.ID) {
+		return fmt.Errorf("error during update of Project entity -> id={%q}: %w", projectId, database.ErrorConflict)
+	}
+
+	str.projects[projectId] = projectData
+
+	return nil
+}
+
+// DeleteProject marks existing Project entity in the memory as deleted at now, the project is hidden from all
+// queries until it is restored.
+// Workspace entities are kept by a separate store, so unlike Store the method never refuses removal and
+// cascade argument has no effect, the store marks the workspaces as deleted with ProjectDeletionHook.
+// If error occurs, the method can return database errors.
+func (str *MemoryStore) DeleteProject(ctx context.Context, claims auth.Claims, projectId string, cascade bool,
+	now time.Time) error {
+	if err := uuid.Validate(projectId); err != nil {
+		return database.ErrorInvalidIdentifier
+	}
+
+	str.mutex.Lock()
+	defer str.mutex.Unlock()
+
+	projectData, err := str.ownedProject(claims, projectId)
+	if err != nil {
+		return err
+	}
+
+	projectData.DateDeleted = &now
+	projectData.DateUpdated = now
+	projectData.UpdatedByUser = claims.Subject
+	str.projects[projectId] = projectData
+
+	str.callDeletionHooks(ctx, ProjectDeletionEvent{ProjectID: projectId, DateDeleted: now, UserID: claims.Subject,
+		Now: now})
+
+	return nil
+}
+
+// ArchiveProject marks existing Project entity in the memory as archived at now, so it is hidden from default
+// queries. Archiving of already archived project keeps its original archive date.
+// If error occurs, the method can return database errors.
+func (str *MemoryStore) ArchiveProject(ctx context.Context, claims auth.Claims, projectId string,
+	now time.Time) error {
+	if err := uuid.Validate(projectId); err != nil {
+		return database.ErrorInvalidIdentifier
+	}
+
+	str.mutex.Lock()
+	defer str.mutex.Unlock()
+
+	projectData, err := str.ownedProject(claims, projectId)
+	if err != nil {
+		return err
+	}
+
+	if projectData.DateArchived != nil {
+		return nil
+	}
+
+	projectData.DateArchived = &now
+	projectData.DateUpdated = now
+	projectData.UpdatedByUser = claims.Subject
+	str.projects[projectId] = projectData
+
+	return nil
+}
+
+// RestoreProject returns archived or deleted Project entity in the memory back to default queries.
+// ProjectDeletionHook functions are called for deleted projects, so their workspaces are restored as well.
+// If error occurs, the method can return database errors.
+func (str *MemoryStore) RestoreProject(ctx context.Context, claims auth.Claims, projectId string,
+	now time.Time) error {
+	if err := uuid.Validate(projectId); err != nil {
+		return database.ErrorInvalidIdentifier
+	}
+
+	str.mutex.Lock()
+	defer str.mutex.Unlock()
+
+	projectData, found := str.projects[projectId]
+	if !found {
+		return fmt.Errorf("error during search of Project entity -> id={%q}: %w", projectId, database.ErrorNotFound)
+	}
+
+	if !str.isOwner(projectId, claims.Subject) {
+		return database.ErrorForbidden
+	}
+
+	if projectData.DateArchived == nil && projectData.DateDeleted == nil {
+		return nil
+	}
+
+	dateDeleted := projectData.DateDeleted
+	projectData.DateArchived = nil
+	projectData.DateDeleted = nil
+	projectData.DateUpdated = now
+	projectData.UpdatedByUser = claims.Subject
+	str.projects[projectId] = projectData
+
+	if dateDeleted != nil {
+		str.callDeletionHooks(ctx, ProjectDeletionEvent{ProjectID: projectId, DateDeleted: *dateDeleted,
+			Restored: true, UserID: claims.Subject, Now: now})
+	}
+
+	return nil
+}
+
+// WithProjectDeletionHooks registers hooks which are called after Project entities are deleted or restored.
+// Hooks are called while the mutex is held, so they must not call MemoryStore back.
+func (str *MemoryStore) WithProjectDeletionHooks(hooks ...ProjectDeletionHook) *MemoryStore {
+	str.mutex.Lock()
+	defer str.mutex.Unlock()
+
+	str.deletionHooks = append(str.deletionHooks, hooks...)
+
+	return str
+}
+
+// callDeletionHooks calls ProjectDeletionHook functions with the event, the mutex must be held.
+func (str *MemoryStore) callDeletionHooks(ctx context.Context, event ProjectDeletionEvent) {
+	for _, hook := range str.deletionHooks {
+		hook(ctx, event)
+	}
+}
+
+// QueryProjects looking for Project entities readable by the claims subject using skip/top mechanics with descending
+// order by update date field. Archived projects are returned only if includeArchived is set.
+func (str *MemoryStore) QueryProjects(ctx context.Context, claims auth.Claims, includeArchived bool, skip int32,
+	top int32) ([]Project, error) {
+	str.mutex.RLock()
+	defer str.mutex.RUnlock()
+
+	projectCollection := make([]Project, 0, len(str.projects))
+	for _, projectData := range str.projects {
+		if (includeArchived || projectData.DateArchived == nil) && str.readable(projectData, claims.Subject) {
+			projectCollection = append(projectCollection, projectData)
+		}
+	}
+	sortProjects(projectCollection)
+
+	start, end, err := pageBounds(len(projectCollection), skip, top)
+	if err != nil {
+		return nil, fmt.Errorf("error during search of Project entities: %w", err)
+	}
+	if start == end {
+		return nil, nil
+	}
+
+	return projectCollection[start:end], nil
+}
+
+// QueryProjectByID looking for Project entity with projectId identifier readable by the claims subject.
+// Project which is not readable by the subject is reported with database.ErrorNotFound.
+func (str *MemoryStore) QueryProjectByID(ctx context.Context, claims auth.Claims, projectId string) (Project,
+	error) {
+	if err := uuid.Validate(projectId); err != nil {
+		return Project{}, err
+	}
+
+	str.mutex.RLock()
+	defer str.mutex.RUnlock()
+
+	projectData, found := str.projects[projectId]
+	if !found || !str.readable(projectData, claims.Subject) {
+		return Project{}, database.ErrorNotFound
+	}
+
+	return projectData, nil
+}
+
+// CanReadProject reports whether Project entity with projectId identifier is readable by the claims subject.
+// If the project does not exist, the method returns database.ErrorNotFound.
+func (str *MemoryStore) CanReadProject(ctx context.Context, claims auth.Claims, projectId string) (bool, error) {
+	if err := uuid.Validate(projectId); err != nil {
+		return false, err
+	}
+
+	str.mutex.RLock()
+	defer str.mutex.RUnlock()
+
+	projectData, found := str.liveProject(projectId)
+	if !found {
+		return false, database.ErrorNotFound
+	}
+
+	return str.readable(projectData, claims.Subject), nil
+}
+
+// CanWriteProject reports whether content of Project entity with projectId identifier can be changed by the claims
+// subject, which is allowed to owners and to members of groups which hold ReadWriteRole in the project.
+// Project which is not readable by the subject is not writable as well.
+// If the project does not exist, the method returns database.ErrorNotFound.
+func (str *MemoryStore) CanWriteProject(ctx context.Context, claims auth.Claims, projectId string) (bool, error) {
+	if err := uuid.Validate(projectId); err != nil {
+		return false, err
+	}
+
+	str.mutex.RLock()
+	defer str.mutex.RUnlock()
+
+	projectData, found := str.liveProject(projectId)
+	if !found {
+		return false, database.ErrorNotFound
+	}
+
+	return str.readable(projectData, claims.Subject) && str.writable(projectId, claims.Subject), nil
+}
+
+// CreateGroup adds new Group entity to the memory.
+// If creation is successful, the method returns Group entity.
+// Can return validation or database errors.
+func (str *MemoryStore) CreateGroup(ctx context.Context, claims auth.Claims, group NewGroup,
+	now time.Time) (Group, error) {
+	if err := validation.Check(ctx, group); err != nil {
+		return Group{}, fmt.Errorf("error during data validation of Group entity: %w", err)
+	}
+
+	groupData := Group{
+		ID:            uuid.Generate(),
+		Name:          group.Name,
+		DateCreated:   now,
+		CreatedByUser: claims.Subject,
+		DateUpdated:   now,
+		UpdatedByUser: claims.Subject,
+	}
+
+	str.mutex.Lock()
+	defer str.mutex.Unlock()
+
+	if str.groupNameTaken(groupData.Name, "") {
+		return Group{}, fmt.Errorf("error during create of new Group entity: %w", database.ErrorConflict)
+	}
+
+	str.groups[groupData.ID] = groupData
+
+	return groupData, nil
+}
+
+// UpdateGroup renames existing Group entity in the memory.
+// If error occurs, the method can return validation or database errors.
+func (str *MemoryStore) UpdateGroup(ctx context.Context, claims auth.Claims, groupId string, group UpdateGroup,
+	now time.Time) error {
+	if err := uuid.Validate(groupId); err != nil {
+		return database.ErrorInvalidIdentifier
+	}
+
+	if err := validation.Check(ctx, group); err != nil {
+		return fmt.Errorf("error during data validation of Group entity: %w", err)
+	}
+
+	str.mutex.Lock()
+	defer str.mutex.Unlock()
+
+	groupData, err := str.ownedGroup(claims, groupId)
+	if err != nil {
+		return err
+	}
+
+	if str.groupNameTaken(*group.Name, groupId) {
+		return fmt.Errorf("error during update of Group entity -> id={%q}: %w", groupId, database.ErrorConflict)
+	}
+
+	groupData.Name = *group.Name
+	groupData.DateUpdated = now
+	groupData.UpdatedByUser = claims.Subject
+	str.groups[groupId] = groupData
+
+	return nil
+}
+
+// DeleteGroup removes existing Group entity from the memory together with its user, role and project assignments
+// and invitations. Decided access requests keep their history without reference to the group.
+// If error occurs, the method can return database errors.
+func (str *MemoryStore) DeleteGroup(ctx context.Context, claims auth.Claims, groupId string) error {
+	if err := uuid.Validate(groupId); err != nil {
+		return database.ErrorInvalidIdentifier
+	}
+
+	str.mutex.Lock()
+	defer str.mutex.Unlock()
+
+	if _, err := str.ownedGroup(claims, groupId); err != nil {
+		return err
+	}
+
+	for id, groupUser := range str.groupUsers {
+		if groupUser.GroupID == groupId {
+			delete(str.groupUsers, id)
+		}
+	}
+	for id, groupRole := range str.groupRoles {
+		if groupRole.GroupID == groupId {
+			delete(str.groupRoles, id)
+		}
+	}
+	for id, groupAccess := range str.groupAccesses {
+		if groupAccess.GroupID == groupId {
+			delete(str.groupAccesses, id)
+		}
+	}
+	for id, invitation := range str.invitations {
+		if invitation.GroupID == groupId {
+			delete(str.invitations, id)
+		}
+	}
+	for id, request := range str.accessRequests {
+		if request.GroupID != nil && *request.GroupID == groupId {
+			request.GroupID = nil
+			str.accessRequests[id] = request
+		}
+	}
+	delete(str.groups, groupId)
+
+	return nil
+}
+
+// AddGroupUser assigns a user to existing Group entity.
+// If assignment is successful, the method returns GroupUser entity.
+// Repeated assignment of the same user returns database.ErrorConflict.
+func (str *MemoryStore) AddGroupUser(ctx context.Context, claims auth.Claims, groupId string, user NewGroupUser,
+	now time.Time) (GroupUser, error) {
+	if err := uuid.Validate(groupId); err != nil {
+		return GroupUser{}, database.ErrorInvalidIdentifier
+	}
+
+	if err := validation.Check(ctx, user); err != nil {
+		return GroupUser{}, fmt.Errorf("error during data validation of GroupUser entity: %w", err)
+	}
+
+	str.mutex.Lock()
+	defer str.mutex.Unlock()
+
+	if _, err := str.ownedGroup(claims, groupId); err != nil {
+		return GroupUser{}, err
+	}
+
+	if _, found := str.groupUser(groupId, user.UserID); found {
+		return GroupUser{}, fmt.Errorf("error during create of new GroupUser entity -> group_id={%q}: %w", groupId,
+			database.ErrorConflict)
+	}
+
+	groupUserData := GroupUser{
+		ID:            uuid.Generate(),
+		GroupID:       groupId,
+		UserID:        user.UserID,
+		DateCreated:   now,
+		CreatedByUser: claims.Subject,
+	}
+	str.groupUsers[groupUserData.ID] = groupUserData
+
+	return groupUserData, nil
+}
+
+// RemoveGroupUser removes a user assignment from existing Group entity.
+// If the user is not a member of the group, the method returns database.ErrorNotFound.
+func (str *MemoryStore) RemoveGroupUser(ctx context.Context, claims auth.Claims, groupId string,
+	userId string) error {
+	if err := uuid.Validate(groupId); err != nil {
+		return database.ErrorInvalidIdentifier
+	}
+
+	str.mutex.Lock()
+	defer str.mutex.Unlock()
+
+	if _, err := str.ownedGroup(claims, groupId); err != nil {
+		return err
+	}
+
+	groupUserData, found := str.groupUser(groupId, userId)
+	if !found {
+		return database.ErrorNotFound
+	}
+	delete(str.groupUsers, groupUserData.ID)
+
+	return nil
+}
+
+// QueryGroupUsers looking for members of Group entity visible to the claims subject using skip/top mechanics with
+// descending order by creation date field. A group is visible to its creator and members.
+// If error occurs, the method can return database.ErrorNotFound for a group hidden from the subject.
+func (str *MemoryStore) QueryGroupUsers(ctx context.Context, claims auth.Claims, groupId string, skip int32,
+	top int32) ([]GroupUser, error) {
+	if err := uuid.Validate(groupId); err != nil {
+		return nil, err
+	}
+
+	str.mutex.RLock()
+	defer str.mutex.RUnlock()
+
+	if _, err := str.visibleGroup(claims, groupId); err != nil {
+		return nil, err
+	}
+
+	var groupUsers []GroupUser
+	for _, groupUser := range str.groupUsers {
+		if groupUser.GroupID == groupId {
+			groupUsers = append(groupUsers, groupUser)
+		}
+	}
+	sort.Slice(groupUsers, func(i, j int) bool {
+		if !groupUsers[i].DateCreated.Equal(groupUsers[j].DateCreated) {
+			return groupUsers[i].DateCreated.After(groupUsers[j].DateCreated)
+		}
+		return groupUsers[i].ID > groupUsers[j].ID
+	})
+
+	start, end, err := pageBounds(len(groupUsers), skip, top)
+	if err != nil {
+		return nil, fmt.Errorf("error during search of GroupUser entities -> group_id={%q}: %w", groupId, err)
+	}
+	if start == end {
+		return nil, nil
+	}
+
+	return groupUsers[start:end], nil
+}
+
+// QueryGroupsByUser looking for Group entities which the user is a member of, using skip/top mechanics with
+// ascending order by name field. Only groups visible to the claims subject are returned, so users see all their
+// own groups and only the shared ones of other users.
+func (str *MemoryStore) QueryGroupsByUser(ctx context.Context, claims auth.Claims, userId string, skip int32,
+	top int32) ([]Group, error) {
+	str.mutex.RLock()
+	defer str.mutex.RUnlock()
+
+	var groups []Group
+	for _, groupUser := range str.groupUsers {
+		if groupUser.UserID != userId {
+			continue
+		}
+
+		if groupData, err := str.visibleGroup(claims, groupUser.GroupID); err == nil {
+			groups = append(groups, groupData)
+		}
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		return groups[i].Name < groups[j].Name
+	})
+
+	start, end, err := pageBounds(len(groups), skip, top)
+	if err != nil {
+		return nil, fmt.Errorf("error during search of Group entities -> user_id={%q}: %w", userId, err)
+	}
+	if start == end {
+		return nil, nil
+	}
+
+	return groups[start:end], nil
+}
+
+// QueryGroupByID looking for Group entity with groupId identifier visible to the claims subject.
+// A group is visible to its creator and members, others receive database.ErrorNotFound.
+func (str *MemoryStore) QueryGroupByID(ctx context.Context, claims auth.Claims, groupId string) (Group, error) {
+	if err := uuid.Validate(groupId); err != nil {
+		return Group{}, err
+	}
+
+	str.mutex.RLock()
+	defer str.mutex.RUnlock()
+
+	return str.visibleGroup(claims, groupId)
+}
+
+// CreateRole adds new Role entity to the role catalogue.
+// Only users with auth.RoleAdmin role can change the catalogue.
+// Can return validation or database errors.
+func (str *MemoryStore) CreateRole(ctx context.Context, claims auth.Claims, role NewRole) (Role, error) {
+	if !claims.AuthorizeCheck(auth.RoleAdmin) {
+		return Role{}, database.ErrorForbidden
+	}
+
+	if err := validation.Check(ctx, role); err != nil {
+		return Role{}, fmt.Errorf("error during data validation of Role entity: %w", err)
+	}
+
+	roleData := Role{
+		ID:   uuid.Generate(),
+		Name: role.Name,
+	}
+
+	str.mutex.Lock()
+	defer str.mutex.Unlock()
+
+	for _, existing := range str.roles {
+		if existing.Name == roleData.Name {
+			return Role{}, fmt.Errorf("error during create of new Role entity: %w", database.ErrorConflict)
+		}
+	}
+
+	str.roles[roleData.ID] = roleData
+
+	return roleData, nil
+}
+
+// RetireRole marks Role entity as retired at now.
+// Only users with auth.RoleAdmin role can change the catalogue.
+// If error occurs, the method can return ErrorRoleRetired or database errors.
+func (str *MemoryStore) RetireRole(ctx context.Context, claims auth.Claims, roleId string, now time.Time) error {
+	if err := uuid.Validate(roleId); err != nil {
+		return database.ErrorInvalidIdentifier
+	}
+
+	if !claims.AuthorizeCheck(auth.RoleAdmin) {
+		return database.ErrorForbidden
+	}
+
+	str.mutex.Lock()
+	defer str.mutex.Unlock()
+
+	roleData, found := str.roles[roleId]
+	if !found {
+		return fmt.Errorf("error during search of Role entity -> id={%q}: %w", roleId, database.ErrorNotFound)
+	}
+
+	if roleData.DateRetired != nil {
+		return fmt.Errorf("error during retirement of Role entity -> id={%q}: %w", roleId, ErrorRoleRetired)
+	}
+
+	roleData.DateRetired = &now
+	str.roles[roleId] = roleData
+
+	return nil
+}
+
+// QueryRoles looking for all Role entities including retired ones using skip/top mechanics with ascending order by
+// name field.
+func (str *MemoryStore) QueryRoles(ctx context.Context, skip int32, top int32) ([]Role, error) {
+	str.mutex.RLock()
+	defer str.mutex.RUnlock()
+
+	roles := make([]Role, 0, len(str.roles))
+	for _, roleData := range str.roles {
+		roles = append(roles, roleData)
+	}
+	sortRoles(roles)
+
+	start, end, err := pageBounds(len(roles), skip, top)
+	if err != nil {
+		return nil, fmt.Errorf("error during search of Role entities: %w", err)
+	}
+	if start == end {
+		return nil, nil
+	}
+
+	return roles[start:end], nil
+}
+
+// QueryRoleByID looking for Role entity with roleId identifier.
+func (str *MemoryStore) QueryRoleByID(ctx context.Context, roleId string) (Role, error) {
+	if err := uuid.Validate(roleId); err != nil {
+		return Role{}, err
+	}
+
+	str.mutex.RLock()
+	defer str.mutex.RUnlock()
+
+	roleData, found := str.roles[roleId]
+	if !found {
+		return Role{}, database.ErrorNotFound
+	}
+
+	return roleData, nil
+}
+
+// AssignGroupRole assigns active Role entity to existing Group entity.
+// If assignment is successful, the method returns GroupRole entity.
+// If error occurs, the method can return ErrorRoleRetired, database.ErrorConflict for repeated assignment or
+// other database errors.
+func (str *MemoryStore) AssignGroupRole(ctx context.Context, claims auth.Claims, groupId string,
+	groupRole NewGroupRole, now time.Time) (GroupRole, error) {
+	if err := uuid.Validate(groupId); err != nil {
+		return GroupRole{}, database.ErrorInvalidIdentifier
+	}
+
+	if err := validation.Check(ctx, groupRole); err != nil {
+		return GroupRole{}, fmt.Errorf("error during data validation of GroupRole entity: %w", err)
+	}
+
+	str.mutex.Lock()
+	defer str.mutex.Unlock()
+
+	if _, err := str.ownedGroup(claims, groupId); err != nil {
+		return GroupRole{}, err
+	}
+
+	roleData, found := str.roles[groupRole.RoleID]
+	if !found {
+		return GroupRole{}, fmt.Errorf("error during search of Role entity -> id={%q}: %w", groupRole.RoleID,
+			database.ErrorInvalidReference)
+	}
+
+	if roleData.DateRetired != nil {
+		return GroupRole{}, fmt.Errorf("error during assignment of Role entity -> id={%q}: %w", roleData.ID,
+			ErrorRoleRetired)
+	}
+
+	for _, existing := range str.groupRoles {
+		if existing.GroupID == groupId && existing.RoleID == roleData.ID {
+			return GroupRole{}, fmt.Errorf("error during create of new GroupRole entity -> group_id={%q}: %w",
+				groupId, database.ErrorConflict)
+		}
+	}
+
+	groupRoleData := GroupRole{
+		ID:            uuid.Generate(),
+		GroupID:       groupId,
+		RoleID:        roleData.ID,
+		DateCreated:   now,
+		CreatedByUser: claims.Subject,
+	}
+	str.groupRoles[groupRoleData.ID] = groupRoleData
+
+	return groupRoleData, nil
+}
+
+// UnassignGroupRole removes Role entity assignment from existing Group entity.
+// If the role is not assigned to the group, the method returns database.ErrorNotFound.
+func (str *MemoryStore) UnassignGroupRole(ctx context.Context, claims auth.Claims, groupId string,
+	roleId string) error {
+	if err := uuid.Validate(groupId); err != nil {
+		return database.ErrorInvalidIdentifier
+	}
+	if err := uuid.Validate(roleId); err != nil {
+		return database.ErrorInvalidIdentifier
+	}
+
+	str.mutex.Lock()
+	defer str.mutex.Unlock()
+
+	if _, err := str.ownedGroup(claims, groupId); err != nil {
+		return err
+	}
+
+	for id, groupRole := range str.groupRoles {
+		if groupRole.GroupID == groupId && groupRole.RoleID == roleId {
+			delete(str.groupRoles, id)
+			return nil
+		}
+	}
+
+	return database.ErrorNotFound
+}
+
+// QueryGroupRoles looking for Role entities assigned to Group entity with ascending order by name field.
+func (str *MemoryStore) QueryGroupRoles(ctx context.Context, groupId string) ([]Role, error) {
+	if err := uuid.Validate(groupId); err != nil {
+		return nil, err
+	}
+
+	str.mutex.RLock()
+	defer str.mutex.RUnlock()
+
+	var roles []Role
+	for _, groupRole := range str.groupRoles {
+		if groupRole.GroupID == groupId {
+			roles = append(roles, str.roles[groupRole.RoleID])
+		}
+	}
+	sortRoles(roles)
+
+	return roles, nil
+}
+
+// QueryEffectiveRoles looking for active Role entities which the user holds in Project entity.
+// A role is held when it is assigned to a group which the user is a member of and which has access to the project.
+// Owners of the project hold every active role.
+// Roles in a project which is not readable by the claims subject are not returned.
+func (str *MemoryStore) QueryEffectiveRoles(ctx context.Context, claims auth.Claims, userId string,
+	projectId string) ([]Role, error) {
+	if err := uuid.Validate(projectId); err != nil {
+		return nil, err
+	}
+
+	str.mutex.RLock()
+	defer str.mutex.RUnlock()
+
+	if projectData, found := str.projects[projectId]; !found || !str.readable(projectData, claims.Subject) {
+		return nil, nil
+	}
+
+	groups := make(map[string]bool)
+	for _, groupUser := range str.groupUsers {
+		if groupUser.UserID == userId {
+			groups[groupUser.GroupID] = false
+		}
+	}
+	for _, groupAccess := range str.groupAccesses {
+		if _, member := groups[groupAccess.GroupID]; member && groupAccess.ProjectID == projectId {
+			groups[groupAccess.GroupID] = true
+		}
+	}
+
+	held := make(map[string]Role)
+	if str.isOwner(projectId, userId) {
+		for _, roleData := range str.roles {
+			if roleData.DateRetired == nil {
+				held[roleData.ID] = roleData
+			}
+		}
+	}
+	for _, groupRole := range str.groupRoles {
+		roleData := str.roles[groupRole.RoleID]
+		if groups[groupRole.GroupID] && roleData.DateRetired == nil {
+			held[roleData.ID] = roleData
+		}
+	}
+
+	var roles []Role
+	for _, roleData := range held {
+		roles = append(roles, roleData)
+	}
+	sortRoles(roles)
+
+	return roles, nil
+}
+
+// GrantGroupAccess gives members of Group entity access to Project entity.
+// If grant is successful, the method returns GroupAccess entity.
+// Both the project and the group should be managed by the claims subject, i.e. the subject owns the project and
+// created the group.
+// If error occurs, the method can return database.ErrorConflict for repeated grant, database.ErrorInvalidReference
+// for unknown group, database.ErrorForbidden or other database errors.
+func (str *MemoryStore) GrantGroupAccess(ctx context.Context, claims auth.Claims, projectId string,
+	access NewGroupAccess, now time.Time) (GroupAccess, error) {
+	if err := uuid.Validate(projectId); err != nil {
+		return GroupAccess{}, database.ErrorInvalidIdentifier
+	}
+
+	if err := validation.Check(ctx, access); err != nil {
+		return GroupAccess{}, fmt.Errorf("error during data validation of GroupAccess entity: %w", err)
+	}
+
+	str.mutex.Lock()
+	defer str.mutex.Unlock()
+
+	if _, err := str.ownedProject(claims, projectId); err != nil {
+		return GroupAccess{}, err
+	}
+
+	groupData, found := str.groups[access.GroupID]
+	if !found {
+		return GroupAccess{}, fmt.Errorf("error during create of new GroupAccess entity -> project_id={%q}: %w",
+			projectId, database.ErrorInvalidReference)
+	}
+
+	if groupData.CreatedByUser != claims.Subject {
+		return GroupAccess{}, database.ErrorForbidden
+	}
+
+	for _, existing := range str.groupAccesses {
+		if existing.ProjectID == projectId && existing.GroupID == access.GroupID {
+			return GroupAccess{}, fmt.Errorf("error during create of new GroupAccess entity -> project_id={%q}: %w",
+				projectId, database.ErrorConflict)
+		}
+	}
+
+	accessData := GroupAccess{
+		ID:            uuid.Generate(),
+		ProjectID:     projectId,
+		GroupID:       access.GroupID,
+		DateCreated:   now,
+		CreatedByUser: claims.Subject,
+	}
+	str.groupAccesses[accessData.ID] = accessData
+
+	return accessData, nil
+}
+
+// RevokeGroupAccess removes access of Group entity members to Project entity.
+// If the group has no access to the project, the method returns database.ErrorNotFound.
+func (str *MemoryStore) RevokeGroupAccess(ctx context.Context, claims auth.Claims, projectId string,
+	groupId string) error {
+	if err := uuid.Validate(projectId); err != nil {
+		return database.ErrorInvalidIdentifier
+	}
+	if err := uuid.Validate(groupId); err != nil {
+		return database.ErrorInvalidIdentifier
+	}
+
+	str.mutex.Lock()
+	defer str.mutex.Unlock()
+
+	if _, err := str.ownedProject(claims, projectId); err != nil {
+		return err
+	}
+
+	for id, access := range str.groupAccesses {
+		if access.ProjectID == projectId && access.GroupID == groupId {
+			delete(str.groupAccesses, id)
+			return nil
+		}
+	}
+
+	return database.ErrorNotFound
+}
+
+// QueryProjectGroups looking for Group entities which have access to Project entity with ascending order by name
+// field. Groups of a project which is not readable by the claims subject are not returned.
+func (str *MemoryStore) QueryProjectGroups(ctx context.Context, claims auth.Claims, projectId string) ([]Group,
+	error) {
+	if err := uuid.Validate(projectId); err != nil {
+		return nil, err
+	}
+
+	str.mutex.RLock()
+	defer str.mutex.RUnlock()
+
+	if projectData, found := str.projects[projectId]; !found || !str.readable(projectData, claims.Subject) {
+		return nil, nil
+	}
+
+	var groups []Group
+	for _, access := range str.groupAccesses {
+		if access.ProjectID == projectId {
+			groups = append(groups, str.groups[access.GroupID])
+		}
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		return groups[i].Name < groups[j].Name
+	})
+
+	return groups, nil
+}
+
+// QueryProjectsForUser looking for Project entities which the claims subject reaches through membership in a group
+// with access to the project, using skip/top mechanics with descending order by update date field.
+// Private projects of other users are excluded even when the subject has group access to them.
+// Archived projects are returned only if includeArchived is set.
+func (str *MemoryStore) QueryProjectsForUser(ctx context.Context, claims auth.Claims, includeArchived bool,
+	skip int32, top int32) ([]Project, error) {
+	str.mutex.RLock()
+	defer str.mutex.RUnlock()
+
+	memberOf := make(map[string]bool)
+	for _, groupUser := range str.groupUsers {
+		if groupUser.UserID == claims.Subject {
+			memberOf[groupUser.GroupID] = true
+		}
+	}
+
+	reachable := make(map[string]bool)
+	var projectCollection []Project
+	for _, access := range str.groupAccesses {
+		projectData, found := str.projects[access.ProjectID]
+		if found && memberOf[access.GroupID] && !reachable[access.ProjectID] &&
+			(includeArchived || projectData.DateArchived == nil) &&
+			canRead(projectData, claims.Subject, str.isOwner(projectData.ID, claims.Subject), true) {
+			reachable[access.ProjectID] = true
+			projectCollection = append(projectCollection, projectData)
+		}
+	}
+	sortProjects(projectCollection)
+
+	start, end, err := pageBounds(len(projectCollection), skip, top)
+	if err != nil {
+		return nil, fmt.Errorf("error during search of Project entities -> user_id={%q}: %w", claims.Subject, err)
+	}
+	if start == end {
+		return nil, nil
+	}
+
+	return projectCollection[start:end], nil
+}
+
+// CreateInvitation adds new Invitation entity of a user into Group entity which has access to Project entity.
+// Only the project owner who also manages the group, i.e. created it, can invite users.
+// If error occurs, the method can return database.ErrorInvalidReference for a group without access to the project,
+// database.ErrorForbidden for a group managed by another user, validation or other database errors.
+func (str *MemoryStore) CreateInvitation(ctx context.Context, claims auth.Claims, projectId string,
+	invitation NewInvitation, now time.Time) (Invitation, error) {
+	if err := uuid.Validate(projectId); err != nil {
+		return Invitation{}, database.ErrorInvalidIdentifier
+	}
+
+	if err := validation.Check(ctx, invitation); err != nil {
+		return Invitation{}, fmt.Errorf("error during data validation of Invitation entity: %w", err)
+	}
+
+	str.mutex.Lock()
+	defer str.mutex.Unlock()
+
+	if _, err := str.ownedProject(claims, projectId); err != nil {
+		return Invitation{}, err
+	}
+
+	if !str.hasGroupAccess(projectId, invitation.GroupID) {
+		return Invitation{}, fmt.Errorf("error during search of GroupAccess entity -> project_id={%q}, "+
+			"group_id={%q}: %w", projectId, invitation.GroupID, database.ErrorInvalidReference)
+	}
+
+	if str.groups[invitation.GroupID].CreatedByUser != claims.Subject {
+		return Invitation{}, database.ErrorForbidden
+	}
+
+	invitationData := newInvitation(claims, projectId, invitation, now)
+	str.invitations[invitationData.ID] = invitationData
+
+	return invitationData, nil
+}
+
+// RevokeInvitation marks pending Invitation entity of Project entity as revoked at now.
+// Only the project owner can revoke invitations.
+// If the invitation is not pending, the method returns database.ErrorNotFound.
+func (str *MemoryStore) RevokeInvitation(ctx context.Context, claims auth.Claims, projectId string,
+	invitationId string, now time.Time) error {
+	if err := uuid.Validate(projectId); err != nil {
+		return database.ErrorInvalidIdentifier
+	}
+	if err := uuid.Validate(invitationId); err != nil {
+		return database.ErrorInvalidIdentifier
+	}
+
+	str.mutex.Lock()
+	defer str.mutex.Unlock()
+
+	if _, err := str.ownedProject(claims, projectId); err != nil {
+		return err
+	}
+
+	invitationData, found := str.invitations[invitationId]
+	if !found || invitationData.ProjectID != projectId || !invitationData.IsPending(now) {
+		return database.ErrorNotFound
+	}
+
+	invitationData.DateRevoked = &now
+	str.invitations[invitationId] = invitationData
+
+	return nil
+}
+
+// AcceptInvitation accepts pending Invitation entity on behalf of the claims subject and assigns the subject to
+// the invitation group. The invitation can be accepted only once by the invited user.
+// If acceptance is successful, the method returns GroupUser entity.
+// The group should still have access to the project and be managed by the inviter.
+// If error occurs, the method can return ErrorInvitationClosed, database.ErrorForbidden for another user or a group
+// no longer managed by the inviter, database.ErrorInvalidReference for a group without access to the project,
+// database.ErrorConflict for a user who is already a group member or other database errors.
+func (str *MemoryStore) AcceptInvitation(ctx context.Context, claims auth.Claims, invitationId string,
+	now time.Time) (GroupUser, error) {
+	if err := uuid.Validate(invitationId); err != nil {
+		return GroupUser{}, database.ErrorInvalidIdentifier
+	}
+
+	str.mutex.Lock()
+	defer str.mutex.Unlock()
+
+	invitationData, found := str.invitations[invitationId]
+	if !found {
+		return GroupUser{}, fmt.Errorf("error during acceptance of Invitation entity -> id={%q}: %w", invitationId,
+			database.ErrorNotFound)
+	}
+
+	if !invitationData.IsPending(now) {
+		return GroupUser{}, fmt.Errorf("error during acceptance of Invitation entity -> id={%q}: %w", invitationId,
+			ErrorInvitationClosed)
+	}
+
+	if !invitationData.IsAddressedTo(claims) {
+		return GroupUser{}, database.ErrorForbidden
+	}
+
+	// The group could lose access to the project or its manager since the invitation was created.
+	if !str.hasGroupAccess(invitationData.ProjectID, invitationData.GroupID) {
+		return GroupUser{}, fmt.Errorf("error during acceptance of Invitation entity -> id={%q}: %w", invitationId,
+			database.ErrorInvalidReference)
+	}
+	if str.groups[invitationData.GroupID].CreatedByUser != invitationData.CreatedByUser {
+		return GroupUser{}, database.ErrorForbidden
+	}
+
+	if _, found := str.groupUser(invitationData.GroupID, claims.Subject); found {
+		return GroupUser{}, fmt.Errorf("error during acceptance of Invitation entity -> id={%q}: %w", invitationId,
+			database.ErrorConflict)
+	}
+
+	groupUserData := GroupUser{
+		ID:            uuid.Generate(),
+		GroupID:       invitationData.GroupID,
+		UserID:        claims.Subject,
+		DateCreated:   now,
+		CreatedByUser: invitationData.CreatedByUser,
+	}
+	str.groupUsers[groupUserData.ID] = groupUserData
+
+	acceptedBy := claims.Subject
+	invitationData.DateAccepted = &now
+	invitationData.AcceptedByUser = &acceptedBy
+	str.invitations[invitationId] = invitationData
+
+	return groupUserData, nil
+}
+
+// QueryPendingInvitations looking for Invitation entities of Project entity which are pending at now with
+// descending order by creation date field.
+// Only the project owner can see invitations.
+func (str *MemoryStore) QueryPendingInvitations(ctx context.Context, claims auth.Claims, projectId string,
+	now time.Time) ([]Invitation, error) {
+	if err := uuid.Validate(projectId); err != nil {
+		return nil, database.ErrorInvalidIdentifier
+	}
+
+	str.mutex.RLock()
+	defer str.mutex.RUnlock()
+
+	if _, err := str.ownedProject(claims, projectId); err != nil {
+		return nil, err
+	}
+
+	var invitations []Invitation
+	for _, invitation := range str.invitations {
+		if invitation.ProjectID == projectId && invitation.IsPending(now) {
+			invitations = append(invitations, invitation)
+		}
+	}
+	sort.Slice(invitations, func(i, j int) bool {
+		return invitations[i].DateCreated.After(invitations[j].DateCreated)
+	})
+
+	return invitations, nil
+}
+
+// AddProjectOwner assigns a user as one more owner of Project entity.
+// Only an owner of the project can add owners.
+// If assignment is successful, the method returns ProjectOwner entity.
+// If error occurs, the method can return database.ErrorConflict for a user who is already an owner,
+// validation or other database errors.
+func (str *MemoryStore) AddProjectOwner(ctx context.Context, claims auth.Claims, projectId string,
+	owner NewProjectOwner, now time.Time) (ProjectOwner, error) {
+	if err := uuid.Validate(projectId); err != nil {
+		return ProjectOwner{}, database.ErrorInvalidIdentifier
+	}
+
+	if err := validation.Check(ctx, owner); err != nil {
+		return ProjectOwner{}, fmt.Errorf("error during data validation of ProjectOwner entity: %w", err)
+	}
+
+	str.mutex.Lock()
+	defer str.mutex.Unlock()
+
+	if _, err := str.ownedProject(claims, projectId); err != nil {
+		return ProjectOwner{}, err
+	}
+
+	if str.isOwner(projectId, owner.UserID) {
+		return ProjectOwner{}, fmt.Errorf("error during create of new ProjectOwner entity -> project_id={%q}: %w",
+			projectId, database.ErrorConflict)
+	}
+
+	ownerData := newProjectOwner(claims, projectId, owner.UserID, now)
+	str.owners[ownerData.ID] = ownerData
+
+	return ownerData, nil
+}
+
+// RemoveProjectOwner removes a user from owners of Project entity. Owners can remove other owners or leave
+// the project themselves.
+// If the user is not an owner of the project, the method returns database.ErrorNotFound.
+// If the user is the last owner of the project, the method returns ErrorLastOwner.
+func (str *MemoryStore) RemoveProjectOwner(ctx context.Context, claims auth.Claims, projectId string,
+	userId string) error {
+	if err := uuid.Validate(projectId); err != nil {
+		return database.ErrorInvalidIdentifier
+	}
+
+	str.mutex.Lock()
+	defer str.mutex.Unlock()
+
+	if _, err := str.ownedProject(claims, projectId); err != nil {
+		return err
+	}
+
+	return str.removeOwner(projectId, userId)
+}
+
+// TransferProjectOwnership makes a user an owner of Project entity instead of the claims subject.
+// The user keeps ownership if they already own the project, other owners are not changed.
+// If transfer is successful, the method returns ProjectOwner entity of the user.
+// If error occurs, the method can return database.ErrorConflict for transfer to the subject itself,
+// validation or other database errors.
+func (str *MemoryStore) TransferProjectOwnership(ctx context.Context, claims auth.Claims, projectId string,
+	transfer TransferOwnership, now time.Time) (ProjectOwner, error) {
+	if err := uuid.Validate(projectId); err != nil {
+		return ProjectOwner{}, database.ErrorInvalidIdentifier
+	}
+
+	if err := validation.Check(ctx, transfer); err != nil {
+		return ProjectOwner{}, fmt.Errorf("error during data validation of ProjectOwner entity: %w", err)
+	}
+
+	str.mutex.Lock()
+	defer str.mutex.Unlock()
+
+	if _, err := str.ownedProject(claims, projectId); err != nil {
+		return ProjectOwner{}, err
+	}
+
+	if transfer.UserID == claims.Subject {
+		return ProjectOwner{}, fmt.Errorf("error during transfer of Project ownership -> id={%q}: %w", projectId,
+			database.ErrorConflict)
+	}
+
+	ownerData, found := str.projectOwner(projectId, transfer.UserID)
+	if !found {
+		ownerData = newProjectOwner(claims, projectId, transfer.UserID, now)
+		str.owners[ownerData.ID] = ownerData
+	}
+
+	if err := str.removeOwner(projectId, claims.Subject); err != nil {
+		return ProjectOwner{}, fmt.Errorf("error during transfer of Project ownership -> id={%q}: %w", projectId, err)
+	}
+
+	return ownerData, nil
+}
+
+// QueryProjectOwners looking for ProjectOwner entities of Project entity readable by the claims subject with
+// ascending order by creation date field.
+// Project which is not readable by the subject is reported with database.ErrorNotFound.
+func (str *MemoryStore) QueryProjectOwners(ctx context.Context, claims auth.Claims, projectId string) ([]ProjectOwner,
+	error) {
+	if err := uuid.Validate(projectId); err != nil {
+		return nil, database.ErrorInvalidIdentifier
+	}
+
+	str.mutex.RLock()
+	defer str.mutex.RUnlock()
+
+	projectData, found := str.projects[projectId]
+	if !found || !str.readable(projectData, claims.Subject) {
+		return nil, database.ErrorNotFound
+	}
+
+	return str.projectOwners(projectId), nil
+}
+
+// IsProjectOwner reports whether the claims subject owns Project entity with projectId identifier.
+// If the project does not exist, the method returns database.ErrorNotFound.
+func (str *MemoryStore) IsProjectOwner(ctx context.Context, claims auth.Claims, projectId string) (bool, error) {
+	if err := uuid.Validate(projectId); err != nil {
+		return false, err
+	}
+
+	str.mutex.RLock()
+	defer str.mutex.RUnlock()
+
+	if _, found := str.liveProject(projectId); !found {
+		return false, database.ErrorNotFound
+	}
+
+	return str.isOwner(projectId, claims.Subject), nil
+}
+
+// WithAccessRequestHooks registers hooks which are called after every change of AccessRequest entities.
+// Hooks are called while the mutex is held, so they must not call MemoryStore back.
+func (str *MemoryStore) WithAccessRequestHooks(hooks ...AccessRequestHook) *MemoryStore {
+	str.mutex.Lock()
+	defer str.mutex.Unlock()
+
+	str.hooks = append(str.hooks, hooks...)
+
+	return str
+}
+
+// RequestAccess adds new pending AccessRequest entity of the claims subject to Team Project entity.
+// If error occurs, the method can return database.ErrorNotFound for a project hidden from the subject,
+// database.ErrorConflict when the subject can already read the project or has a pending request,
+// validation or other database errors.
+func (str *MemoryStore) RequestAccess(ctx context.Context, claims auth.Claims, projectId string,
+	request NewAccessRequest, now time.Time) (AccessRequest, error) {
+	if err := uuid.Validate(projectId); err != nil {
+		return AccessRequest{}, database.ErrorInvalidIdentifier
+	}
+
+	if err := validation.Check(ctx, request); err != nil {
+		return AccessRequest{}, fmt.Errorf("error during data validation of AccessRequest entity: %w", err)
+	}
+
+	str.mutex.Lock()
+	defer str.mutex.Unlock()
+
+	projectData, found := str.liveProject(projectId)
+	if !found {
+		return AccessRequest{}, fmt.Errorf("error during search of Project entity -> id={%q}: %w", projectId,
+			database.ErrorNotFound)
+	}
+
+	err := checkAccessRequestAllowed(projectData, str.readable(projectData, claims.Subject))
+	if err == nil && str.hasPendingAccessRequest(projectId, claims.Subject) {
+		err = database.ErrorConflict
+	}
+	if err != nil {
+		return AccessRequest{}, fmt.Errorf("error during create of new AccessRequest entity -> project_id={%q}: %w",
+			projectId, err)
+	}
+
+	requestData := newAccessRequest(claims, projectId, request, now)
+	str.accessRequests[requestData.ID] = requestData
+
+	callAccessRequestHooks(ctx, str.hooks, requestData, str.projectOwners(requestData.ProjectID))
+
+	return requestData, nil
+}
+
+// ApproveAccessRequest approves pending AccessRequest entity and assigns the requester to Group entity which has
+// access to the project. Only the project owner who also manages the group, i.e. created it, can approve requests.
+// If approval is successful, the method returns GroupUser entity.
+// If error occurs, the method can return ErrorAccessRequestClosed, database.ErrorInvalidReference for a group
+// without access to the project, database.ErrorForbidden for a group managed by another user,
+// database.ErrorConflict for a requester who is already a group member, validation or other database errors.
+func (str *MemoryStore) ApproveAccessRequest(ctx context.Context, claims auth.Claims, requestId string,
+	approval ApproveAccessRequest, now time.Time) (GroupUser, error) {
+	if err := uuid.Validate(requestId); err != nil {
+		return GroupUser{}, database.ErrorInvalidIdentifier
+	}
+
+	if err := validation.Check(ctx, approval); err != nil {
+		return GroupUser{}, fmt.Errorf("error during data validation of AccessRequest entity: %w", err)
+	}
+
+	str.mutex.Lock()
+	defer str.mutex.Unlock()
+
+	requestData, err := str.ownedAccessRequest(claims, requestId)
+	if err != nil {
+		return GroupUser{}, err
+	}
+
+	if !str.hasGroupAccess(requestData.ProjectID, approval.GroupID) {
+		return GroupUser{}, fmt.Errorf("error during search of GroupAccess entity -> project_id={%q}, "+
+			"group_id={%q}: %w", requestData.ProjectID, approval.GroupID, database.ErrorInvalidReference)
+	}
+
+	if str.groups[approval.GroupID].CreatedByUser != claims.Subject {
+		return GroupUser{}, database.ErrorForbidden
+	}
+
+	if _, found := str.groupUser(approval.GroupID, requestData.UserID); found {
+		return GroupUser{}, fmt.Errorf("error during approval of AccessRequest entity -> id={%q}: %w", requestId,
+			database.ErrorConflict)
+	}
+
+	decideAccessRequest(&requestData, claims, AccessRequestApproved, now)
+	requestData.GroupID = &approval.GroupID
+	str.accessRequests[requestId] = requestData
+
+	groupUserData := GroupUser{
+		ID:            uuid.Generate(),
+		GroupID:       approval.GroupID,
+		UserID:        requestData.UserID,
+		DateCreated:   now,
+		CreatedByUser: claims.Subject,
+	}
+	str.groupUsers[groupUserData.ID] = groupUserData
+
+	callAccessRequestHooks(ctx, str.hooks, requestData, str.projectOwners(requestData.ProjectID))
+
+	return groupUserData, nil
+}
+
+// RejectAccessRequest rejects pending AccessRequest entity with a reason.
+// Only the project owner can decide requests.
+// If error occurs, the method can return ErrorAccessRequestClosed, validation or database errors.
+func (str *MemoryStore) RejectAccessRequest(ctx context.Context, claims auth.Claims, requestId string,
+	rejection RejectAccessRequest, now time.Time) error {
+	if err := uuid.Validate(requestId); err != nil {
+		return database.ErrorInvalidIdentifier
+	}
+
+	if err := validation.Check(ctx, rejection); err != nil {
+		return fmt.Errorf("error during data validation of AccessRequest entity: %w", err)
+	}
+
+	str.mutex.Lock()
+	defer str.mutex.Unlock()
+
+	requestData, err := str.ownedAccessRequest(claims, requestId)
+	if err != nil {
+		return err
+	}
+
+	decideAccessRequest(&requestData, claims, AccessRequestRejected, now)
+	requestData.Reason = &rejection.Reason
+	str.accessRequests[requestId] = requestData
+
+	callAccessRequestHooks(ctx, str.hooks, requestData, str.projectOwners(requestData.ProjectID))
+
+	return nil
+}
+
+// QueryPendingAccessRequests looking for pending AccessRequest entities of Project entity using skip/top mechanics
+// with ascending order by creation date field, so the oldest requests go first.
+// Only the project owner can see requests.
+func (str *MemoryStore) QueryPendingAccessRequests(ctx context.Context, claims auth.Claims, projectId string,
+	skip int32, top int32) ([]AccessRequest, error) {
+	if err := uuid.Validate(projectId); err != nil {
+		return nil, database.ErrorInvalidIdentifier
+	}
+
+	str.mutex.RLock()
+	defer str.mutex.RUnlock()
+
+	if _, err := str.ownedProject(claims, projectId); err != nil {
+		return nil, err
+	}
+
+	var requests []AccessRequest
+	for _, request := range str.accessRequests {
+		if request.ProjectID == projectId && request.Status == AccessRequestPending {
+			requests = append(requests, request)
+		}
+	}
+	sort.Slice(requests, func(i, j int) bool {
+		return requests[i].DateCreated.Before(requests[j].DateCreated)
+	})
+
+	return pageAccessRequests(requests, skip, top)
+}
+
+// QueryAccessRequestsByUser looking for AccessRequest entities of the claims subject using skip/top mechanics with
+// descending order by creation date field.
+func (str *MemoryStore) QueryAccessRequestsByUser(ctx context.Context, claims auth.Claims, skip int32,
+	top int32) ([]AccessRequest, error) {
+	str.mutex.RLock()
+	defer str.mutex.RUnlock()
+
+	var requests []AccessRequest
+	for _, request := range str.accessRequests {
+		if request.UserID == claims.Subject {
+			requests = append(requests, request)
+		}
+	}
+	sort.Slice(requests, func(i, j int) bool {
+		return requests[i].DateCreated.After(requests[j].DateCreated)
+	})
+
+	return pageAccessRequests(requests, skip, top)
+}
+
+// ownedAccessRequest returns pending AccessRequest entity if the claims subject owns its project.
+// The caller should hold the mutex.
+func (str *MemoryStore) ownedAccessRequest(claims auth.Claims, requestId string) (AccessRequest, error) {
+	requestData, found := str.accessRequests[requestId]
+	if !found {
+		return AccessRequest{}, fmt.Errorf("error during search of AccessRequest entity -> id={%q}: %w",
+			requestId, database.ErrorNotFound)
+	}
+
+	if _, err := str.ownedProject(claims, requestData.ProjectID); err != nil {
+		return AccessRequest{}, err
+	}
+
+	if requestData.Status != AccessRequestPending {
+		return AccessRequest{}, fmt.Errorf("error during decision of AccessRequest entity -> id={%q}: %w",
+			requestId, ErrorAccessRequestClosed)
+	}
+
+	return requestData, nil
+}
+
+// removeOwner removes the user from owners of Project entity unless the user is the last owner.
+// The caller should hold the mutex.
+func (str *MemoryStore) removeOwner(projectId string, userId string) error {
+	ownerData, found := str.projectOwner(projectId, userId)
+	if !found {
+		return database.ErrorNotFound
+	}
+
+	if len(str.projectOwners(projectId)) == 1 {
+		return ErrorLastOwner
+	}
+
+	delete(str.owners, ownerData.ID)
+
+	return nil
+}
+
+// hasPendingAccessRequest reports whether the user already waits for decision on access to Project entity.
+// The caller should hold the mutex.
+func (str *MemoryStore) hasPendingAccessRequest(projectId string, userId string) bool {
+	for _, request := range str.accessRequests {
+		if request.ProjectID == projectId && request.UserID == userId && request.Status == AccessRequestPending {
+			return true
+		}
+	}
+
+	return false
+}
+
+// pageAccessRequests returns a page of sorted AccessRequest entities.
+func pageAccessRequests(requests []AccessRequest, skip int32, top int32) ([]AccessRequest, error) {
+	start, end, err := pageBounds(len(requests), skip, top)
+	if err != nil {
+		return nil, fmt.Errorf("error during search of AccessRequest entities: %w", err)
+	}
+	if start == end {
+		return nil, nil
+	}
+
+	return requests[start:end], nil
+}
+
+// hasGroupAccess reports whether Group entity has access to Project entity.
+// The caller should hold the mutex.
+func (str *MemoryStore) hasGroupAccess(projectId string, groupId string) bool {
+	for _, access := range str.groupAccesses {
+		if access.ProjectID == projectId && access.GroupID == groupId {
+			return true
+		}
+	}
+
+	return false
+}
+
+// readable reports whether Project entity is readable by the reader according to its collaboration type.
+// The caller should hold the mutex.
+func (str *MemoryStore) readable(projectData Project, readerId string) bool {
+	isTeamMember := false
+	for _, access := range str.groupAccesses {
+		if access.ProjectID != projectData.ID {
+			continue
+		}
+		if _, found := str.groupUser(access.GroupID, readerId); found {
+			isTeamMember = true
+			break
+		}
+	}
+
+	return canRead(projectData, readerId, str.isOwner(projectData.ID, readerId), isTeamMember)
+}
+
+// writable reports whether content of Project entity can be changed by the user, it mirrors WritableCondition.
+// The caller should hold the mutex.
+func (str *MemoryStore) writable(projectId string, userId string) bool {
+	if str.isOwner(projectId, userId) {
+		return true
+	}
+
+	writeRole, found := str.roles[ReadWriteRole]
+	if !found || writeRole.DateRetired != nil {
+		return false
+	}
+
+	for _, groupRole := range str.groupRoles {
+		if groupRole.RoleID != ReadWriteRole || !str.hasGroupAccess(projectId, groupRole.GroupID) {
+			continue
+		}
+		if _, found := str.groupUser(groupRole.GroupID, userId); found {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ownedProject returns Project entity if it exists and is owned by the claims subject.
+// The caller should hold the mutex.
+func (str *MemoryStore) ownedProject(claims auth.Claims, projectId string) (Project, error) {
+	projectData, found := str.liveProject(projectId)
+	if !found {
+		return Project{}, fmt.Errorf("error during search of Project entity -> id={%q}: %w", projectId,
+			database.ErrorNotFound)
+	}
+
+	if !str.isOwner(projectId, claims.Subject) {
+		return Project{}, database.ErrorForbidden
+	}
+
+	return projectData, nil
+}
+
+// liveProject looking for Project entity which is not deleted.
+// The caller should hold the mutex.
+func (str *MemoryStore) liveProject(projectId string) (Project, bool) {
+	projectData, found := str.projects[projectId]
+	if !found || projectData.DateDeleted != nil {
+		return Project{}, false
+	}
+
+	return projectData, true
+}
+
+// isOwner reports whether the user is an owner of Project entity.
+// The caller should hold the mutex.
+func (str *MemoryStore) isOwner(projectId string, userId string) bool {
+	_, found := str.projectOwner(projectId, userId)
+
+	return found
+}
+
+// projectOwner looking for ownership of the user in Project entity.
+// The caller should hold the mutex.
+func (str *MemoryStore) projectOwner(projectId string, userId string) (ProjectOwner, bool) {
+	for _, owner := range str.owners {
+		if owner.ProjectID == projectId && owner.UserID == userId {
+			return owner, true
+		}
+	}
+
+	return ProjectOwner{}, false
+}
+
+// projectOwners returns ProjectOwner entities of Project entity with ascending order by creation date field.
+// The caller should hold the mutex.
+func (str *MemoryStore) projectOwners(projectId string) []ProjectOwner {
+	var owners []ProjectOwner
+	for _, owner := range str.owners {
+		if owner.ProjectID == projectId {
+			owners = append(owners, owner)
+		}
+	}
+	sort.Slice(owners, func(i, j int) bool {
+		if !owners[i].DateCreated.Equal(owners[j].DateCreated) {
+			return owners[i].DateCreated.Before(owners[j].DateCreated)
+		}
+		return owners[i].UserID < owners[j].UserID
+	})
+
+	return owners
+}
+
+// ownedGroup returns Group entity if it exists and was created by the claims subject.
+// The caller should hold the mutex.
+func (str *MemoryStore) ownedGroup(claims auth.Claims, groupId string) (Group, error) {
+	groupData, found := str.groups[groupId]
+	if !found {
+		return Group{}, fmt.Errorf("error during search of Group entity -> id={%q}: %w", groupId,
+			database.ErrorNotFound)
+	}
+
+	if groupData.CreatedByUser != claims.Subject {
+		return Group{}, database.ErrorForbidden
+	}
+
+	return groupData, nil
+}
+
+// visibleGroup returns Group entity if it exists and the claims subject is its creator or member.
+// The caller should hold the mutex.
+func (str *MemoryStore) visibleGroup(claims auth.Claims, groupId string) (Group, error) {
+	groupData, found := str.groups[groupId]
+	if !found {
+		return Group{}, database.ErrorNotFound
+	}
+
+	if groupData.CreatedByUser != claims.Subject {
+		if _, isMember := str.groupUser(groupId, claims.Subject); !isMember {
+			return Group{}, database.ErrorNotFound
+		}
+	}
+
+	return groupData, nil
+}
+
+// groupUser looking for assignment of the user to Group entity.
+// The caller should hold the mutex.
+func (str *MemoryStore) groupUser(groupId string, userId string) (GroupUser, bool) {
+	for _, groupUser := range str.groupUsers {
+		if groupUser.GroupID == groupId && groupUser.UserID == userId {
+			return groupUser, true
+		}
+	}
+
+	return GroupUser{}, false
+}
+
+// groupNameTaken reports whether other Group entity already uses the name.
+// The caller should hold the mutex.
+func (str *MemoryStore) groupNameTaken(name string, exceptId string) bool {
+	for _, groupData := range str.groups {
+		if groupData.Name == name && groupData.ID != exceptId {
+			return true
+		}
+	}
+
+	return false
+}
+
+// projectNameTaken reports whether other Project entity already uses the name.
+// The caller should hold the mutex.
+func (str *MemoryStore) projectNameTaken(name string, exceptId string) bool {
+	for _, projectData := range str.projects {
+		if projectData.Name == name && projectData.ID != exceptId {
+			return true
+		}
+	}
+
+	return false
+}
+
+// sortProjects orders Project entities by update date field in descending order.
+// Identifier is used as a tiebreaker to keep pages stable.
+func sortProjects(projectCollection []Project) {
+	sort.Slice(projectCollection, func(i, j int) bool {
+		if !projectCollection[i].DateUpdated.Equal(projectCollection[j].DateUpdated) {
+			return projectCollection[i].DateUpdated.After(projectCollection[j].DateUpdated)
+		}
+		return projectCollection[i].ID > projectCollection[j].ID
+	})
+}
+
+// sortRoles orders Role entities by name field in ascending order.
+func sortRoles(roles []Role) {
+	sort.Slice(roles, func(i, j int) bool {
+		return roles[i].Name < roles[j].Name
+	})
+}
+
+// pageBounds converts skip/top arguments into slice bounds of a collection with the specified length.
+func pageBounds(length int, skip int32, top int32) (int, int, error) {
+	if skip < 0 {
+		return 0, 0, errors.New("skip argument must not be negative")
+	}
+	if top < 0 {
+		return 0, 0, errors.New("top argument must not be negative")
+	}
+
+	start := int(skip)
+	if start > length {
+		start = length
+	}
+
+	end := start + int(top)
+	if end > length {
+		end = length
+	}
+
+	return start, end, nil
+}
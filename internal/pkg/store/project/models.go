@@ -2,6 +2,22 @@ package project
 
 import "time"
 
+const (
+	PublicCollaborationType  = "f4a284cd-5a65-4468-8800-e0d8762933a9"
+	TeamCollaborationType    = "0894f08f-1f45-4662-84bb-ac1d4f70b95d"
+	PrivateCollaborationType = "b84bd65c-4fd5-4b67-abd1-b342fd67ee17"
+)
+
+// ReadWriteRole allows members of groups with access to a Project to change its content, e.g. to add workspaces.
+const ReadWriteRole = "16ab20b6-2016-4923-b14e-743b516efcf7"
+
+// Statuses of AccessRequest entities.
+const (
+	AccessRequestPending  = "PENDING"
+	AccessRequestApproved = "APPROVED"
+	AccessRequestRejected = "REJECTED"
+)
+
 // CollaborationType represents a team collaboration level for a Project entity.
 type CollaborationType struct {
 	ID   string `db:"project_collaboration_type_id" json:"id"`
@@ -9,21 +25,37 @@ type CollaborationType struct {
 }
 
 // Project represents a unit of work structuring.
+// Archived projects are hidden from default queries, deleted projects are hidden from all queries until they are
+// restored or purged.
 type Project struct {
-	ID            string    `db:"project_id" json:"id"`
-	ProjectTypeID string    `db:"project_collaboration_type_id" json:"projectTypeId"`
-	Name          string    `db:"name" json:"name"`
-	Description   string    `db:"description" json:"description"`
+	ID            string     `db:"project_id" json:"id"`
+	ProjectTypeID string     `db:"project_collaboration_type_id" json:"projectTypeId"`
+	Name          string     `db:"name" json:"name"`
+	Description   string     `db:"description" json:"description"`
+	DateCreated   time.Time  `db:"date_created" json:"dateCreated"`
+	CreatedByUser string     `db:"created_by_user_id" json:"createdByUser"`
+	DateUpdated   time.Time  `db:"date_updated" json:"dateUpdated"`
+	UpdatedByUser string     `db:"updated_by_user_id" json:"updatedByUser"`
+	DateArchived  *time.Time `db:"date_archived" json:"dateArchived,omitempty"`
+	DateDeleted   *time.Time `db:"date_deleted" json:"dateDeleted,omitempty"`
+}
+
+// ProjectOwner represents a user who owns a Project. Owners hold every permission in the project regardless of
+// their group roles, and every project keeps at least one owner.
+type ProjectOwner struct {
+	ID            string    `db:"project_owner_id" json:"id"`
+	ProjectID     string    `db:"project_id" json:"projectId"`
+	UserID        string    `db:"user_id" json:"userId"`
 	DateCreated   time.Time `db:"date_created" json:"dateCreated"`
 	CreatedByUser string    `db:"created_by_user_id" json:"createdByUser"`
-	DateUpdated   time.Time `db:"date_updated" json:"dateUpdated"`
-	UpdatedByUser string    `db:"updated_by_user_id" json:"updatedByUser"`
 }
 
 // Role represents a one or several permissions to allow/restrict work in Project.
+// Retired roles stay assigned for history, but cannot be assigned again and do not grant permissions.
 type Role struct {
-	ID   string `db:"project_role_id" json:"id"`
-	Name string `db:"name" json:"name"`
+	ID          string     `db:"project_role_id" json:"id"`
+	Name        string     `db:"name" json:"name"`
+	DateRetired *time.Time `db:"date_retired" json:"dateRetired,omitempty"`
 }
 
 // Group represents a unit of team organization.
@@ -63,6 +95,36 @@ type GroupAccess struct {
 	CreatedByUser string    `db:"created_by_user_id" json:"createdByUser"`
 }
 
+// Invitation represents a single-use invitation of a user into a Group with access to a Project.
+// The user is identified either by subject identifier or by email.
+type Invitation struct {
+	ID             string     `db:"project_invitation_id" json:"id"`
+	ProjectID      string     `db:"project_id" json:"projectId"`
+	GroupID        string     `db:"project_group_id" json:"groupId"`
+	UserID         *string    `db:"invitee_user_id" json:"userId,omitempty"`
+	Email          *string    `db:"invitee_email" json:"email,omitempty"`
+	DateExpires    time.Time  `db:"date_expires" json:"dateExpires"`
+	DateAccepted   *time.Time `db:"date_accepted" json:"dateAccepted,omitempty"`
+	AcceptedByUser *string    `db:"accepted_by_user_id" json:"acceptedByUser,omitempty"`
+	DateRevoked    *time.Time `db:"date_revoked" json:"dateRevoked,omitempty"`
+	DateCreated    time.Time  `db:"date_created" json:"dateCreated"`
+	CreatedByUser  string     `db:"created_by_user_id" json:"createdByUser"`
+}
+
+// AccessRequest represents a request of a user for access to a Team Project, it is decided by the project owner.
+type AccessRequest struct {
+	ID            string     `db:"project_access_request_id" json:"id"`
+	ProjectID     string     `db:"project_id" json:"projectId"`
+	UserID        string     `db:"user_id" json:"userId"`
+	Message       *string    `db:"message" json:"message,omitempty"`
+	Status        string     `db:"status" json:"status"`
+	GroupID       *string    `db:"project_group_id" json:"groupId,omitempty"`
+	Reason        *string    `db:"reason" json:"reason,omitempty"`
+	DateCreated   time.Time  `db:"date_created" json:"dateCreated"`
+	DateDecided   *time.Time `db:"date_decided" json:"dateDecided,omitempty"`
+	DecidedByUser *string    `db:"decided_by_user_id" json:"decidedByUser,omitempty"`
+}
+
 // NewProject describes all data that should be specified during creation of new Project entity.
 type NewProject struct {
 	ProjectTypeID string `json:"projectTypeId" validate:"required"`
@@ -71,6 +133,73 @@ type NewProject struct {
 }
 
 // UpdateProject describes all data that can be changed during update of existing Project entity.
+// Fields which equal to nil are not changed.
 type UpdateProject struct {
-	// TODO Implement structure
+	ProjectTypeID *string `json:"projectTypeId" validate:"omitempty,uuid"`
+	Name          *string `json:"name" validate:"omitempty,min=1"`
+	Description   *string `json:"description" validate:"omitempty,min=1"`
+}
+
+// NewProjectOwner describes all data that should be specified during assignment of an owner to a Project.
+type NewProjectOwner struct {
+	UserID string `json:"userId" validate:"required"`
+}
+
+// TransferOwnership describes all data that should be specified during transfer of Project ownership to a user.
+type TransferOwnership struct {
+	UserID string `json:"userId" validate:"required"`
+}
+
+// NewGroup describes all data that should be specified during creation of new Group entity.
+type NewGroup struct {
+	Name string `json:"name" validate:"required"`
+}
+
+// UpdateGroup describes all data that can be changed during update of existing Group entity.
+type UpdateGroup struct {
+	Name *string `json:"name" validate:"required,min=1"`
+}
+
+// NewGroupUser describes all data that should be specified during assignment of a user to a Group.
+type NewGroupUser struct {
+	UserID string `json:"userId" validate:"required"`
+}
+
+// NewRole describes all data that should be specified during creation of new Role entity.
+type NewRole struct {
+	Name string `json:"name" validate:"required"`
+}
+
+// NewGroupRole describes all data that should be specified during assignment of a Role to a Group.
+type NewGroupRole struct {
+	RoleID string `json:"roleId" validate:"required,uuid"`
+}
+
+// NewGroupAccess describes all data that should be specified during grant of Project access to a Group.
+type NewGroupAccess struct {
+	GroupID string `json:"groupId" validate:"required,uuid"`
+}
+
+// NewInvitation describes all data that should be specified during invitation of a user into a Group.
+// Either UserID or Email must be specified, ExpiresInHours equals to DefaultInvitationLifetime when omitted.
+type NewInvitation struct {
+	GroupID        string `json:"groupId" validate:"required,uuid"`
+	UserID         string `json:"userId" validate:"required_without=Email"`
+	Email          string `json:"email" validate:"omitempty,email"`
+	ExpiresInHours int32  `json:"expiresInHours" validate:"omitempty,min=1,max=720"`
+}
+
+// NewAccessRequest describes all data that can be specified during request of access to a Project.
+type NewAccessRequest struct {
+	Message string `json:"message" validate:"omitempty,max=500"`
+}
+
+// ApproveAccessRequest describes all data that should be specified during approval of AccessRequest entity.
+type ApproveAccessRequest struct {
+	GroupID string `json:"groupId" validate:"required,uuid"`
+}
+
+// RejectAccessRequest describes all data that should be specified during rejection of AccessRequest entity.
+type RejectAccessRequest struct {
+	Reason string `json:"reason" validate:"required,max=500"`
 }
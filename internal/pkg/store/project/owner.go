@@ -0,0 +1,346 @@
+package project
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/SKorolchuk/dpio-workspace/internal/pkg/infra/auth"
+	"github.com/SKorolchuk/dpio-workspace/internal/pkg/infra/database"
+	"github.com/SKorolchuk/dpio-workspace/internal/pkg/infra/uuid"
+	"github.com/SKorolchuk/dpio-workspace/internal/pkg/infra/validation"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// ErrorLastOwner is returned when the only owner of Project entity is removed.
+var ErrorLastOwner = errors.New("project must keep at least one owner")
+
+// AddProjectOwner assigns a user as one more owner of Project entity.
+// Only an owner of the project can add owners.
+// If assignment is successful, the method returns ProjectOwner entity.
+// If error occurs, the method can return database.ErrorConflict for a user who is already an owner,
+// validation or other database errors.
+func (str Store) AddProjectOwner(ctx context.Context, claims auth.Claims, projectId string, owner NewProjectOwner,
+	now time.Time) (ProjectOwner, error) {
+	if err := uuid.Validate(projectId); err != nil {
+		return ProjectOwner{}, database.ErrorInvalidIdentifier
+	}
+
+	if err := validation.Check(ctx, owner); err != nil {
+		return ProjectOwner{}, fmt.Errorf("error during data validation of ProjectOwner entity: %w", err)
+	}
+
+	if _, err := str.ownedProject(ctx, claims, projectId); err != nil {
+		return ProjectOwner{}, err
+	}
+
+	ownerData := newProjectOwner(claims, projectId, owner.UserID, now)
+	if err := str.insertProjectOwner(ctx, str.cluster.Primary(), ownerData); err != nil {
+		return ProjectOwner{}, fmt.Errorf("error during create of new ProjectOwner entity -> project_id={%q}: %w",
+			projectId, err)
+	}
+
+	return ownerData, nil
+}
+
+// RemoveProjectOwner removes a user from owners of Project entity. Owners can remove other owners or leave
+// the project themselves.
+// If the user is not an owner of the project, the method returns database.ErrorNotFound.
+// If the user is the last owner of the project, the method returns ErrorLastOwner.
+func (str Store) RemoveProjectOwner(ctx context.Context, claims auth.Claims, projectId string, userId string) error {
+	if err := uuid.Validate(projectId); err != nil {
+		return database.ErrorInvalidIdentifier
+	}
+
+	if _, err := str.ownedProject(ctx, claims, projectId); err != nil {
+		return err
+	}
+
+	err := database.WithTransaction(ctx, str.cluster.Primary(), func(transaction *sqlx.Tx) error {
+		return str.deleteProjectOwner(ctx, transaction, projectId, userId)
+	})
+	if err != nil {
+		if err == database.ErrorNotFound {
+			return database.ErrorNotFound
+		}
+
+		return fmt.Errorf("error during delete of ProjectOwner entity -> project_id={%q}, user_id={%q}: %w",
+			projectId, userId, err)
+	}
+
+	return nil
+}
+
+// TransferProjectOwnership makes a user an owner of Project entity instead of the claims subject.
+// The user keeps ownership if they already own the project, other owners are not changed.
+// If transfer is successful, the method returns ProjectOwner entity of the user.
+// If error occurs, the method can return database.ErrorConflict for transfer to the subject itself,
+// validation or other database errors.
+func (str Store) TransferProjectOwnership(ctx context.Context, claims auth.Claims, projectId string,
+	transfer TransferOwnership, now time.Time) (ProjectOwner, error) {
+	if err := uuid.Validate(projectId); err != nil {
+		return ProjectOwner{}, database.ErrorInvalidIdentifier
+	}
+
+	if err := validation.Check(ctx, transfer); err != nil {
+		return ProjectOwner{}, fmt.Errorf("error during data validation of ProjectOwner entity: %w", err)
+	}
+
+	if _, err := str.ownedProject(ctx, claims, projectId); err != nil {
+		return ProjectOwner{}, err
+	}
+
+	if transfer.UserID == claims.Subject {
+		return ProjectOwner{}, fmt.Errorf("error during transfer of Project ownership -> id={%q}: %w", projectId,
+			database.ErrorConflict)
+	}
+
+	queryParams := struct {
+		ProjectID string `db:"project_id"`
+		UserID    string `db:"user_id"`
+	}{
+		ProjectID: projectId,
+		UserID:    transfer.UserID,
+	}
+
+	const selectQuery = `
+	SELECT
+		o.project_owner_id,
+		o.project_id,
+		o.user_id,
+		o.date_created,
+		o.created_by_user_id
+	FROM
+		PROJECT_OWNER AS o
+	WHERE
+		o.project_id = :project_id AND o.user_id = :user_id`
+
+	var ownerData ProjectOwner
+	err := database.WithTransaction(ctx, str.cluster.Primary(), func(transaction *sqlx.Tx) error {
+		err := database.NamedQueryStruct(ctx, str.logger, transaction, selectQuery, queryParams, &ownerData)
+		switch {
+		case err == database.ErrorNotFound:
+			ownerData = newProjectOwner(claims, projectId, transfer.UserID, now)
+			if err := str.insertProjectOwner(ctx, transaction, ownerData); err != nil {
+				return err
+			}
+		case err != nil:
+			return err
+		}
+
+		return str.deleteProjectOwner(ctx, transaction, projectId, claims.Subject)
+	})
+	if err != nil {
+		return ProjectOwner{}, fmt.Errorf("error during transfer of Project ownership -> id={%q}: %w", projectId, err)
+	}
+
+	return ownerData, nil
+}
+
+// QueryProjectOwners looking for ProjectOwner entities of Project entity readable by the claims subject with
+// ascending order by creation date field.
+// Project which is not readable by the subject is reported with database.ErrorNotFound.
+func (str Store) QueryProjectOwners(ctx context.Context, claims auth.Claims, projectId string) ([]ProjectOwner,
+	error) {
+	if err := uuid.Validate(projectId); err != nil {
+		return nil, database.ErrorInvalidIdentifier
+	}
+
+	if _, err := str.QueryProjectByID(ctx, claims, projectId); err != nil {
+		if err == database.ErrorNotFound {
+			return nil, database.ErrorNotFound
+		}
+
+		return nil, fmt.Errorf("error during search of Project entity -> id={%q}: %w", projectId, err)
+	}
+
+	owners, err := str.queryProjectOwners(ctx, str.cluster.Reader(ctx), projectId)
+	if err != nil {
+		if err == database.ErrorNotFound {
+			return nil, database.ErrorNotFound
+		}
+
+		return nil, fmt.Errorf("error during search of ProjectOwner entities -> project_id={%q}: %w", projectId, err)
+	}
+
+	return owners, nil
+}
+
+// IsProjectOwner reports whether the claims subject owns Project entity with projectId identifier.
+// If the project does not exist, the method returns database.ErrorNotFound.
+func (str Store) IsProjectOwner(ctx context.Context, claims auth.Claims, projectId string) (bool, error) {
+	if _, err := str.queryProjectByID(ctx, projectId); err != nil {
+		return false, err
+	}
+
+	return str.isProjectOwner(ctx, str.cluster.Primary(), projectId, claims.Subject)
+}
+
+// ownedProject returns Project entity if it exists and is owned by the claims subject.
+// If the subject is not an owner, the method returns database.ErrorForbidden.
+func (str Store) ownedProject(ctx context.Context, claims auth.Claims, projectId string) (Project, error) {
+	projectData, err := str.queryProjectByID(ctx, projectId)
+	if err != nil {
+		return Project{}, fmt.Errorf("error during search of Project entity -> id={%q}: %w", projectId, err)
+	}
+
+	isOwner, err := str.isProjectOwner(ctx, str.cluster.Primary(), projectId, claims.Subject)
+	if err != nil {
+		return Project{}, fmt.Errorf("error during search of ProjectOwner entity -> project_id={%q}: %w",
+			projectId, err)
+	}
+
+	if !isOwner {
+		return Project{}, database.ErrorForbidden
+	}
+
+	return projectData, nil
+}
+
+// isProjectOwner reports whether the user is an owner of Project entity.
+func (str Store) isProjectOwner(ctx context.Context, connection sqlx.ExtContext, projectId string,
+	userId string) (bool, error) {
+	queryParams := struct {
+		ProjectID string `db:"project_id"`
+		UserID    string `db:"user_id"`
+	}{
+		ProjectID: projectId,
+		UserID:    userId,
+	}
+
+	const query = `
+	SELECT
+		o.project_owner_id
+	FROM
+		PROJECT_OWNER AS o
+	WHERE
+		o.project_id = :project_id AND o.user_id = :user_id`
+
+	var owner struct {
+		ID string `db:"project_owner_id"`
+	}
+	if err := database.NamedQueryStruct(ctx, str.logger, connection, query, queryParams, &owner); err != nil {
+		if err == database.ErrorNotFound {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	return true, nil
+}
+
+// queryProjectOwners looking for ProjectOwner entities of Project entity with ascending order by creation date field.
+func (str Store) queryProjectOwners(ctx context.Context, connection sqlx.ExtContext,
+	projectId string) ([]ProjectOwner, error) {
+	queryParams := struct {
+		ProjectID string `db:"project_id"`
+	}{
+		ProjectID: projectId,
+	}
+
+	const query = `
+	SELECT
+		o.project_owner_id,
+		o.project_id,
+		o.user_id,
+		o.date_created,
+		o.created_by_user_id
+	FROM
+		PROJECT_OWNER AS o
+	WHERE
+		o.project_id = :project_id
+	ORDER BY o.date_created, o.user_id`
+
+	var owners []ProjectOwner
+	if err := database.NamedQuerySlice(ctx, str.logger, connection, query, queryParams, &owners); err != nil {
+		return nil, err
+	}
+
+	return owners, nil
+}
+
+// insertProjectOwner stores ProjectOwner entity, repeated owner is reported with database.ErrorConflict.
+func (str Store) insertProjectOwner(ctx context.Context, connection sqlx.ExtContext, ownerData ProjectOwner) error {
+	const query = `
+	INSERT INTO PROJECT_OWNER
+		(project_owner_id, project_id, user_id, date_created, created_by_user_id)
+	VALUES
+		(:project_owner_id, :project_id, :user_id, :date_created, :created_by_user_id)`
+
+	return database.NamedExecContext(ctx, str.logger, connection, query, ownerData)
+}
+
+// deleteProjectOwner removes the user from owners of Project entity unless the user is the last owner.
+// The project row is locked before owners are counted, so concurrent removals cannot leave the project without
+// owners. SQLite has no row locks, there the count and the removal are kept in a single statement while writing
+// transactions are serialized by the database itself.
+// If the user is not an owner, the method returns database.ErrorNotFound.
+func (str Store) deleteProjectOwner(ctx context.Context, transaction *sqlx.Tx, projectId string, userId string) error {
+	queryParams := struct {
+		ProjectID string `db:"project_id"`
+		UserID    string `db:"user_id"`
+	}{
+		ProjectID: projectId,
+		UserID:    userId,
+	}
+
+	lockQuery := `
+	SELECT
+		p.project_id
+	FROM
+		PROJECT AS p
+	WHERE
+		p.project_id = :project_id`
+
+	if transaction.DriverName() != database.DriverSQLite {
+		lockQuery += `
+	FOR UPDATE`
+	}
+
+	const query = `
+	DELETE FROM
+		PROJECT_OWNER
+	WHERE
+		project_id = :project_id AND user_id = :user_id
+		AND (SELECT COUNT(*) FROM PROJECT_OWNER AS o WHERE o.project_id = :project_id) > 1
+	RETURNING project_owner_id`
+
+	var locked struct {
+		ID string `db:"project_id"`
+	}
+	if err := database.NamedQueryStruct(ctx, str.logger, transaction, lockQuery, queryParams, &locked); err != nil {
+		return err
+	}
+
+	var removed struct {
+		ID string `db:"project_owner_id"`
+	}
+	err := database.NamedQueryStruct(ctx, str.logger, transaction, query, queryParams, &removed)
+	if err != database.ErrorNotFound {
+		return err
+	}
+
+	isOwner, err := str.isProjectOwner(ctx, transaction, projectId, userId)
+	if err != nil {
+		return err
+	}
+	if isOwner {
+		return ErrorLastOwner
+	}
+
+	return database.ErrorNotFound
+}
+
+// newProjectOwner creates ProjectOwner entity of the user.
+func newProjectOwner(claims auth.Claims, projectId string, userId string, now time.Time) ProjectOwner {
+	return ProjectOwner{
+		ID:            uuid.Generate(),
+		ProjectID:     projectId,
+		UserID:        userId,
+		DateCreated:   now,
+		CreatedByUser: claims.Subject,
+	}
+}
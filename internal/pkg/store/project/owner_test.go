@@ -0,0 +1,39 @@
+package project
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/SKorolchuk/dpio-workspace/internal/pkg/infra/database"
+)
+
+func TestMemoryStoreRemoveProjectOwner(t *testing.T) {
+	str := NewMemoryStore()
+	ctx := context.Background()
+	projectData := mustCreateProject(t, str, "Team", TeamCollaborationType, baseDate)
+
+	remove := func(subjectId string, userId string) error {
+		return str.RemoveProjectOwner(ctx, claimsOf(subjectId), projectData.ID, userId)
+	}
+
+	if err := remove(ownerID, ownerID); !errors.Is(err, ErrorLastOwner) {
+		t.Errorf("RemoveProjectOwner() of the last owner error = %v, want %v", err, ErrorLastOwner)
+	}
+	if _, err := str.AddProjectOwner(ctx, claimsOf(ownerID), projectData.ID, NewProjectOwner{UserID: memberID},
+		baseDate); err != nil {
+		t.Fatalf("AddProjectOwner() error = %v", err)
+	}
+	if err := remove(strangerID, memberID); !errors.Is(err, database.ErrorForbidden) {
+		t.Errorf("RemoveProjectOwner() by a stranger error = %v, want %v", err, database.ErrorForbidden)
+	}
+	if err := remove(ownerID, strangerID); !errors.Is(err, database.ErrorNotFound) {
+		t.Errorf("RemoveProjectOwner() of not an owner error = %v, want %v", err, database.ErrorNotFound)
+	}
+	if err := remove(ownerID, ownerID); err != nil {
+		t.Fatalf("RemoveProjectOwner() error = %v", err)
+	}
+	if err := remove(memberID, memberID); !errors.Is(err, ErrorLastOwner) {
+		t.Errorf("RemoveProjectOwner() of the remaining owner error = %v, want %v", err, ErrorLastOwner)
+	}
+}
@@ -10,9 +10,29 @@ import (
 	"github.com/SKorolchuk/dpio-workspace/internal/pkg/infra/database"
 	"github.com/SKorolchuk/dpio-workspace/internal/pkg/infra/uuid"
 	"github.com/SKorolchuk/dpio-workspace/internal/pkg/infra/validation"
+
+	"github.com/jmoiron/sqlx"
 )
 
-// CreateProject adds new Project entity to the database.
+// ErrorProjectHasWorkspaces is returned when Project entity cannot be removed without its Workspace entities.
+var ErrorProjectHasWorkspaces = errors.New("project has workspaces")
+
+// ProjectDeletionEvent describes Project entity which was marked as deleted or restored.
+// DateDeleted is the deletion date of the project, for restored projects it is the date before restore, so
+// entities deleted together with the project can be found.
+type ProjectDeletionEvent struct {
+	ProjectID   string
+	DateDeleted time.Time
+	Restored    bool
+	UserID      string
+	Now         time.Time
+}
+
+// ProjectDeletionHook is called by MemoryStore after Project entity is deleted or restored, so in-memory stores of
+// dependent entities can follow the project state in the same way as database does.
+type ProjectDeletionHook func(ctx context.Context, event ProjectDeletionEvent)
+
+// CreateProject adds new Project entity to the database, the claims subject becomes its first owner.
 // If creation is successful, the method returns Project entity.
 // Can return validation or database errors.
 func (str Store) CreateProject(ctx context.Context, claims auth.Claims, project NewProject, now time.Time) (Project,
@@ -40,7 +60,14 @@ func (str Store) CreateProject(ctx context.Context, claims auth.Claims, project
 		(:project_id, :project_collaboration_type_id, :name, :description, :date_created,
 			:created_by_user_id, :date_updated, :updated_by_user_id)`
 
-	if err := database.NamedExecContext(ctx, str.logger, str.connection, query, projectData); err != nil {
+	err := database.WithTransaction(ctx, str.cluster.Primary(), func(transaction *sqlx.Tx) error {
+		if err := database.NamedExecContext(ctx, str.logger, transaction, query, projectData); err != nil {
+			return err
+		}
+
+		return str.insertProjectOwner(ctx, transaction, newProjectOwner(claims, projectData.ID, claims.Subject, now))
+	})
+	if err != nil {
 		return Project{}, fmt.Errorf("error during create of new Project entity: %w", err)
 	}
 
@@ -48,33 +75,423 @@ func (str Store) CreateProject(ctx context.Context, claims auth.Claims, project
 }
 
 // UpdateProject change existing Project entity in the database.
+// Only specified fields of project are changed, audit fields are filled with now and claims subject.
 // If error occurs, the method can return validation or database errors.
 func (str Store) UpdateProject(ctx context.Context, claims auth.Claims, projectId string, project UpdateProject,
 	now time.Time) error {
-	return errors.New("TODO Implement method")
+	if err := uuid.Validate(projectId); err != nil {
+		return database.ErrorInvalidIdentifier
+	}
+
+	if err := validation.Check(ctx, project); err != nil {
+		return fmt.Errorf("error during data validation of Project entity: %w", err)
+	}
+
+	projectData, err := str.ownedProject(ctx, claims, projectId)
+	if err != nil {
+		return err
+	}
+
+	applyProjectUpdate(&projectData, project)
+	projectData.DateUpdated = now
+	projectData.UpdatedByUser = claims.Subject
+
+	const query = `
+	UPDATE
+		PROJECT
+	SET
+		"project_collaboration_type_id" = :project_collaboration_type_id,
+		"name" = :name,
+		"description" = :description,
+		"date_updated" = :date_updated,
+		"updated_by_user_id" = :updated_by_user_id
+	WHERE
+		project_id = :project_id`
+
+	if err := database.NamedExecContext(ctx, str.logger, str.cluster.Primary(), query, projectData); err != nil {
+		return fmt.Errorf("error during update of Project entity -> id={%q}: %w", projectId, err)
+	}
+
+	return nil
+}
+
+// DeleteProject marks existing Project entity as deleted at now. The project and its workspaces are hidden from all
+// queries until the project is restored, after the retention period they are removed permanently by the purge job.
+// The method refuses to delete Project which has Workspace entities unless cascade is set, in that case the
+// workspaces are marked as deleted at the same date.
+// If error occurs, the method can return ErrorProjectHasWorkspaces or database errors.
+func (str Store) DeleteProject(ctx context.Context, claims auth.Claims, projectId string, cascade bool,
+	now time.Time) error {
+	if err := uuid.Validate(projectId); err != nil {
+		return database.ErrorInvalidIdentifier
+	}
+
+	if _, err := str.ownedProject(ctx, claims, projectId); err != nil {
+		return err
+	}
+
+	queryParams := struct {
+		ProjectID string    `db:"project_id"`
+		UserID    string    `db:"updated_by_user_id"`
+		Now       time.Time `db:"now"`
+	}{
+		ProjectID: projectId,
+		UserID:    claims.Subject,
+		Now:       now,
+	}
+
+	const countQuery = `
+	SELECT
+		COUNT(*) AS amount
+	FROM
+		WORKSPACE AS w
+	WHERE
+		w.project_id = :project_id AND w.date_deleted IS NULL`
+
+	const deleteQuery = `
+	UPDATE
+		PROJECT
+	SET
+		"date_deleted" = :now,
+		"date_updated" = :now,
+		"updated_by_user_id" = :updated_by_user_id
+	WHERE
+		project_id = :project_id AND date_deleted IS NULL`
+
+	const workspaceDeleteQuery = `
+	UPDATE
+		WORKSPACE
+	SET
+		"date_deleted" = :now,
+		"date_updated" = :now,
+		"updated_by_user_id" = :updated_by_user_id
+	WHERE
+		project_id = :project_id AND date_deleted IS NULL`
+
+	err := database.WithTransaction(ctx, str.cluster.Primary(), func(transaction *sqlx.Tx) error {
+		if !cascade {
+			var workspaces struct {
+				Amount int `db:"amount"`
+			}
+			if err := database.NamedQueryStruct(ctx, str.logger, transaction, countQuery, queryParams,
+				&workspaces); err != nil {
+				return err
+			}
+
+			if workspaces.Amount > 0 {
+				return ErrorProjectHasWorkspaces
+			}
+		}
+
+		if err := database.NamedExecContext(ctx, str.logger, transaction, workspaceDeleteQuery,
+			queryParams); err != nil {
+			return err
+		}
+
+		return database.NamedExecContext(ctx, str.logger, transaction, deleteQuery, queryParams)
+	})
+	if err != nil {
+		return fmt.Errorf("error during delete of Project entity -> id={%q}: %w", projectId, err)
+	}
+
+	return nil
 }
 
-// DeleteProject removes existing Project entity in the database.
+// ArchiveProject marks existing Project entity as archived at now, so it is hidden from default queries.
+// Archiving of already archived project keeps its original archive date.
 // If error occurs, the method can return database errors.
-func (str Store) DeleteProject(ctx context.Context, claims auth.Claims, projectId string) error {
-	return errors.New("TODO Implement method")
+func (str Store) ArchiveProject(ctx context.Context, claims auth.Claims, projectId string, now time.Time) error {
+	if err := uuid.Validate(projectId); err != nil {
+		return database.ErrorInvalidIdentifier
+	}
+
+	if _, err := str.ownedProject(ctx, claims, projectId); err != nil {
+		return err
+	}
+
+	queryParams := struct {
+		ProjectID string    `db:"project_id"`
+		UserID    string    `db:"updated_by_user_id"`
+		Now       time.Time `db:"now"`
+	}{
+		ProjectID: projectId,
+		UserID:    claims.Subject,
+		Now:       now,
+	}
+
+	const query = `
+	UPDATE
+		PROJECT
+	SET
+		"date_archived" = :now,
+		"date_updated" = :now,
+		"updated_by_user_id" = :updated_by_user_id
+	WHERE
+		project_id = :project_id AND date_archived IS NULL`
+
+	if err := database.NamedExecContext(ctx, str.logger, str.cluster.Primary(), query, queryParams); err != nil {
+		return fmt.Errorf("error during archive of Project entity -> id={%q}: %w", projectId, err)
+	}
+
+	return nil
+}
+
+// RestoreProject returns archived or deleted Project entity back to default queries.
+// Deleted projects can be restored until they are purged, together with workspaces deleted at the same date.
+// If error occurs, the method can return database errors.
+func (str Store) RestoreProject(ctx context.Context, claims auth.Claims, projectId string, now time.Time) error {
+	if err := uuid.Validate(projectId); err != nil {
+		return database.ErrorInvalidIdentifier
+	}
+
+	if _, err := str.queryProject(ctx, projectId, true); err != nil {
+		return fmt.Errorf("error during search of Project entity -> id={%q}: %w", projectId, err)
+	}
+
+	isOwner, err := str.isProjectOwner(ctx, str.cluster.Primary(), projectId, claims.Subject)
+	if err != nil {
+		return fmt.Errorf("error during search of ProjectOwner entity -> project_id={%q}: %w", projectId, err)
+	}
+
+	if !isOwner {
+		return database.ErrorForbidden
+	}
+
+	queryParams := struct {
+		ProjectID string    `db:"project_id"`
+		UserID    string    `db:"updated_by_user_id"`
+		Now       time.Time `db:"now"`
+	}{
+		ProjectID: projectId,
+		UserID:    claims.Subject,
+		Now:       now,
+	}
+
+	const workspaceRestoreQuery = `
+	UPDATE
+		WORKSPACE
+	SET
+		"date_deleted" = NULL,
+		"date_updated" = :now,
+		"updated_by_user_id" = :updated_by_user_id
+	WHERE
+		project_id = :project_id AND
+		date_deleted = (SELECT p.date_deleted FROM PROJECT AS p WHERE p.project_id = :project_id)`
+
+	const query = `
+	UPDATE
+		PROJECT
+	SET
+		"date_archived" = NULL,
+		"date_deleted" = NULL,
+		"date_updated" = :now,
+		"updated_by_user_id" = :updated_by_user_id
+	WHERE
+		project_id = :project_id AND (date_archived IS NOT NULL OR date_deleted IS NOT NULL)`
+
+	err = database.WithTransaction(ctx, str.cluster.Primary(), func(transaction *sqlx.Tx) error {
+		if err := database.NamedExecContext(ctx, str.logger, transaction, workspaceRestoreQuery,
+			queryParams); err != nil {
+			return err
+		}
+
+		return database.NamedExecContext(ctx, str.logger, transaction, query, queryParams)
+	})
+	if err != nil {
+		return fmt.Errorf("error during restore of Project entity -> id={%q}: %w", projectId, err)
+	}
+
+	return nil
+}
+
+// QueryProjects looking for Project entities readable by the claims subject using skip/top mechanics with descending
+// order by update date field. Archived projects are returned only if includeArchived is set.
+func (str Store) QueryProjects(ctx context.Context, claims auth.Claims, includeArchived bool, skip int32,
+	top int32) ([]Project, error) {
+	queryParams := struct {
+		Visibility
+		IncludeArchived bool  `db:"include_archived"`
+		Skip            int32 `db:"offset"`
+		Top             int32 `db:"top"`
+	}{
+		Visibility:      NewVisibility(claims),
+		IncludeArchived: includeArchived,
+		Skip:            skip,
+		Top:             top,
+	}
+
+	const query = `
+	SELECT
+		p.project_id,
+		p.project_collaboration_type_id,
+		p.name,
+		p.description,
+		p.date_created,
+		p.created_by_user_id,
+		p.date_updated,
+		p.updated_by_user_id,
+		p.date_archived,
+		p.date_deleted
+	FROM
+		PROJECT AS p
+	WHERE
+		` + ReadableCondition + `
+		AND (:include_archived OR p.date_archived IS NULL)
+	ORDER BY p.date_updated DESC
+	LIMIT :top OFFSET :offset`
+
+	var projectCollection []Project
+	connection := str.cluster.Reader(ctx)
+	if err := database.NamedQuerySlice(ctx, str.logger, connection, query, queryParams,
+		&projectCollection); err != nil {
+		if err == database.ErrorNotFound {
+			return nil, database.ErrorNotFound
+		}
+
+		return nil, fmt.Errorf("error during search of Project entities: %w", err)
+	}
+
+	return projectCollection, nil
 }
 
-// QueryProjects looking for all Project entities using skip/top mechanics with descending order by update date field.
-func (str Store) QueryProjects(ctx context.Context, skip int32, top int32) ([]Project, error) {
-	return []Project{}, errors.New("TODO Implement method")
+// applyProjectUpdate copies specified fields of update into projectData.
+func applyProjectUpdate(projectData *Project, project UpdateProject) {
+	if project.ProjectTypeID != nil {
+		projectData.ProjectTypeID = *project.ProjectTypeID
+	}
+
+	if project.Name != nil {
+		projectData.Name = *project.Name
+	}
+
+	if project.Description != nil {
+		projectData.Description = *project.Description
+	}
 }
 
-// QueryProjectByID looking for Project entity with projectId identifier.
-func (str Store) QueryProjectByID(ctx context.Context, projectId string) (Project, error) {
+// QueryProjectByID looking for Project entity with projectId identifier readable by the claims subject.
+// Project which is not readable by the subject is reported with database.ErrorNotFound.
+func (str Store) QueryProjectByID(ctx context.Context, claims auth.Claims, projectId string) (Project, error) {
 	if err := uuid.Validate(projectId); err != nil {
 		return Project{}, err
 	}
 
 	queryParams := struct {
+		Visibility
 		ProjectID string `db:"project_id"`
 	}{
-		ProjectID: projectId,
+		Visibility: NewVisibility(claims),
+		ProjectID:  projectId,
+	}
+
+	const query = `
+	SELECT
+		p.project_id,
+		p.project_collaboration_type_id,
+		p.name,
+		p.description,
+		p.date_created,
+		p.created_by_user_id,
+		p.date_updated,
+		p.updated_by_user_id,
+		p.date_archived,
+		p.date_deleted
+	FROM
+		PROJECT AS p
+	WHERE
+		p.project_id = :project_id AND ` + ReadableCondition
+
+	var projectData Project
+	connection := str.cluster.Primary()
+	if err := database.NamedQueryStruct(ctx, str.logger, connection, query, queryParams, &projectData); err != nil {
+		if err == database.ErrorNotFound {
+			return Project{}, database.ErrorNotFound
+		}
+
+		return Project{}, fmt.Errorf("error during search of Project entity -> id={%q}: %w", projectId, err)
+	}
+
+	return projectData, nil
+}
+
+// CanReadProject reports whether Project entity with projectId identifier is readable by the claims subject.
+// If the project does not exist, the method returns database.ErrorNotFound.
+func (str Store) CanReadProject(ctx context.Context, claims auth.Claims, projectId string) (bool, error) {
+	if _, err := str.queryProjectByID(ctx, projectId); err != nil {
+		return false, err
+	}
+
+	if _, err := str.QueryProjectByID(ctx, claims, projectId); err != nil {
+		if err == database.ErrorNotFound {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	return true, nil
+}
+
+// CanWriteProject reports whether content of Project entity with projectId identifier can be changed by the claims
+// subject, which is allowed to owners and to members of groups which hold ReadWriteRole in the project.
+// Project which is not readable by the subject is not writable as well.
+// If the project does not exist, the method returns database.ErrorNotFound.
+func (str Store) CanWriteProject(ctx context.Context, claims auth.Claims, projectId string) (bool, error) {
+	if _, err := str.queryProjectByID(ctx, projectId); err != nil {
+		return false, err
+	}
+
+	queryParams := struct {
+		Visibility
+		ProjectID string `db:"project_id"`
+	}{
+		Visibility: NewVisibility(claims),
+		ProjectID:  projectId,
+	}
+
+	const query = `
+	SELECT
+		p.project_id
+	FROM
+		PROJECT AS p
+	WHERE
+		p.project_id = :project_id AND ` + ReadableCondition + ` AND ` + WritableCondition
+
+	var projectData struct {
+		ID string `db:"project_id"`
+	}
+	connection := str.cluster.Primary()
+	if err := database.NamedQueryStruct(ctx, str.logger, connection, query, queryParams, &projectData); err != nil {
+		if err == database.ErrorNotFound {
+			return false, nil
+		}
+
+		return false, fmt.Errorf("error during search of Project entity -> id={%q}: %w", projectId, err)
+	}
+
+	return true, nil
+}
+
+// queryProjectByID looking for Project entity with projectId identifier regardless of its visibility,
+// deleted project is reported with database.ErrorNotFound.
+// It is used by mutations which perform their own permission checks.
+func (str Store) queryProjectByID(ctx context.Context, projectId string) (Project, error) {
+	return str.queryProject(ctx, projectId, false)
+}
+
+// queryProject looking for Project entity with projectId identifier regardless of its visibility.
+// Deleted project is found only if includeDeleted is set.
+func (str Store) queryProject(ctx context.Context, projectId string, includeDeleted bool) (Project, error) {
+	if err := uuid.Validate(projectId); err != nil {
+		return Project{}, err
+	}
+
+	queryParams := struct {
+		ProjectID      string `db:"project_id"`
+		IncludeDeleted bool   `db:"include_deleted"`
+	}{
+		ProjectID:      projectId,
+		IncludeDeleted: includeDeleted,
 	}
 
 	const query = `
@@ -86,14 +503,17 @@ func (str Store) QueryProjectByID(ctx context.Context, projectId string) (Projec
 		p.date_created,
 		p.created_by_user_id,
 		p.date_updated,
-		p.updated_by_user_id
+		p.updated_by_user_id,
+		p.date_archived,
+		p.date_deleted
 	FROM
 		PROJECT AS p
 	WHERE
-		p.project_id = :project_id`
+		p.project_id = :project_id AND (:include_deleted OR p.date_deleted IS NULL)`
 
 	var projectData Project
-	if err := database.NamedQueryStruct(ctx, str.logger, str.connection, query, queryParams, &projectData); err != nil {
+	connection := str.cluster.Primary()
+	if err := database.NamedQueryStruct(ctx, str.logger, connection, query, queryParams, &projectData); err != nil {
 		if err == database.ErrorNotFound {
 			return Project{}, database.ErrorNotFound
 		}
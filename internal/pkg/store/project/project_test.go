@@ -1 +1,237 @@
 package project
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/SKorolchuk/dpio-workspace/internal/pkg/infra/auth"
+	"github.com/SKorolchuk/dpio-workspace/internal/pkg/infra/database"
+)
+
+const (
+	ownerID    = "92eded9e-979c-4e94-afc5-2333fcc920f6"
+	memberID   = "4f5a2c1e-6b3d-4e7f-8a9b-0c1d2e3f4a5b"
+	strangerID = "1a2b3c4d-5e6f-4a7b-8c9d-0e1f2a3b4c5d"
+	missingID  = "00000000-0000-4000-8000-000000000000"
+)
+
+var baseDate = time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// claimsOf returns claims of the user with userId identifier.
+func claimsOf(userId string) auth.Claims {
+	var claims auth.Claims
+	claims.Subject = userId
+
+	return claims
+}
+
+// mustCreateProject adds Project entity of the collaboration type owned by ownerID to the store.
+func mustCreateProject(t *testing.T, str *MemoryStore, name string, projectTypeId string, now time.Time) Project {
+	t.Helper()
+
+	projectData, err := str.CreateProject(context.Background(), claimsOf(ownerID),
+		NewProject{ProjectTypeID: projectTypeId, Name: name, Description: name}, now)
+	if err != nil {
+		t.Fatalf("CreateProject() error = %v", err)
+	}
+
+	return projectData
+}
+
+// mustJoinProject adds memberID to a new group with access to the project which holds the roles.
+func mustJoinProject(t *testing.T, str *MemoryStore, projectData Project, roleIds ...string) Group {
+	t.Helper()
+
+	ctx, claims := context.Background(), claimsOf(ownerID)
+	groupData, err := str.CreateGroup(ctx, claims, NewGroup{Name: "Group of " + projectData.Name}, baseDate)
+	if err != nil {
+		t.Fatalf("CreateGroup() error = %v", err)
+	}
+	if _, err := str.AddGroupUser(ctx, claims, groupData.ID, NewGroupUser{UserID: memberID}, baseDate); err != nil {
+		t.Fatalf("AddGroupUser() error = %v", err)
+	}
+	for _, roleId := range roleIds {
+		groupRole := NewGroupRole{RoleID: roleId}
+		if _, err := str.AssignGroupRole(ctx, claims, groupData.ID, groupRole, baseDate); err != nil {
+			t.Fatalf("AssignGroupRole() error = %v", err)
+		}
+	}
+	access := NewGroupAccess{GroupID: groupData.ID}
+	if _, err := str.GrantGroupAccess(ctx, claims, projectData.ID, access, baseDate); err != nil {
+		t.Fatalf("GrantGroupAccess() error = %v", err)
+	}
+
+	return groupData
+}
+
+func TestMemoryStoreQueryProjectByID(t *testing.T) {
+	str := NewMemoryStore()
+	public := mustCreateProject(t, str, "Public", PublicCollaborationType, baseDate)
+	team := mustCreateProject(t, str, "Team", TeamCollaborationType, baseDate)
+	private := mustCreateProject(t, str, "Private", PrivateCollaborationType, baseDate)
+	shared := mustCreateProject(t, str, "Shared", TeamCollaborationType, baseDate)
+	mustJoinProject(t, str, shared)
+
+	tests := []struct {
+		name      string
+		userId    string
+		projectId string
+		wantErr   error
+	}{
+		{name: "missing project", userId: ownerID, projectId: missingID, wantErr: database.ErrorNotFound},
+		{name: "public project of another user", userId: strangerID, projectId: public.ID},
+		{name: "team project of another user", userId: strangerID, projectId: team.ID,
+			wantErr: database.ErrorNotFound},
+		{name: "team project through group access", userId: memberID, projectId: shared.ID},
+		{name: "private project of another user", userId: memberID, projectId: private.ID,
+			wantErr: database.ErrorNotFound},
+		{name: "private project of its owner", userId: ownerID, projectId: private.ID},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			projectData, err := str.QueryProjectByID(context.Background(), claimsOf(test.userId), test.projectId)
+			if !errors.Is(err, test.wantErr) {
+				t.Fatalf("QueryProjectByID() error = %v, want %v", err, test.wantErr)
+			}
+			if err == nil && projectData.ID != test.projectId {
+				t.Errorf("QueryProjectByID() id = %q, want %q", projectData.ID, test.projectId)
+			}
+		})
+	}
+}
+
+func TestMemoryStoreUpdateProject(t *testing.T) {
+	str := NewMemoryStore()
+	public := mustCreateProject(t, str, "Public", PublicCollaborationType, baseDate)
+	private := mustCreateProject(t, str, "Private", PrivateCollaborationType, baseDate)
+	name := "Renamed"
+
+	tests := []struct {
+		name      string
+		userId    string
+		projectId string
+		wantErr   error
+	}{
+		{name: "invalid identifier", userId: ownerID, projectId: "1", wantErr: database.ErrorInvalidIdentifier},
+		{name: "missing project", userId: ownerID, projectId: missingID, wantErr: database.ErrorNotFound},
+		{name: "readable project of another user", userId: strangerID, projectId: public.ID,
+			wantErr: database.ErrorForbidden},
+		{name: "hidden project of another user", userId: strangerID, projectId: private.ID,
+			wantErr: database.ErrorForbidden},
+		{name: "owner", userId: ownerID, projectId: public.ID},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := str.UpdateProject(context.Background(), claimsOf(test.userId), test.projectId,
+				UpdateProject{Name: &name}, baseDate.Add(time.Hour))
+			if !errors.Is(err, test.wantErr) {
+				t.Errorf("UpdateProject() error = %v, want %v", err, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestMemoryStoreQueryProjects(t *testing.T) {
+	str := NewMemoryStore()
+	oldest := mustCreateProject(t, str, "Oldest", PublicCollaborationType, baseDate)
+	newest := mustCreateProject(t, str, "Newest", PublicCollaborationType, baseDate.Add(2*time.Hour))
+	middle := mustCreateProject(t, str, "Middle", PublicCollaborationType, baseDate.Add(time.Hour))
+	hidden := mustCreateProject(t, str, "Hidden", PrivateCollaborationType, baseDate.Add(3*time.Hour))
+
+	tests := []struct {
+		name    string
+		userId  string
+		skip    int32
+		top     int32
+		wantIDs []string
+		wantErr bool
+	}{
+		{name: "descending by update date", userId: ownerID, top: 10,
+			wantIDs: []string{hidden.ID, newest.ID, middle.ID, oldest.ID}},
+		{name: "hidden projects are skipped", userId: strangerID, top: 10,
+			wantIDs: []string{newest.ID, middle.ID, oldest.ID}},
+		{name: "page", userId: strangerID, skip: 1, top: 1, wantIDs: []string{middle.ID}},
+		{name: "page after the end", userId: strangerID, skip: 5, top: 1},
+		{name: "negative skip", userId: strangerID, skip: -1, top: 1, wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			projects, err := str.QueryProjects(context.Background(), claimsOf(test.userId), false, test.skip,
+				test.top)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("QueryProjects() error = %v, want error %t", err, test.wantErr)
+			}
+
+			if len(projects) != len(test.wantIDs) {
+				t.Fatalf("QueryProjects() returned %d projects, want %d", len(projects), len(test.wantIDs))
+			}
+			for i, projectData := range projects {
+				if projectData.ID != test.wantIDs[i] {
+					t.Errorf("QueryProjects()[%d] = %q, want %q", i, projectData.Name, test.wantIDs[i])
+				}
+			}
+		})
+	}
+}
+
+func TestMemoryStoreCanWriteProject(t *testing.T) {
+	str := NewMemoryStore()
+	ctx := context.Background()
+
+	readRole, err := str.CreateRole(ctx, auth.Claims{Roles: []string{auth.RoleAdmin}}, NewRole{Name: "Read"})
+	if err != nil {
+		t.Fatalf("CreateRole() error = %v", err)
+	}
+
+	public := mustCreateProject(t, str, "Public", PublicCollaborationType, baseDate)
+	readOnly := mustCreateProject(t, str, "Read only", TeamCollaborationType, baseDate)
+	mustJoinProject(t, str, readOnly, readRole.ID)
+	writable := mustCreateProject(t, str, "Writable", TeamCollaborationType, baseDate)
+	mustJoinProject(t, str, writable, ReadWriteRole)
+	private := mustCreateProject(t, str, "Private", PrivateCollaborationType, baseDate)
+	mustJoinProject(t, str, private, ReadWriteRole)
+
+	tests := []struct {
+		name      string
+		userId    string
+		projectId string
+		want      bool
+		wantErr   error
+	}{
+		{name: "missing project", userId: ownerID, projectId: missingID, wantErr: database.ErrorNotFound},
+		{name: "owner", userId: ownerID, projectId: public.ID, want: true},
+		{name: "reader of public project", userId: strangerID, projectId: public.ID},
+		{name: "member without write role", userId: memberID, projectId: readOnly.ID},
+		{name: "member with write role", userId: memberID, projectId: writable.ID, want: true},
+		{name: "member of hidden project", userId: memberID, projectId: private.ID},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			canWrite, err := str.CanWriteProject(ctx, claimsOf(test.userId), test.projectId)
+			if !errors.Is(err, test.wantErr) {
+				t.Fatalf("CanWriteProject() error = %v, want %v", err, test.wantErr)
+			}
+			if canWrite != test.want {
+				t.Errorf("CanWriteProject() = %t, want %t", canWrite, test.want)
+			}
+		})
+	}
+
+	t.Run("retired write role", func(t *testing.T) {
+		admin := auth.Claims{Roles: []string{auth.RoleAdmin}}
+		if err := str.RetireRole(ctx, admin, ReadWriteRole, baseDate.Add(time.Hour)); err != nil {
+			t.Fatalf("RetireRole() error = %v", err)
+		}
+
+		canWrite, err := str.CanWriteProject(ctx, claimsOf(memberID), writable.ID)
+		if err != nil || canWrite {
+			t.Errorf("CanWriteProject() = %t, %v, want false, nil", canWrite, err)
+		}
+	})
+}
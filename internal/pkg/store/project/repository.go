@@ -0,0 +1,115 @@
+package project
+
+import (
+	"context"
+	"time"
+
+	"github.com/SKorolchuk/dpio-workspace/internal/pkg/infra/auth"
+)
+
+// ProjectRepository declares storage-agnostic operations over Project entities.
+type ProjectRepository interface {
+	CreateProject(ctx context.Context, claims auth.Claims, project NewProject, now time.Time) (Project, error)
+	UpdateProject(ctx context.Context, claims auth.Claims, projectId string, project UpdateProject,
+		now time.Time) error
+	DeleteProject(ctx context.Context, claims auth.Claims, projectId string, cascade bool, now time.Time) error
+	ArchiveProject(ctx context.Context, claims auth.Claims, projectId string, now time.Time) error
+	RestoreProject(ctx context.Context, claims auth.Claims, projectId string, now time.Time) error
+	QueryProjects(ctx context.Context, claims auth.Claims, includeArchived bool, skip int32,
+		top int32) ([]Project, error)
+	QueryProjectByID(ctx context.Context, claims auth.Claims, projectId string) (Project, error)
+	CanReadProject(ctx context.Context, claims auth.Claims, projectId string) (bool, error)
+	CanWriteProject(ctx context.Context, claims auth.Claims, projectId string) (bool, error)
+}
+
+// OwnerRepository declares storage-agnostic operations over ProjectOwner entities.
+type OwnerRepository interface {
+	AddProjectOwner(ctx context.Context, claims auth.Claims, projectId string, owner NewProjectOwner,
+		now time.Time) (ProjectOwner, error)
+	RemoveProjectOwner(ctx context.Context, claims auth.Claims, projectId string, userId string) error
+	TransferProjectOwnership(ctx context.Context, claims auth.Claims, projectId string, transfer TransferOwnership,
+		now time.Time) (ProjectOwner, error)
+	QueryProjectOwners(ctx context.Context, claims auth.Claims, projectId string) ([]ProjectOwner, error)
+	IsProjectOwner(ctx context.Context, claims auth.Claims, projectId string) (bool, error)
+}
+
+// GroupRepository declares storage-agnostic operations over Group and GroupUser entities.
+type GroupRepository interface {
+	CreateGroup(ctx context.Context, claims auth.Claims, group NewGroup, now time.Time) (Group, error)
+	UpdateGroup(ctx context.Context, claims auth.Claims, groupId string, group UpdateGroup, now time.Time) error
+	DeleteGroup(ctx context.Context, claims auth.Claims, groupId string) error
+	AddGroupUser(ctx context.Context, claims auth.Claims, groupId string, user NewGroupUser,
+		now time.Time) (GroupUser, error)
+	RemoveGroupUser(ctx context.Context, claims auth.Claims, groupId string, userId string) error
+	QueryGroupUsers(ctx context.Context, claims auth.Claims, groupId string, skip int32,
+		top int32) ([]GroupUser, error)
+	QueryGroupsByUser(ctx context.Context, claims auth.Claims, userId string, skip int32, top int32) ([]Group, error)
+	QueryGroupByID(ctx context.Context, claims auth.Claims, groupId string) (Group, error)
+}
+
+// RoleRepository declares storage-agnostic operations over Role and GroupRole entities.
+type RoleRepository interface {
+	CreateRole(ctx context.Context, claims auth.Claims, role NewRole) (Role, error)
+	RetireRole(ctx context.Context, claims auth.Claims, roleId string, now time.Time) error
+	QueryRoles(ctx context.Context, skip int32, top int32) ([]Role, error)
+	QueryRoleByID(ctx context.Context, roleId string) (Role, error)
+	AssignGroupRole(ctx context.Context, claims auth.Claims, groupId string, groupRole NewGroupRole,
+		now time.Time) (GroupRole, error)
+	UnassignGroupRole(ctx context.Context, claims auth.Claims, groupId string, roleId string) error
+	QueryGroupRoles(ctx context.Context, groupId string) ([]Role, error)
+	QueryEffectiveRoles(ctx context.Context, claims auth.Claims, userId string, projectId string) ([]Role, error)
+}
+
+// AccessRepository declares storage-agnostic operations over GroupAccess entities.
+type AccessRepository interface {
+	GrantGroupAccess(ctx context.Context, claims auth.Claims, projectId string, access NewGroupAccess,
+		now time.Time) (GroupAccess, error)
+	RevokeGroupAccess(ctx context.Context, claims auth.Claims, projectId string, groupId string) error
+	QueryProjectGroups(ctx context.Context, claims auth.Claims, projectId string) ([]Group, error)
+	QueryProjectsForUser(ctx context.Context, claims auth.Claims, includeArchived bool, skip int32,
+		top int32) ([]Project, error)
+}
+
+// InvitationRepository declares storage-agnostic operations over Invitation entities.
+type InvitationRepository interface {
+	CreateInvitation(ctx context.Context, claims auth.Claims, projectId string, invitation NewInvitation,
+		now time.Time) (Invitation, error)
+	RevokeInvitation(ctx context.Context, claims auth.Claims, projectId string, invitationId string,
+		now time.Time) error
+	AcceptInvitation(ctx context.Context, claims auth.Claims, invitationId string, now time.Time) (GroupUser, error)
+	QueryPendingInvitations(ctx context.Context, claims auth.Claims, projectId string,
+		now time.Time) ([]Invitation, error)
+}
+
+// AccessRequestRepository declares storage-agnostic operations over AccessRequest entities.
+type AccessRequestRepository interface {
+	RequestAccess(ctx context.Context, claims auth.Claims, projectId string, request NewAccessRequest,
+		now time.Time) (AccessRequest, error)
+	ApproveAccessRequest(ctx context.Context, claims auth.Claims, requestId string, approval ApproveAccessRequest,
+		now time.Time) (GroupUser, error)
+	RejectAccessRequest(ctx context.Context, claims auth.Claims, requestId string, rejection RejectAccessRequest,
+		now time.Time) error
+	QueryPendingAccessRequests(ctx context.Context, claims auth.Claims, projectId string, skip int32,
+		top int32) ([]AccessRequest, error)
+	QueryAccessRequestsByUser(ctx context.Context, claims auth.Claims, skip int32, top int32) ([]AccessRequest, error)
+}
+
+// Repository declares all operations over Project, ProjectOwner, Group, Role, GroupAccess, Invitation and
+// AccessRequest entities.
+// Implementations should report missing entities with database.ErrorNotFound and
+// actions of non-authorized users with database.ErrorForbidden.
+type Repository interface {
+	ProjectRepository
+	OwnerRepository
+	GroupRepository
+	RoleRepository
+	AccessRepository
+	InvitationRepository
+	AccessRequestRepository
+}
+
+// Compile-time checks of Repository implementations.
+var (
+	_ Repository = Store{}
+	_ Repository = (*MemoryStore)(nil)
+)
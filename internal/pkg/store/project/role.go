@@ -1 +1,356 @@
 package project
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/SKorolchuk/dpio-workspace/internal/pkg/infra/auth"
+	"github.com/SKorolchuk/dpio-workspace/internal/pkg/infra/database"
+	"github.com/SKorolchuk/dpio-workspace/internal/pkg/infra/uuid"
+	"github.com/SKorolchuk/dpio-workspace/internal/pkg/infra/validation"
+)
+
+// ErrorRoleRetired is returned when retired Role entity is assigned or retired again.
+var ErrorRoleRetired = errors.New("role is retired")
+
+// CreateRole adds new Role entity to the role catalogue.
+// Only users with auth.RoleAdmin role can change the catalogue.
+// Can return validation or database errors.
+func (str Store) CreateRole(ctx context.Context, claims auth.Claims, role NewRole) (Role, error) {
+	if !claims.AuthorizeCheck(auth.RoleAdmin) {
+		return Role{}, database.ErrorForbidden
+	}
+
+	if err := validation.Check(ctx, role); err != nil {
+		return Role{}, fmt.Errorf("error during data validation of Role entity: %w", err)
+	}
+
+	roleData := Role{
+		ID:   uuid.Generate(),
+		Name: role.Name,
+	}
+
+	const query = `
+	INSERT INTO PROJECT_ROLE
+		(project_role_id, name)
+	VALUES
+		(:project_role_id, :name)`
+
+	if err := database.NamedExecContext(ctx, str.logger, str.cluster.Primary(), query, roleData); err != nil {
+		return Role{}, fmt.Errorf("error during create of new Role entity: %w", err)
+	}
+
+	return roleData, nil
+}
+
+// RetireRole marks Role entity as retired at now.
+// Only users with auth.RoleAdmin role can change the catalogue.
+// If error occurs, the method can return ErrorRoleRetired or database errors.
+func (str Store) RetireRole(ctx context.Context, claims auth.Claims, roleId string, now time.Time) error {
+	if err := uuid.Validate(roleId); err != nil {
+		return database.ErrorInvalidIdentifier
+	}
+
+	if !claims.AuthorizeCheck(auth.RoleAdmin) {
+		return database.ErrorForbidden
+	}
+
+	roleData, err := str.QueryRoleByID(ctx, roleId)
+	if err != nil {
+		return fmt.Errorf("error during search of Role entity -> id={%q}: %w", roleId, err)
+	}
+
+	if roleData.DateRetired != nil {
+		return fmt.Errorf("error during retirement of Role entity -> id={%q}: %w", roleId, ErrorRoleRetired)
+	}
+
+	roleData.DateRetired = &now
+
+	const query = `
+	UPDATE
+		PROJECT_ROLE
+	SET
+		"date_retired" = :date_retired
+	WHERE
+		project_role_id = :project_role_id`
+
+	if err := database.NamedExecContext(ctx, str.logger, str.cluster.Primary(), query, roleData); err != nil {
+		return fmt.Errorf("error during retirement of Role entity -> id={%q}: %w", roleId, err)
+	}
+
+	return nil
+}
+
+// QueryRoles looking for all Role entities including retired ones using skip/top mechanics with ascending order by
+// name field.
+func (str Store) QueryRoles(ctx context.Context, skip int32, top int32) ([]Role, error) {
+	queryParams := struct {
+		Skip int32 `db:"offset"`
+		Top  int32 `db:"top"`
+	}{
+		Skip: skip,
+		Top:  top,
+	}
+
+	const query = `
+	SELECT
+		r.project_role_id,
+		r.name,
+		r.date_retired
+	FROM
+		PROJECT_ROLE AS r
+	ORDER BY r.name
+	LIMIT :top OFFSET :offset`
+
+	var roles []Role
+	connection := str.cluster.Reader(ctx)
+	if err := database.NamedQuerySlice(ctx, str.logger, connection, query, queryParams, &roles); err != nil {
+		if err == database.ErrorNotFound {
+			return nil, database.ErrorNotFound
+		}
+
+		return nil, fmt.Errorf("error during search of Role entities: %w", err)
+	}
+
+	return roles, nil
+}
+
+// QueryRoleByID looking for Role entity with roleId identifier.
+func (str Store) QueryRoleByID(ctx context.Context, roleId string) (Role, error) {
+	if err := uuid.Validate(roleId); err != nil {
+		return Role{}, err
+	}
+
+	queryParams := struct {
+		RoleID string `db:"project_role_id"`
+	}{
+		RoleID: roleId,
+	}
+
+	const query = `
+	SELECT
+		r.project_role_id,
+		r.name,
+		r.date_retired
+	FROM
+		PROJECT_ROLE AS r
+	WHERE
+		r.project_role_id = :project_role_id`
+
+	var roleData Role
+	connection := str.cluster.Primary()
+	if err := database.NamedQueryStruct(ctx, str.logger, connection, query, queryParams, &roleData); err != nil {
+		if err == database.ErrorNotFound {
+			return Role{}, database.ErrorNotFound
+		}
+
+		return Role{}, fmt.Errorf("error during search of Role entity -> id={%q}: %w", roleId, err)
+	}
+
+	return roleData, nil
+}
+
+// AssignGroupRole assigns active Role entity to existing Group entity.
+// If assignment is successful, the method returns GroupRole entity.
+// If error occurs, the method can return ErrorRoleRetired, database.ErrorConflict for repeated assignment or
+// other database errors.
+func (str Store) AssignGroupRole(ctx context.Context, claims auth.Claims, groupId string, groupRole NewGroupRole,
+	now time.Time) (GroupRole, error) {
+	if err := uuid.Validate(groupId); err != nil {
+		return GroupRole{}, database.ErrorInvalidIdentifier
+	}
+
+	if err := validation.Check(ctx, groupRole); err != nil {
+		return GroupRole{}, fmt.Errorf("error during data validation of GroupRole entity: %w", err)
+	}
+
+	groupData, err := str.queryGroup(ctx, groupId)
+	if err != nil {
+		return GroupRole{}, fmt.Errorf("error during search of Group entity -> id={%q}: %w", groupId, err)
+	}
+
+	if groupData.CreatedByUser != claims.Subject {
+		return GroupRole{}, database.ErrorForbidden
+	}
+
+	roleData, err := str.QueryRoleByID(ctx, groupRole.RoleID)
+	if err != nil {
+		if err == database.ErrorNotFound {
+			err = database.ErrorInvalidReference
+		}
+		return GroupRole{}, fmt.Errorf("error during search of Role entity -> id={%q}: %w", groupRole.RoleID, err)
+	}
+
+	if roleData.DateRetired != nil {
+		return GroupRole{}, fmt.Errorf("error during assignment of Role entity -> id={%q}: %w", roleData.ID,
+			ErrorRoleRetired)
+	}
+
+	groupRoleData := GroupRole{
+		ID:            uuid.Generate(),
+		GroupID:       groupId,
+		RoleID:        roleData.ID,
+		DateCreated:   now,
+		CreatedByUser: claims.Subject,
+	}
+
+	const query = `
+	INSERT INTO PROJECT_GROUP_ROLE
+		(project_group_role_id, project_group_id, project_role_id, date_created, created_by_user_id)
+	VALUES
+		(:project_group_role_id, :project_group_id, :project_role_id, :date_created, :created_by_user_id)`
+
+	if err := database.NamedExecContext(ctx, str.logger, str.cluster.Primary(), query, groupRoleData); err != nil {
+		return GroupRole{}, fmt.Errorf("error during create of new GroupRole entity -> group_id={%q}: %w", groupId,
+			err)
+	}
+
+	return groupRoleData, nil
+}
+
+// UnassignGroupRole removes Role entity assignment from existing Group entity.
+// If the role is not assigned to the group, the method returns database.ErrorNotFound.
+func (str Store) UnassignGroupRole(ctx context.Context, claims auth.Claims, groupId string, roleId string) error {
+	if err := uuid.Validate(groupId); err != nil {
+		return database.ErrorInvalidIdentifier
+	}
+	if err := uuid.Validate(roleId); err != nil {
+		return database.ErrorInvalidIdentifier
+	}
+
+	groupData, err := str.queryGroup(ctx, groupId)
+	if err != nil {
+		return fmt.Errorf("error during search of Group entity -> id={%q}: %w", groupId, err)
+	}
+
+	if groupData.CreatedByUser != claims.Subject {
+		return database.ErrorForbidden
+	}
+
+	queryParams := struct {
+		GroupID string `db:"project_group_id"`
+		RoleID  string `db:"project_role_id"`
+	}{
+		GroupID: groupId,
+		RoleID:  roleId,
+	}
+
+	const query = `
+	DELETE FROM
+		PROJECT_GROUP_ROLE
+	WHERE
+		project_group_id = :project_group_id AND project_role_id = :project_role_id
+	RETURNING project_group_role_id`
+
+	var removed struct {
+		ID string `db:"project_group_role_id"`
+	}
+	connection := str.cluster.Primary()
+	if err := database.NamedQueryStruct(ctx, str.logger, connection, query, queryParams, &removed); err != nil {
+		if err == database.ErrorNotFound {
+			return database.ErrorNotFound
+		}
+
+		return fmt.Errorf("error during delete of GroupRole entity -> group_id={%q}, role_id={%q}: %w", groupId,
+			roleId, err)
+	}
+
+	return nil
+}
+
+// QueryGroupRoles looking for Role entities assigned to Group entity with ascending order by name field.
+func (str Store) QueryGroupRoles(ctx context.Context, groupId string) ([]Role, error) {
+	if err := uuid.Validate(groupId); err != nil {
+		return nil, err
+	}
+
+	queryParams := struct {
+		GroupID string `db:"project_group_id"`
+	}{
+		GroupID: groupId,
+	}
+
+	const query = `
+	SELECT
+		r.project_role_id,
+		r.name,
+		r.date_retired
+	FROM
+		PROJECT_ROLE AS r
+		JOIN PROJECT_GROUP_ROLE AS gr ON gr.project_role_id = r.project_role_id
+	WHERE
+		gr.project_group_id = :project_group_id
+	ORDER BY r.name`
+
+	var roles []Role
+	connection := str.cluster.Reader(ctx)
+	if err := database.NamedQuerySlice(ctx, str.logger, connection, query, queryParams, &roles); err != nil {
+		if err == database.ErrorNotFound {
+			return nil, database.ErrorNotFound
+		}
+
+		return nil, fmt.Errorf("error during search of Role entities -> group_id={%q}: %w", groupId, err)
+	}
+
+	return roles, nil
+}
+
+// QueryEffectiveRoles looking for active Role entities which the user holds in Project entity.
+// A role is held when it is assigned to a group which the user is a member of and which has access to the project.
+// Owners of the project hold every active role.
+// Roles in a project which is not readable by the claims subject are not returned.
+func (str Store) QueryEffectiveRoles(ctx context.Context, claims auth.Claims, userId string,
+	projectId string) ([]Role, error) {
+	if err := uuid.Validate(projectId); err != nil {
+		return nil, err
+	}
+
+	queryParams := struct {
+		Visibility
+		UserID    string `db:"user_id"`
+		ProjectID string `db:"project_id"`
+	}{
+		Visibility: NewVisibility(claims),
+		UserID:     userId,
+		ProjectID:  projectId,
+	}
+
+	const query = `
+	SELECT
+		r.project_role_id,
+		r.name,
+		r.date_retired
+	FROM
+		PROJECT_ROLE AS r
+	WHERE
+		r.date_retired IS NULL
+		AND EXISTS(SELECT 1 FROM PROJECT AS p WHERE p.project_id = :project_id AND ` + ReadableCondition + `)
+		AND (EXISTS(SELECT 1
+				FROM
+					PROJECT_OWNER AS o
+				WHERE
+					o.project_id = :project_id AND o.user_id = :user_id)
+			OR EXISTS(SELECT 1
+				FROM
+					PROJECT_GROUP_USER AS gu
+					JOIN PROJECT_GROUP_ACCESS AS ga ON ga.project_group_id = gu.project_group_id
+					JOIN PROJECT_GROUP_ROLE AS gr ON gr.project_group_id = gu.project_group_id
+				WHERE
+					gu.user_id = :user_id AND ga.project_id = :project_id AND gr.project_role_id = r.project_role_id))
+	ORDER BY r.name`
+
+	var roles []Role
+	connection := str.cluster.Reader(ctx)
+	if err := database.NamedQuerySlice(ctx, str.logger, connection, query, queryParams, &roles); err != nil {
+		if err == database.ErrorNotFound {
+			return nil, database.ErrorNotFound
+		}
+
+		return nil, fmt.Errorf("error during search of effective Role entities -> user_id={%q}, project_id={%q}: %w",
+			userId, projectId, err)
+	}
+
+	return roles, nil
+}
@@ -0,0 +1,144 @@
+package project
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/SKorolchuk/dpio-workspace/internal/pkg/infra/auth"
+	"github.com/SKorolchuk/dpio-workspace/internal/pkg/infra/database"
+)
+
+// adminClaimsOf returns claims of the user with auth.RoleAdmin role.
+func adminClaimsOf(userId string) auth.Claims {
+	claims := claimsOf(userId)
+	claims.Roles = []string{auth.RoleAdmin}
+
+	return claims
+}
+
+func TestMemoryStoreRoleCatalogue(t *testing.T) {
+	str := NewMemoryStore()
+	ctx := context.Background()
+
+	if _, err := str.CreateRole(ctx, claimsOf(ownerID), NewRole{Name: "Reviewer"}); !errors.Is(err,
+		database.ErrorForbidden) {
+		t.Errorf("CreateRole() by regular user error = %v, want %v", err, database.ErrorForbidden)
+	}
+
+	roleData, err := str.CreateRole(ctx, adminClaimsOf(ownerID), NewRole{Name: "Reviewer"})
+	if err != nil {
+		t.Fatalf("CreateRole() error = %v", err)
+	}
+	if _, err := str.CreateRole(ctx, adminClaimsOf(ownerID), NewRole{Name: "Reviewer"}); !errors.Is(err,
+		database.ErrorConflict) {
+		t.Errorf("CreateRole() of duplicated name error = %v, want %v", err, database.ErrorConflict)
+	}
+
+	if err := str.RetireRole(ctx, claimsOf(ownerID), roleData.ID, baseDate); !errors.Is(err,
+		database.ErrorForbidden) {
+		t.Errorf("RetireRole() by regular user error = %v, want %v", err, database.ErrorForbidden)
+	}
+	if err := str.RetireRole(ctx, adminClaimsOf(ownerID), roleData.ID, baseDate); err != nil {
+		t.Fatalf("RetireRole() error = %v", err)
+	}
+	if err := str.RetireRole(ctx, adminClaimsOf(ownerID), roleData.ID, baseDate); !errors.Is(err,
+		ErrorRoleRetired) {
+		t.Errorf("RetireRole() of retired role error = %v, want %v", err, ErrorRoleRetired)
+	}
+
+	roles, err := str.QueryRoles(ctx, 0, 10)
+	if err != nil {
+		t.Fatalf("QueryRoles() error = %v", err)
+	}
+	if len(roles) != 2 {
+		t.Errorf("QueryRoles() returned %d roles, want predefined and retired ones", len(roles))
+	}
+}
+
+func TestMemoryStoreAssignGroupRole(t *testing.T) {
+	str := NewMemoryStore()
+	ctx, claims := context.Background(), claimsOf(ownerID)
+
+	groupData, err := str.CreateGroup(ctx, claims, NewGroup{Name: "Writers"}, baseDate)
+	if err != nil {
+		t.Fatalf("CreateGroup() error = %v", err)
+	}
+	retired, err := str.CreateRole(ctx, adminClaimsOf(ownerID), NewRole{Name: "Retired"})
+	if err != nil {
+		t.Fatalf("CreateRole() error = %v", err)
+	}
+	if err := str.RetireRole(ctx, adminClaimsOf(ownerID), retired.ID, baseDate); err != nil {
+		t.Fatalf("RetireRole() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		userId  string
+		roleId  string
+		wantErr error
+	}{
+		{name: "group of another user", userId: strangerID, roleId: ReadWriteRole, wantErr: database.ErrorForbidden},
+		{name: "missing role", userId: ownerID, roleId: missingID, wantErr: database.ErrorInvalidReference},
+		{name: "retired role", userId: ownerID, roleId: retired.ID, wantErr: ErrorRoleRetired},
+		{name: "active role", userId: ownerID, roleId: ReadWriteRole},
+		{name: "repeated assignment", userId: ownerID, roleId: ReadWriteRole, wantErr: database.ErrorConflict},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := str.AssignGroupRole(ctx, claimsOf(test.userId), groupData.ID, NewGroupRole{RoleID: test.roleId},
+				baseDate)
+			if !errors.Is(err, test.wantErr) {
+				t.Errorf("AssignGroupRole() error = %v, want %v", err, test.wantErr)
+			}
+		})
+	}
+
+	if err := str.UnassignGroupRole(ctx, claimsOf(strangerID), groupData.ID, ReadWriteRole); !errors.Is(err,
+		database.ErrorForbidden) {
+		t.Errorf("UnassignGroupRole() by another user error = %v, want %v", err, database.ErrorForbidden)
+	}
+	if err := str.UnassignGroupRole(ctx, claims, groupData.ID, ReadWriteRole); err != nil {
+		t.Fatalf("UnassignGroupRole() error = %v", err)
+	}
+	if err := str.UnassignGroupRole(ctx, claims, groupData.ID, ReadWriteRole); !errors.Is(err,
+		database.ErrorNotFound) {
+		t.Errorf("UnassignGroupRole() of unassigned role error = %v, want %v", err, database.ErrorNotFound)
+	}
+}
+
+func TestMemoryStoreQueryEffectiveRoles(t *testing.T) {
+	str := NewMemoryStore()
+	shared := mustCreateProject(t, str, "Shared", TeamCollaborationType, baseDate)
+	private := mustCreateProject(t, str, "Private", PrivateCollaborationType, baseDate)
+	mustJoinProject(t, str, shared, ReadWriteRole)
+
+	tests := []struct {
+		name      string
+		userId    string
+		subjectId string
+		projectId string
+		wantRoles int
+	}{
+		{name: "member through group access", userId: memberID, subjectId: memberID, projectId: shared.ID,
+			wantRoles: 1},
+		{name: "owner holds every active role", userId: ownerID, subjectId: ownerID, projectId: private.ID,
+			wantRoles: 1},
+		{name: "member without access", userId: memberID, subjectId: ownerID, projectId: private.ID},
+		{name: "project hidden from the subject", userId: ownerID, subjectId: strangerID, projectId: private.ID},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			roles, err := str.QueryEffectiveRoles(context.Background(), claimsOf(test.subjectId), test.userId,
+				test.projectId)
+			if err != nil {
+				t.Fatalf("QueryEffectiveRoles() error = %v", err)
+			}
+			if len(roles) != test.wantRoles {
+				t.Errorf("QueryEffectiveRoles() returned %d roles, want %d", len(roles), test.wantRoles)
+			}
+		})
+	}
+}
@@ -1,22 +1,32 @@
 package project
 
 import (
-	"github.com/jmoiron/sqlx"
+	"github.com/SKorolchuk/dpio-workspace/internal/pkg/infra/database"
+
 	"go.uber.org/zap"
 )
 
-// Store represents a point of access to CollaborationType, Project, Role, Group, GroupRole, GroupUser and
-// GroupAccess entities.
+// Store represents a point of access to CollaborationType, Project, ProjectOwner, Role, Group, GroupRole, GroupUser,
+// GroupAccess, Invitation and AccessRequest entities.
+// Listing queries are served by a read replica of the cluster when a healthy one is available.
 type Store struct {
-	logger     *zap.SugaredLogger
-	connection *sqlx.DB
+	logger  *zap.SugaredLogger
+	cluster *database.Cluster
+	hooks   []AccessRequestHook
 }
 
-// NewStore creates an instance of Store for access to CollaborationType, Project, Role, Group, GroupRole, GroupUser and
-// GroupAccess entities.
-func NewStore(logger *zap.SugaredLogger, connection *sqlx.DB) Store {
+// NewStore creates an instance of Store for access to CollaborationType, Project, ProjectOwner, Role, Group, GroupRole,
+// GroupUser, GroupAccess, Invitation and AccessRequest entities.
+func NewStore(logger *zap.SugaredLogger, cluster *database.Cluster) Store {
 	return Store{
-		logger:     logger,
-		connection: connection,
+		logger:  logger,
+		cluster: cluster,
 	}
 }
+
+// WithAccessRequestHooks returns a copy of Store which calls hooks after every change of AccessRequest entities.
+func (str Store) WithAccessRequestHooks(hooks ...AccessRequestHook) Store {
+	str.hooks = append(append([]AccessRequestHook{}, str.hooks...), hooks...)
+
+	return str
+}
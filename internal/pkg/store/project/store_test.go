@@ -0,0 +1,498 @@
+package project_test
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/SKorolchuk/dpio-workspace/internal/pkg/infra/auth"
+	"github.com/SKorolchuk/dpio-workspace/internal/pkg/infra/database"
+	store "github.com/SKorolchuk/dpio-workspace/internal/pkg/store/database"
+	"github.com/SKorolchuk/dpio-workspace/internal/pkg/store/project"
+	"github.com/SKorolchuk/dpio-workspace/internal/pkg/store/workspace"
+
+	"github.com/jmoiron/sqlx"
+	"go.uber.org/zap"
+)
+
+const (
+	sqlOwnerID    = "92eded9e-979c-4e94-afc5-2333fcc920f6"
+	sqlMemberID   = "4f5a2c1e-6b3d-4e7f-8a9b-0c1d2e3f4a5b"
+	sqlStrangerID = "1a2b3c4d-5e6f-4a7b-8c9d-0e1f2a3b4c5d"
+)
+
+var sqlBaseDate = time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// sqlClaimsOf returns claims of the user with userId identifier.
+func sqlClaimsOf(userId string) auth.Claims {
+	var claims auth.Claims
+	claims.Subject = userId
+
+	return claims
+}
+
+// openTestStore creates Store over migrated SQLite database with collaboration types, ReadWriteRole and stems.
+// Stores of Workspace entities can be created over the returned cluster.
+func openTestStore(t *testing.T) (project.Store, *database.Cluster) {
+	t.Helper()
+
+	connection, err := database.Open(database.DbConfig{Driver: database.DriverSQLite,
+		DatabaseName: filepath.Join(t.TempDir(), "project.db")})
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	t.Cleanup(func() { _ = database.Close(connection) })
+
+	ctx := context.Background()
+	if err := store.Migrate(ctx, connection); err != nil {
+		t.Fatalf("Migrate() error = %v", err)
+	}
+
+	fixture := store.Fixture{
+		CollaborationTypes: []project.CollaborationType{
+			{ID: project.PublicCollaborationType, Name: "Public"},
+			{ID: project.TeamCollaborationType, Name: "Team"},
+			{ID: project.PrivateCollaborationType, Name: "Private"},
+		},
+		Roles: []project.Role{{ID: project.ReadWriteRole, Name: "ProjectReadWriteAll"}},
+		Stems: []workspace.Stem{{ID: workspace.StickerWorkspaceType, Name: "Sticker Pane"}},
+	}
+	if err := store.SeedFixture(ctx, connection, fixture); err != nil {
+		t.Fatalf("SeedFixture() error = %v", err)
+	}
+
+	cluster := database.NewCluster(connection)
+
+	return project.NewStore(zap.NewNop().Sugar(), cluster), cluster
+}
+
+// deletedWorkspaces returns names of deleted Workspace entities of the project in order of names.
+func deletedWorkspaces(t *testing.T, connection *sqlx.DB, projectId string) []string {
+	t.Helper()
+
+	const query = `SELECT name FROM WORKSPACE WHERE project_id = $1 AND date_deleted IS NOT NULL ORDER BY name`
+
+	var names []string
+	if err := connection.Select(&names, query, projectId); err != nil {
+		t.Fatalf("Select() error = %v", err)
+	}
+
+	return names
+}
+
+func TestStoreDeleteProjectCascade(t *testing.T) {
+	str, cluster := openTestStore(t)
+	workspaces := workspace.NewStore(zap.NewNop().Sugar(), cluster)
+	ctx, claims := context.Background(), sqlClaimsOf(sqlOwnerID)
+
+	projectData, err := str.CreateProject(ctx, claims,
+		project.NewProject{ProjectTypeID: project.PublicCollaborationType, Name: "Cascade", Description: "Cascade"},
+		sqlBaseDate)
+	if err != nil {
+		t.Fatalf("CreateProject() error = %v", err)
+	}
+
+	var wsIds []string
+	for _, name := range []string{"Live", "Removed before"} {
+		wsData, err := workspaces.CreateWorkspace(ctx, claims, workspace.NewWorkspace{ProjectID: projectData.ID,
+			StemID: workspace.StickerWorkspaceType, Name: name, AssetAmountLimit: 10, MaxX: 100, MaxY: 100,
+			MaxZ: 1}, sqlBaseDate)
+		if err != nil {
+			t.Fatalf("CreateWorkspace() error = %v", err)
+		}
+		wsIds = append(wsIds, wsData.ID)
+	}
+	if err := workspaces.DeleteWorkspace(ctx, claims, wsIds[1], sqlBaseDate.Add(time.Hour)); err != nil {
+		t.Fatalf("DeleteWorkspace() error = %v", err)
+	}
+
+	err = str.DeleteProject(ctx, claims, projectData.ID, false, sqlBaseDate.Add(2*time.Hour))
+	if !errors.Is(err, project.ErrorProjectHasWorkspaces) {
+		t.Fatalf("DeleteProject() without cascade error = %v, want %v", err, project.ErrorProjectHasWorkspaces)
+	}
+
+	if err := str.DeleteProject(ctx, claims, projectData.ID, true, sqlBaseDate.Add(2*time.Hour)); err != nil {
+		t.Fatalf("DeleteProject() error = %v", err)
+	}
+	if names := deletedWorkspaces(t, cluster.Primary(), projectData.ID); len(names) != 2 {
+		t.Errorf("deleted workspaces after DeleteProject() = %v, want both", names)
+	}
+
+	if err := str.RestoreProject(ctx, claims, projectData.ID, sqlBaseDate.Add(3*time.Hour)); err != nil {
+		t.Fatalf("RestoreProject() error = %v", err)
+	}
+	names := deletedWorkspaces(t, cluster.Primary(), projectData.ID)
+	if len(names) != 1 || names[0] != "Removed before" {
+		t.Errorf("deleted workspaces after RestoreProject() = %v, want only the one removed before", names)
+	}
+}
+
+func TestStoreGroupVisibility(t *testing.T) {
+	str, _ := openTestStore(t)
+	ctx := context.Background()
+
+	groupData, err := str.CreateGroup(ctx, sqlClaimsOf(sqlOwnerID), project.NewGroup{Name: "Shared"}, sqlBaseDate)
+	if err != nil {
+		t.Fatalf("CreateGroup() error = %v", err)
+	}
+	if _, err := str.AddGroupUser(ctx, sqlClaimsOf(sqlOwnerID), groupData.ID,
+		project.NewGroupUser{UserID: sqlMemberID}, sqlBaseDate); err != nil {
+		t.Fatalf("AddGroupUser() error = %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		userId     string
+		wantErr    error
+		wantGroups int
+	}{
+		{name: "creator", userId: sqlOwnerID, wantGroups: 1},
+		{name: "member", userId: sqlMemberID, wantGroups: 1},
+		{name: "stranger", userId: sqlStrangerID, wantErr: database.ErrorNotFound},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			claims := sqlClaimsOf(test.userId)
+
+			if _, err := str.QueryGroupByID(ctx, claims, groupData.ID); !errors.Is(err, test.wantErr) {
+				t.Errorf("QueryGroupByID() error = %v, want %v", err, test.wantErr)
+			}
+			if _, err := str.QueryGroupUsers(ctx, claims, groupData.ID, 0, 10); !errors.Is(err, test.wantErr) {
+				t.Errorf("QueryGroupUsers() error = %v, want %v", err, test.wantErr)
+			}
+
+			groups, err := str.QueryGroupsByUser(ctx, claims, sqlMemberID, 0, 10)
+			if err != nil && !errors.Is(err, database.ErrorNotFound) {
+				t.Fatalf("QueryGroupsByUser() error = %v", err)
+			}
+			if len(groups) != test.wantGroups {
+				t.Errorf("QueryGroupsByUser() returned %d groups, want %d", len(groups), test.wantGroups)
+			}
+		})
+	}
+}
+
+func TestStoreQueryEffectiveRoles(t *testing.T) {
+	str, _ := openTestStore(t)
+	ctx, claims := context.Background(), sqlClaimsOf(sqlOwnerID)
+
+	projectData, err := str.CreateProject(ctx, claims,
+		project.NewProject{ProjectTypeID: project.TeamCollaborationType, Name: "Team", Description: "Team"},
+		sqlBaseDate)
+	if err != nil {
+		t.Fatalf("CreateProject() error = %v", err)
+	}
+	groupData, err := str.CreateGroup(ctx, claims, project.NewGroup{Name: "Writers"}, sqlBaseDate)
+	if err != nil {
+		t.Fatalf("CreateGroup() error = %v", err)
+	}
+	if _, err := str.AddGroupUser(ctx, claims, groupData.ID, project.NewGroupUser{UserID: sqlMemberID},
+		sqlBaseDate); err != nil {
+		t.Fatalf("AddGroupUser() error = %v", err)
+	}
+	if _, err := str.AssignGroupRole(ctx, claims, groupData.ID, project.NewGroupRole{RoleID: project.ReadWriteRole},
+		sqlBaseDate); err != nil {
+		t.Fatalf("AssignGroupRole() error = %v", err)
+	}
+
+	effectiveRoles := func(subjectId string, userId string) int {
+		roles, err := str.QueryEffectiveRoles(ctx, sqlClaimsOf(subjectId), userId, projectData.ID)
+		if err != nil && !errors.Is(err, database.ErrorNotFound) {
+			t.Fatalf("QueryEffectiveRoles() error = %v", err)
+		}
+		return len(roles)
+	}
+
+	if count := effectiveRoles(sqlMemberID, sqlMemberID); count != 0 {
+		t.Errorf("roles of member before group access = %d, want 0", count)
+	}
+
+	if _, err := str.GrantGroupAccess(ctx, claims, projectData.ID, project.NewGroupAccess{GroupID: groupData.ID},
+		sqlBaseDate); err != nil {
+		t.Fatalf("GrantGroupAccess() error = %v", err)
+	}
+
+	if count := effectiveRoles(sqlMemberID, sqlMemberID); count != 1 {
+		t.Errorf("roles of member with group access = %d, want 1", count)
+	}
+	if count := effectiveRoles(sqlOwnerID, sqlOwnerID); count != 1 {
+		t.Errorf("roles of owner = %d, want every active role", count)
+	}
+	if count := effectiveRoles(sqlStrangerID, sqlMemberID); count != 0 {
+		t.Errorf("roles in project hidden from the subject = %d, want 0", count)
+	}
+}
+
+func TestStoreGrantGroupAccess(t *testing.T) {
+	str, _ := openTestStore(t)
+	ctx, claims := context.Background(), sqlClaimsOf(sqlOwnerID)
+
+	projectData, err := str.CreateProject(ctx, claims,
+		project.NewProject{ProjectTypeID: project.TeamCollaborationType, Name: "Team", Description: "Team"},
+		sqlBaseDate)
+	if err != nil {
+		t.Fatalf("CreateProject() error = %v", err)
+	}
+	foreignGroup, err := str.CreateGroup(ctx, sqlClaimsOf(sqlStrangerID), project.NewGroup{Name: "Foreign"},
+		sqlBaseDate)
+	if err != nil {
+		t.Fatalf("CreateGroup() error = %v", err)
+	}
+
+	_, err = str.GrantGroupAccess(ctx, claims, projectData.ID, project.NewGroupAccess{GroupID: foreignGroup.ID},
+		sqlBaseDate)
+	if !errors.Is(err, database.ErrorForbidden) {
+		t.Errorf("GrantGroupAccess() of another user's group error = %v, want %v", err, database.ErrorForbidden)
+	}
+
+	_, err = str.GrantGroupAccess(ctx, claims, projectData.ID,
+		project.NewGroupAccess{GroupID: "00000000-0000-4000-8000-000000000000"}, sqlBaseDate)
+	if !errors.Is(err, database.ErrorInvalidReference) {
+		t.Errorf("GrantGroupAccess() of unknown group error = %v, want %v", err, database.ErrorInvalidReference)
+	}
+}
+
+func TestStoreCreateAsset(t *testing.T) {
+	str, cluster := openTestStore(t)
+	workspaces := workspace.NewStore(zap.NewNop().Sugar(), cluster)
+	ctx, claims := context.Background(), sqlClaimsOf(sqlOwnerID)
+
+	projectData, err := str.CreateProject(ctx, claims,
+		project.NewProject{ProjectTypeID: project.TeamCollaborationType, Name: "Team", Description: "Team"},
+		sqlBaseDate)
+	if err != nil {
+		t.Fatalf("CreateProject() error = %v", err)
+	}
+	wsData, err := workspaces.CreateWorkspace(ctx, claims, workspace.NewWorkspace{ProjectID: projectData.ID,
+		StemID: workspace.StickerWorkspaceType, Name: "Board", AssetAmountLimit: 1, MaxX: 100, MaxY: 100, MaxZ: 10},
+		sqlBaseDate)
+	if err != nil {
+		t.Fatalf("CreateWorkspace() error = %v", err)
+	}
+
+	newAsset := func(x int32) workspace.NewAsset {
+		return workspace.NewAsset{WorkspaceID: wsData.ID, AssetRefID: "sticker", X: x, Y: 10, Z: 1, Scale: 1,
+			Height: 20, Width: 20, Length: 1}
+	}
+
+	tests := []struct {
+		name    string
+		userId  string
+		asset   workspace.NewAsset
+		wantErr error
+	}{
+		{name: "hidden project", userId: sqlStrangerID, asset: newAsset(10), wantErr: database.ErrorNotFound},
+		{name: "out of bounds", userId: sqlOwnerID, asset: newAsset(90), wantErr: workspace.ErrorAssetOutOfBounds},
+		{name: "owner", userId: sqlOwnerID, asset: newAsset(10)},
+		{name: "asset amount limit", userId: sqlOwnerID, asset: newAsset(10),
+			wantErr: workspace.ErrorAssetLimitExceeded},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := workspaces.CreateAsset(ctx, sqlClaimsOf(test.userId), test.asset, sqlBaseDate)
+			if !errors.Is(err, test.wantErr) {
+				t.Errorf("CreateAsset() error = %v, want %v", err, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestStoreInvitationGroupManager(t *testing.T) {
+	str, _ := openTestStore(t)
+	ctx, claims := context.Background(), sqlClaimsOf(sqlOwnerID)
+
+	projectData, err := str.CreateProject(ctx, claims,
+		project.NewProject{ProjectTypeID: project.TeamCollaborationType, Name: "Team", Description: "Team"},
+		sqlBaseDate)
+	if err != nil {
+		t.Fatalf("CreateProject() error = %v", err)
+	}
+	if _, err := str.AddProjectOwner(ctx, claims, projectData.ID, project.NewProjectOwner{UserID: sqlMemberID},
+		sqlBaseDate); err != nil {
+		t.Fatalf("AddProjectOwner() error = %v", err)
+	}
+
+	grantedGroup := func(userId string, name string) project.Group {
+		groupData, err := str.CreateGroup(ctx, sqlClaimsOf(userId), project.NewGroup{Name: name}, sqlBaseDate)
+		if err != nil {
+			t.Fatalf("CreateGroup() error = %v", err)
+		}
+		if _, err := str.GrantGroupAccess(ctx, sqlClaimsOf(userId), projectData.ID,
+			project.NewGroupAccess{GroupID: groupData.ID}, sqlBaseDate); err != nil {
+			t.Fatalf("GrantGroupAccess() error = %v", err)
+		}
+
+		return groupData
+	}
+	ownGroup, coOwnerGroup := grantedGroup(sqlOwnerID, "Own"), grantedGroup(sqlMemberID, "Co-owner")
+
+	_, err = str.CreateInvitation(ctx, claims, projectData.ID,
+		project.NewInvitation{GroupID: coOwnerGroup.ID, UserID: sqlStrangerID}, sqlBaseDate)
+	if !errors.Is(err, database.ErrorForbidden) {
+		t.Errorf("CreateInvitation() into group of another owner error = %v, want %v", err, database.ErrorForbidden)
+	}
+
+	invitation, err := str.CreateInvitation(ctx, claims, projectData.ID,
+		project.NewInvitation{GroupID: ownGroup.ID, UserID: sqlStrangerID}, sqlBaseDate)
+	if err != nil {
+		t.Fatalf("CreateInvitation() error = %v", err)
+	}
+	if err := str.RevokeGroupAccess(ctx, claims, projectData.ID, ownGroup.ID); err != nil {
+		t.Fatalf("RevokeGroupAccess() error = %v", err)
+	}
+
+	_, err = str.AcceptInvitation(ctx, sqlClaimsOf(sqlStrangerID), invitation.ID, sqlBaseDate.Add(time.Hour))
+	if !errors.Is(err, database.ErrorInvalidReference) {
+		t.Errorf("AcceptInvitation() after revoked group access error = %v, want %v", err,
+			database.ErrorInvalidReference)
+	}
+}
+
+func TestStoreApproveAccessRequest(t *testing.T) {
+	str, _ := openTestStore(t)
+	ctx, claims := context.Background(), sqlClaimsOf(sqlOwnerID)
+
+	projectData, err := str.CreateProject(ctx, claims,
+		project.NewProject{ProjectTypeID: project.TeamCollaborationType, Name: "Team", Description: "Team"},
+		sqlBaseDate)
+	if err != nil {
+		t.Fatalf("CreateProject() error = %v", err)
+	}
+	if _, err := str.AddProjectOwner(ctx, claims, projectData.ID, project.NewProjectOwner{UserID: sqlMemberID},
+		sqlBaseDate); err != nil {
+		t.Fatalf("AddProjectOwner() error = %v", err)
+	}
+	coOwnerGroup, err := str.CreateGroup(ctx, sqlClaimsOf(sqlMemberID), project.NewGroup{Name: "Co-owner"},
+		sqlBaseDate)
+	if err != nil {
+		t.Fatalf("CreateGroup() error = %v", err)
+	}
+	if _, err := str.GrantGroupAccess(ctx, sqlClaimsOf(sqlMemberID), projectData.ID,
+		project.NewGroupAccess{GroupID: coOwnerGroup.ID}, sqlBaseDate); err != nil {
+		t.Fatalf("GrantGroupAccess() error = %v", err)
+	}
+
+	requestData, err := str.RequestAccess(ctx, sqlClaimsOf(sqlStrangerID), projectData.ID,
+		project.NewAccessRequest{}, sqlBaseDate)
+	if err != nil {
+		t.Fatalf("RequestAccess() error = %v", err)
+	}
+
+	approval := project.ApproveAccessRequest{GroupID: coOwnerGroup.ID}
+	_, err = str.ApproveAccessRequest(ctx, claims, requestData.ID, approval, sqlBaseDate)
+	if !errors.Is(err, database.ErrorForbidden) {
+		t.Errorf("ApproveAccessRequest() into group of another owner error = %v, want %v", err,
+			database.ErrorForbidden)
+	}
+
+	if _, err := str.ApproveAccessRequest(ctx, sqlClaimsOf(sqlMemberID), requestData.ID, approval,
+		sqlBaseDate); err != nil {
+		t.Fatalf("ApproveAccessRequest() error = %v", err)
+	}
+	_, err = str.ApproveAccessRequest(ctx, sqlClaimsOf(sqlMemberID), requestData.ID, approval, sqlBaseDate)
+	if !errors.Is(err, project.ErrorAccessRequestClosed) {
+		t.Errorf("ApproveAccessRequest() of decided request error = %v, want %v", err,
+			project.ErrorAccessRequestClosed)
+	}
+}
+
+func TestStoreRemoveProjectOwner(t *testing.T) {
+	str, _ := openTestStore(t)
+	ctx, claims := context.Background(), sqlClaimsOf(sqlOwnerID)
+
+	projectData, err := str.CreateProject(ctx, claims,
+		project.NewProject{ProjectTypeID: project.TeamCollaborationType, Name: "Team", Description: "Team"},
+		sqlBaseDate)
+	if err != nil {
+		t.Fatalf("CreateProject() error = %v", err)
+	}
+
+	remove := func(subjectId string, userId string) error {
+		return str.RemoveProjectOwner(ctx, sqlClaimsOf(subjectId), projectData.ID, userId)
+	}
+
+	if err := remove(sqlOwnerID, sqlOwnerID); !errors.Is(err, project.ErrorLastOwner) {
+		t.Errorf("RemoveProjectOwner() of the last owner error = %v, want %v", err, project.ErrorLastOwner)
+	}
+	if _, err := str.AddProjectOwner(ctx, claims, projectData.ID, project.NewProjectOwner{UserID: sqlMemberID},
+		sqlBaseDate); err != nil {
+		t.Fatalf("AddProjectOwner() error = %v", err)
+	}
+	if err := remove(sqlOwnerID, sqlStrangerID); !errors.Is(err, database.ErrorNotFound) {
+		t.Errorf("RemoveProjectOwner() of not an owner error = %v, want %v", err, database.ErrorNotFound)
+	}
+	if err := remove(sqlOwnerID, sqlOwnerID); err != nil {
+		t.Fatalf("RemoveProjectOwner() error = %v", err)
+	}
+	if err := remove(sqlMemberID, sqlMemberID); !errors.Is(err, project.ErrorLastOwner) {
+		t.Errorf("RemoveProjectOwner() of the remaining owner error = %v, want %v", err, project.ErrorLastOwner)
+	}
+
+	_, err = str.TransferProjectOwnership(ctx, sqlClaimsOf(sqlMemberID), projectData.ID,
+		project.TransferOwnership{UserID: sqlOwnerID}, sqlBaseDate)
+	if err != nil {
+		t.Fatalf("TransferProjectOwnership() error = %v", err)
+	}
+	if isOwner, err := str.IsProjectOwner(ctx, sqlClaimsOf(sqlMemberID), projectData.ID); err != nil || isOwner {
+		t.Errorf("IsProjectOwner() of the previous owner = %v, %v, want false", isOwner, err)
+	}
+}
+
+func TestStorePurgeWorkspace(t *testing.T) {
+	str, cluster := openTestStore(t)
+	workspaces := workspace.NewStore(zap.NewNop().Sugar(), cluster)
+	ctx, claims := context.Background(), sqlClaimsOf(sqlOwnerID)
+
+	projectData, err := str.CreateProject(ctx, claims,
+		project.NewProject{ProjectTypeID: project.TeamCollaborationType, Name: "Team", Description: "Team"},
+		sqlBaseDate)
+	if err != nil {
+		t.Fatalf("CreateProject() error = %v", err)
+	}
+
+	wsIds := make(map[string]string)
+	for _, name := range []string{"Purged", "Kept"} {
+		wsData, err := workspaces.CreateWorkspace(ctx, claims, workspace.NewWorkspace{ProjectID: projectData.ID,
+			StemID: workspace.StickerWorkspaceType, Name: name, AssetAmountLimit: 10, MaxX: 100, MaxY: 100,
+			MaxZ: 10}, sqlBaseDate)
+		if err != nil {
+			t.Fatalf("CreateWorkspace() error = %v", err)
+		}
+		wsIds[name] = wsData.ID
+	}
+	for _, placement := range []struct{ name, assetRef string }{
+		{name: "Purged", assetRef: "unique"}, {name: "Purged", assetRef: "unique"},
+		{name: "Purged", assetRef: "shared"}, {name: "Kept", assetRef: "shared"},
+	} {
+		if _, err := workspaces.CreateAsset(ctx, claims, workspace.NewAsset{WorkspaceID: wsIds[placement.name],
+			AssetRefID: placement.assetRef, X: 1, Y: 1, Z: 1, Scale: 1, Height: 1, Width: 1, Length: 1},
+			sqlBaseDate); err != nil {
+			t.Fatalf("CreateAsset() error = %v", err)
+		}
+	}
+
+	_, err = workspaces.PurgeWorkspace(ctx, sqlClaimsOf(sqlStrangerID), wsIds["Purged"])
+	if !errors.Is(err, database.ErrorForbidden) {
+		t.Errorf("PurgeWorkspace() by a stranger error = %v, want %v", err, database.ErrorForbidden)
+	}
+
+	assetRefs, err := workspaces.PurgeWorkspace(ctx, claims, wsIds["Purged"])
+	if err != nil {
+		t.Fatalf("PurgeWorkspace() error = %v", err)
+	}
+	if len(assetRefs) != 1 || assetRefs[0] != "unique" {
+		t.Errorf("PurgeWorkspace() = %v, want only the reference which is not used anymore", assetRefs)
+	}
+
+	var assetAmount int
+	if err := cluster.Primary().Get(&assetAmount, `SELECT COUNT(*) FROM ASSET`); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if assetAmount != 1 {
+		t.Errorf("assets after PurgeWorkspace() = %d, want 1 of the kept workspace", assetAmount)
+	}
+}
@@ -0,0 +1,89 @@
+package project
+
+import (
+	"github.com/SKorolchuk/dpio-workspace/internal/pkg/infra/auth"
+)
+
+// ReadableCondition is a SQL condition over PROJECT table aliased as "p" which holds when the project is readable
+// by the reader described with Visibility parameters:
+//   - Public projects are readable by any authenticated user;
+//   - Team projects are readable through membership in a group with access to the project;
+//   - Private projects are readable by their creator and owners only.
+//
+// Creator and owners of a project can always read it regardless of its collaboration type, the creator keeps
+// access after transfer of the ownership.
+// Deleted projects are not readable at all.
+const ReadableCondition = `p.date_deleted IS NULL AND (
+		p.project_collaboration_type_id = :public_type_id
+		OR p.created_by_user_id = :reader_id
+		OR EXISTS(SELECT 1 FROM PROJECT_OWNER AS vo WHERE vo.project_id = p.project_id AND vo.user_id = :reader_id)
+		OR (p.project_collaboration_type_id = :team_type_id AND EXISTS(SELECT 1
+			FROM
+				PROJECT_GROUP_ACCESS AS vga
+				JOIN PROJECT_GROUP_USER AS vgu ON vgu.project_group_id = vga.project_group_id
+			WHERE
+				vga.project_id = p.project_id AND vgu.user_id = :reader_id)))`
+
+// WritableCondition is a SQL condition over PROJECT table aliased as "p" which holds when content of the project
+// can be changed by the reader described with Visibility parameters: by owners of the project and by members of
+// groups with access to the project which hold ReadWriteRole, retired role does not allow changes.
+// It does not check readability of the project and is intended to be combined with ReadableCondition.
+const WritableCondition = `(
+		EXISTS(SELECT 1 FROM PROJECT_OWNER AS wo WHERE wo.project_id = p.project_id AND wo.user_id = :reader_id)
+		OR EXISTS(SELECT 1
+			FROM
+				PROJECT_GROUP_ACCESS AS wga
+				JOIN PROJECT_GROUP_USER AS wgu ON wgu.project_group_id = wga.project_group_id
+				JOIN PROJECT_GROUP_ROLE AS wgr ON wgr.project_group_id = wga.project_group_id
+				JOIN PROJECT_ROLE AS wr ON wr.project_role_id = wgr.project_role_id
+			WHERE
+				wga.project_id = p.project_id AND wgu.user_id = :reader_id
+				AND wr.project_role_id = :write_role_id AND wr.date_retired IS NULL))`
+
+// Visibility holds parameters of ReadableCondition and WritableCondition, it is intended to be embedded into query
+// parameters.
+type Visibility struct {
+	ReaderID     string `db:"reader_id"`
+	PublicTypeID string `db:"public_type_id"`
+	TeamTypeID   string `db:"team_type_id"`
+	WriteRoleID  string `db:"write_role_id"`
+}
+
+// NewVisibility creates Visibility parameters for the claims subject.
+func NewVisibility(claims auth.Claims) Visibility {
+	return Visibility{
+		ReaderID:     claims.Subject,
+		PublicTypeID: PublicCollaborationType,
+		TeamTypeID:   TeamCollaborationType,
+		WriteRoleID:  ReadWriteRole,
+	}
+}
+
+// Params returns Visibility parameters as named query arguments, it is intended for queries which are compiled at
+// runtime and keep their arguments in a map.
+func (visibility Visibility) Params() map[string]interface{} {
+	return map[string]interface{}{
+		"reader_id":      visibility.ReaderID,
+		"public_type_id": visibility.PublicTypeID,
+		"team_type_id":   visibility.TeamTypeID,
+		"write_role_id":  visibility.WriteRoleID,
+	}
+}
+
+// canRead reports whether the project is readable by the reader with readerId identifier, isOwner tells whether the
+// reader owns the project and isTeamMember tells whether the reader is a member of a group with access to the project.
+// It mirrors ReadableCondition.
+func canRead(projectData Project, readerId string, isOwner bool, isTeamMember bool) bool {
+	switch {
+	case projectData.DateDeleted != nil:
+		return false
+	case projectData.ProjectTypeID == PublicCollaborationType:
+		return true
+	case projectData.CreatedByUser == readerId, isOwner:
+		return true
+	case projectData.ProjectTypeID == TeamCollaborationType:
+		return isTeamMember
+	default:
+		return false
+	}
+}
@@ -0,0 +1,75 @@
+package search
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/SKorolchuk/dpio-workspace/internal/pkg/infra/auth"
+	"github.com/SKorolchuk/dpio-workspace/internal/pkg/infra/validation"
+)
+
+// MemoryStore represents an in-memory point of access to search over Project and Workspace entities.
+// It follows the fallback semantics of Store and is intended for unit tests and offline demo mode.
+type MemoryStore struct {
+	projects   ProjectSource
+	workspaces WorkspaceSource
+}
+
+// NewMemoryStore creates an instance of MemoryStore which searches through entities listed by projects and
+// workspaces for the claims subject.
+func NewMemoryStore(projects ProjectSource, workspaces WorkspaceSource) *MemoryStore {
+	return &MemoryStore{
+		projects:   projects,
+		workspaces: workspaces,
+	}
+}
+
+// Search looking for Project and Workspace entities of projects readable by the claims subject whose name or
+// description contains every word of the query text with descending order by rank.
+// Can return validation errors.
+func (str *MemoryStore) Search(ctx context.Context, claims auth.Claims, query Query) ([]Result, error) {
+	if err := validation.Check(ctx, query); err != nil {
+		return nil, fmt.Errorf("error during data validation of search Query: %w", err)
+	}
+
+	terms := searchTerms(query.Text)
+	if len(terms) == 0 {
+		return nil, nil
+	}
+
+	projectCollection, err := str.projects.QueryProjects(ctx, claims, query.IncludeArchived, 0, math.MaxInt32)
+	if err != nil {
+		return nil, fmt.Errorf("error during search of Project entities: %w", err)
+	}
+	wsCollection, err := str.workspaces.QueryWorkspaces(ctx, claims, query.IncludeArchived, 0, math.MaxInt32)
+	if err != nil {
+		return nil, fmt.Errorf("error during search of Workspace entities: %w", err)
+	}
+
+	candidates := make([]candidate, 0, len(projectCollection)+len(wsCollection))
+	for _, projectData := range projectCollection {
+		candidates = append(candidates, candidate{
+			Result: Result{
+				Kind:      KindProject,
+				ID:        projectData.ID,
+				ProjectID: projectData.ID,
+				Name:      projectData.Name,
+			},
+			Description: projectData.Description,
+		})
+	}
+	for _, wsData := range wsCollection {
+		candidates = append(candidates, candidate{
+			Result: Result{
+				Kind:      KindWorkspace,
+				ID:        wsData.ID,
+				ProjectID: wsData.ProjectID,
+				Name:      wsData.Name,
+			},
+			Description: wsData.Description,
+		})
+	}
+
+	return rankCandidates(candidates, terms, query.Top), nil
+}
@@ -0,0 +1,28 @@
+package search
+
+// Kinds of Result entities.
+const (
+	KindProject   = "project"
+	KindWorkspace = "workspace"
+)
+
+// Result represents a Project or a Workspace entity which matches a search query.
+// Snippet is an HTML-escaped fragment of the entity name and description where matched words are wrapped into
+// <mark> tags. Results with higher Rank match the query better.
+type Result struct {
+	Kind      string  `db:"kind" json:"kind"`
+	ID        string  `db:"id" json:"id"`
+	ProjectID string  `db:"project_id" json:"projectId"`
+	Name      string  `db:"name" json:"name"`
+	Snippet   string  `db:"snippet" json:"snippet"`
+	Rank      float64 `db:"rank" json:"rank"`
+}
+
+// Query describes all data that should be specified during search over Project and Workspace entities.
+// Text supports web search syntax on Postgres: quoted phrases, "or" and "-" for excluded words.
+// Archived projects and workspaces are returned only if IncludeArchived is set.
+type Query struct {
+	Text            string `json:"q" validate:"required,max=200"`
+	IncludeArchived bool   `json:"includeArchived"`
+	Top             int32  `json:"top" validate:"min=1"`
+}
@@ -0,0 +1,36 @@
+package search
+
+import (
+	"context"
+
+	"github.com/SKorolchuk/dpio-workspace/internal/pkg/infra/auth"
+	"github.com/SKorolchuk/dpio-workspace/internal/pkg/store/project"
+	"github.com/SKorolchuk/dpio-workspace/internal/pkg/store/workspace"
+)
+
+// Repository declares storage-agnostic search over Project and Workspace entities.
+// Implementations return only entities of projects readable by the claims subject.
+type Repository interface {
+	Search(ctx context.Context, claims auth.Claims, query Query) ([]Result, error)
+}
+
+// ProjectSource declares listing of readable Project entities which MemoryStore searches through.
+type ProjectSource interface {
+	QueryProjects(ctx context.Context, claims auth.Claims, includeArchived bool, skip int32,
+		top int32) ([]project.Project, error)
+}
+
+// WorkspaceSource declares listing of readable Workspace entities which MemoryStore searches through.
+type WorkspaceSource interface {
+	QueryWorkspaces(ctx context.Context, claims auth.Claims, includeArchived bool, skip int32,
+		top int32) ([]workspace.Workspace, error)
+}
+
+// Compile-time checks of Repository implementations.
+var (
+	_ Repository = Store{}
+	_ Repository = (*MemoryStore)(nil)
+
+	_ ProjectSource   = (*project.MemoryStore)(nil)
+	_ WorkspaceSource = (*workspace.MemoryStore)(nil)
+)
@@ -0,0 +1,177 @@
+// Package search contains full-text search over Project and Workspace entities.
+package search
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/SKorolchuk/dpio-workspace/internal/pkg/infra/auth"
+	"github.com/SKorolchuk/dpio-workspace/internal/pkg/infra/database"
+	"github.com/SKorolchuk/dpio-workspace/internal/pkg/infra/validation"
+	"github.com/SKorolchuk/dpio-workspace/internal/pkg/store/project"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// headlineOptions configures snippets produced by ts_headline, its sentinels are replaced by escapeSnippet.
+const headlineOptions = `StartSel="` + sentinelStart + `", StopSel="` + sentinelEnd + `", ` +
+	`MinWords=10, MaxWords=30, MaxFragments=2, FragmentDelimiter=" … "`
+
+// Search looking for Project and Workspace entities of projects readable by the claims subject which match the query
+// text with descending order by rank.
+// Postgres matches words of name and description with the english text search configuration, ranks matches with
+// ts_rank where name weighs more than description, and highlights them with ts_headline.
+// SQLite matches word prefixes of name and description with its full-text index and ranks matches in the same way.
+// Can return validation or database errors.
+func (str Store) Search(ctx context.Context, claims auth.Claims, query Query) ([]Result, error) {
+	if err := validation.Check(ctx, query); err != nil {
+		return nil, fmt.Errorf("error during data validation of search Query: %w", err)
+	}
+
+	connection := str.cluster.Reader(ctx)
+	if connection.DriverName() != database.DriverPostgres {
+		return str.searchWords(ctx, connection, claims, query)
+	}
+
+	queryParams := struct {
+		project.Visibility
+		Text            string `db:"text"`
+		Headline        string `db:"headline"`
+		Sentinels       string `db:"sentinels"`
+		IncludeArchived bool   `db:"include_archived"`
+		Top             int32  `db:"top"`
+	}{
+		Visibility:      project.NewVisibility(claims),
+		Text:            query.Text,
+		Headline:        headlineOptions,
+		Sentinels:       sentinelStart + sentinelEnd,
+		IncludeArchived: query.IncludeArchived,
+		Top:             query.Top,
+	}
+
+	const sqlQuery = `
+	WITH search AS (SELECT websearch_to_tsquery('english', :text) AS query),
+	matches AS (
+		SELECT
+			'project' AS kind,
+			p.project_id AS id,
+			p.project_id,
+			p.name,
+			p.description,
+			ts_rank(p.search_vector, search.query) AS rank
+		FROM
+			PROJECT AS p
+			CROSS JOIN search
+		WHERE
+			p.search_vector @@ search.query AND ` + project.ReadableCondition + `
+			AND (:include_archived OR p.date_archived IS NULL)
+		UNION ALL
+		SELECT
+			'workspace' AS kind,
+			w.workspace_id AS id,
+			w.project_id,
+			w.name,
+			w.description,
+			ts_rank(w.search_vector, search.query) AS rank
+		FROM
+			WORKSPACE AS w
+			JOIN PROJECT AS p ON p.project_id = w.project_id
+			CROSS JOIN search
+		WHERE
+			w.search_vector @@ search.query AND w.date_deleted IS NULL AND ` + project.ReadableCondition + `
+			AND (:include_archived OR (w.date_archived IS NULL AND p.date_archived IS NULL))
+		ORDER BY rank DESC, kind, id
+		LIMIT :top)
+	SELECT
+		m.kind,
+		m.id,
+		m.project_id,
+		m.name,
+		ts_headline('english', translate(m.name || '. ' || m.description, :sentinels, ''), search.query,
+			:headline) AS snippet,
+		m.rank
+	FROM
+		matches AS m
+		CROSS JOIN search
+	ORDER BY m.rank DESC, m.kind, m.id`
+
+	var results []Result
+	if err := database.NamedQuerySlice(ctx, str.logger, connection, sqlQuery, queryParams, &results); err != nil {
+		return nil, fmt.Errorf("error during search of Project and Workspace entities: %w", err)
+	}
+
+	for i := range results {
+		results[i].Snippet = escapeSnippet(results[i].Snippet)
+	}
+
+	return results, nil
+}
+
+// searchWords looking for Project and Workspace entities whose name or description contains a word starting with
+// every word of the query text in the SQLite full-text index. At most top matches are read with descending order by
+// rank, which weighs words found in the name more, and snippets of them are computed by the application.
+func (str Store) searchWords(ctx context.Context, connection *sqlx.DB, claims auth.Claims,
+	query Query) ([]Result, error) {
+	terms := searchTerms(query.Text)
+	if len(terms) == 0 {
+		return nil, nil
+	}
+
+	queryParams := project.NewVisibility(claims).Params()
+	queryParams["include_archived"] = query.IncludeArchived
+	queryParams["top"] = query.Top
+	queryParams["match"] = matchExpression(terms)
+
+	projectRanks := make([]string, 0, len(terms))
+	wsRanks := make([]string, 0, len(terms))
+	for i, term := range terms {
+		paramName := fmt.Sprintf("term_%d", i)
+		queryParams[paramName] = "%" + database.LikePattern(term) + "%"
+
+		const rank = `CASE WHEN LOWER(%[1]s.name) LIKE :%[2]s ESCAPE '\' THEN %[3]g
+			WHEN LOWER(%[1]s.description) LIKE :%[2]s ESCAPE '\' THEN %[4]g ELSE 0 END`
+		projectRanks = append(projectRanks, fmt.Sprintf(rank, "p", paramName, nameWeight, descriptionWeight))
+		wsRanks = append(wsRanks, fmt.Sprintf(rank, "w", paramName, nameWeight, descriptionWeight))
+	}
+	divisor := fmt.Sprintf(") / %d.0 AS rank", len(terms))
+
+	sqlQuery := `
+	SELECT
+		'project' AS kind,
+		p.project_id AS id,
+		p.project_id,
+		p.name,
+		p.description,
+		(` + strings.Join(projectRanks, " + ") + divisor + `
+	FROM
+		PROJECT_SEARCH AS s
+		JOIN PROJECT AS p ON p.project_id = s.project_id
+	WHERE
+		PROJECT_SEARCH MATCH :match AND ` + project.ReadableCondition + `
+		AND (:include_archived OR p.date_archived IS NULL)
+	UNION ALL
+	SELECT
+		'workspace' AS kind,
+		w.workspace_id AS id,
+		w.project_id,
+		w.name,
+		w.description,
+		(` + strings.Join(wsRanks, " + ") + divisor + `
+	FROM
+		WORKSPACE_SEARCH AS s
+		JOIN WORKSPACE AS w ON w.workspace_id = s.workspace_id
+		JOIN PROJECT AS p ON p.project_id = w.project_id
+	WHERE
+		WORKSPACE_SEARCH MATCH :match AND w.date_deleted IS NULL AND ` + project.ReadableCondition + `
+		AND (:include_archived OR (w.date_archived IS NULL AND p.date_archived IS NULL))
+	ORDER BY rank DESC, kind, id
+	LIMIT :top`
+
+	var candidates []candidate
+	if err := database.NamedQuerySlice(ctx, str.logger, connection, sqlQuery, queryParams, &candidates); err != nil {
+		return nil, fmt.Errorf("error during search of Project and Workspace entities: %w", err)
+	}
+
+	return rankCandidates(candidates, terms, query.Top), nil
+}
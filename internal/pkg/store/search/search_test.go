@@ -0,0 +1,132 @@
+package search
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/SKorolchuk/dpio-workspace/internal/pkg/infra/auth"
+	"github.com/SKorolchuk/dpio-workspace/internal/pkg/infra/database"
+	store "github.com/SKorolchuk/dpio-workspace/internal/pkg/store/database"
+	"github.com/SKorolchuk/dpio-workspace/internal/pkg/store/project"
+	"github.com/SKorolchuk/dpio-workspace/internal/pkg/store/workspace"
+
+	"github.com/jmoiron/sqlx"
+	"go.uber.org/zap"
+)
+
+// Identifiers of entities in the SQLite test database.
+const (
+	testUserID         = "9b0f2a66-3c34-4b4e-8b8f-5a8c4a1e0001"
+	testStemID         = "9b0f2a66-3c34-4b4e-8b8f-5a8c4a1e0101"
+	testPublicProject  = "9b0f2a66-3c34-4b4e-8b8f-5a8c4a1e0201"
+	testPrivateProject = "9b0f2a66-3c34-4b4e-8b8f-5a8c4a1e0202"
+	testWorkspaceID    = "9b0f2a66-3c34-4b4e-8b8f-5a8c4a1e0301"
+)
+
+// openTestDatabase creates migrated SQLite database with a public and a private project, the public one has a
+// workspace.
+func openTestDatabase(t *testing.T) *sqlx.DB {
+	t.Helper()
+
+	connection, err := database.Open(database.DbConfig{Driver: database.DriverSQLite,
+		DatabaseName: filepath.Join(t.TempDir(), "search.db")})
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	t.Cleanup(func() { _ = database.Close(connection) })
+
+	ctx := context.Background()
+	if err := store.Migrate(ctx, connection); err != nil {
+		t.Fatalf("Migrate() error = %v", err)
+	}
+
+	now := time.Now().UTC()
+	newProject := func(id string, typeId string, name string, description string) project.Project {
+		return project.Project{ID: id, ProjectTypeID: typeId, Name: name, Description: description,
+			DateCreated: now, CreatedByUser: testUserID, DateUpdated: now, UpdatedByUser: testUserID}
+	}
+
+	fixture := store.Fixture{
+		CollaborationTypes: []project.CollaborationType{
+			{ID: project.PublicCollaborationType, Name: "Public"},
+			{ID: project.PrivateCollaborationType, Name: "Private"},
+		},
+		Projects: []project.Project{
+			newProject(testPublicProject, project.PublicCollaborationType, "Harbour scenes",
+				"Boats & <b>docks</b> of the old harbour"),
+			newProject(testPrivateProject, project.PrivateCollaborationType, "Private harbour", "Secret drafts"),
+		},
+		Stems: []workspace.Stem{{ID: testStemID, Name: "Scene"}},
+		Workspaces: []workspace.Workspace{{ID: testWorkspaceID, ProjectID: testPublicProject, StemID: testStemID,
+			Name: "Lighthouse", Description: "Night view of the harbour", AssetAmountLimit: 10, MaxX: 10, MaxY: 10,
+			MaxZ: 10, DateCreated: now, CreatedByUser: testUserID, DateUpdated: now, UpdatedByUser: testUserID}},
+	}
+	if err := store.SeedFixture(ctx, connection, fixture); err != nil {
+		t.Fatalf("SeedFixture() error = %v", err)
+	}
+
+	return connection
+}
+
+func TestStoreSearchSQLite(t *testing.T) {
+	connection := openTestDatabase(t)
+	searchStore := NewStore(zap.NewNop().Sugar(), database.NewCluster(connection))
+	reader := auth.Claims{}
+	reader.Subject = "9b0f2a66-3c34-4b4e-8b8f-5a8c4a1e0002"
+
+	tests := []struct {
+		name    string
+		text    string
+		top     int32
+		wantIDs []string
+	}{
+		{name: "name match ranks first", text: "harbour", top: 10,
+			wantIDs: []string{testPublicProject, testWorkspaceID}},
+		{name: "word prefix", text: "light", top: 10, wantIDs: []string{testWorkspaceID}},
+		{name: "every word must match", text: "harbour lighthouse", top: 10, wantIDs: []string{testWorkspaceID}},
+		{name: "limited by top", text: "harbour", top: 1, wantIDs: []string{testPublicProject}},
+		{name: "no match", text: "castle", top: 10},
+		{name: "only punctuation", text: "&&", top: 10},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			results, err := searchStore.Search(context.Background(), reader, Query{Text: test.text, Top: test.top})
+			if err != nil {
+				t.Fatalf("Search() error = %v", err)
+			}
+
+			var ids []string
+			for _, result := range results {
+				ids = append(ids, result.ID)
+			}
+			if len(ids) != len(test.wantIDs) {
+				t.Fatalf("Search() = %v, want %v", ids, test.wantIDs)
+			}
+			for i := range ids {
+				if ids[i] != test.wantIDs[i] {
+					t.Errorf("Search() = %v, want %v", ids, test.wantIDs)
+				}
+			}
+		})
+	}
+
+	t.Run("index follows updates", func(t *testing.T) {
+		const renameQuery = `UPDATE WORKSPACE SET name = 'Beacon' WHERE workspace_id = $1`
+		if _, err := connection.Exec(renameQuery, testWorkspaceID); err != nil {
+			t.Fatalf("Exec() error = %v", err)
+		}
+
+		for text, wantCount := range map[string]int{"lighthouse": 0, "beacon": 1} {
+			results, err := searchStore.Search(context.Background(), reader, Query{Text: text, Top: 10})
+			if err != nil {
+				t.Fatalf("Search() error = %v", err)
+			}
+			if len(results) != wantCount {
+				t.Errorf("Search(%q) returned %d results, want %d", text, len(results), wantCount)
+			}
+		}
+	})
+}
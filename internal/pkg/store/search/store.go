@@ -0,0 +1,22 @@
+package search
+
+import (
+	"github.com/SKorolchuk/dpio-workspace/internal/pkg/infra/database"
+
+	"go.uber.org/zap"
+)
+
+// Store represents a point of access to search over Project and Workspace entities.
+// Search queries are served by a read replica of the cluster when a healthy one is available.
+type Store struct {
+	logger  *zap.SugaredLogger
+	cluster *database.Cluster
+}
+
+// NewStore creates an instance of Store for search over Project and Workspace entities.
+func NewStore(logger *zap.SugaredLogger, cluster *database.Cluster) Store {
+	return Store{
+		logger:  logger,
+		cluster: cluster,
+	}
+}
@@ -0,0 +1,190 @@
+package search
+
+import (
+	"html"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Settings of search performed by the application instead of the database.
+const (
+	maxTerms      = 8
+	snippetLength = 160
+	snippetLead   = 40
+)
+
+// Rank weights of query words found in name and in description, they follow default weights of ts_rank.
+const (
+	nameWeight        = 1.0
+	descriptionWeight = 0.4
+)
+
+// Markers of matched words in snippets.
+const (
+	markStart = "<mark>"
+	markEnd   = "</mark>"
+)
+
+// Sentinels which delimit matched words in snippets produced by the database. They are control characters removed
+// from the text before it is highlighted, so they are never confused with the text itself.
+const (
+	sentinelStart = "\x02"
+	sentinelEnd   = "\x03"
+)
+
+// candidate represents an entity which may match search query, its rank and snippet are computed by the application.
+type candidate struct {
+	Result
+	Description string `db:"description"`
+}
+
+// searchTerms splits text into distinct lower case words, at most maxTerms words are returned.
+func searchTerms(text string) []string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(char rune) bool {
+		return !unicode.IsLetter(char) && !unicode.IsDigit(char)
+	})
+
+	terms := make([]string, 0, len(words))
+	for _, word := range words {
+		if len(terms) == maxTerms {
+			break
+		}
+		if !containsTerm(terms, word) {
+			terms = append(terms, word)
+		}
+	}
+
+	return terms
+}
+
+// matchExpression returns SQLite full-text query which matches documents containing a word with prefix of every term.
+// Terms contain only letters and digits, so they are never read as query operators.
+func matchExpression(terms []string) string {
+	prefixes := make([]string, 0, len(terms))
+	for _, term := range terms {
+		prefixes = append(prefixes, term+"*")
+	}
+
+	return strings.Join(prefixes, " ")
+}
+
+// rankCandidates returns at most top candidates which contain every term with descending order by rank, type and
+// identifier, snippets are computed for the returned results only.
+func rankCandidates(candidates []candidate, terms []string, top int32) []Result {
+	ranked := make([]candidate, 0, len(candidates))
+	for _, item := range candidates {
+		if item.Rank = matchRank(item.Name, item.Description, terms); item.Rank > 0 {
+			ranked = append(ranked, item)
+		}
+	}
+	sort.Slice(ranked, func(i, j int) bool {
+		switch {
+		case ranked[i].Rank != ranked[j].Rank:
+			return ranked[i].Rank > ranked[j].Rank
+		case ranked[i].Kind != ranked[j].Kind:
+			return ranked[i].Kind < ranked[j].Kind
+		default:
+			return ranked[i].ID < ranked[j].ID
+		}
+	})
+	if len(ranked) > int(top) {
+		ranked = ranked[:top]
+	}
+
+	results := make([]Result, 0, len(ranked))
+	for _, item := range ranked {
+		item.Snippet = highlight(item.Name+". "+item.Description, terms)
+		results = append(results, item.Result)
+	}
+
+	return results
+}
+
+// matchRank reports how well name and description match terms. Every term must occur in the name or in the
+// description, and a term found in the name weighs more. Zero rank means that the text does not match.
+func matchRank(name string, description string, terms []string) float64 {
+	name, description = strings.ToLower(name), strings.ToLower(description)
+
+	rank := 0.0
+	for _, term := range terms {
+		switch {
+		case strings.Contains(name, term):
+			rank += nameWeight
+		case strings.Contains(description, term):
+			rank += descriptionWeight
+		default:
+			return 0
+		}
+	}
+
+	return rank / float64(len(terms))
+}
+
+// highlight returns HTML-escaped fragment of text around the first match of terms where every match is wrapped into
+// <mark> tags. Cut ends of the text are replaced with an ellipsis.
+func highlight(text string, terms []string) string {
+	quoted := make([]string, 0, len(terms))
+	for _, term := range terms {
+		quoted = append(quoted, regexp.QuoteMeta(term))
+	}
+	matches := regexp.MustCompile("(?i)"+strings.Join(quoted, "|")).FindAllStringIndex(text, -1)
+
+	start, end := 0, len(text)
+	if end > snippetLength {
+		if len(matches) > 0 && matches[0][0] > snippetLead {
+			start = runeStart(text, matches[0][0]-snippetLead)
+		}
+		if start+snippetLength < end {
+			end = runeStart(text, start+snippetLength)
+		}
+	}
+
+	var snippet strings.Builder
+	if start > 0 {
+		snippet.WriteString("… ")
+	}
+	position := start
+	for _, match := range matches {
+		if match[0] < position || match[1] > end {
+			continue
+		}
+		snippet.WriteString(html.EscapeString(text[position:match[0]]))
+		snippet.WriteString(markStart + html.EscapeString(text[match[0]:match[1]]) + markEnd)
+		position = match[1]
+	}
+	snippet.WriteString(html.EscapeString(text[position:end]))
+	if end < len(text) {
+		snippet.WriteString(" …")
+	}
+
+	return snippet.String()
+}
+
+// escapeSnippet HTML-escapes snippet produced by the database and replaces sentinels around matched words with
+// <mark> tags. Tags which are part of the text itself are escaped as any other text.
+func escapeSnippet(snippet string) string {
+	return strings.NewReplacer(sentinelStart, markStart, sentinelEnd, markEnd).Replace(html.EscapeString(snippet))
+}
+
+// runeStart moves index of text back to the start of the rune it points into.
+func runeStart(text string, index int) int {
+	for index > 0 && !utf8.RuneStart(text[index]) {
+		index--
+	}
+
+	return index
+}
+
+// containsTerm reports whether terms contain term.
+func containsTerm(terms []string, term string) bool {
+	for _, item := range terms {
+		if item == term {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,119 @@
+package search
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestMatchRank(t *testing.T) {
+	tests := []struct {
+		name        string
+		description string
+		terms       []string
+		want        float64
+	}{
+		{name: "Harbour", description: "Boats", terms: []string{"harbour"}, want: nameWeight},
+		{name: "Harbour", description: "Boats", terms: []string{"boat"}, want: descriptionWeight},
+		{name: "HARBOUR", description: "Boats", terms: []string{"harbour", "boat"},
+			want: (nameWeight + descriptionWeight) / 2},
+		{name: "Harbour", description: "Boats", terms: []string{"harbour", "docks"}},
+		{name: "Harbour", description: "Boats", terms: []string{"river"}},
+	}
+
+	for _, test := range tests {
+		t.Run(strings.Join(test.terms, " "), func(t *testing.T) {
+			if got := matchRank(test.name, test.description, test.terms); got != test.want {
+				t.Errorf("matchRank() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestHighlight(t *testing.T) {
+	long := strings.Repeat("calm water ", 20) + "harbour"
+	lead := long[len(long)-len("harbour")-snippetLead : len(long)-len("harbour")]
+
+	tests := []struct {
+		name  string
+		text  string
+		terms []string
+		want  string
+	}{
+		{name: "case insensitive matches", text: "Harbour and harbour", terms: []string{"harbour"},
+			want: "<mark>Harbour</mark> and <mark>harbour</mark>"},
+		{name: "escaped text", text: "Boats & <b>docks</b>", terms: []string{"docks"},
+			want: "Boats &amp; &lt;b&gt;<mark>docks</mark>&lt;/b&gt;"},
+		{name: "literal mark tags", text: "<mark>boats</mark>", terms: []string{"boats"},
+			want: "&lt;mark&gt;<mark>boats</mark>&lt;/mark&gt;"},
+		{name: "no match", text: "Boats", terms: []string{"river"}, want: "Boats"},
+		{name: "long text cut around the match", text: long, terms: []string{"harbour"},
+			want: "… " + lead + "<mark>harbour</mark>"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := highlight(test.text, test.terms); got != test.want {
+				t.Errorf("highlight() = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestEscapeSnippet(t *testing.T) {
+	tests := []struct {
+		name    string
+		snippet string
+		want    string
+	}{
+		{name: "marked words", snippet: "Old " + sentinelStart + "harbour" + sentinelEnd + " scenes",
+			want: "Old <mark>harbour</mark> scenes"},
+		{name: "escaped text", snippet: "Boats & " + sentinelStart + "<b>docks</b>" + sentinelEnd,
+			want: "Boats &amp; <mark>&lt;b&gt;docks&lt;/b&gt;</mark>"},
+		{name: "literal mark tags", snippet: "<mark>boats</mark> " + sentinelStart + "harbour" + sentinelEnd,
+			want: "&lt;mark&gt;boats&lt;/mark&gt; <mark>harbour</mark>"},
+		{name: "no matches", snippet: `"quoted" text`, want: "&#34;quoted&#34; text"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := escapeSnippet(test.snippet); got != test.want {
+				t.Errorf("escapeSnippet() = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestRankCandidates(t *testing.T) {
+	newCandidate := func(kind string, id string, name string, description string) candidate {
+		return candidate{Result: Result{Kind: kind, ID: id, Name: name}, Description: description}
+	}
+	candidates := []candidate{
+		newCandidate(KindWorkspace, "4", "Docks", "Harbour boats"),
+		newCandidate(KindProject, "3", "Harbour", "Boats"),
+		newCandidate(KindWorkspace, "2", "Harbour", "Boats"),
+		newCandidate(KindProject, "1", "Harbour", "Trains"),
+		newCandidate(KindProject, "5", "Boats", "Harbour"),
+	}
+
+	ids := func(results []Result) []string {
+		resultIds := make([]string, 0, len(results))
+		for _, result := range results {
+			resultIds = append(resultIds, result.ID)
+		}
+		return resultIds
+	}
+
+	results := rankCandidates(candidates, []string{"harbour", "boat"}, 10)
+	if want := []string{"3", "5", "2", "4"}; !reflect.DeepEqual(ids(results), want) {
+		t.Errorf("rankCandidates() = %v, want %v", ids(results), want)
+	}
+	if results[0].Snippet != "<mark>Harbour</mark>. <mark>Boat</mark>s" {
+		t.Errorf("rankCandidates() snippet = %q", results[0].Snippet)
+	}
+
+	if results := rankCandidates(candidates, []string{"harbour"}, 2); !reflect.DeepEqual(ids(results),
+		[]string{"1", "3"}) {
+		t.Errorf("rankCandidates() with top = %v, want %v", ids(results), []string{"1", "3"})
+	}
+}
@@ -2,6 +2,7 @@ package workspace
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -9,11 +10,18 @@ import (
 	"github.com/SKorolchuk/dpio-workspace/internal/pkg/infra/database"
 	"github.com/SKorolchuk/dpio-workspace/internal/pkg/infra/uuid"
 	"github.com/SKorolchuk/dpio-workspace/internal/pkg/infra/validation"
+	"github.com/SKorolchuk/dpio-workspace/internal/pkg/store/project"
+
+	"github.com/jmoiron/sqlx"
 )
 
+// ErrorAssetOutOfBounds is returned when Asset entity does not fit into MaxX, MaxY and MaxZ bounds of its workspace.
+var ErrorAssetOutOfBounds = errors.New("asset does not fit into bounds of the workspace")
+
 // CreateAsset adds new Asset entity to the database.
+// The workspace should be readable by the claims subject, not deleted or archived, and its project writable.
 // If creation is successful, the method returns Asset entity.
-// Can return validation or database errors.
+// Can return ErrorAssetLimitExceeded, ErrorAssetOutOfBounds, validation or database errors.
 func (str Store) CreateAsset(ctx context.Context, claims auth.Claims, newAsset NewAsset, now time.Time) (Asset,
 	error) {
 	if err := validation.Check(ctx, newAsset); err != nil {
@@ -45,8 +53,24 @@ func (str Store) CreateAsset(ctx context.Context, claims auth.Claims, newAsset N
 		(:asset_id, :workspace_id, :asset_external_ref_id, :position_x, :position_y, :position_z, :scale, :height_by_y,
 			:width_by_x, :length_by_z, :date_created, :created_by_user_id, :date_updated, :updated_by_user_id)`
 
-	if err := database.NamedExecContext(ctx, str.logger, str.connection, query, asset); err != nil {
-		return Asset{}, fmt.Errorf("error during create of new Asset entity: %w", err)
+	err := database.WithTransaction(ctx, str.cluster.Primary(), func(transaction *sqlx.Tx) error {
+		wsData, assetAmount, err := str.queryWritableWorkspace(ctx, transaction, claims, asset.WorkspaceID)
+		if err != nil {
+			return fmt.Errorf("error during search of Workspace entity -> id={%q}: %w", asset.WorkspaceID, err)
+		}
+
+		if err := checkPlacement(wsData, assetAmount, asset); err != nil {
+			return err
+		}
+
+		if err := database.NamedExecContext(ctx, str.logger, transaction, query, asset); err != nil {
+			return fmt.Errorf("error during create of new Asset entity: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return Asset{}, err
 	}
 
 	return asset, nil
@@ -64,14 +88,13 @@ func (str Store) UpdateAsset(ctx context.Context, claims auth.Claims, assetId st
 		return fmt.Errorf("error during data validation of Asset entity: %w", err)
 	}
 
-	assetData, err := str.QueryAssetByID(ctx, assetId)
+	assetData, err := str.queryAssetByID(ctx, assetId)
 	if err != nil {
 		return fmt.Errorf("error during search of Asset entity -> id={%q}: %w", assetId, err)
 	}
 
-	// TODO implement group-based edit check in downstream logic
-	if assetData.CreatedByUser != claims.Subject {
-		return database.ErrorForbidden
+	if err := str.checkChangeAllowed(ctx, claims, assetData.WorkspaceID); err != nil {
+		return err
 	}
 
 	if asset.AssetRefID != nil {
@@ -119,7 +142,7 @@ func (str Store) UpdateAsset(ctx context.Context, claims auth.Claims, assetId st
 	WHERE
 		asset_id = :asset_id`
 
-	if err := database.NamedExecContext(ctx, str.logger, str.connection, query, assetData); err != nil {
+	if err := database.NamedExecContext(ctx, str.logger, str.cluster.Primary(), query, assetData); err != nil {
 		return fmt.Errorf("error during update of Asset entity -> id={%s}: %w", assetId, err)
 	}
 
@@ -133,13 +156,13 @@ func (str Store) DeleteAsset(ctx context.Context, claims auth.Claims, assetId st
 		return database.ErrorInvalidIdentifier
 	}
 
-	assetData, err := str.QueryAssetByID(ctx, assetId)
+	assetData, err := str.queryAssetByID(ctx, assetId)
 	if err != nil {
 		return fmt.Errorf("error during search of Asset entity -> id={%q}: %w", assetId, err)
 	}
 
-	if assetData.CreatedByUser != claims.Subject {
-		return database.ErrorForbidden
+	if err := str.checkChangeAllowed(ctx, claims, assetData.WorkspaceID); err != nil {
+		return err
 	}
 
 	queryParams := struct {
@@ -154,7 +177,7 @@ func (str Store) DeleteAsset(ctx context.Context, claims auth.Claims, assetId st
 	WHERE
 		asset_id = :asset_id`
 
-	if err := database.NamedExecContext(ctx, str.logger, str.connection, query, queryParams); err != nil {
+	if err := database.NamedExecContext(ctx, str.logger, str.cluster.Primary(), query, queryParams); err != nil {
 		return fmt.Errorf("error during delete of Asset entity -> id={%q}: %w", assetId, err)
 	}
 
@@ -162,18 +185,21 @@ func (str Store) DeleteAsset(ctx context.Context, claims auth.Claims, assetId st
 }
 
 // QueryAssetsByWorkspace looking for all Asset entities that belong to a specific Workspace using skip/top mechanics with
-// descending order by update date field.
-func (str Store) QueryAssetsByWorkspace(ctx context.Context, workspaceId string, skip int32, top int32) ([]Asset,
-	error) {
+// descending order by update date and identifier fields.
+// Assets of a workspace whose project is not readable by the claims subject are not returned.
+func (str Store) QueryAssetsByWorkspace(ctx context.Context, claims auth.Claims, workspaceId string, skip int32,
+	top int32) ([]Asset, error) {
 	if err := uuid.Validate(workspaceId); err != nil {
 		return []Asset{}, database.ErrorInvalidIdentifier
 	}
 
 	queryParams := struct {
+		project.Visibility
 		WorkspaceId string `db:"workspace_id"`
 		Skip        int32  `db:"offset"`
 		Top         int32  `db:"top"`
 	}{
+		Visibility:  project.NewVisibility(claims),
 		WorkspaceId: workspaceId,
 		Skip:        skip,
 		Top:         top,
@@ -197,13 +223,16 @@ func (str Store) QueryAssetsByWorkspace(ctx context.Context, workspaceId string,
 		a.updated_by_user_id
 	FROM
 		ASSET AS a
+		JOIN WORKSPACE AS w ON w.workspace_id = a.workspace_id
+		JOIN PROJECT AS p ON p.project_id = w.project_id
 	WHERE
-		a.workspace_id = :workspace_id
-	ORDER BY a.date_updated DESC
-	OFFSET :offset ROWS FETCH NEXT :top ROWS ONLY`
+		a.workspace_id = :workspace_id AND w.date_deleted IS NULL AND ` + project.ReadableCondition + `
+	ORDER BY a.date_updated DESC, a.asset_id DESC
+	LIMIT :top OFFSET :offset`
 
 	var assetCollection []Asset
-	if err := database.NamedQuerySlice(ctx, str.logger, str.connection, query, queryParams, &assetCollection); err != nil {
+	connection := str.cluster.Reader(ctx)
+	if err := database.NamedQuerySlice(ctx, str.logger, connection, query, queryParams, &assetCollection); err != nil {
 		if err == database.ErrorNotFound {
 			return nil, database.ErrorNotFound
 		}
@@ -214,8 +243,159 @@ func (str Store) QueryAssetsByWorkspace(ctx context.Context, workspaceId string,
 	return assetCollection, nil
 }
 
-// QueryAssetByID looking for Asset entity with assetId identifier.
-func (str Store) QueryAssetByID(ctx context.Context, assetId string) (Asset, error) {
+// QueryAssetsByWorkspacePage looking for a page of Asset entities that belong to a specific Workspace using keyset
+// pagination with descending order by update date and identifier fields.
+// Assets of a workspace whose project is not readable by the claims subject are not returned.
+// If the page cursor is malformed, the method returns database.ErrorInvalidCursor.
+func (str Store) QueryAssetsByWorkspacePage(ctx context.Context, claims auth.Claims, workspaceId string,
+	page PageRequest) (AssetPage, error) {
+	if err := uuid.Validate(workspaceId); err != nil {
+		return AssetPage{}, database.ErrorInvalidIdentifier
+	}
+
+	cursor, err := pageCursor(page)
+	if err != nil {
+		return AssetPage{}, fmt.Errorf("error during search of Asset entities: %w", err)
+	}
+
+	queryParams := struct {
+		project.Visibility
+		database.Keyset
+		WorkspaceId string `db:"workspace_id"`
+		Top         int32  `db:"top"`
+	}{
+		Visibility:  project.NewVisibility(claims),
+		Keyset:      database.NewKeyset(cursor),
+		WorkspaceId: workspaceId,
+		Top:         page.Top + 1,
+	}
+
+	const filter = `
+		a.workspace_id = :workspace_id AND w.date_deleted IS NULL AND ` + project.ReadableCondition
+
+	const selectQuery = `
+	SELECT
+		a.asset_id,
+		a.workspace_id,
+		a.asset_external_ref_id,
+		a.position_x,
+		a.position_y,
+		a.position_z,
+		a.scale,
+		a.height_by_y,
+		a.width_by_x,
+		a.length_by_z,
+		a.date_created,
+		a.created_by_user_id,
+		a.date_updated,
+		a.updated_by_user_id
+	FROM
+		ASSET AS a
+		JOIN WORKSPACE AS w ON w.workspace_id = a.workspace_id
+		JOIN PROJECT AS p ON p.project_id = w.project_id
+	WHERE` + filter
+
+	const keysetCondition = `
+		AND (a.date_updated < :after_date OR (a.date_updated = :after_date AND a.asset_id < :after_id))`
+
+	const orderQuery = `
+	ORDER BY a.date_updated DESC, a.asset_id DESC
+	LIMIT :top`
+
+	const countQuery = `
+	SELECT
+		COUNT(*) AS amount
+	FROM
+		ASSET AS a
+		JOIN WORKSPACE AS w ON w.workspace_id = a.workspace_id
+		JOIN PROJECT AS p ON p.project_id = w.project_id
+	WHERE` + filter
+
+	query := selectQuery
+	if cursor != nil {
+		query += keysetCondition
+	}
+	query += orderQuery
+
+	var assetCollection []Asset
+	connection := str.cluster.Reader(ctx)
+	if err := database.NamedQuerySlice(ctx, str.logger, connection, query, queryParams, &assetCollection); err != nil {
+		return AssetPage{}, fmt.Errorf("error during search of Asset entities: %w", err)
+	}
+
+	assetPage := AssetPage{Items: assetCollection}
+	if len(assetCollection) > int(page.Top) {
+		assetPage.Items = assetCollection[:page.Top]
+		last := assetPage.Items[page.Top-1]
+		assetPage.NextCursor = database.EncodeCursor(database.Cursor{Date: last.DateUpdated, ID: last.ID})
+	}
+
+	if page.WithTotal {
+		total, err := str.countRows(ctx, countQuery, queryParams)
+		if err != nil {
+			return AssetPage{}, fmt.Errorf("error during count of Asset entities: %w", err)
+		}
+		assetPage.Total = &total
+	}
+
+	return assetPage, nil
+}
+
+// QueryAssetByID looking for Asset entity with assetId identifier whose project is readable by the claims subject.
+// Asset of a project which is not readable by the subject is reported with database.ErrorNotFound.
+func (str Store) QueryAssetByID(ctx context.Context, claims auth.Claims, assetId string) (Asset, error) {
+	if err := uuid.Validate(assetId); err != nil {
+		return Asset{}, err
+	}
+
+	queryParams := struct {
+		project.Visibility
+		AssetID string `db:"asset_id"`
+	}{
+		Visibility: project.NewVisibility(claims),
+		AssetID:    assetId,
+	}
+
+	const query = `
+	SELECT
+		a.asset_id,
+		a.workspace_id,
+		a.asset_external_ref_id,
+		a.position_x,
+		a.position_y,
+		a.position_z,
+		a.scale,
+		a.height_by_y,
+		a.width_by_x,
+		a.length_by_z,
+		a.date_created,
+		a.created_by_user_id,
+		a.date_updated,
+		a.updated_by_user_id
+	FROM
+		ASSET AS a
+		JOIN WORKSPACE AS w ON w.workspace_id = a.workspace_id
+		JOIN PROJECT AS p ON p.project_id = w.project_id
+	WHERE
+		a.asset_id = :asset_id AND w.date_deleted IS NULL AND ` + project.ReadableCondition
+
+	var assetData Asset
+	connection := str.cluster.Primary()
+	if err := database.NamedQueryStruct(ctx, str.logger, connection, query, queryParams, &assetData); err != nil {
+		if err == database.ErrorNotFound {
+			return Asset{}, database.ErrorNotFound
+		}
+
+		return Asset{}, fmt.Errorf("error during search of Asset entity -> id={%q}: %w", assetId, err)
+	}
+
+	return assetData, nil
+}
+
+// queryAssetByID looking for Asset entity with assetId identifier regardless of visibility of its project,
+// asset of deleted workspace or project is reported with database.ErrorNotFound.
+// It is used by mutations which perform their own permission checks.
+func (str Store) queryAssetByID(ctx context.Context, assetId string) (Asset, error) {
 	if err := uuid.Validate(assetId); err != nil {
 		return Asset{}, err
 	}
@@ -244,11 +424,14 @@ func (str Store) QueryAssetByID(ctx context.Context, assetId string) (Asset, err
 		a.updated_by_user_id
 	FROM
 		ASSET AS a
+		JOIN WORKSPACE AS w ON w.workspace_id = a.workspace_id
+		JOIN PROJECT AS p ON p.project_id = w.project_id
 	WHERE
-		p.asset_id = :asset_id`
+		a.asset_id = :asset_id AND w.date_deleted IS NULL AND p.date_deleted IS NULL`
 
 	var assetData Asset
-	if err := database.NamedQueryStruct(ctx, str.logger, str.connection, query, queryParams, &assetData); err != nil {
+	connection := str.cluster.Primary()
+	if err := database.NamedQueryStruct(ctx, str.logger, connection, query, queryParams, &assetData); err != nil {
 		if err == database.ErrorNotFound {
 			return Asset{}, database.ErrorNotFound
 		}
@@ -258,3 +441,79 @@ func (str Store) QueryAssetByID(ctx context.Context, assetId string) (Asset, err
 
 	return assetData, nil
 }
+
+// queryWritableWorkspace looking for not deleted and not archived Workspace entity with wsId identifier in the
+// transaction and counts its Asset entities. On PostgreSQL the workspace row stays locked until the end of the
+// transaction, so concurrent creations cannot pass AssetAmountLimit together.
+// It reports database.ErrorNotFound unless the project of the workspace is readable by the claims subject and is not
+// archived, and database.ErrorForbidden unless the subject can write the project.
+func (str Store) queryWritableWorkspace(ctx context.Context, transaction *sqlx.Tx, claims auth.Claims,
+	wsId string) (Workspace, int, error) {
+	if err := uuid.Validate(wsId); err != nil {
+		return Workspace{}, 0, database.ErrorInvalidIdentifier
+	}
+
+	queryParams := struct {
+		project.Visibility
+		WorkspaceID string `db:"workspace_id"`
+	}{
+		Visibility:  project.NewVisibility(claims),
+		WorkspaceID: wsId,
+	}
+
+	query := `
+	SELECT
+		w.workspace_id,
+		w.asset_amount_limit,
+		w.x_max,
+		w.y_max,
+		w.z_max,
+		(SELECT COUNT(*) FROM ASSET AS a WHERE a.workspace_id = w.workspace_id) AS asset_amount,
+		` + project.WritableCondition + ` AS writable
+	FROM
+		WORKSPACE AS w
+		JOIN PROJECT AS p ON p.project_id = w.project_id
+	WHERE
+		w.workspace_id = :workspace_id AND w.date_deleted IS NULL AND w.date_archived IS NULL AND
+		p.date_deleted IS NULL AND p.date_archived IS NULL AND ` + project.ReadableCondition
+
+	if transaction.DriverName() != database.DriverSQLite {
+		query += `
+	FOR UPDATE OF w`
+	}
+
+	var wsData struct {
+		ID               string `db:"workspace_id"`
+		AssetAmountLimit int32  `db:"asset_amount_limit"`
+		MaxX             int32  `db:"x_max"`
+		MaxY             int32  `db:"y_max"`
+		MaxZ             int32  `db:"z_max"`
+		AssetAmount      int    `db:"asset_amount"`
+		Writable         bool   `db:"writable"`
+	}
+	if err := database.NamedQueryStruct(ctx, str.logger, transaction, query, queryParams, &wsData); err != nil {
+		return Workspace{}, 0, err
+	}
+
+	if !wsData.Writable {
+		return Workspace{}, 0, database.ErrorForbidden
+	}
+
+	return Workspace{ID: wsData.ID, AssetAmountLimit: wsData.AssetAmountLimit, MaxX: wsData.MaxX, MaxY: wsData.MaxY,
+		MaxZ: wsData.MaxZ}, wsData.AssetAmount, nil
+}
+
+// checkPlacement reports whether asset can be added to wsData which already holds assetAmount Asset entities.
+// If the placement is not allowed, the function returns ErrorAssetLimitExceeded or ErrorAssetOutOfBounds.
+func checkPlacement(wsData Workspace, assetAmount int, asset Asset) error {
+	if assetAmount >= int(wsData.AssetAmountLimit) {
+		return ErrorAssetLimitExceeded
+	}
+
+	if !fitsInto(asset.X, asset.Width, wsData.MaxX) || !fitsInto(asset.Y, asset.Height, wsData.MaxY) ||
+		!fitsInto(asset.Z, asset.Length, wsData.MaxZ) {
+		return ErrorAssetOutOfBounds
+	}
+
+	return nil
+}
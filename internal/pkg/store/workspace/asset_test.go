@@ -0,0 +1,82 @@
+package workspace
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/SKorolchuk/dpio-workspace/internal/pkg/infra/database"
+)
+
+func TestMemoryStoreCreateAsset(t *testing.T) {
+	projects := newTestProjects(t)
+	str := NewMemoryStore(projects.store)
+	ctx := context.Background()
+
+	ws := newTestWorkspace(projects.team.ID, "Team")
+	ws.MaxZ = 10
+	team := mustCreateWorkspace(t, str, ownerID, ws, baseDate)
+	public := mustCreateWorkspace(t, str, ownerID, newTestWorkspace(projects.public.ID, "Public"), baseDate)
+	archived := mustCreateWorkspace(t, str, ownerID, newTestWorkspace(projects.team.ID, "Archived"), baseDate)
+	deleted := mustCreateWorkspace(t, str, ownerID, newTestWorkspace(projects.team.ID, "Deleted"), baseDate)
+	ws = newTestWorkspace(projects.team.ID, "Full")
+	ws.AssetAmountLimit = 1
+	ws.MaxZ = 10
+	full := mustCreateWorkspace(t, str, ownerID, ws, baseDate)
+
+	if err := str.ArchiveWorkspace(ctx, claimsOf(ownerID), archived.ID, baseDate); err != nil {
+		t.Fatalf("ArchiveWorkspace() error = %v", err)
+	}
+	if err := str.DeleteWorkspace(ctx, claimsOf(ownerID), deleted.ID, baseDate); err != nil {
+		t.Fatalf("DeleteWorkspace() error = %v", err)
+	}
+	if _, err := str.CreateAsset(ctx, claimsOf(ownerID), newTestAsset(full.ID), baseDate); err != nil {
+		t.Fatalf("CreateAsset() error = %v", err)
+	}
+
+	outOfBounds := newTestAsset(team.ID)
+	outOfBounds.X = 90
+
+	tests := []struct {
+		name    string
+		userId  string
+		asset   NewAsset
+		wantErr error
+	}{
+		{name: "writer of the project", userId: writerID, asset: newTestAsset(team.ID)},
+		{name: "missing workspace", userId: ownerID, asset: newTestAsset(missingID), wantErr: database.ErrorNotFound},
+		{name: "hidden project", userId: strangerID, asset: newTestAsset(team.ID), wantErr: database.ErrorNotFound},
+		{name: "readable project without write role", userId: writerID, asset: newTestAsset(public.ID),
+			wantErr: database.ErrorForbidden},
+		{name: "archived workspace", userId: ownerID, asset: newTestAsset(archived.ID),
+			wantErr: database.ErrorNotFound},
+		{name: "deleted workspace", userId: ownerID, asset: newTestAsset(deleted.ID), wantErr: database.ErrorNotFound},
+		{name: "asset amount limit", userId: ownerID, asset: newTestAsset(full.ID), wantErr: ErrorAssetLimitExceeded},
+		{name: "out of bounds", userId: ownerID, asset: outOfBounds, wantErr: ErrorAssetOutOfBounds},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := str.CreateAsset(ctx, claimsOf(test.userId), test.asset, baseDate.Add(time.Hour))
+			if !errors.Is(err, test.wantErr) {
+				t.Errorf("CreateAsset() error = %v, want %v", err, test.wantErr)
+			}
+		})
+	}
+}
+
+// newTestAsset describes Asset entity of 20x20x1 size placed at (10, 10, 1) of the workspace.
+func newTestAsset(wsId string) NewAsset {
+	return NewAsset{
+		WorkspaceID: wsId,
+		AssetRefID:  "sticker",
+		X:           10,
+		Y:           10,
+		Z:           1,
+		Scale:       1,
+		Height:      20,
+		Width:       20,
+		Length:      1,
+	}
+}
@@ -0,0 +1,254 @@
+package workspace
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/SKorolchuk/dpio-workspace/internal/pkg/infra/auth"
+	"github.com/SKorolchuk/dpio-workspace/internal/pkg/infra/database"
+	"github.com/SKorolchuk/dpio-workspace/internal/pkg/infra/uuid"
+	"github.com/SKorolchuk/dpio-workspace/internal/pkg/infra/validation"
+	"github.com/SKorolchuk/dpio-workspace/internal/pkg/store/project"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// ErrorAssetLimitExceeded is returned when Workspace entity would hold more Asset entities than its limit allows.
+var ErrorAssetLimitExceeded = errors.New("asset amount limit of the workspace is exceeded")
+
+// ErrorFlatStem is returned when Asset entities of a 3D environment with depth are moved into a workspace of a flat
+// stem. Only 3D environments have depth, workspaces of other stems have a single layer.
+var ErrorFlatStem = errors.New("stem of the workspace does not support assets with depth")
+
+// CloneWorkspace copies Workspace entity with wsId identifier and all of its Asset entities under the target name in
+// a single transaction. Copies get new identifiers and keep references to external assets.
+// The source workspace must be readable by the claims subject, the target project must be writable by the subject
+// and must not be archived. 3D environment with assets of non-zero depth cannot be copied into a flat stem, and asset
+// amount limit of the copy must fit all copied assets.
+// If error occurs, the method can return ErrorFlatStem, ErrorAssetLimitExceeded, validation or database errors.
+func (str Store) CloneWorkspace(ctx context.Context, claims auth.Claims, wsId string, target CloneTarget,
+	now time.Time) (Workspace, error) {
+	if err := uuid.Validate(wsId); err != nil {
+		return Workspace{}, database.ErrorInvalidIdentifier
+	}
+
+	if err := validation.Check(ctx, target); err != nil {
+		return Workspace{}, fmt.Errorf("error during data validation of CloneTarget: %w", err)
+	}
+
+	var wsCopy Workspace
+	err := database.WithTransaction(ctx, str.cluster.Primary(), func(transaction *sqlx.Tx) error {
+		wsData, err := str.queryReadableWorkspace(ctx, transaction, claims, wsId)
+		if err != nil {
+			return fmt.Errorf("error during search of Workspace entity -> id={%q}: %w", wsId, err)
+		}
+
+		wsCopy = cloneOf(claims, wsData, target, now)
+		if err := str.checkWritableProject(ctx, transaction, claims, wsCopy.ProjectID); err != nil {
+			return fmt.Errorf("error during search of Project entity -> id={%q}: %w", wsCopy.ProjectID, err)
+		}
+
+		assetCollection, err := str.queryWorkspaceAssets(ctx, transaction, wsId)
+		if err != nil {
+			return fmt.Errorf("error during search of Asset entities -> workspace_id={%q}: %w", wsId, err)
+		}
+
+		if err := checkClone(wsData, wsCopy, assetCollection); err != nil {
+			return fmt.Errorf("error during clone of Workspace entity -> id={%q}: %w", wsId, err)
+		}
+
+		if err := str.insertWorkspace(ctx, transaction, wsCopy); err != nil {
+			return fmt.Errorf("error during create of Workspace entity copy -> id={%q}: %w", wsId, err)
+		}
+
+		for _, assetData := range assetCollection {
+			assetCopy := assetCopyOf(claims, assetData, wsCopy.ID, now)
+			if err := str.insertAsset(ctx, transaction, assetCopy); err != nil {
+				return fmt.Errorf("error during create of Asset entity copy -> id={%q}: %w", assetData.ID, err)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return Workspace{}, err
+	}
+
+	return wsCopy, nil
+}
+
+// insertWorkspace adds wsData to the database through the connection.
+func (str Store) insertWorkspace(ctx context.Context, connection sqlx.ExtContext, wsData Workspace) error {
+	const query = `
+	INSERT INTO WORKSPACE
+		(workspace_id, project_id, stem_id, name, description, asset_amount_limit, x_max, y_max, z_max,
+			date_created, created_by_user_id, date_updated, updated_by_user_id)
+	VALUES (:workspace_id, :project_id, :stem_id, :name, :description, :asset_amount_limit, :x_max, :y_max,
+				:z_max, :date_created, :created_by_user_id, :date_updated, :updated_by_user_id)`
+
+	return database.NamedExecContext(ctx, str.logger, connection, query, wsData)
+}
+
+// insertAsset adds assetData to the database through the connection.
+func (str Store) insertAsset(ctx context.Context, connection sqlx.ExtContext, assetData Asset) error {
+	const query = `
+	INSERT INTO ASSET
+		(asset_id, workspace_id, asset_external_ref_id, position_x, position_y, position_z, scale, height_by_y,
+			width_by_x, length_by_z, date_created, created_by_user_id, date_updated, updated_by_user_id)
+	VALUES
+		(:asset_id, :workspace_id, :asset_external_ref_id, :position_x, :position_y, :position_z, :scale, :height_by_y,
+			:width_by_x, :length_by_z, :date_created, :created_by_user_id, :date_updated, :updated_by_user_id)`
+
+	return database.NamedExecContext(ctx, str.logger, connection, query, assetData)
+}
+
+// queryReadableWorkspace looking for live Workspace entity with wsId identifier whose project is readable by the
+// claims subject, other workspaces are reported with database.ErrorNotFound.
+func (str Store) queryReadableWorkspace(ctx context.Context, connection sqlx.ExtContext, claims auth.Claims,
+	wsId string) (Workspace, error) {
+	queryParams := struct {
+		project.Visibility
+		WorkspaceID string `db:"workspace_id"`
+	}{
+		Visibility:  project.NewVisibility(claims),
+		WorkspaceID: wsId,
+	}
+
+	const query = `
+	SELECT
+		w.workspace_id,
+		w.project_id,
+		w.stem_id,
+		w.name,
+		w.description,
+		w.asset_amount_limit,
+		w.x_max,
+		w.y_max,
+		w.z_max,
+		w.date_created,
+		w.created_by_user_id,
+		w.date_updated,
+		w.updated_by_user_id,
+		w.date_archived,
+		w.date_deleted
+	FROM
+		WORKSPACE AS w
+		JOIN PROJECT AS p ON p.project_id = w.project_id
+	WHERE
+		w.workspace_id = :workspace_id AND w.date_deleted IS NULL AND ` + project.ReadableCondition
+
+	var wsData Workspace
+	if err := database.NamedQueryStruct(ctx, str.logger, connection, query, queryParams, &wsData); err != nil {
+		return Workspace{}, err
+	}
+
+	return wsData, nil
+}
+
+// workspaceAssetsQuery selects all Asset entities of Workspace entity with ascending order by creation date and
+// identifier fields.
+const workspaceAssetsQuery = `
+	SELECT
+		a.asset_id,
+		a.workspace_id,
+		a.asset_external_ref_id,
+		a.position_x,
+		a.position_y,
+		a.position_z,
+		a.scale,
+		a.height_by_y,
+		a.width_by_x,
+		a.length_by_z,
+		a.date_created,
+		a.created_by_user_id,
+		a.date_updated,
+		a.updated_by_user_id
+	FROM
+		ASSET AS a
+	WHERE
+		a.workspace_id = :workspace_id
+	ORDER BY a.date_created, a.asset_id`
+
+// queryWorkspaceAssets looking for all Asset entities of Workspace entity with ascending order by creation date and
+// identifier fields.
+func (str Store) queryWorkspaceAssets(ctx context.Context, transaction *sqlx.Tx, wsId string) ([]Asset, error) {
+	queryParams := struct {
+		WorkspaceID string `db:"workspace_id"`
+	}{
+		WorkspaceID: wsId,
+	}
+
+	var assetCollection []Asset
+	if err := database.NamedQuerySlice(ctx, str.logger, transaction, workspaceAssetsQuery, queryParams,
+		&assetCollection); err != nil {
+		return nil, err
+	}
+
+	return assetCollection, nil
+}
+
+// cloneOf returns a copy of wsData described by target which is created by the claims subject at now.
+func cloneOf(claims auth.Claims, wsData Workspace, target CloneTarget, now time.Time) Workspace {
+	wsCopy := Workspace{
+		ID:               uuid.Generate(),
+		ProjectID:        wsData.ProjectID,
+		StemID:           wsData.StemID,
+		Name:             target.Name,
+		Description:      wsData.Description,
+		AssetAmountLimit: wsData.AssetAmountLimit,
+		MaxX:             wsData.MaxX,
+		MaxY:             wsData.MaxY,
+		MaxZ:             wsData.MaxZ,
+		DateCreated:      now,
+		CreatedByUser:    claims.Subject,
+		DateUpdated:      now,
+		UpdatedByUser:    claims.Subject,
+	}
+
+	if target.ProjectID != "" {
+		wsCopy.ProjectID = target.ProjectID
+	}
+	if target.StemID != "" {
+		wsCopy.StemID = target.StemID
+	}
+	if target.AssetAmountLimit != nil {
+		wsCopy.AssetAmountLimit = *target.AssetAmountLimit
+	}
+
+	return wsCopy
+}
+
+// assetCopyOf returns a copy of assetData placed into Workspace entity with wsId identifier which is created by the
+// claims subject at now.
+func assetCopyOf(claims auth.Claims, assetData Asset, wsId string, now time.Time) Asset {
+	assetData.ID = uuid.Generate()
+	assetData.WorkspaceID = wsId
+	assetData.DateCreated = now
+	assetData.CreatedByUser = claims.Subject
+	assetData.DateUpdated = now
+	assetData.UpdatedByUser = claims.Subject
+
+	return assetData
+}
+
+// checkClone reports whether assetCollection of wsData can be copied into wsCopy.
+// If the copy is not allowed, the function returns ErrorAssetLimitExceeded or ErrorFlatStem.
+func checkClone(wsData Workspace, wsCopy Workspace, assetCollection []Asset) error {
+	if len(assetCollection) > int(wsCopy.AssetAmountLimit) {
+		return ErrorAssetLimitExceeded
+	}
+
+	isFlat := wsCopy.StemID == StickerWorkspaceType || wsCopy.StemID == Environment2DWorkspaceType
+	if wsData.StemID != Environment3DWorkspaceType || !isFlat {
+		return nil
+	}
+	for _, assetData := range assetCollection {
+		if assetData.Length > 0 {
+			return ErrorFlatStem
+		}
+	}
+
+	return nil
+}
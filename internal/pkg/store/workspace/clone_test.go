@@ -0,0 +1,122 @@
+package workspace
+
+import (
+	"testing"
+)
+
+func TestCheckClone(t *testing.T) {
+	workspaceOf := func(stemId string, assetAmountLimit int32) Workspace {
+		return Workspace{StemID: stemId, AssetAmountLimit: assetAmountLimit, MaxX: 100, MaxY: 100, MaxZ: 100}
+	}
+	flat := []Asset{{Width: 1, Height: 1}, {Width: 2, Height: 2}}
+	deep := []Asset{{Width: 1, Height: 1}, {Width: 2, Height: 2, Length: 3}}
+
+	tests := []struct {
+		name            string
+		wsData          Workspace
+		wsCopy          Workspace
+		assetCollection []Asset
+		wantErr         error
+	}{
+		{
+			name:            "same stem",
+			wsData:          workspaceOf(Environment3DWorkspaceType, 2),
+			wsCopy:          workspaceOf(Environment3DWorkspaceType, 2),
+			assetCollection: deep,
+		},
+		{
+			name:            "empty workspace",
+			wsData:          workspaceOf(StickerWorkspaceType, 2),
+			wsCopy:          workspaceOf(StickerWorkspaceType, 1),
+			assetCollection: nil,
+		},
+		{
+			name:            "limit is exceeded",
+			wsData:          workspaceOf(StickerWorkspaceType, 2),
+			wsCopy:          workspaceOf(StickerWorkspaceType, 1),
+			assetCollection: flat,
+			wantErr:         ErrorAssetLimitExceeded,
+		},
+		{
+			name:            "flat 3D environment into sticker pane",
+			wsData:          workspaceOf(Environment3DWorkspaceType, 2),
+			wsCopy:          workspaceOf(StickerWorkspaceType, 2),
+			assetCollection: flat,
+		},
+		{
+			name:            "3D environment with depth into sticker pane",
+			wsData:          workspaceOf(Environment3DWorkspaceType, 2),
+			wsCopy:          workspaceOf(StickerWorkspaceType, 2),
+			assetCollection: deep,
+			wantErr:         ErrorFlatStem,
+		},
+		{
+			name:            "3D environment with depth into 2D environment",
+			wsData:          workspaceOf(Environment3DWorkspaceType, 2),
+			wsCopy:          workspaceOf(Environment2DWorkspaceType, 2),
+			assetCollection: deep,
+			wantErr:         ErrorFlatStem,
+		},
+		{
+			name:            "2D environment into 3D environment",
+			wsData:          workspaceOf(Environment2DWorkspaceType, 2),
+			wsCopy:          workspaceOf(Environment3DWorkspaceType, 2),
+			assetCollection: flat,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if err := checkClone(test.wsData, test.wsCopy, test.assetCollection); err != test.wantErr {
+				t.Errorf("checkClone() error = %v, want %v", err, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestCloneOf(t *testing.T) {
+	limit := int32(5)
+	wsData := Workspace{ID: missingID, ProjectID: ownerID, StemID: StickerWorkspaceType, Name: "Source",
+		Description: "Description", AssetAmountLimit: 10, MaxX: 1, MaxY: 2, MaxZ: 3, CreatedByUser: ownerID}
+
+	tests := []struct {
+		name   string
+		target CloneTarget
+		want   Workspace
+	}{
+		{
+			name:   "source settings",
+			target: CloneTarget{Name: "Copy"},
+			want: Workspace{ProjectID: ownerID, StemID: StickerWorkspaceType, Name: "Copy",
+				Description: "Description", AssetAmountLimit: 10, MaxX: 1, MaxY: 2, MaxZ: 3},
+		},
+		{
+			name: "target settings",
+			target: CloneTarget{Name: "Copy", ProjectID: writerID, StemID: Environment3DWorkspaceType,
+				AssetAmountLimit: &limit},
+			want: Workspace{ProjectID: writerID, StemID: Environment3DWorkspaceType, Name: "Copy",
+				Description: "Description", AssetAmountLimit: 5, MaxX: 1, MaxY: 2, MaxZ: 3},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			wsCopy := cloneOf(claimsOf(writerID), wsData, test.target, baseDate)
+			if wsCopy.ID == "" || wsCopy.ID == wsData.ID {
+				t.Errorf("cloneOf() id = %q, want a new identifier", wsCopy.ID)
+			}
+			if wsCopy.CreatedByUser != writerID || wsCopy.UpdatedByUser != writerID {
+				t.Errorf("cloneOf() authors = %q, %q, want %q", wsCopy.CreatedByUser, wsCopy.UpdatedByUser, writerID)
+			}
+			if !wsCopy.DateCreated.Equal(baseDate) || !wsCopy.DateUpdated.Equal(baseDate) {
+				t.Errorf("cloneOf() dates = %v, %v, want %v", wsCopy.DateCreated, wsCopy.DateUpdated, baseDate)
+			}
+
+			test.want.ID, test.want.CreatedByUser, test.want.UpdatedByUser = wsCopy.ID, writerID, writerID
+			test.want.DateCreated, test.want.DateUpdated = baseDate, baseDate
+			if wsCopy != test.want {
+				t.Errorf("cloneOf() = %+v, want %+v", wsCopy, test.want)
+			}
+		})
+	}
+}
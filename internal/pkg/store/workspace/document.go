@@ -0,0 +1,376 @@
+package workspace
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/SKorolchuk/dpio-workspace/internal/pkg/infra/auth"
+	"github.com/SKorolchuk/dpio-workspace/internal/pkg/infra/database"
+	"github.com/SKorolchuk/dpio-workspace/internal/pkg/infra/uuid"
+	"github.com/SKorolchuk/dpio-workspace/internal/pkg/infra/validation"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// ErrorDocumentVersion is returned when Document of an unsupported version is imported.
+var ErrorDocumentVersion = errors.New("document version is not supported")
+
+// ExportWorkspace writes Document of Workspace entity with wsId identifier into w. Asset entities are streamed
+// row by row with ascending order by creation date and identifier fields, so large workspaces are not loaded
+// into the memory. The workspace must be readable by the claims subject.
+// Nothing is written if the workspace is not found.
+// If error occurs, the method can return database errors or errors of w.
+func (str Store) ExportWorkspace(ctx context.Context, claims auth.Claims, wsId string, w io.Writer) error {
+	if err := uuid.Validate(wsId); err != nil {
+		return database.ErrorInvalidIdentifier
+	}
+
+	connection := str.cluster.Reader(ctx)
+	wsData, err := str.queryReadableWorkspace(ctx, connection, claims, wsId)
+	if err != nil {
+		return fmt.Errorf("error during search of Workspace entity -> id={%q}: %w", wsId, err)
+	}
+
+	stem, err := str.QueryStemByID(ctx, wsData.StemID)
+	if err != nil {
+		return fmt.Errorf("error during search of Stem entity -> id={%q}: %w", wsData.StemID, err)
+	}
+
+	queryParams := struct {
+		WorkspaceID string `db:"workspace_id"`
+	}{
+		WorkspaceID: wsId,
+	}
+
+	return writeDocument(w, wsData, stem, func(write func(Asset) error) error {
+		var assetData Asset
+		err := database.NamedQueryEach(ctx, str.logger, connection, workspaceAssetsQuery, queryParams, &assetData,
+			func() error {
+				return write(assetData)
+			})
+		if err != nil {
+			return fmt.Errorf("error during export of Asset entities -> workspace_id={%q}: %w", wsId, err)
+		}
+
+		return nil
+	})
+}
+
+// ImportWorkspace creates Workspace entity with all Asset entities of doc in the project of options in a single
+// transaction. Imported entities get new identifiers and keep references to external assets.
+// The target project must be writable by the claims subject and must not be archived. Stem of the document is
+// matched by identifier and then by name.
+// Nothing is imported if the document has conflicts or options request a dry run, the report lists conflicts of
+// the document in both cases.
+// If error occurs, the method can return ErrorDocumentVersion, validation or database errors.
+func (str Store) ImportWorkspace(ctx context.Context, claims auth.Claims, doc Document, options ImportOptions,
+	now time.Time) (ImportReport, error) {
+	if err := checkDocument(ctx, doc, options); err != nil {
+		return ImportReport{}, err
+	}
+
+	report := ImportReport{DryRun: options.DryRun, Conflicts: []ImportConflict{}}
+	wsData := importedWorkspace(claims, doc, options, now)
+	err := database.WithTransaction(ctx, str.cluster.Primary(), func(transaction *sqlx.Tx) error {
+		if err := str.checkWritableProject(ctx, transaction, claims, options.ProjectID); err != nil {
+			return fmt.Errorf("error during search of Project entity -> id={%q}: %w", options.ProjectID, err)
+		}
+
+		stem, err := str.queryDocumentStem(ctx, transaction, doc.Stem)
+		switch {
+		case err == database.ErrorNotFound:
+			report.Conflicts = append(report.Conflicts, stemConflict(doc.Stem))
+		case err != nil:
+			return fmt.Errorf("error during search of Stem entity -> name={%q}: %w", doc.Stem.Name, err)
+		}
+		wsData.StemID = stem.ID
+
+		isTaken, err := str.isWorkspaceNameTaken(ctx, transaction, wsData.Name)
+		if err != nil {
+			return fmt.Errorf("error during search of Workspace entity -> name={%q}: %w", wsData.Name, err)
+		}
+		if isTaken {
+			report.Conflicts = append(report.Conflicts, nameConflict(wsData.Name))
+		}
+
+		report.Conflicts = append(report.Conflicts, documentConflicts(wsData, doc.Assets)...)
+		if len(report.Conflicts) > 0 || options.DryRun {
+			return nil
+		}
+
+		if err := str.insertWorkspace(ctx, transaction, wsData); err != nil {
+			return fmt.Errorf("error during import of Workspace entity -> name={%q}: %w", wsData.Name, err)
+		}
+
+		report.AssetIDs = make(map[string]string, len(doc.Assets))
+		for _, docAsset := range doc.Assets {
+			assetData := importedAsset(claims, docAsset, wsData.ID, now)
+			if err := str.insertAsset(ctx, transaction, assetData); err != nil {
+				return fmt.Errorf("error during import of Asset entity -> id={%q}: %w", docAsset.ID, err)
+			}
+			if docAsset.ID != "" {
+				report.AssetIDs[docAsset.ID] = assetData.ID
+			}
+		}
+		report.Workspace = &wsData
+
+		return nil
+	})
+	if err != nil {
+		return ImportReport{}, err
+	}
+
+	return report, nil
+}
+
+// queryDocumentStem looking for Stem entity with identifier of docStem, Stem entity with name of docStem is returned
+// when the identifier is not known.
+func (str Store) queryDocumentStem(ctx context.Context, connection sqlx.ExtContext, docStem Stem) (Stem, error) {
+	queryParams := struct {
+		StemID *string `db:"stem_id"`
+		Name   string  `db:"name"`
+	}{
+		Name: docStem.Name,
+	}
+	if err := uuid.Validate(docStem.ID); err == nil {
+		queryParams.StemID = &docStem.ID
+	}
+
+	const query = `
+	SELECT
+		s.stem_id,
+		s.name
+	FROM
+		STEM AS s
+	WHERE
+		s.stem_id = :stem_id OR s.name = :name
+	ORDER BY CASE WHEN s.stem_id = :stem_id THEN 0 ELSE 1 END
+	LIMIT 1`
+
+	var stem Stem
+	if err := database.NamedQueryStruct(ctx, str.logger, connection, query, queryParams, &stem); err != nil {
+		return Stem{}, err
+	}
+
+	return stem, nil
+}
+
+// isWorkspaceNameTaken reports whether any Workspace entity already uses the name.
+func (str Store) isWorkspaceNameTaken(ctx context.Context, connection sqlx.ExtContext, name string) (bool, error) {
+	queryParams := struct {
+		Name string `db:"name"`
+	}{
+		Name: name,
+	}
+
+	const query = `
+	SELECT
+		w.workspace_id
+	FROM
+		WORKSPACE AS w
+	WHERE
+		w.name = :name
+	LIMIT 1`
+
+	var wsData struct {
+		ID string `db:"workspace_id"`
+	}
+	err := database.NamedQueryStruct(ctx, str.logger, connection, query, queryParams, &wsData)
+	if err != nil {
+		if err == database.ErrorNotFound {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	return true, nil
+}
+
+// checkDocument validates version and data of doc together with import options.
+func checkDocument(ctx context.Context, doc Document, options ImportOptions) error {
+	if doc.Version != DocumentVersion {
+		return ErrorDocumentVersion
+	}
+
+	if err := validation.Check(ctx, options); err != nil {
+		return fmt.Errorf("error during data validation of ImportOptions: %w", err)
+	}
+
+	if err := validation.Check(ctx, doc); err != nil {
+		return fmt.Errorf("error during data validation of Document: %w", err)
+	}
+
+	return nil
+}
+
+// writeDocument writes Document of wsData and stem into w, eachAsset should call write for every Asset entity of
+// the workspace. Assets are written as soon as they are passed, so the document is never kept in the memory.
+func writeDocument(w io.Writer, wsData Workspace, stem Stem, eachAsset func(write func(Asset) error) error) error {
+	wsContent, err := json.Marshal(documentWorkspaceOf(wsData))
+	if err != nil {
+		return err
+	}
+
+	stemContent, err := json.Marshal(stem)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(w, `{"version":%d,"workspace":%s,"stem":%s,"assets":[`, DocumentVersion, wsContent,
+		stemContent)
+	if err != nil {
+		return err
+	}
+
+	separator := ""
+	err = eachAsset(func(assetData Asset) error {
+		assetContent, err := json.Marshal(documentAssetOf(assetData))
+		if err != nil {
+			return err
+		}
+
+		if _, err := io.WriteString(w, separator); err != nil {
+			return err
+		}
+		separator = ","
+
+		_, err = w.Write(assetContent)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = io.WriteString(w, "]}\n")
+
+	return err
+}
+
+// documentWorkspaceOf returns representation of wsData inside Document.
+func documentWorkspaceOf(wsData Workspace) DocumentWorkspace {
+	return DocumentWorkspace{
+		ID:               wsData.ID,
+		Name:             wsData.Name,
+		Description:      wsData.Description,
+		AssetAmountLimit: wsData.AssetAmountLimit,
+		MaxX:             wsData.MaxX,
+		MaxY:             wsData.MaxY,
+		MaxZ:             wsData.MaxZ,
+	}
+}
+
+// documentAssetOf returns representation of assetData inside Document.
+func documentAssetOf(assetData Asset) DocumentAsset {
+	return DocumentAsset{
+		ID:         assetData.ID,
+		AssetRefID: assetData.AssetRefID,
+		X:          assetData.X,
+		Y:          assetData.Y,
+		Z:          assetData.Z,
+		Scale:      assetData.Scale,
+		Height:     assetData.Height,
+		Width:      assetData.Width,
+		Length:     assetData.Length,
+	}
+}
+
+// importedWorkspace returns Workspace entity with new identifier which is described by doc and options and is
+// created by the claims subject at now. Stem of the workspace is resolved separately.
+func importedWorkspace(claims auth.Claims, doc Document, options ImportOptions, now time.Time) Workspace {
+	wsData := Workspace{
+		ID:               uuid.Generate(),
+		ProjectID:        options.ProjectID,
+		Name:             doc.Workspace.Name,
+		Description:      doc.Workspace.Description,
+		AssetAmountLimit: doc.Workspace.AssetAmountLimit,
+		MaxX:             doc.Workspace.MaxX,
+		MaxY:             doc.Workspace.MaxY,
+		MaxZ:             doc.Workspace.MaxZ,
+		DateCreated:      now,
+		CreatedByUser:    claims.Subject,
+		DateUpdated:      now,
+		UpdatedByUser:    claims.Subject,
+	}
+
+	if options.Name != "" {
+		wsData.Name = options.Name
+	}
+
+	return wsData
+}
+
+// importedAsset returns Asset entity with new identifier which is described by docAsset, placed into Workspace
+// entity with wsId identifier and created by the claims subject at now.
+func importedAsset(claims auth.Claims, docAsset DocumentAsset, wsId string, now time.Time) Asset {
+	return Asset{
+		ID:            uuid.Generate(),
+		WorkspaceID:   wsId,
+		AssetRefID:    docAsset.AssetRefID,
+		X:             docAsset.X,
+		Y:             docAsset.Y,
+		Z:             docAsset.Z,
+		Scale:         docAsset.Scale,
+		Height:        docAsset.Height,
+		Width:         docAsset.Width,
+		Length:        docAsset.Length,
+		DateCreated:   now,
+		CreatedByUser: claims.Subject,
+		DateUpdated:   now,
+		UpdatedByUser: claims.Subject,
+	}
+}
+
+// documentConflicts returns conflicts of document assets with asset amount limit and bounds of wsData.
+func documentConflicts(wsData Workspace, docAssets []DocumentAsset) []ImportConflict {
+	var conflicts []ImportConflict
+	if len(docAssets) > int(wsData.AssetAmountLimit) {
+		conflicts = append(conflicts, ImportConflict{
+			Code:  ConflictAssetLimitExceeded,
+			Field: "assets",
+			Message: fmt.Sprintf("document has %d assets while asset amount limit of the workspace is %d",
+				len(docAssets), wsData.AssetAmountLimit),
+		})
+	}
+
+	for i, docAsset := range docAssets {
+		if !fitsInto(docAsset.X, docAsset.Width, wsData.MaxX) || !fitsInto(docAsset.Y, docAsset.Height, wsData.MaxY) ||
+			!fitsInto(docAsset.Z, docAsset.Length, wsData.MaxZ) {
+			conflicts = append(conflicts, ImportConflict{
+				Code:  ConflictOutOfBounds,
+				Field: fmt.Sprintf("assets[%d]", i),
+				Message: fmt.Sprintf("asset %q does not fit into workspace bounds %dx%dx%d", docAsset.ID,
+					wsData.MaxX, wsData.MaxY, wsData.MaxZ),
+			})
+		}
+	}
+
+	return conflicts
+}
+
+// fitsInto reports whether a segment with the position and size fits into [0, max] range of a workspace axis.
+func fitsInto(position int32, size int32, max int32) bool {
+	return int64(position)+int64(size) <= int64(max)
+}
+
+// stemConflict returns conflict of Document whose stem is not known.
+func stemConflict(docStem Stem) ImportConflict {
+	return ImportConflict{
+		Code:    ConflictUnknownStem,
+		Field:   "stem",
+		Message: fmt.Sprintf("stem %q with identifier %q is not known", docStem.Name, docStem.ID),
+	}
+}
+
+// nameConflict returns conflict of Document whose workspace name is already taken.
+func nameConflict(name string) ImportConflict {
+	return ImportConflict{
+		Code:    ConflictNameTaken,
+		Field:   "workspace.name",
+		Message: fmt.Sprintf("workspace name %q is already taken", name),
+	}
+}
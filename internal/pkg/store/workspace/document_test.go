@@ -0,0 +1,110 @@
+package workspace
+
+import (
+	"context"
+	"testing"
+)
+
+func TestDocumentConflicts(t *testing.T) {
+	wsData := Workspace{AssetAmountLimit: 2, MaxX: 10, MaxY: 10, MaxZ: 0}
+	asset := func(x int32, width int32, z int32, length int32) DocumentAsset {
+		return DocumentAsset{AssetRefID: "ref", X: x, Width: width, Y: 0, Height: 10, Z: z, Length: length}
+	}
+
+	tests := []struct {
+		name      string
+		docAssets []DocumentAsset
+		want      []ImportConflict
+	}{
+		{
+			name: "no assets",
+		},
+		{
+			name:      "assets touch the bounds",
+			docAssets: []DocumentAsset{asset(0, 10, 0, 0), asset(10, 0, 0, 0)},
+		},
+		{
+			name:      "asset amount limit is exceeded",
+			docAssets: []DocumentAsset{asset(0, 1, 0, 0), asset(0, 1, 0, 0), asset(0, 1, 0, 0)},
+			want:      []ImportConflict{{Code: ConflictAssetLimitExceeded, Field: "assets"}},
+		},
+		{
+			name:      "asset is out of bounds",
+			docAssets: []DocumentAsset{asset(0, 1, 0, 0), asset(5, 6, 0, 0)},
+			want:      []ImportConflict{{Code: ConflictOutOfBounds, Field: "assets[1]"}},
+		},
+		{
+			name:      "asset has depth in a flat workspace",
+			docAssets: []DocumentAsset{asset(0, 1, 0, 1)},
+			want:      []ImportConflict{{Code: ConflictOutOfBounds, Field: "assets[0]"}},
+		},
+		{
+			name:      "sum of position and size overflows int32",
+			docAssets: []DocumentAsset{asset(2147483647, 2147483647, 0, 0)},
+			want:      []ImportConflict{{Code: ConflictOutOfBounds, Field: "assets[0]"}},
+		},
+		{
+			name: "several conflicts",
+			docAssets: []DocumentAsset{asset(11, 0, 0, 0), asset(0, 1, 0, 0),
+				asset(0, 1, 1, 0)},
+			want: []ImportConflict{{Code: ConflictAssetLimitExceeded, Field: "assets"},
+				{Code: ConflictOutOfBounds, Field: "assets[0]"}, {Code: ConflictOutOfBounds, Field: "assets[2]"}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			conflicts := documentConflicts(wsData, test.docAssets)
+			if len(conflicts) != len(test.want) {
+				t.Fatalf("documentConflicts() = %+v, want %+v", conflicts, test.want)
+			}
+			for i, conflict := range conflicts {
+				if conflict.Code != test.want[i].Code || conflict.Field != test.want[i].Field {
+					t.Errorf("documentConflicts()[%d] = %+v, want %+v", i, conflict, test.want[i])
+				}
+				if conflict.Message == "" {
+					t.Errorf("documentConflicts()[%d] has no message", i)
+				}
+			}
+		})
+	}
+}
+
+func TestCheckDocument(t *testing.T) {
+	valid := Document{
+		Version:   DocumentVersion,
+		Workspace: DocumentWorkspace{Name: "Imported", AssetAmountLimit: 1, MaxX: 1, MaxY: 1},
+		Assets:    []DocumentAsset{{AssetRefID: "ref"}},
+	}
+	options := ImportOptions{ProjectID: ownerID}
+
+	unknownVersion := valid
+	unknownVersion.Version = DocumentVersion + 1
+	negativePosition := valid
+	negativePosition.Assets = []DocumentAsset{{AssetRefID: "ref", X: -1}}
+
+	tests := []struct {
+		name    string
+		doc     Document
+		options ImportOptions
+		wantErr bool
+	}{
+		{name: "valid document", doc: valid, options: options},
+		{name: "unknown version", doc: unknownVersion, options: options, wantErr: true},
+		{name: "invalid asset", doc: negativePosition, options: options, wantErr: true},
+		{name: "invalid project", doc: valid, options: ImportOptions{ProjectID: "1"}, wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := checkDocument(context.Background(), test.doc, test.options)
+			if (err != nil) != test.wantErr {
+				t.Errorf("checkDocument() error = %v, want error %t", err, test.wantErr)
+			}
+		})
+	}
+
+	if err := checkDocument(context.Background(), unknownVersion, options); err != ErrorDocumentVersion {
+		t.Errorf("checkDocument() error = %v, want %v", err, ErrorDocumentVersion)
+	}
+}
@@ -0,0 +1,1162 @@
+package workspace
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/SKorolchuk/dpio-workspace/internal/pkg/infra/auth"
+	"github.com/SKorolchuk/dpio-workspace/internal/pkg/infra/database"
+	"github.com/SKorolchuk/dpio-workspace/internal/pkg/infra/uuid"
+	"github.com/SKorolchuk/dpio-workspace/internal/pkg/infra/validation"
+	"github.com/SKorolchuk/dpio-workspace/internal/pkg/store/project"
+)
+
+// MemoryStore represents a concurrency-safe in-memory point of access to Workspace, Asset and Stem entities.
+// It follows the semantics of Store and is intended for unit tests and offline demo mode.
+type MemoryStore struct {
+	mutex      sync.RWMutex
+	projects   ProjectPolicy
+	workspaces map[string]Workspace
+	assets     map[string]Asset
+	stems      map[string]Stem
+}
+
+// NewMemoryStore creates an instance of MemoryStore with predefined Stem entities.
+// Operations consult projects to decide whether Project entity of a workspace is readable and whether
+// the user owns it.
+func NewMemoryStore(projects ProjectPolicy) *MemoryStore {
+	return &MemoryStore{
+		projects:   projects,
+		workspaces: make(map[string]Workspace),
+		assets:     make(map[string]Asset),
+		stems: map[string]Stem{
+			StickerWorkspaceType:       {ID: StickerWorkspaceType, Name: "Sticker Pane"},
+			Environment2DWorkspaceType: {ID: Environment2DWorkspaceType, Name: "2D Environment"},
+			Environment3DWorkspaceType: {ID: Environment3DWorkspaceType, Name: "3D Environment"},
+		},
+	}
+}
+
+// CreateWorkspace adds new Workspace entity to the memory.
+// The project of the workspace must be writable by the claims subject and must not be archived.
+// If creation is successful, the method returns Workspace entity.
+// Can return validation or database errors.
+func (str *MemoryStore) CreateWorkspace(ctx context.Context, claims auth.Claims, ws NewWorkspace,
+	now time.Time) (Workspace, error) {
+	if err := validation.Check(ctx, ws); err != nil {
+		return Workspace{}, fmt.Errorf("error during data validation of Workspace entity: %w", err)
+	}
+
+	wsData := Workspace{
+		ID:               uuid.Generate(),
+		ProjectID:        ws.ProjectID,
+		StemID:           ws.StemID,
+		Name:             ws.Name,
+		Description:      ws.Description,
+		AssetAmountLimit: ws.AssetAmountLimit,
+		MaxX:             ws.MaxX,
+		MaxY:             ws.MaxY,
+		MaxZ:             ws.MaxZ,
+		DateCreated:      now,
+		DateUpdated:      now,
+		CreatedByUser:    claims.Subject,
+		UpdatedByUser:    claims.Subject,
+	}
+
+	if err := str.checkWritableProject(ctx, claims, wsData.ProjectID); err != nil {
+		return Workspace{}, fmt.Errorf("error during search of Project entity -> id={%q}: %w", wsData.ProjectID, err)
+	}
+
+	str.mutex.Lock()
+	defer str.mutex.Unlock()
+
+	if _, found := str.stems[wsData.StemID]; !found {
+		return Workspace{}, fmt.Errorf("error during create of new Workspace entity: %w",
+			database.ErrorInvalidReference)
+	}
+
+	if str.workspaceNameTaken(wsData.Name, wsData.ID) {
+		return Workspace{}, fmt.Errorf("error during create of new Workspace entity: %w", database.ErrorConflict)
+	}
+
+	str.workspaces[wsData.ID] = wsData
+
+	return wsData, nil
+}
+
+// UpdateWorkspace change existing Workspace entity in the memory.
+// If error occurs, the method can return validation or database errors.
+func (str *MemoryStore) UpdateWorkspace(ctx context.Context, claims auth.Claims, wsId string, ws UpdateWorkspace,
+	now time.Time) error {
+	if err := uuid.Validate(wsId); err != nil {
+		return database.ErrorInvalidIdentifier
+	}
+
+	if err := validation.Check(ctx, ws); err != nil {
+		return fmt.Errorf("error during data validation of Workspace entity: %w", err)
+	}
+
+	canWrite, err := str.canWriteProjectOf(ctx, claims, wsId)
+	if err != nil {
+		return fmt.Errorf("error during search of Project entity -> workspace_id={%q}: %w", wsId, err)
+	}
+
+	str.mutex.Lock()
+	defer str.mutex.Unlock()
+
+	wsData, found := str.liveWorkspace(wsId)
+	if !found {
+		return fmt.Errorf("error during search of Workspace entity -> id={%q}: %w", wsId, database.ErrorNotFound)
+	}
+
+	if !canWrite {
+		return database.ErrorForbidden
+	}
+
+	if ws.Name != nil {
+		wsData.Name = *ws.Name
+	}
+	if ws.Description != nil {
+		wsData.Description = *ws.Description
+	}
+	if ws.AssetAmountLimit != nil {
+		wsData.AssetAmountLimit = *ws.AssetAmountLimit
+	}
+	if ws.MaxX != nil {
+		wsData.MaxX = *ws.MaxX
+	}
+	if ws.MaxY != nil {
+		wsData.MaxY = *ws.MaxY
+	}
+	if ws.MaxZ != nil {
+		wsData.MaxZ = *ws.MaxZ
+	}
+	wsData.DateUpdated = now
+	wsData.UpdatedByUser = claims.Subject
+
+	if str.workspaceNameTaken(wsData.Name, wsData.ID) {
+		return fmt.Errorf("error during update of Workspace entity -> id={%s}: %w", wsId, database.ErrorConflict)
+	}
+
+	str.workspaces[wsId] = wsData
+
+	return nil
+}
+
+// DeleteWorkspace marks existing Workspace entity in the memory as deleted at now, the workspace and its assets are
+// hidden from all queries until the workspace is restored.
+// If error occurs, the method can return database errors.
+func (str *MemoryStore) DeleteWorkspace(ctx context.Context, claims auth.Claims, wsId string, now time.Time) error {
+	return str.changeWorkspaceState(ctx, claims, wsId, false, func(wsData *Workspace) bool {
+		wsData.DateDeleted = &now
+		return true
+	}, now)
+}
+
+// ArchiveWorkspace marks existing Workspace entity in the memory as archived at now, so it is hidden from default
+// queries. Archiving of already archived workspace keeps its original archive date.
+// If error occurs, the method can return database errors.
+func (str *MemoryStore) ArchiveWorkspace(ctx context.Context, claims auth.Claims, wsId string, now time.Time) error {
+	return str.changeWorkspaceState(ctx, claims, wsId, false, func(wsData *Workspace) bool {
+		if wsData.DateArchived != nil {
+			return false
+		}
+
+		wsData.DateArchived = &now
+		return true
+	}, now)
+}
+
+// RestoreWorkspace returns archived or deleted Workspace entity in the memory back to default queries.
+// If error occurs, the method can return database errors.
+func (str *MemoryStore) RestoreWorkspace(ctx context.Context, claims auth.Claims, wsId string, now time.Time) error {
+	return str.changeWorkspaceState(ctx, claims, wsId, true, func(wsData *Workspace) bool {
+		if wsData.DateArchived == nil && wsData.DateDeleted == nil {
+			return false
+		}
+
+		wsData.DateArchived = nil
+		wsData.DateDeleted = nil
+		return true
+	}, now)
+}
+
+// FollowProjectDeletion is a project.ProjectDeletionHook which marks Workspace entities of deleted Project entity
+// as deleted at the same date. When the project is restored, the workspaces deleted together with it are restored.
+func (str *MemoryStore) FollowProjectDeletion(ctx context.Context, event project.ProjectDeletionEvent) {
+	str.mutex.Lock()
+	defer str.mutex.Unlock()
+
+	for wsId, wsData := range str.workspaces {
+		if wsData.ProjectID != event.ProjectID {
+			continue
+		}
+
+		switch {
+		case !event.Restored && wsData.DateDeleted == nil:
+			dateDeleted := event.DateDeleted
+			wsData.DateDeleted = &dateDeleted
+		case event.Restored && wsData.DateDeleted != nil && wsData.DateDeleted.Equal(event.DateDeleted):
+			wsData.DateDeleted = nil
+		default:
+			continue
+		}
+
+		wsData.DateUpdated = event.Now
+		wsData.UpdatedByUser = event.UserID
+		str.workspaces[wsId] = wsData
+	}
+}
+
+// PurgeWorkspace permanently removes Workspace entity together with its Asset entities from the memory,
+// regardless of whether the workspace was deleted before.
+// If removal is successful, the method returns external asset references which are no longer used by any Asset
+// entity.
+// If error occurs, the method can return database errors.
+func (str *MemoryStore) PurgeWorkspace(ctx context.Context, claims auth.Claims, wsId string) ([]string, error) {
+	if err := uuid.Validate(wsId); err != nil {
+		return nil, database.ErrorInvalidIdentifier
+	}
+
+	canWrite, err := str.canWriteProjectOf(ctx, claims, wsId)
+	if err != nil {
+		return nil, fmt.Errorf("error during search of Project entity -> workspace_id={%q}: %w", wsId, err)
+	}
+
+	str.mutex.Lock()
+	defer str.mutex.Unlock()
+
+	if _, found := str.workspaces[wsId]; !found {
+		return nil, fmt.Errorf("error during search of Workspace entity -> id={%q}: %w", wsId, database.ErrorNotFound)
+	}
+
+	if !canWrite {
+		return nil, database.ErrorForbidden
+	}
+
+	removedRefs := make(map[string]bool)
+	for id, asset := range str.assets {
+		if asset.WorkspaceID == wsId {
+			removedRefs[asset.AssetRefID] = true
+			delete(str.assets, id)
+		}
+	}
+	for _, asset := range str.assets {
+		delete(removedRefs, asset.AssetRefID)
+	}
+	delete(str.workspaces, wsId)
+
+	assetRefs := make([]string, 0, len(removedRefs))
+	for assetRef := range removedRefs {
+		assetRefs = append(assetRefs, assetRef)
+	}
+	sort.Strings(assetRefs)
+
+	return assetRefs, nil
+}
+
+// CloneWorkspace copies Workspace entity with wsId identifier and all of its Asset entities under the target name.
+// Copies get new identifiers and keep references to external assets.
+// The source workspace must be readable by the claims subject, the target project must be writable by the subject
+// and must not be archived. 3D environment with assets of non-zero depth cannot be copied into a flat stem, and asset
+// amount limit of the copy must fit all copied assets.
+// If error occurs, the method can return ErrorFlatStem, ErrorAssetLimitExceeded, validation or database errors.
+func (str *MemoryStore) CloneWorkspace(ctx context.Context, claims auth.Claims, wsId string, target CloneTarget,
+	now time.Time) (Workspace, error) {
+	if err := uuid.Validate(wsId); err != nil {
+		return Workspace{}, database.ErrorInvalidIdentifier
+	}
+
+	if err := validation.Check(ctx, target); err != nil {
+		return Workspace{}, fmt.Errorf("error during data validation of CloneTarget: %w", err)
+	}
+
+	wsData, err := str.QueryWorkspaceByID(ctx, claims, wsId)
+	if err != nil {
+		return Workspace{}, fmt.Errorf("error during search of Workspace entity -> id={%q}: %w", wsId, err)
+	}
+
+	wsCopy := cloneOf(claims, wsData, target, now)
+	if err := str.checkWritableProject(ctx, claims, wsCopy.ProjectID); err != nil {
+		return Workspace{}, fmt.Errorf("error during search of Project entity -> id={%q}: %w", wsCopy.ProjectID, err)
+	}
+
+	str.mutex.Lock()
+	defer str.mutex.Unlock()
+
+	if _, found := str.liveWorkspace(wsId); !found {
+		return Workspace{}, fmt.Errorf("error during search of Workspace entity -> id={%q}: %w", wsId,
+			database.ErrorNotFound)
+	}
+
+	assetCollection := str.workspaceAssets(wsId)
+	if err := checkClone(wsData, wsCopy, assetCollection); err != nil {
+		return Workspace{}, fmt.Errorf("error during clone of Workspace entity -> id={%q}: %w", wsId, err)
+	}
+
+	if _, found := str.stems[wsCopy.StemID]; !found {
+		return Workspace{}, fmt.Errorf("error during create of Workspace entity copy -> id={%q}: %w", wsId,
+			database.ErrorInvalidReference)
+	}
+
+	if str.workspaceNameTaken(wsCopy.Name, wsCopy.ID) {
+		return Workspace{}, fmt.Errorf("error during create of Workspace entity copy -> id={%q}: %w", wsId,
+			database.ErrorConflict)
+	}
+
+	str.workspaces[wsCopy.ID] = wsCopy
+	for _, assetData := range assetCollection {
+		assetCopy := assetCopyOf(claims, assetData, wsCopy.ID, now)
+		str.assets[assetCopy.ID] = assetCopy
+	}
+
+	return wsCopy, nil
+}
+
+// ExportWorkspace writes Document of Workspace entity with wsId identifier into w with ascending order of Asset
+// entities by creation date and identifier fields. The workspace must be readable by the claims subject.
+// Nothing is written if the workspace is not found.
+// If error occurs, the method can return database errors or errors of w.
+func (str *MemoryStore) ExportWorkspace(ctx context.Context, claims auth.Claims, wsId string, w io.Writer) error {
+	if err := uuid.Validate(wsId); err != nil {
+		return database.ErrorInvalidIdentifier
+	}
+
+	wsData, err := str.QueryWorkspaceByID(ctx, claims, wsId)
+	if err != nil {
+		return fmt.Errorf("error during search of Workspace entity -> id={%q}: %w", wsId, err)
+	}
+
+	str.mutex.RLock()
+	stem := str.stems[wsData.StemID]
+	assetCollection := str.workspaceAssets(wsId)
+	str.mutex.RUnlock()
+
+	return writeDocument(w, wsData, stem, func(write func(Asset) error) error {
+		for _, assetData := range assetCollection {
+			if err := write(assetData); err != nil {
+				return fmt.Errorf("error during export of Asset entities -> workspace_id={%q}: %w", wsId, err)
+			}
+		}
+
+		return nil
+	})
+}
+
+// ImportWorkspace adds Workspace entity with all Asset entities of doc to the memory in the project of options.
+// Imported entities get new identifiers and keep references to external assets.
+// The target project must be writable by the claims subject and must not be archived. Stem of the document is
+// matched by identifier and then by name.
+// Nothing is imported if the document has conflicts or options request a dry run, the report lists conflicts of
+// the document in both cases.
+// If error occurs, the method can return ErrorDocumentVersion, validation or database errors.
+func (str *MemoryStore) ImportWorkspace(ctx context.Context, claims auth.Claims, doc Document, options ImportOptions,
+	now time.Time) (ImportReport, error) {
+	if err := checkDocument(ctx, doc, options); err != nil {
+		return ImportReport{}, err
+	}
+
+	if err := str.checkWritableProject(ctx, claims, options.ProjectID); err != nil {
+		return ImportReport{}, fmt.Errorf("error during search of Project entity -> id={%q}: %w", options.ProjectID,
+			err)
+	}
+
+	report := ImportReport{DryRun: options.DryRun, Conflicts: []ImportConflict{}}
+	wsData := importedWorkspace(claims, doc, options, now)
+
+	str.mutex.Lock()
+	defer str.mutex.Unlock()
+
+	stem, found := str.documentStem(doc.Stem)
+	if !found {
+		report.Conflicts = append(report.Conflicts, stemConflict(doc.Stem))
+	}
+	wsData.StemID = stem.ID
+
+	if str.workspaceNameTaken(wsData.Name, wsData.ID) {
+		report.Conflicts = append(report.Conflicts, nameConflict(wsData.Name))
+	}
+
+	report.Conflicts = append(report.Conflicts, documentConflicts(wsData, doc.Assets)...)
+	if len(report.Conflicts) > 0 || options.DryRun {
+		return report, nil
+	}
+
+	str.workspaces[wsData.ID] = wsData
+	report.AssetIDs = make(map[string]string, len(doc.Assets))
+	for _, docAsset := range doc.Assets {
+		assetData := importedAsset(claims, docAsset, wsData.ID, now)
+		str.assets[assetData.ID] = assetData
+		if docAsset.ID != "" {
+			report.AssetIDs[docAsset.ID] = assetData.ID
+		}
+	}
+	report.Workspace = &wsData
+
+	return report, nil
+}
+
+// QueryWorkspaces looking for Workspace entities of projects readable by the claims subject using skip/top mechanics
+// with descending order by update date and identifier fields.
+// Archived workspaces and workspaces of archived projects are returned only if includeArchived is set.
+func (str *MemoryStore) QueryWorkspaces(ctx context.Context, claims auth.Claims, includeArchived bool, skip int32,
+	top int32) ([]Workspace, error) {
+	spec := withDefaultSort(WorkspaceQuery{IncludeArchived: includeArchived})
+	wsCollection, err := str.listWorkspaces(ctx, claims, spec)
+	if err != nil {
+		return nil, fmt.Errorf("error during search of Workspace entities: %w", err)
+	}
+
+	start, end, err := pageBounds(len(wsCollection), skip, top)
+	if err != nil {
+		return nil, fmt.Errorf("error during search of Workspace entities: %w", err)
+	}
+	if start == end {
+		return nil, nil
+	}
+
+	return wsCollection[start:end], nil
+}
+
+// QueryWorkspacesPage looking for a page of Workspace entities of projects readable by the claims subject using
+// keyset pagination with descending order by update date and identifier fields.
+// Archived workspaces and workspaces of archived projects are returned only if includeArchived is set.
+// If the page cursor is malformed, the method returns database.ErrorInvalidCursor.
+func (str *MemoryStore) QueryWorkspacesPage(ctx context.Context, claims auth.Claims, includeArchived bool,
+	page PageRequest) (WorkspacePage, error) {
+	return str.QueryWorkspacesBySpec(ctx, claims, WorkspaceQuery{IncludeArchived: includeArchived}, page)
+}
+
+// QueryWorkspacesBySpec looking for a page of Workspace entities of projects readable by the claims subject which
+// satisfy filters of spec using keyset pagination with the order requested by spec.
+// If the page cursor is malformed or belongs to another order, the method returns database.ErrorInvalidCursor.
+// Can return validation errors.
+func (str *MemoryStore) QueryWorkspacesBySpec(ctx context.Context, claims auth.Claims, spec WorkspaceQuery,
+	page PageRequest) (WorkspacePage, error) {
+	spec, cursor, err := workspaceQueryCursor(ctx, spec, page)
+	if err != nil {
+		return WorkspacePage{}, fmt.Errorf("error during search of Workspace entities: %w", err)
+	}
+
+	wsCollection, err := str.listWorkspaces(ctx, claims, spec)
+	if err != nil {
+		return WorkspacePage{}, fmt.Errorf("error during search of Workspace entities: %w", err)
+	}
+
+	start := 0
+	if cursor != nil {
+		anchor := cursorWorkspace(*cursor)
+		start = sort.Search(len(wsCollection), func(i int) bool {
+			return compareWorkspaces(spec, wsCollection[i], anchor) > 0
+		})
+	}
+	end, hasNext := keysetEnd(len(wsCollection), start, page.Top)
+
+	var wsPage WorkspacePage
+	if start < end {
+		wsPage.Items = wsCollection[start:end]
+	}
+	if hasNext {
+		wsPage.NextCursor = database.EncodeCursor(workspaceCursor(spec, wsCollection[end-1]))
+	}
+	if page.WithTotal {
+		total := int64(len(wsCollection))
+		wsPage.Total = &total
+	}
+
+	return wsPage, nil
+}
+
+// QueryWorkspaceByID looking for Workspace entity with wsId identifier whose project is readable by the claims
+// subject. Workspace of a project which is not readable by the subject is reported with database.ErrorNotFound.
+func (str *MemoryStore) QueryWorkspaceByID(ctx context.Context, claims auth.Claims, wsId string) (Workspace,
+	error) {
+	if err := uuid.Validate(wsId); err != nil {
+		return Workspace{}, err
+	}
+
+	str.mutex.RLock()
+	wsData, found := str.liveWorkspace(wsId)
+	str.mutex.RUnlock()
+
+	if !found {
+		return Workspace{}, database.ErrorNotFound
+	}
+
+	canRead, err := str.canReadProject(ctx, claims, wsData.ProjectID)
+	if err != nil {
+		return Workspace{}, fmt.Errorf("error during search of Workspace entity -> id={%q}: %w", wsId, err)
+	}
+	if !canRead {
+		return Workspace{}, database.ErrorNotFound
+	}
+
+	return wsData, nil
+}
+
+// QueryWorkspacesByProjectAndStem looking for all Workspace entities that belong to a specific Project and have a
+// specific Stem with descending order by update date and identifier fields.
+// If stemId argument equals to nil. No Stem filter will be applied.
+// Workspaces of a project which is not readable by the claims subject are not returned.
+// Archived workspaces and workspaces of archived project are returned only if includeArchived is set.
+func (str *MemoryStore) QueryWorkspacesByProjectAndStem(ctx context.Context, claims auth.Claims, projectId string,
+	stemId *string, includeArchived bool) ([]Workspace, error) {
+	if err := uuid.Validate(projectId); err != nil {
+		return []Workspace{}, err
+	}
+	if stemId != nil {
+		if err := uuid.Validate(*stemId); err != nil {
+			return []Workspace{}, err
+		}
+	}
+
+	spec := WorkspaceQuery{ProjectIDs: []string{projectId}, IncludeArchived: includeArchived}
+	if stemId != nil {
+		spec.StemIDs = []string{*stemId}
+	}
+
+	wsCollection, err := str.listWorkspaces(ctx, claims, withDefaultSort(spec))
+	if err != nil {
+		return nil, fmt.Errorf("error during search of Workspace entities: %w", err)
+	}
+	if len(wsCollection) == 0 {
+		return nil, nil
+	}
+
+	return wsCollection, nil
+}
+
+// CreateAsset adds new Asset entity to the memory.
+// The workspace should be readable by the claims subject, not deleted or archived, and its project writable.
+// If creation is successful, the method returns Asset entity.
+// Can return ErrorAssetLimitExceeded, ErrorAssetOutOfBounds, validation or database errors.
+func (str *MemoryStore) CreateAsset(ctx context.Context, claims auth.Claims, newAsset NewAsset,
+	now time.Time) (Asset, error) {
+	if err := validation.Check(ctx, newAsset); err != nil {
+		return Asset{}, fmt.Errorf("error during data validation of Asset entity: %w", err)
+	}
+
+	asset := Asset{
+		ID:            uuid.Generate(),
+		WorkspaceID:   newAsset.WorkspaceID,
+		AssetRefID:    newAsset.AssetRefID,
+		X:             newAsset.X,
+		Y:             newAsset.Y,
+		Z:             newAsset.Z,
+		Scale:         newAsset.Scale,
+		Height:        newAsset.Height,
+		Width:         newAsset.Width,
+		Length:        newAsset.Length,
+		DateCreated:   now,
+		DateUpdated:   now,
+		CreatedByUser: claims.Subject,
+		UpdatedByUser: claims.Subject,
+	}
+
+	str.mutex.RLock()
+	wsData, found := str.workspaces[asset.WorkspaceID]
+	str.mutex.RUnlock()
+
+	if !found || wsData.DateDeleted != nil || wsData.DateArchived != nil {
+		return Asset{}, fmt.Errorf("error during search of Workspace entity -> id={%q}: %w", asset.WorkspaceID,
+			database.ErrorNotFound)
+	}
+
+	if err := str.checkWritableProject(ctx, claims, wsData.ProjectID); err != nil {
+		return Asset{}, fmt.Errorf("error during search of Workspace entity -> id={%q}: %w", asset.WorkspaceID, err)
+	}
+
+	str.mutex.Lock()
+	defer str.mutex.Unlock()
+
+	wsData, found = str.workspaces[asset.WorkspaceID]
+	if !found || wsData.DateDeleted != nil || wsData.DateArchived != nil {
+		return Asset{}, fmt.Errorf("error during search of Workspace entity -> id={%q}: %w", asset.WorkspaceID,
+			database.ErrorNotFound)
+	}
+
+	if err := checkPlacement(wsData, len(str.workspaceAssets(wsData.ID)), asset); err != nil {
+		return Asset{}, err
+	}
+
+	str.assets[asset.ID] = asset
+
+	return asset, nil
+}
+
+// UpdateAsset change existing Asset entity in the memory.
+// If error occurs, the method can return validation or database errors.
+func (str *MemoryStore) UpdateAsset(ctx context.Context, claims auth.Claims, assetId string, asset UpdateAsset,
+	now time.Time) error {
+	if err := uuid.Validate(assetId); err != nil {
+		return database.ErrorInvalidIdentifier
+	}
+
+	if err := validation.Check(ctx, asset); err != nil {
+		return fmt.Errorf("error during data validation of Asset entity: %w", err)
+	}
+
+	canWrite, err := str.canWriteProjectOf(ctx, claims, str.assetWorkspaceID(assetId))
+	if err != nil {
+		return fmt.Errorf("error during search of Project entity -> asset_id={%q}: %w", assetId, err)
+	}
+
+	str.mutex.Lock()
+	defer str.mutex.Unlock()
+
+	assetData, found := str.liveAsset(assetId)
+	if !found {
+		return fmt.Errorf("error during search of Asset entity -> id={%q}: %w", assetId, database.ErrorNotFound)
+	}
+
+	if !canWrite {
+		return database.ErrorForbidden
+	}
+
+	if asset.AssetRefID != nil {
+		assetData.AssetRefID = *asset.AssetRefID
+	}
+	if asset.X != nil {
+		assetData.X = *asset.X
+	}
+	if asset.Y != nil {
+		assetData.Y = *asset.Y
+	}
+	if asset.Z != nil {
+		assetData.Z = *asset.Z
+	}
+	if asset.Scale != nil {
+		assetData.Scale = *asset.Scale
+	}
+	if asset.Height != nil {
+		assetData.Height = *asset.Height
+	}
+	if asset.Width != nil {
+		assetData.Width = *asset.Width
+	}
+	if asset.Length != nil {
+		assetData.Length = *asset.Length
+	}
+
+	assetData.DateUpdated = now
+	assetData.UpdatedByUser = claims.Subject
+
+	str.assets[assetId] = assetData
+
+	return nil
+}
+
+// DeleteAsset removes existing Asset entity from the memory.
+// If error occurs, the method can return database errors.
+func (str *MemoryStore) DeleteAsset(ctx context.Context, claims auth.Claims, assetId string) error {
+	if err := uuid.Validate(assetId); err != nil {
+		return database.ErrorInvalidIdentifier
+	}
+
+	canWrite, err := str.canWriteProjectOf(ctx, claims, str.assetWorkspaceID(assetId))
+	if err != nil {
+		return fmt.Errorf("error during search of Project entity -> asset_id={%q}: %w", assetId, err)
+	}
+
+	str.mutex.Lock()
+	defer str.mutex.Unlock()
+
+	if _, found := str.liveAsset(assetId); !found {
+		return fmt.Errorf("error during search of Asset entity -> id={%q}: %w", assetId, database.ErrorNotFound)
+	}
+
+	if !canWrite {
+		return database.ErrorForbidden
+	}
+
+	delete(str.assets, assetId)
+
+	return nil
+}
+
+// QueryAssetsByWorkspace looking for all Asset entities that belong to a specific Workspace using skip/top mechanics
+// with descending order by update date and identifier fields.
+// Assets of a workspace whose project is not readable by the claims subject are not returned.
+func (str *MemoryStore) QueryAssetsByWorkspace(ctx context.Context, claims auth.Claims, workspaceId string,
+	skip int32, top int32) ([]Asset, error) {
+	if err := uuid.Validate(workspaceId); err != nil {
+		return []Asset{}, database.ErrorInvalidIdentifier
+	}
+
+	assetCollection, err := str.listAssets(ctx, claims, workspaceId)
+	if err != nil {
+		return nil, fmt.Errorf("error during search of Asset entities: %w", err)
+	}
+
+	start, end, err := pageBounds(len(assetCollection), skip, top)
+	if err != nil {
+		return nil, fmt.Errorf("error during search of Asset entities: %w", err)
+	}
+	if start == end {
+		return nil, nil
+	}
+
+	return assetCollection[start:end], nil
+}
+
+// QueryAssetsByWorkspacePage looking for a page of Asset entities that belong to a specific Workspace using keyset
+// pagination with descending order by update date and identifier fields.
+// Assets of a workspace whose project is not readable by the claims subject are not returned.
+// If the page cursor is malformed, the method returns database.ErrorInvalidCursor.
+func (str *MemoryStore) QueryAssetsByWorkspacePage(ctx context.Context, claims auth.Claims, workspaceId string,
+	page PageRequest) (AssetPage, error) {
+	if err := uuid.Validate(workspaceId); err != nil {
+		return AssetPage{}, database.ErrorInvalidIdentifier
+	}
+
+	cursor, err := pageCursor(page)
+	if err != nil {
+		return AssetPage{}, fmt.Errorf("error during search of Asset entities: %w", err)
+	}
+
+	assetCollection, err := str.listAssets(ctx, claims, workspaceId)
+	if err != nil {
+		return AssetPage{}, fmt.Errorf("error during search of Asset entities: %w", err)
+	}
+
+	start := keysetStart(len(assetCollection), cursor, func(i int) (time.Time, string) {
+		return assetCollection[i].DateUpdated, assetCollection[i].ID
+	})
+	end, hasNext := keysetEnd(len(assetCollection), start, page.Top)
+
+	var assetPage AssetPage
+	if start < end {
+		assetPage.Items = assetCollection[start:end]
+	}
+	if hasNext {
+		last := assetCollection[end-1]
+		assetPage.NextCursor = database.EncodeCursor(database.Cursor{Date: last.DateUpdated, ID: last.ID})
+	}
+	if page.WithTotal {
+		total := int64(len(assetCollection))
+		assetPage.Total = &total
+	}
+
+	return assetPage, nil
+}
+
+// QueryAssetByID looking for Asset entity with assetId identifier whose project is readable by the claims subject.
+// Asset of a project which is not readable by the subject is reported with database.ErrorNotFound.
+func (str *MemoryStore) QueryAssetByID(ctx context.Context, claims auth.Claims, assetId string) (Asset, error) {
+	if err := uuid.Validate(assetId); err != nil {
+		return Asset{}, err
+	}
+
+	str.mutex.RLock()
+	asset, found := str.assets[assetId]
+	wsData, wsFound := str.liveWorkspace(asset.WorkspaceID)
+	str.mutex.RUnlock()
+
+	if !found || !wsFound {
+		return Asset{}, database.ErrorNotFound
+	}
+
+	canRead, err := str.canReadProject(ctx, claims, wsData.ProjectID)
+	if err != nil {
+		return Asset{}, fmt.Errorf("error during search of Asset entity -> id={%q}: %w", assetId, err)
+	}
+	if !canRead {
+		return Asset{}, database.ErrorNotFound
+	}
+
+	return asset, nil
+}
+
+// QueryStems looking for all Stem entities.
+func (str *MemoryStore) QueryStems(ctx context.Context, skip int32, top int32) ([]Stem, error) {
+	stemCollection := str.listStems()
+
+	start, end, err := pageBounds(len(stemCollection), skip, top)
+	if err != nil {
+		return nil, fmt.Errorf("error during search of Stem entities: %w", err)
+	}
+	if start == end {
+		return nil, nil
+	}
+
+	return stemCollection[start:end], nil
+}
+
+// QueryStemsPage looking for a page of Stem entities using keyset pagination with descending order by identifier
+// field, cursors of Stem pages keep only the identifier.
+// If the page cursor is malformed, the method returns database.ErrorInvalidCursor.
+func (str *MemoryStore) QueryStemsPage(ctx context.Context, page PageRequest) (StemPage, error) {
+	cursor, err := pageCursor(page)
+	if err != nil {
+		return StemPage{}, fmt.Errorf("error during search of Stem entities: %w", err)
+	}
+
+	stemCollection := str.listStems()
+
+	start := keysetStart(len(stemCollection), cursor, func(i int) (time.Time, string) {
+		return time.Time{}, stemCollection[i].ID
+	})
+	end, hasNext := keysetEnd(len(stemCollection), start, page.Top)
+
+	var stemPage StemPage
+	if start < end {
+		stemPage.Items = stemCollection[start:end]
+	}
+	if hasNext {
+		stemPage.NextCursor = database.EncodeCursor(database.Cursor{ID: stemCollection[end-1].ID})
+	}
+	if page.WithTotal {
+		total := int64(len(stemCollection))
+		stemPage.Total = &total
+	}
+
+	return stemPage, nil
+}
+
+// QueryStemByID looking for Stem entity with stemId identifier.
+func (str *MemoryStore) QueryStemByID(ctx context.Context, stemId string) (Stem, error) {
+	if err := uuid.Validate(stemId); err != nil {
+		return Stem{}, err
+	}
+
+	str.mutex.RLock()
+	defer str.mutex.RUnlock()
+
+	stem, found := str.stems[stemId]
+	if !found {
+		return Stem{}, database.ErrorNotFound
+	}
+
+	return stem, nil
+}
+
+// workspaceNameTaken reports whether other Workspace entity already uses the name.
+// The caller should hold the mutex.
+func (str *MemoryStore) workspaceNameTaken(name string, exceptId string) bool {
+	for _, wsData := range str.workspaces {
+		if wsData.Name == name && wsData.ID != exceptId {
+			return true
+		}
+	}
+
+	return false
+}
+
+// documentStem looking for Stem entity with identifier of docStem, Stem entity with name of docStem is returned when
+// the identifier is not known.
+// The caller should hold the mutex.
+func (str *MemoryStore) documentStem(docStem Stem) (Stem, bool) {
+	if stem, found := str.stems[docStem.ID]; found {
+		return stem, true
+	}
+
+	for _, stem := range str.stems {
+		if stem.Name == docStem.Name {
+			return stem, true
+		}
+	}
+
+	return Stem{}, false
+}
+
+// workspaceAssets returns all Asset entities of Workspace entity with ascending order by creation date and
+// identifier fields.
+// The caller should hold the mutex.
+func (str *MemoryStore) workspaceAssets(wsId string) []Asset {
+	var assetCollection []Asset
+	for _, assetData := range str.assets {
+		if assetData.WorkspaceID == wsId {
+			assetCollection = append(assetCollection, assetData)
+		}
+	}
+	sort.Slice(assetCollection, func(i, j int) bool {
+		if !assetCollection[i].DateCreated.Equal(assetCollection[j].DateCreated) {
+			return assetCollection[i].DateCreated.Before(assetCollection[j].DateCreated)
+		}
+		return assetCollection[i].ID < assetCollection[j].ID
+	})
+
+	return assetCollection
+}
+
+// listWorkspaces returns all Workspace entities of projects readable by the claims subject which satisfy filters of
+// spec with the order requested by spec, sort field and direction of spec must be filled.
+// It must be called without holding the mutex since projects belong to another store.
+func (str *MemoryStore) listWorkspaces(ctx context.Context, claims auth.Claims,
+	spec WorkspaceQuery) ([]Workspace, error) {
+	str.mutex.RLock()
+	wsCollection := make([]Workspace, 0, len(str.workspaces))
+	for _, wsData := range str.workspaces {
+		if matchesWorkspace(spec, wsData) {
+			wsCollection = append(wsCollection, wsData)
+		}
+	}
+	str.mutex.RUnlock()
+
+	listed := make(map[string]bool)
+	visibleCollection := make([]Workspace, 0, len(wsCollection))
+	for _, wsData := range wsCollection {
+		isListed, checked := listed[wsData.ProjectID]
+		if !checked {
+			var err error
+			if isListed, err = str.listsProject(ctx, claims, wsData.ProjectID, spec.IncludeArchived); err != nil {
+				return nil, err
+			}
+			listed[wsData.ProjectID] = isListed
+		}
+
+		if isListed {
+			visibleCollection = append(visibleCollection, wsData)
+		}
+	}
+	sort.Slice(visibleCollection, func(i, j int) bool {
+		return compareWorkspaces(spec, visibleCollection[i], visibleCollection[j]) < 0
+	})
+
+	return visibleCollection, nil
+}
+
+// listAssets returns all Asset entities of Workspace entity with descending order by update date and identifier
+// fields, assets of a workspace whose project is not readable by the claims subject are not returned.
+// It must be called without holding the mutex since projects belong to another store.
+func (str *MemoryStore) listAssets(ctx context.Context, claims auth.Claims, workspaceId string) ([]Asset, error) {
+	str.mutex.RLock()
+	wsData, found := str.liveWorkspace(workspaceId)
+	str.mutex.RUnlock()
+
+	if !found {
+		return nil, nil
+	}
+
+	canRead, err := str.canReadProject(ctx, claims, wsData.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+	if !canRead {
+		return nil, nil
+	}
+
+	str.mutex.RLock()
+	defer str.mutex.RUnlock()
+
+	var assetCollection []Asset
+	for _, asset := range str.assets {
+		if asset.WorkspaceID == workspaceId {
+			assetCollection = append(assetCollection, asset)
+		}
+	}
+	sortAssets(assetCollection)
+
+	return assetCollection, nil
+}
+
+// listStems returns all Stem entities with descending order by identifier field.
+func (str *MemoryStore) listStems() []Stem {
+	str.mutex.RLock()
+	defer str.mutex.RUnlock()
+
+	stemCollection := make([]Stem, 0, len(str.stems))
+	for _, stem := range str.stems {
+		stemCollection = append(stemCollection, stem)
+	}
+	sort.Slice(stemCollection, func(i, j int) bool {
+		return stemCollection[i].ID > stemCollection[j].ID
+	})
+
+	return stemCollection
+}
+
+// changeWorkspaceState applies change to Workspace entity with wsId identifier on behalf of a user who can write
+// content of its project, audit fields are updated only if change reports that the workspace has changed.
+// Deleted workspace is found only if includeDeleted is set.
+func (str *MemoryStore) changeWorkspaceState(ctx context.Context, claims auth.Claims, wsId string,
+	includeDeleted bool, change func(wsData *Workspace) bool, now time.Time) error {
+	if err := uuid.Validate(wsId); err != nil {
+		return database.ErrorInvalidIdentifier
+	}
+
+	canWrite, err := str.canWriteProjectOf(ctx, claims, wsId)
+	if err != nil {
+		return fmt.Errorf("error during search of Project entity -> workspace_id={%q}: %w", wsId, err)
+	}
+
+	str.mutex.Lock()
+	defer str.mutex.Unlock()
+
+	wsData, found := str.workspaces[wsId]
+	if !found || (!includeDeleted && wsData.DateDeleted != nil) {
+		return fmt.Errorf("error during search of Workspace entity -> id={%q}: %w", wsId, database.ErrorNotFound)
+	}
+
+	if !canWrite {
+		return database.ErrorForbidden
+	}
+
+	if !change(&wsData) {
+		return nil
+	}
+
+	wsData.DateUpdated = now
+	wsData.UpdatedByUser = claims.Subject
+	str.workspaces[wsId] = wsData
+
+	return nil
+}
+
+// liveWorkspace looking for Workspace entity which is not deleted.
+// The caller should hold the mutex.
+func (str *MemoryStore) liveWorkspace(wsId string) (Workspace, bool) {
+	wsData, found := str.workspaces[wsId]
+	if !found || wsData.DateDeleted != nil {
+		return Workspace{}, false
+	}
+
+	return wsData, true
+}
+
+// liveAsset looking for Asset entity of Workspace entity which is not deleted.
+// The caller should hold the mutex.
+func (str *MemoryStore) liveAsset(assetId string) (Asset, bool) {
+	assetData, found := str.assets[assetId]
+	if !found {
+		return Asset{}, false
+	}
+
+	if _, found := str.liveWorkspace(assetData.WorkspaceID); !found {
+		return Asset{}, false
+	}
+
+	return assetData, true
+}
+
+// listsProject reports whether Workspace entities of Project entity are listed for the claims subject, archived
+// project is listed only if includeArchived is set.
+// It must be called without holding the mutex since projects belong to another store.
+func (str *MemoryStore) listsProject(ctx context.Context, claims auth.Claims, projectId string,
+	includeArchived bool) (bool, error) {
+	if includeArchived {
+		return str.canReadProject(ctx, claims, projectId)
+	}
+
+	projectData, err := str.projects.QueryProjectByID(ctx, claims, projectId)
+	if err != nil {
+		if err == database.ErrorNotFound {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	return projectData.DateArchived == nil, nil
+}
+
+// canReadProject reports whether Project entity is readable by the claims subject, missing project is not readable.
+// It must be called without holding the mutex since projects belong to another store.
+func (str *MemoryStore) canReadProject(ctx context.Context, claims auth.Claims, projectId string) (bool, error) {
+	canRead, err := str.projects.CanReadProject(ctx, claims, projectId)
+	if err != nil {
+		if err == database.ErrorNotFound {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	return canRead, nil
+}
+
+// checkWritableProject confirms that new workspaces can be placed into Project entity with projectId identifier.
+// It reports database.ErrorNotFound unless the project is readable by the claims subject and is not archived, and
+// database.ErrorForbidden unless the subject is an owner of the project or holds project.ReadWriteRole in it.
+// It must be called without holding the mutex since projects belong to another store.
+func (str *MemoryStore) checkWritableProject(ctx context.Context, claims auth.Claims, projectId string) error {
+	projectData, err := str.projects.QueryProjectByID(ctx, claims, projectId)
+	if err != nil {
+		return err
+	}
+
+	if projectData.DateArchived != nil {
+		return database.ErrorNotFound
+	}
+
+	canWrite, err := str.projects.CanWriteProject(ctx, claims, projectId)
+	if err != nil {
+		return err
+	}
+
+	if !canWrite {
+		return database.ErrorForbidden
+	}
+
+	return nil
+}
+
+// canWriteProjectOf reports whether the claims subject can write content of Project entity of Workspace entity with
+// wsId identifier, see project.ProjectPolicy.CanWriteProject. Missing workspace or project is not writable.
+// It must be called without holding the mutex since projects belong to another store.
+func (str *MemoryStore) canWriteProjectOf(ctx context.Context, claims auth.Claims, wsId string) (bool, error) {
+	str.mutex.RLock()
+	wsData, found := str.workspaces[wsId]
+	str.mutex.RUnlock()
+
+	if !found {
+		return false, nil
+	}
+
+	canWrite, err := str.projects.CanWriteProject(ctx, claims, wsData.ProjectID)
+	if err != nil {
+		if err == database.ErrorNotFound {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	return canWrite, nil
+}
+
+// assetWorkspaceID returns identifier of Workspace entity which contains Asset entity with assetId identifier.
+func (str *MemoryStore) assetWorkspaceID(assetId string) string {
+	str.mutex.RLock()
+	defer str.mutex.RUnlock()
+
+	return str.assets[assetId].WorkspaceID
+}
+
+// sortAssets orders Asset entities by update date field in descending order.
+// Identifier is used as a tiebreaker to keep pages stable.
+func sortAssets(assetCollection []Asset) {
+	sort.Slice(assetCollection, func(i, j int) bool {
+		if !assetCollection[i].DateUpdated.Equal(assetCollection[j].DateUpdated) {
+			return assetCollection[i].DateUpdated.After(assetCollection[j].DateUpdated)
+		}
+		return assetCollection[i].ID > assetCollection[j].ID
+	})
+}
+
+// pageBounds converts skip/top arguments into slice bounds of a collection with the specified length.
+func pageBounds(length int, skip int32, top int32) (int, int, error) {
+	if skip < 0 {
+		return 0, 0, errors.New("skip argument must not be negative")
+	}
+	if top < 0 {
+		return 0, 0, errors.New("top argument must not be negative")
+	}
+
+	start := int(skip)
+	if start > length {
+		start = length
+	}
+
+	end := start + int(top)
+	if end > length {
+		end = length
+	}
+
+	return start, end, nil
+}
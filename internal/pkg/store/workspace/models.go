@@ -15,20 +15,24 @@ type Stem struct {
 }
 
 // Workspace represents shared area when Asset objects can be composed into a scene or a part of specific project.
+// Archived workspaces are hidden from default queries, deleted workspaces are hidden from all queries until they are
+// restored or purged.
 type Workspace struct {
-	ID               string    `db:"workspace_id" json:"id"`
-	ProjectID        string    `db:"project_id" json:"projectId"`
-	StemID           string    `db:"stem_id" json:"stemId"`
-	Name             string    `db:"name" json:"name"`
-	Description      string    `db:"description" json:"description"`
-	AssetAmountLimit int32     `db:"asset_amount_limit" json:"assetAmountLimit"`
-	MaxX             int32     `db:"x_max" json:"maxX"`
-	MaxY             int32     `db:"y_max" json:"maxY"`
-	MaxZ             int32     `db:"z_max" json:"maxZ"`
-	DateCreated      time.Time `db:"date_created" json:"dateCreated"`
-	CreatedByUser    string    `db:"created_by_user_id" json:"createdByUser"`
-	DateUpdated      time.Time `db:"date_updated" json:"dateUpdated"`
-	UpdatedByUser    string    `db:"updated_by_user_id" json:"updatedByUser"`
+	ID               string     `db:"workspace_id" json:"id"`
+	ProjectID        string     `db:"project_id" json:"projectId"`
+	StemID           string     `db:"stem_id" json:"stemId"`
+	Name             string     `db:"name" json:"name"`
+	Description      string     `db:"description" json:"description"`
+	AssetAmountLimit int32      `db:"asset_amount_limit" json:"assetAmountLimit"`
+	MaxX             int32      `db:"x_max" json:"maxX"`
+	MaxY             int32      `db:"y_max" json:"maxY"`
+	MaxZ             int32      `db:"z_max" json:"maxZ"`
+	DateCreated      time.Time  `db:"date_created" json:"dateCreated"`
+	CreatedByUser    string     `db:"created_by_user_id" json:"createdByUser"`
+	DateUpdated      time.Time  `db:"date_updated" json:"dateUpdated"`
+	UpdatedByUser    string     `db:"updated_by_user_id" json:"updatedByUser"`
+	DateArchived     *time.Time `db:"date_archived" json:"dateArchived,omitempty"`
+	DateDeleted      *time.Time `db:"date_deleted" json:"dateDeleted,omitempty"`
 }
 
 // NewWorkspace describes all data that should be specified during creation of new Workspace entity.
@@ -53,6 +57,16 @@ type UpdateWorkspace struct {
 	MaxZ             *int32  `json:"maxZ" validate:"required,gte=0"`
 }
 
+// CloneTarget describes all data that should be specified during copy of existing Workspace entity.
+// ProjectID and StemID equal to the source workspace ones when omitted, AssetAmountLimit is copied when it equals
+// to nil.
+type CloneTarget struct {
+	ProjectID        string `json:"projectId" validate:"omitempty,uuid"`
+	StemID           string `json:"stemId" validate:"omitempty,uuid"`
+	Name             string `json:"name" validate:"required"`
+	AssetAmountLimit *int32 `json:"assetAmountLimit" validate:"omitempty,gte=1"`
+}
+
 // Asset represents reference to dynamic element that should be displayed in Workspace area.
 type Asset struct {
 	ID            string    `db:"asset_id" json:"id"`
@@ -95,3 +109,137 @@ type UpdateAsset struct {
 	Width      *int32  `json:"width" validate:"required,gte=0"`
 	Length     *int32  `json:"length" validate:"required,gte=0"`
 }
+
+// DocumentVersion is the version of Document format produced by export, import accepts documents of this version only.
+const DocumentVersion = 1
+
+// Codes of ImportConflict entities.
+const (
+	ConflictNameTaken          = "NAME_TAKEN"
+	ConflictUnknownStem        = "UNKNOWN_STEM"
+	ConflictAssetLimitExceeded = "ASSET_LIMIT_EXCEEDED"
+	ConflictOutOfBounds        = "OUT_OF_BOUNDS"
+)
+
+// Document represents portable JSON document of Workspace entity with its Stem and Asset entities which can be moved
+// between environments. Identifiers of the document are informational, import creates entities with new identifiers
+// and keeps references to external assets.
+type Document struct {
+	Version   int               `json:"version"`
+	Workspace DocumentWorkspace `json:"workspace"`
+	Stem      Stem              `json:"stem"`
+	Assets    []DocumentAsset   `json:"assets" validate:"max=10000,dive"`
+}
+
+// DocumentWorkspace represents Workspace entity inside Document.
+type DocumentWorkspace struct {
+	ID               string `json:"id"`
+	Name             string `json:"name" validate:"required"`
+	Description      string `json:"description"`
+	AssetAmountLimit int32  `json:"assetAmountLimit" validate:"gte=1"`
+	MaxX             int32  `json:"maxX" validate:"gte=1"`
+	MaxY             int32  `json:"maxY" validate:"gte=1"`
+	MaxZ             int32  `json:"maxZ" validate:"gte=0"`
+}
+
+// DocumentAsset represents Asset entity inside Document.
+type DocumentAsset struct {
+	ID         string `json:"id"`
+	AssetRefID string `json:"assetRefId" validate:"required"`
+	X          int32  `json:"x" validate:"gte=0"`
+	Y          int32  `json:"y" validate:"gte=0"`
+	Z          int32  `json:"z" validate:"gte=0"`
+	Scale      int32  `json:"scale" validate:"gte=0"`
+	Height     int32  `json:"height" validate:"gte=0"`
+	Width      int32  `json:"width" validate:"gte=0"`
+	Length     int32  `json:"length" validate:"gte=0"`
+}
+
+// ImportOptions describes all data that should be specified during import of Document into a Project.
+// Name overrides the workspace name of the document when it is specified, DryRun reports conflicts without import.
+type ImportOptions struct {
+	ProjectID string `json:"projectId" validate:"required,uuid"`
+	Name      string `json:"name"`
+	DryRun    bool   `json:"dryRun"`
+}
+
+// ImportConflict represents a problem of Document which prevents its import, Field points to the document field.
+type ImportConflict struct {
+	Code    string `json:"code"`
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// ImportReport represents result of Document import. Workspace is set only when the document is imported, AssetIDs
+// map identifiers of document assets to identifiers of their imported copies.
+type ImportReport struct {
+	DryRun    bool              `json:"dryRun"`
+	Workspace *Workspace        `json:"workspace,omitempty"`
+	AssetIDs  map[string]string `json:"assetIds,omitempty"`
+	Conflicts []ImportConflict  `json:"conflicts"`
+}
+
+// Sort fields of WorkspaceQuery.
+const (
+	SortByDateUpdated = "dateUpdated"
+	SortByDateCreated = "dateCreated"
+	SortByName        = "name"
+)
+
+// Sort directions of WorkspaceQuery.
+const (
+	SortAscending  = "asc"
+	SortDescending = "desc"
+)
+
+// WorkspaceQuery describes filters and order of Workspace entities listing.
+// Empty filters are not applied, lists of identifiers match any of their values. Name filters ignore letter case.
+// Date ranges include their After bound and exclude their Before bound.
+// Workspaces are ordered by update date in descending order unless SortBy and SortDirection are specified,
+// identifier is used as a tiebreaker in the same direction.
+type WorkspaceQuery struct {
+	ProjectIDs      []string   `json:"projectId" validate:"max=50,dive,uuid"`
+	StemIDs         []string   `json:"stemId" validate:"max=50,dive,uuid"`
+	CreatedBy       string     `json:"createdBy" validate:"max=200"`
+	NamePrefix      string     `json:"namePrefix" validate:"max=200"`
+	NameContains    string     `json:"nameContains" validate:"max=200"`
+	CreatedAfter    *time.Time `json:"createdAfter"`
+	CreatedBefore   *time.Time `json:"createdBefore"`
+	UpdatedAfter    *time.Time `json:"updatedAfter"`
+	UpdatedBefore   *time.Time `json:"updatedBefore"`
+	IncludeArchived bool       `json:"includeArchived"`
+	SortBy          string     `json:"sortBy" validate:"omitempty,oneof=dateUpdated dateCreated name"`
+	SortDirection   string     `json:"sortDirection" validate:"omitempty,oneof=asc desc"`
+}
+
+// PageRequest describes a requested page of a collection with keyset pagination.
+// Cursor equals to empty string for the first page, Total of the page is counted only if WithTotal is set.
+type PageRequest struct {
+	Cursor    string
+	Top       int32
+	WithTotal bool
+}
+
+// WorkspacePage represents a page of Workspace entities with an opaque cursor of the next page.
+// NextCursor equals to empty string on the last page.
+type WorkspacePage struct {
+	Items      []Workspace `json:"items"`
+	NextCursor string      `json:"nextCursor,omitempty"`
+	Total      *int64      `json:"total,omitempty"`
+}
+
+// AssetPage represents a page of Asset entities with an opaque cursor of the next page.
+// NextCursor equals to empty string on the last page.
+type AssetPage struct {
+	Items      []Asset `json:"items"`
+	NextCursor string  `json:"nextCursor,omitempty"`
+	Total      *int64  `json:"total,omitempty"`
+}
+
+// StemPage represents a page of Stem entities with an opaque cursor of the next page.
+// NextCursor equals to empty string on the last page.
+type StemPage struct {
+	Items      []Stem `json:"items"`
+	NextCursor string `json:"nextCursor,omitempty"`
+	Total      *int64 `json:"total,omitempty"`
+}
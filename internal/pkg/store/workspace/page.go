@@ -0,0 +1,61 @@
+package workspace
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/SKorolchuk/dpio-workspace/internal/pkg/infra/database"
+)
+
+// pageCursor validates page request and decodes its cursor, nil cursor means the first page.
+// If the cursor is malformed, the function returns database.ErrorInvalidCursor.
+func pageCursor(page PageRequest) (*database.Cursor, error) {
+	if page.Top < 1 {
+		return nil, errors.New("top argument must be positive")
+	}
+
+	return database.DecodeCursor(page.Cursor)
+}
+
+// keysetStart returns index of the first element after cursor in a collection of length elements ordered by update
+// date and identifier in descending order, elementKey returns the ordering key of i-th element.
+func keysetStart(length int, cursor *database.Cursor, elementKey func(i int) (time.Time, string)) int {
+	if cursor == nil {
+		return 0
+	}
+
+	return sort.Search(length, func(i int) bool {
+		dateUpdated, id := elementKey(i)
+		if dateUpdated.Equal(cursor.Date) {
+			return id < cursor.ID
+		}
+
+		return dateUpdated.Before(cursor.Date)
+	})
+}
+
+// keysetEnd returns end bound of a page which starts at start index of a collection of length elements and reports
+// whether the collection has elements after the page.
+func keysetEnd(length int, start int, top int32) (int, bool) {
+	end := start + int(top)
+	if end >= length {
+		return length, false
+	}
+
+	return end, true
+}
+
+// countRows executes query which returns amount of rows in a column named amount.
+func (str Store) countRows(ctx context.Context, query string, queryParams interface{}) (int64, error) {
+	var rows struct {
+		Amount int64 `db:"amount"`
+	}
+	connection := str.cluster.Reader(ctx)
+	if err := database.NamedQueryStruct(ctx, str.logger, connection, query, queryParams, &rows); err != nil {
+		return 0, err
+	}
+
+	return rows.Amount, nil
+}
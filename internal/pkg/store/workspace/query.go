@@ -0,0 +1,293 @@
+package workspace
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/SKorolchuk/dpio-workspace/internal/pkg/infra/auth"
+	"github.com/SKorolchuk/dpio-workspace/internal/pkg/infra/database"
+	"github.com/SKorolchuk/dpio-workspace/internal/pkg/infra/validation"
+	"github.com/SKorolchuk/dpio-workspace/internal/pkg/store/project"
+)
+
+// workspaceSortColumns maps sort fields of WorkspaceQuery to columns of WORKSPACE table aliased as w.
+// Only these columns are placed into ORDER BY clauses of compiled queries.
+var workspaceSortColumns = map[string]string{
+	SortByDateUpdated: "w.date_updated",
+	SortByDateCreated: "w.date_created",
+	SortByName:        "w.name",
+}
+
+// compiledQuery represents WorkspaceQuery compiled into SQL.
+// User values are never placed into SQL text, they are passed as named arguments instead.
+type compiledQuery struct {
+	query      string
+	countQuery string
+	params     map[string]interface{}
+}
+
+// withDefaultSort returns spec with default sort field and direction filled in.
+func withDefaultSort(spec WorkspaceQuery) WorkspaceQuery {
+	if spec.SortBy == "" {
+		spec.SortBy = SortByDateUpdated
+	}
+	if spec.SortDirection == "" {
+		spec.SortDirection = SortDescending
+	}
+
+	return spec
+}
+
+// sortName returns name of the order requested by spec, it is kept in page cursors.
+func (spec WorkspaceQuery) sortName() string {
+	return spec.SortBy + "." + spec.SortDirection
+}
+
+// workspaceQueryCursor validates spec and page request, fills default order of spec and decodes page cursor.
+// Cursor of another order is reported with database.ErrorInvalidCursor.
+func workspaceQueryCursor(ctx context.Context, spec WorkspaceQuery, page PageRequest) (WorkspaceQuery,
+	*database.Cursor, error) {
+	if err := validation.Check(ctx, spec); err != nil {
+		return WorkspaceQuery{}, nil, fmt.Errorf("error during data validation of WorkspaceQuery: %w", err)
+	}
+	spec = withDefaultSort(spec)
+
+	cursor, err := pageCursor(page)
+	if err != nil {
+		return WorkspaceQuery{}, nil, err
+	}
+
+	if cursor != nil && cursor.Sort != spec.sortName() {
+		return WorkspaceQuery{}, nil, database.ErrorInvalidCursor
+	}
+
+	return spec, cursor, nil
+}
+
+// workspaceCursor returns cursor which points right after wsData in the order of spec.
+func workspaceCursor(spec WorkspaceQuery, wsData Workspace) database.Cursor {
+	cursor := database.Cursor{Sort: spec.sortName(), ID: wsData.ID}
+	switch spec.SortBy {
+	case SortByName:
+		cursor.Text = wsData.Name
+	case SortByDateCreated:
+		cursor.Date = wsData.DateCreated
+	default:
+		cursor.Date = wsData.DateUpdated
+	}
+
+	return cursor
+}
+
+// compileWorkspaceQuery compiles spec with filled order into SQL query over Workspace entities of projects readable
+// by the claims subject. Rows after cursor are selected when cursor is specified, zero limit returns all rows.
+// Count query applies the same filters regardless of cursor and limit.
+func compileWorkspaceQuery(claims auth.Claims, spec WorkspaceQuery, cursor *database.Cursor,
+	limit int32) compiledQuery {
+	params := project.NewVisibility(claims).Params()
+	conditions := []string{"w.date_deleted IS NULL", project.ReadableCondition}
+
+	if !spec.IncludeArchived {
+		conditions = append(conditions, "w.date_archived IS NULL AND p.date_archived IS NULL")
+	}
+	if len(spec.ProjectIDs) > 0 {
+		conditions = append(conditions, "w.project_id IN ("+listParams(params, "project_id", spec.ProjectIDs)+")")
+	}
+	if len(spec.StemIDs) > 0 {
+		conditions = append(conditions, "w.stem_id IN ("+listParams(params, "stem_id", spec.StemIDs)+")")
+	}
+	if spec.CreatedBy != "" {
+		params["created_by"] = spec.CreatedBy
+		conditions = append(conditions, "w.created_by_user_id = :created_by")
+	}
+	if spec.NamePrefix != "" {
+		params["name_prefix"] = database.LikePattern(spec.NamePrefix) + "%"
+		conditions = append(conditions, `LOWER(w.name) LIKE :name_prefix ESCAPE '\'`)
+	}
+	if spec.NameContains != "" {
+		params["name_contains"] = "%" + database.LikePattern(spec.NameContains) + "%"
+		conditions = append(conditions, `LOWER(w.name) LIKE :name_contains ESCAPE '\'`)
+	}
+	if spec.CreatedAfter != nil {
+		params["created_after"] = spec.CreatedAfter.UTC()
+		conditions = append(conditions, "w.date_created >= :created_after")
+	}
+	if spec.CreatedBefore != nil {
+		params["created_before"] = spec.CreatedBefore.UTC()
+		conditions = append(conditions, "w.date_created < :created_before")
+	}
+	if spec.UpdatedAfter != nil {
+		params["updated_after"] = spec.UpdatedAfter.UTC()
+		conditions = append(conditions, "w.date_updated >= :updated_after")
+	}
+	if spec.UpdatedBefore != nil {
+		params["updated_before"] = spec.UpdatedBefore.UTC()
+		conditions = append(conditions, "w.date_updated < :updated_before")
+	}
+
+	const selectQuery = `
+	SELECT
+		w.workspace_id,
+		w.project_id,
+		w.stem_id,
+		w.name,
+		w.description,
+		w.asset_amount_limit,
+		w.x_max,
+		w.y_max,
+		w.z_max,
+		w.date_created,
+		w.created_by_user_id,
+		w.date_updated,
+		w.updated_by_user_id,
+		w.date_archived,
+		w.date_deleted
+	FROM
+		WORKSPACE AS w
+		JOIN PROJECT AS p ON p.project_id = w.project_id
+	WHERE
+		`
+
+	const countQuery = `
+	SELECT
+		COUNT(*) AS amount
+	FROM
+		WORKSPACE AS w
+		JOIN PROJECT AS p ON p.project_id = w.project_id
+	WHERE
+		`
+
+	compiled := compiledQuery{
+		countQuery: countQuery + strings.Join(conditions, "\n\t\tAND "),
+		params:     params,
+	}
+
+	column, direction, operator := workspaceSortColumns[spec.SortBy], "DESC", "<"
+	if spec.SortDirection == SortAscending {
+		direction, operator = "ASC", ">"
+	}
+
+	if cursor != nil {
+		afterKey := ":after_date"
+		if spec.SortBy == SortByName {
+			afterKey = ":after_text"
+		}
+		params["after_date"] = cursor.Date.UTC()
+		params["after_text"] = cursor.Text
+		params["after_id"] = cursor.ID
+		conditions = append(conditions, fmt.Sprintf(
+			"(%[1]s %[2]s %[3]s OR (%[1]s = %[3]s AND w.workspace_id %[2]s :after_id))", column, operator, afterKey))
+	}
+
+	compiled.query = selectQuery + strings.Join(conditions, "\n\t\tAND ") +
+		"\n\tORDER BY " + column + " " + direction + ", w.workspace_id " + direction
+	if limit > 0 {
+		params["top"] = limit
+		compiled.query += "\n\tLIMIT :top"
+	}
+
+	return compiled
+}
+
+// listParams adds values into params as separate named arguments with the name prefix and returns the list of their
+// placeholders, e.g. ":stem_id_0, :stem_id_1".
+func listParams(params map[string]interface{}, name string, values []string) string {
+	placeholders := make([]string, 0, len(values))
+	for i, value := range values {
+		paramName := fmt.Sprintf("%s_%d", name, i)
+		params[paramName] = value
+		placeholders = append(placeholders, ":"+paramName)
+	}
+
+	return strings.Join(placeholders, ", ")
+}
+
+// matchesWorkspace reports whether wsData satisfies filters of spec. It mirrors conditions of compileWorkspaceQuery
+// except visibility and archive state of the workspace project which are checked separately.
+func matchesWorkspace(spec WorkspaceQuery, wsData Workspace) bool {
+	name := strings.ToLower(wsData.Name)
+	switch {
+	case wsData.DateDeleted != nil:
+		return false
+	case !spec.IncludeArchived && wsData.DateArchived != nil:
+		return false
+	case len(spec.ProjectIDs) > 0 && !containsString(spec.ProjectIDs, wsData.ProjectID):
+		return false
+	case len(spec.StemIDs) > 0 && !containsString(spec.StemIDs, wsData.StemID):
+		return false
+	case spec.CreatedBy != "" && wsData.CreatedByUser != spec.CreatedBy:
+		return false
+	case spec.NamePrefix != "" && !strings.HasPrefix(name, strings.ToLower(spec.NamePrefix)):
+		return false
+	case spec.NameContains != "" && !strings.Contains(name, strings.ToLower(spec.NameContains)):
+		return false
+	case spec.CreatedAfter != nil && wsData.DateCreated.Before(*spec.CreatedAfter):
+		return false
+	case spec.CreatedBefore != nil && !wsData.DateCreated.Before(*spec.CreatedBefore):
+		return false
+	case spec.UpdatedAfter != nil && wsData.DateUpdated.Before(*spec.UpdatedAfter):
+		return false
+	case spec.UpdatedBefore != nil && !wsData.DateUpdated.Before(*spec.UpdatedBefore):
+		return false
+	}
+
+	return true
+}
+
+// compareWorkspaces compares Workspace entities in the order of spec with filled sort field and direction,
+// negative result means that a goes before b.
+func compareWorkspaces(spec WorkspaceQuery, a Workspace, b Workspace) int {
+	result := 0
+	switch spec.SortBy {
+	case SortByName:
+		result = strings.Compare(a.Name, b.Name)
+	case SortByDateCreated:
+		result = compareTimes(a.DateCreated.UnixNano(), b.DateCreated.UnixNano())
+	default:
+		result = compareTimes(a.DateUpdated.UnixNano(), b.DateUpdated.UnixNano())
+	}
+	if result == 0 {
+		result = strings.Compare(a.ID, b.ID)
+	}
+
+	if spec.SortDirection == SortDescending {
+		return -result
+	}
+
+	return result
+}
+
+// cursorWorkspace returns Workspace entity which holds sort keys of cursor, so it can be compared with
+// compareWorkspaces.
+func cursorWorkspace(cursor database.Cursor) Workspace {
+	return Workspace{
+		ID:          cursor.ID,
+		Name:        cursor.Text,
+		DateCreated: cursor.Date,
+		DateUpdated: cursor.Date,
+	}
+}
+
+// compareTimes compares two Unix times in nanoseconds.
+func compareTimes(a int64, b int64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// containsString reports whether values contain value.
+func containsString(values []string, value string) bool {
+	for _, item := range values {
+		if item == value {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,121 @@
+package workspace
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/SKorolchuk/dpio-workspace/internal/pkg/infra/database"
+)
+
+// namedParamRegex matches named arguments of compiled queries, e.g. ":after_id".
+var namedParamRegex = regexp.MustCompile(`:(\w+)`)
+
+func TestCompileWorkspaceQuery(t *testing.T) {
+	after := time.Date(2021, 1, 1, 3, 0, 0, 0, time.FixedZone("UTC+3", 3*60*60))
+	cursor := &database.Cursor{Date: after, Text: "Workspace", ID: missingID}
+	injection := "x' OR '1'='1"
+
+	tests := []struct {
+		name        string
+		spec        WorkspaceQuery
+		cursor      *database.Cursor
+		limit       int32
+		wantQuery   []string
+		unwantQuery []string
+		wantParams  map[string]interface{}
+	}{
+		{
+			name:  "default order without filters",
+			spec:  WorkspaceQuery{},
+			limit: 10,
+			wantQuery: []string{"w.date_archived IS NULL", "ORDER BY w.date_updated DESC, w.workspace_id DESC",
+				"LIMIT :top"},
+			unwantQuery: []string{":after_id", "w.project_id IN"},
+			wantParams:  map[string]interface{}{"top": int32(10), "reader_id": ownerID},
+		},
+		{
+			name:        "archived workspaces without limit",
+			spec:        WorkspaceQuery{IncludeArchived: true, SortBy: SortByDateCreated, SortDirection: SortAscending},
+			wantQuery:   []string{"ORDER BY w.date_created ASC, w.workspace_id ASC"},
+			unwantQuery: []string{"w.date_archived IS NULL", "LIMIT"},
+		},
+		{
+			name: "list filters",
+			spec: WorkspaceQuery{ProjectIDs: []string{ownerID, missingID}, StemIDs: []string{StickerWorkspaceType}},
+			wantQuery: []string{"w.project_id IN (:project_id_0, :project_id_1)",
+				"w.stem_id IN (:stem_id_0)"},
+			wantParams: map[string]interface{}{"project_id_0": ownerID, "project_id_1": missingID,
+				"stem_id_0": StickerWorkspaceType},
+		},
+		{
+			name: "user values are passed as arguments",
+			spec: WorkspaceQuery{CreatedBy: injection, NamePrefix: injection, NameContains: "50%_off"},
+			wantQuery: []string{"w.created_by_user_id = :created_by", "LOWER(w.name) LIKE :name_prefix",
+				"LOWER(w.name) LIKE :name_contains"},
+			unwantQuery: []string{injection, "50%"},
+			wantParams: map[string]interface{}{"created_by": injection, "name_prefix": "x' or '1'='1%",
+				"name_contains": `%50\%\_off%`},
+		},
+		{
+			name:       "date ranges",
+			spec:       WorkspaceQuery{CreatedAfter: &after, UpdatedBefore: &after},
+			wantQuery:  []string{"w.date_created >= :created_after", "w.date_updated < :updated_before"},
+			wantParams: map[string]interface{}{"created_after": after.UTC(), "updated_before": after.UTC()},
+		},
+		{
+			name:   "descending date order after cursor",
+			spec:   WorkspaceQuery{SortBy: SortByDateUpdated, SortDirection: SortDescending},
+			cursor: cursor,
+			wantQuery: []string{"(w.date_updated < :after_date OR " +
+				"(w.date_updated = :after_date AND w.workspace_id < :after_id))"},
+			wantParams: map[string]interface{}{"after_date": after.UTC(), "after_id": missingID},
+		},
+		{
+			name:   "ascending name order after cursor",
+			spec:   WorkspaceQuery{SortBy: SortByName, SortDirection: SortAscending},
+			cursor: cursor,
+			wantQuery: []string{"(w.name > :after_text OR (w.name = :after_text AND w.workspace_id > :after_id))",
+				"ORDER BY w.name ASC, w.workspace_id ASC"},
+			wantParams: map[string]interface{}{"after_text": "Workspace", "after_id": missingID},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			spec := withDefaultSort(test.spec)
+			compiled := compileWorkspaceQuery(claimsOf(ownerID), spec, test.cursor, test.limit)
+
+			for _, fragment := range test.wantQuery {
+				if !strings.Contains(compiled.query, fragment) {
+					t.Errorf("query does not contain %q:\n%s", fragment, compiled.query)
+				}
+			}
+			for _, fragment := range test.unwantQuery {
+				if strings.Contains(compiled.query, fragment) {
+					t.Errorf("query contains %q:\n%s", fragment, compiled.query)
+				}
+			}
+			for name, want := range test.wantParams {
+				if got, found := compiled.params[name]; !found || got != want {
+					t.Errorf("params[%q] = %v, want %v", name, got, want)
+				}
+			}
+
+			for _, query := range []string{compiled.query, compiled.countQuery} {
+				for _, match := range namedParamRegex.FindAllStringSubmatch(query, -1) {
+					if _, found := compiled.params[match[1]]; !found {
+						t.Errorf("argument %q of the query is not specified", match[1])
+					}
+				}
+			}
+
+			for _, fragment := range []string{"ORDER BY", "LIMIT", ":after_id"} {
+				if strings.Contains(compiled.countQuery, fragment) {
+					t.Errorf("count query contains %q:\n%s", fragment, compiled.countQuery)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package workspace
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/SKorolchuk/dpio-workspace/internal/pkg/infra/auth"
+	"github.com/SKorolchuk/dpio-workspace/internal/pkg/store/project"
+)
+
+// WorkspaceRepository declares storage-agnostic operations over Workspace entities.
+type WorkspaceRepository interface {
+	CreateWorkspace(ctx context.Context, claims auth.Claims, ws NewWorkspace, now time.Time) (Workspace, error)
+	UpdateWorkspace(ctx context.Context, claims auth.Claims, wsId string, ws UpdateWorkspace, now time.Time) error
+	DeleteWorkspace(ctx context.Context, claims auth.Claims, wsId string, now time.Time) error
+	ArchiveWorkspace(ctx context.Context, claims auth.Claims, wsId string, now time.Time) error
+	RestoreWorkspace(ctx context.Context, claims auth.Claims, wsId string, now time.Time) error
+	PurgeWorkspace(ctx context.Context, claims auth.Claims, wsId string) ([]string, error)
+	CloneWorkspace(ctx context.Context, claims auth.Claims, wsId string, target CloneTarget,
+		now time.Time) (Workspace, error)
+	ExportWorkspace(ctx context.Context, claims auth.Claims, wsId string, w io.Writer) error
+	ImportWorkspace(ctx context.Context, claims auth.Claims, doc Document, options ImportOptions,
+		now time.Time) (ImportReport, error)
+	QueryWorkspaces(ctx context.Context, claims auth.Claims, includeArchived bool, skip int32,
+		top int32) ([]Workspace, error)
+	QueryWorkspacesPage(ctx context.Context, claims auth.Claims, includeArchived bool,
+		page PageRequest) (WorkspacePage, error)
+	QueryWorkspacesBySpec(ctx context.Context, claims auth.Claims, spec WorkspaceQuery,
+		page PageRequest) (WorkspacePage, error)
+	QueryWorkspaceByID(ctx context.Context, claims auth.Claims, wsId string) (Workspace, error)
+	QueryWorkspacesByProjectAndStem(ctx context.Context, claims auth.Claims, projectId string, stemId *string,
+		includeArchived bool) ([]Workspace, error)
+}
+
+// AssetRepository declares storage-agnostic operations over Asset entities.
+type AssetRepository interface {
+	CreateAsset(ctx context.Context, claims auth.Claims, newAsset NewAsset, now time.Time) (Asset, error)
+	UpdateAsset(ctx context.Context, claims auth.Claims, assetId string, asset UpdateAsset, now time.Time) error
+	DeleteAsset(ctx context.Context, claims auth.Claims, assetId string) error
+	QueryAssetsByWorkspace(ctx context.Context, claims auth.Claims, workspaceId string, skip int32,
+		top int32) ([]Asset, error)
+	QueryAssetsByWorkspacePage(ctx context.Context, claims auth.Claims, workspaceId string,
+		page PageRequest) (AssetPage, error)
+	QueryAssetByID(ctx context.Context, claims auth.Claims, assetId string) (Asset, error)
+}
+
+// StemRepository declares storage-agnostic operations over Stem entities.
+type StemRepository interface {
+	QueryStems(ctx context.Context, skip int32, top int32) ([]Stem, error)
+	QueryStemsPage(ctx context.Context, page PageRequest) (StemPage, error)
+	QueryStemByID(ctx context.Context, stemId string) (Stem, error)
+}
+
+// ProjectPolicy declares checks of Project entity visibility, state, ownership and write permission which in-memory
+// implementations rely on, since Project entities are kept outside of them.
+type ProjectPolicy interface {
+	QueryProjectByID(ctx context.Context, claims auth.Claims, projectId string) (project.Project, error)
+	CanReadProject(ctx context.Context, claims auth.Claims, projectId string) (bool, error)
+	CanWriteProject(ctx context.Context, claims auth.Claims, projectId string) (bool, error)
+	IsProjectOwner(ctx context.Context, claims auth.Claims, projectId string) (bool, error)
+}
+
+// Repository declares all operations over Workspace, Asset and Stem entities.
+// Implementations should report missing entities with database.ErrorNotFound and
+// actions of non-authorized users with database.ErrorForbidden.
+type Repository interface {
+	WorkspaceRepository
+	AssetRepository
+	StemRepository
+}
+
+// Compile-time checks of Repository implementations.
+var (
+	_ Repository = Store{}
+	_ Repository = (*MemoryStore)(nil)
+
+	_ ProjectPolicy = (*project.MemoryStore)(nil)
+)
@@ -25,10 +25,11 @@ func (str Store) QueryStems(ctx context.Context, skip int32, top int32) ([]Stem,
 	FROM
 		STEM AS s
 	ORDER BY s.stem_id DESC
-	OFFSET :offset ROWS FETCH NEXT :top ROWS ONLY`
+	LIMIT :top OFFSET :offset`
 
 	var stemCollection []Stem
-	if err := database.NamedQuerySlice(ctx, str.logger, str.connection, query, queryParams, &stemCollection); err != nil {
+	connection := str.cluster.Reader(ctx)
+	if err := database.NamedQuerySlice(ctx, str.logger, connection, query, queryParams, &stemCollection); err != nil {
 		if err == database.ErrorNotFound {
 			return nil, database.ErrorNotFound
 		}
@@ -39,7 +40,75 @@ func (str Store) QueryStems(ctx context.Context, skip int32, top int32) ([]Stem,
 	return stemCollection, nil
 }
 
+// QueryStemsPage looking for a page of Stem entities using keyset pagination with descending order by identifier
+// field, cursors of Stem pages keep only the identifier.
+// If the page cursor is malformed, the method returns database.ErrorInvalidCursor.
+func (str Store) QueryStemsPage(ctx context.Context, page PageRequest) (StemPage, error) {
+	cursor, err := pageCursor(page)
+	if err != nil {
+		return StemPage{}, fmt.Errorf("error during search of Stem entities: %w", err)
+	}
+
+	queryParams := struct {
+		database.Keyset
+		Top int32 `db:"top"`
+	}{
+		Keyset: database.NewKeyset(cursor),
+		Top:    page.Top + 1,
+	}
+
+	const selectQuery = `
+	SELECT
+		s.stem_id,
+		s.name
+	FROM
+		STEM AS s`
+
+	const keysetCondition = `
+	WHERE
+		s.stem_id < :after_id`
+
+	const orderQuery = `
+	ORDER BY s.stem_id DESC
+	LIMIT :top`
+
+	const countQuery = `
+	SELECT
+		COUNT(*) AS amount
+	FROM
+		STEM AS s`
+
+	query := selectQuery
+	if cursor != nil {
+		query += keysetCondition
+	}
+	query += orderQuery
+
+	var stemCollection []Stem
+	connection := str.cluster.Reader(ctx)
+	if err := database.NamedQuerySlice(ctx, str.logger, connection, query, queryParams, &stemCollection); err != nil {
+		return StemPage{}, fmt.Errorf("error during search of Stem entities: %w", err)
+	}
+
+	stemPage := StemPage{Items: stemCollection}
+	if len(stemCollection) > int(page.Top) {
+		stemPage.Items = stemCollection[:page.Top]
+		stemPage.NextCursor = database.EncodeCursor(database.Cursor{ID: stemPage.Items[page.Top-1].ID})
+	}
+
+	if page.WithTotal {
+		total, err := str.countRows(ctx, countQuery, queryParams)
+		if err != nil {
+			return StemPage{}, fmt.Errorf("error during count of Stem entities: %w", err)
+		}
+		stemPage.Total = &total
+	}
+
+	return stemPage, nil
+}
+
 // QueryStemByID looking for Stem entity with stemId identifier.
+// The query is served by the primary database like other lookups by identifier.
 func (str Store) QueryStemByID(ctx context.Context, stemId string) (Stem, error) {
 	if err := uuid.Validate(stemId); err != nil {
 		return Stem{}, err
@@ -58,10 +127,11 @@ func (str Store) QueryStemByID(ctx context.Context, stemId string) (Stem, error)
 	FROM
 		STEM AS s
 	WHERE
-		p.stem_id = :stem_id`
+		s.stem_id = :stem_id`
 
 	var stem Stem
-	if err := database.NamedQueryStruct(ctx, str.logger, str.connection, query, queryParams, &stem); err != nil {
+	connection := str.cluster.Primary()
+	if err := database.NamedQueryStruct(ctx, str.logger, connection, query, queryParams, &stem); err != nil {
 		if err == database.ErrorNotFound {
 			return Stem{}, database.ErrorNotFound
 		}
@@ -1,20 +1,22 @@
 package workspace
 
 import (
-	"github.com/jmoiron/sqlx"
+	"github.com/SKorolchuk/dpio-workspace/internal/pkg/infra/database"
+
 	"go.uber.org/zap"
 )
 
 // Store represents a point of access to Workspace, Asset and Stem entities.
+// Listing queries are served by a read replica of the cluster when a healthy one is available.
 type Store struct {
-	logger     *zap.SugaredLogger
-	connection *sqlx.DB
+	logger  *zap.SugaredLogger
+	cluster *database.Cluster
 }
 
 // NewStore creates an instance of Store for access to Workspace, Asset and Stem entities.
-func NewStore(logger *zap.SugaredLogger, connection *sqlx.DB) Store {
+func NewStore(logger *zap.SugaredLogger, cluster *database.Cluster) Store {
 	return Store{
-		logger:     logger,
-		connection: connection,
+		logger:  logger,
+		cluster: cluster,
 	}
 }
@@ -4,15 +4,27 @@ package workspace
 import (
 	"context"
 	"fmt"
+	"sort"
 	"time"
 
 	"github.com/SKorolchuk/dpio-workspace/internal/pkg/infra/auth"
 	"github.com/SKorolchuk/dpio-workspace/internal/pkg/infra/database"
 	"github.com/SKorolchuk/dpio-workspace/internal/pkg/infra/uuid"
 	"github.com/SKorolchuk/dpio-workspace/internal/pkg/infra/validation"
+	"github.com/SKorolchuk/dpio-workspace/internal/pkg/store/project"
+
+	"github.com/jmoiron/sqlx"
 )
 
+// archivedCondition is a SQL condition which hides archived Workspace entities and workspaces of archived projects
+// unless include_archived parameter is set. It expects WORKSPACE aliased as w and PROJECT aliased as p.
+const archivedCondition = `(:include_archived OR (w.date_archived IS NULL AND p.date_archived IS NULL))`
+
+// assetRefBatchSize limits amount of external asset references which are checked by a single query.
+const assetRefBatchSize = 500
+
 // CreateWorkspace adds new Workspace entity to the database.
+// The project of the workspace must be writable by the claims subject and must not be archived.
 // If creation is successful, the method returns Workspace entity.
 // Can return validation or database errors.
 func (str Store) CreateWorkspace(ctx context.Context, claims auth.Claims, ws NewWorkspace, now time.Time) (Workspace,
@@ -37,15 +49,19 @@ func (str Store) CreateWorkspace(ctx context.Context, claims auth.Claims, ws New
 		UpdatedByUser:    claims.Subject,
 	}
 
-	const query = `
-	INSERT INTO WORKSPACE
-		(workspace_id, project_id, stem_id, name, description, asset_amount_limit, x_max, y_max, z_max,
-			date_created, created_by_user_id, date_updated, updated_by_user_id)
-	VALUES (:workspace_id, :project_id, :stem_id, :name, :description, :asset_amount_limit, :x_max, :y_max,
-				:z_max, :date_created, :created_by_user_id, :date_updated, :updated_by_user_id)`
+	err := database.WithTransaction(ctx, str.cluster.Primary(), func(transaction *sqlx.Tx) error {
+		if err := str.checkWritableProject(ctx, transaction, claims, wsData.ProjectID); err != nil {
+			return fmt.Errorf("error during search of Project entity -> id={%q}: %w", wsData.ProjectID, err)
+		}
+
+		if err := str.insertWorkspace(ctx, transaction, wsData); err != nil {
+			return fmt.Errorf("error during create of new Workspace entity: %w", err)
+		}
 
-	if err := database.NamedExecContext(ctx, str.logger, str.connection, query, wsData); err != nil {
-		return Workspace{}, fmt.Errorf("error during create of new Workspace entity: %w", err)
+		return nil
+	})
+	if err != nil {
+		return Workspace{}, err
 	}
 
 	return wsData, nil
@@ -63,14 +79,13 @@ func (str Store) UpdateWorkspace(ctx context.Context, claims auth.Claims, wsId s
 		return fmt.Errorf("error during data validation of Workspace entity: %w", err)
 	}
 
-	wsData, err := str.QueryWorkspaceByID(ctx, wsId)
+	wsData, err := str.queryWorkspaceByID(ctx, wsId)
 	if err != nil {
 		return fmt.Errorf("error during search of Workspace entity -> id={%q}: %w", wsId, err)
 	}
 
-	// TODO implement group-based edit check in downstream logic
-	if wsData.CreatedByUser != claims.Subject {
-		return database.ErrorForbidden
+	if err := str.checkChangeAllowed(ctx, claims, wsId); err != nil {
+		return err
 	}
 
 	if ws.Name != nil {
@@ -112,59 +127,280 @@ func (str Store) UpdateWorkspace(ctx context.Context, claims auth.Claims, wsId s
 	WHERE
 		workspace_id = :workspace_id`
 
-	if err := database.NamedExecContext(ctx, str.logger, str.connection, query, wsData); err != nil {
+	if err := database.NamedExecContext(ctx, str.logger, str.cluster.Primary(), query, wsData); err != nil {
 		return fmt.Errorf("error during update of Workspace entity -> id={%s}: %w", wsId, err)
 	}
 
 	return nil
 }
 
-// DeleteWorkspace removes existing Workspace entity in the database.
+// DeleteWorkspace marks existing Workspace entity as deleted at now. The workspace and its assets are hidden from all
+// queries until the workspace is restored, after the retention period they are removed permanently by the purge job.
 // If error occurs, the method can return database errors.
-func (str Store) DeleteWorkspace(ctx context.Context, claims auth.Claims, wsId string) error {
+func (str Store) DeleteWorkspace(ctx context.Context, claims auth.Claims, wsId string, now time.Time) error {
 	if err := uuid.Validate(wsId); err != nil {
 		return database.ErrorInvalidIdentifier
 	}
 
-	wsData, err := str.QueryWorkspaceByID(ctx, wsId)
-	if err != nil {
+	if _, err := str.queryWorkspaceByID(ctx, wsId); err != nil {
 		return fmt.Errorf("error during search of Workspace entity -> id={%q}: %w", wsId, err)
 	}
 
-	if wsData.CreatedByUser != claims.Subject {
-		return database.ErrorForbidden
+	if err := str.checkChangeAllowed(ctx, claims, wsId); err != nil {
+		return err
 	}
 
 	queryParams := struct {
-		WorkspaceID string `db:"workspace_id"`
+		WorkspaceID string    `db:"workspace_id"`
+		UserID      string    `db:"updated_by_user_id"`
+		Now         time.Time `db:"now"`
+	}{
+		WorkspaceID: wsId,
+		UserID:      claims.Subject,
+		Now:         now,
+	}
+
+	const query = `
+	UPDATE
+		WORKSPACE
+	SET
+		"date_deleted" = :now,
+		"date_updated" = :now,
+		"updated_by_user_id" = :updated_by_user_id
+	WHERE
+		workspace_id = :workspace_id AND date_deleted IS NULL`
+
+	if err := database.NamedExecContext(ctx, str.logger, str.cluster.Primary(), query, queryParams); err != nil {
+		return fmt.Errorf("error during delete of Workspace entity -> id={%q}: %w", wsId, err)
+	}
+
+	return nil
+}
+
+// ArchiveWorkspace marks existing Workspace entity as archived at now, so it is hidden from default queries.
+// Archiving of already archived workspace keeps its original archive date.
+// If error occurs, the method can return database errors.
+func (str Store) ArchiveWorkspace(ctx context.Context, claims auth.Claims, wsId string, now time.Time) error {
+	if err := uuid.Validate(wsId); err != nil {
+		return database.ErrorInvalidIdentifier
+	}
+
+	if _, err := str.queryWorkspaceByID(ctx, wsId); err != nil {
+		return fmt.Errorf("error during search of Workspace entity -> id={%q}: %w", wsId, err)
+	}
+
+	if err := str.checkChangeAllowed(ctx, claims, wsId); err != nil {
+		return err
+	}
+
+	queryParams := struct {
+		WorkspaceID string    `db:"workspace_id"`
+		UserID      string    `db:"updated_by_user_id"`
+		Now         time.Time `db:"now"`
 	}{
 		WorkspaceID: wsId,
+		UserID:      claims.Subject,
+		Now:         now,
+	}
+
+	const query = `
+	UPDATE
+		WORKSPACE
+	SET
+		"date_archived" = :now,
+		"date_updated" = :now,
+		"updated_by_user_id" = :updated_by_user_id
+	WHERE
+		workspace_id = :workspace_id AND date_archived IS NULL`
+
+	if err := database.NamedExecContext(ctx, str.logger, str.cluster.Primary(), query, queryParams); err != nil {
+		return fmt.Errorf("error during archive of Workspace entity -> id={%q}: %w", wsId, err)
+	}
+
+	return nil
+}
+
+// RestoreWorkspace returns archived or deleted Workspace entity back to default queries.
+// Deleted workspaces can be restored until they are purged, workspaces of a deleted project are restored together
+// with the project.
+// If error occurs, the method can return database errors.
+func (str Store) RestoreWorkspace(ctx context.Context, claims auth.Claims, wsId string, now time.Time) error {
+	if err := uuid.Validate(wsId); err != nil {
+		return database.ErrorInvalidIdentifier
+	}
+
+	if _, err := str.queryWorkspace(ctx, wsId, true); err != nil {
+		return fmt.Errorf("error during search of Workspace entity -> id={%q}: %w", wsId, err)
 	}
 
-	// TODO Implement cascade delete of Assets and return list of marked Asset External Refs
+	if err := str.checkChangeAllowed(ctx, claims, wsId); err != nil {
+		return err
+	}
+
+	queryParams := struct {
+		WorkspaceID string    `db:"workspace_id"`
+		UserID      string    `db:"updated_by_user_id"`
+		Now         time.Time `db:"now"`
+	}{
+		WorkspaceID: wsId,
+		UserID:      claims.Subject,
+		Now:         now,
+	}
 
 	const query = `
+	UPDATE
+		WORKSPACE
+	SET
+		"date_archived" = NULL,
+		"date_deleted" = NULL,
+		"date_updated" = :now,
+		"updated_by_user_id" = :updated_by_user_id
+	WHERE
+		workspace_id = :workspace_id AND (date_archived IS NOT NULL OR date_deleted IS NOT NULL)`
+
+	if err := database.NamedExecContext(ctx, str.logger, str.cluster.Primary(), query, queryParams); err != nil {
+		return fmt.Errorf("error during restore of Workspace entity -> id={%q}: %w", wsId, err)
+	}
+
+	return nil
+}
+
+// PurgeWorkspace permanently removes Workspace entity together with its Asset entities in a single transaction,
+// regardless of whether the workspace was deleted before.
+// If removal is successful, the method returns external asset references which are no longer used by any Asset
+// entity, so the asset storage can collect their binaries. The references are taken from the removed rows, so assets
+// added concurrently are either removed and reported or kept together with their references.
+// If error occurs, the method can return database errors.
+func (str Store) PurgeWorkspace(ctx context.Context, claims auth.Claims, wsId string) ([]string, error) {
+	if err := uuid.Validate(wsId); err != nil {
+		return nil, database.ErrorInvalidIdentifier
+	}
+
+	if _, err := str.queryWorkspace(ctx, wsId, true); err != nil {
+		return nil, fmt.Errorf("error during search of Workspace entity -> id={%q}: %w", wsId, err)
+	}
+
+	if err := str.checkChangeAllowed(ctx, claims, wsId); err != nil {
+		return nil, err
+	}
+
+	queryParams := struct {
+		WorkspaceID string `db:"workspace_id"`
+	}{
+		WorkspaceID: wsId,
+	}
+
+	const deleteAssetsQuery = `
+	DELETE FROM
+		ASSET
+	WHERE
+		workspace_id = :workspace_id
+	RETURNING asset_external_ref_id`
+
+	const deleteWorkspaceQuery = `
 	DELETE FROM
 		WORKSPACE
 	WHERE
 		workspace_id = :workspace_id`
 
-	if err := database.NamedExecContext(ctx, str.logger, str.connection, query, queryParams); err != nil {
-		return fmt.Errorf("error during delete of Workspace entity -> id={%q}: %w", wsId, err)
+	var assetRefs []string
+	err := database.WithTransaction(ctx, str.cluster.Primary(), func(transaction *sqlx.Tx) error {
+		var removedAssets []struct {
+			AssetRefID string `db:"asset_external_ref_id"`
+		}
+		if err := database.NamedQuerySlice(ctx, str.logger, transaction, deleteAssetsQuery, queryParams,
+			&removedAssets); err != nil {
+			return err
+		}
+
+		removedRefs := make([]string, 0, len(removedAssets))
+		for _, removedAsset := range removedAssets {
+			removedRefs = append(removedRefs, removedAsset.AssetRefID)
+		}
+
+		var err error
+		if assetRefs, err = UnusedAssetRefs(ctx, transaction, removedRefs); err != nil {
+			return err
+		}
+
+		return database.NamedExecContext(ctx, str.logger, transaction, deleteWorkspaceQuery, queryParams)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error during purge of Workspace entity -> id={%q}: %w", wsId, err)
 	}
 
-	return nil
+	return assetRefs, nil
+}
+
+// UnusedAssetRefs filters distinct external asset references which are not used by any Asset entity visible to the
+// transaction and returns them in ascending order. Purges call it after removal of their assets, so references of
+// assets added meanwhile are reported exactly when the removal took them along.
+func UnusedAssetRefs(ctx context.Context, transaction *sqlx.Tx, assetRefs []string) ([]string, error) {
+	const usedRefsQuery = `
+	SELECT DISTINCT
+		a.asset_external_ref_id
+	FROM
+		ASSET AS a
+	WHERE
+		a.asset_external_ref_id IN (?)`
+
+	unusedRefs := make(map[string]bool, len(assetRefs))
+	for _, assetRef := range assetRefs {
+		unusedRefs[assetRef] = true
+	}
+
+	candidates := make([]string, 0, len(unusedRefs))
+	for assetRef := range unusedRefs {
+		candidates = append(candidates, assetRef)
+	}
+	sort.Strings(candidates)
+
+	// References are checked in batches to stay below limits of bound parameters in a single query.
+	for start := 0; start < len(candidates); start += assetRefBatchSize {
+		end := start + assetRefBatchSize
+		if end > len(candidates) {
+			end = len(candidates)
+		}
+
+		query, args, err := sqlx.In(usedRefsQuery, candidates[start:end])
+		if err != nil {
+			return nil, err
+		}
+
+		var usedRefs []string
+		if err := transaction.SelectContext(ctx, &usedRefs, transaction.Rebind(query), args...); err != nil {
+			return nil, err
+		}
+		for _, assetRef := range usedRefs {
+			delete(unusedRefs, assetRef)
+		}
+	}
+
+	result := make([]string, 0, len(unusedRefs))
+	for _, assetRef := range candidates {
+		if unusedRefs[assetRef] {
+			result = append(result, assetRef)
+		}
+	}
+
+	return result, nil
 }
 
-// QueryWorkspaces looking for all Workspace entities using skip/top mechanics with descending order by update date
-// field.
-func (str Store) QueryWorkspaces(ctx context.Context, skip int32, top int32) ([]Workspace, error) {
+// QueryWorkspaces looking for Workspace entities of projects readable by the claims subject using skip/top mechanics
+// with descending order by update date and identifier fields.
+// Archived workspaces and workspaces of archived projects are returned only if includeArchived is set.
+func (str Store) QueryWorkspaces(ctx context.Context, claims auth.Claims, includeArchived bool, skip int32,
+	top int32) ([]Workspace, error) {
 	queryParams := struct {
-		Skip int32 `db:"offset"`
-		Top  int32 `db:"top"`
+		project.Visibility
+		IncludeArchived bool  `db:"include_archived"`
+		Skip            int32 `db:"offset"`
+		Top             int32 `db:"top"`
 	}{
-		Skip: skip,
-		Top:  top,
+		Visibility:      project.NewVisibility(claims),
+		IncludeArchived: includeArchived,
+		Skip:            skip,
+		Top:             top,
 	}
 
 	const query = `
@@ -181,14 +417,21 @@ func (str Store) QueryWorkspaces(ctx context.Context, skip int32, top int32) ([]
 		w.date_created,
 		w.created_by_user_id,
 		w.date_updated,
-		w.updated_by_user_id
+		w.updated_by_user_id,
+		w.date_archived,
+		w.date_deleted
 	FROM
 		WORKSPACE AS w
-	ORDER BY w.date_updated DESC
-	OFFSET :offset ROWS FETCH NEXT :top ROWS ONLY`
+		JOIN PROJECT AS p ON p.project_id = w.project_id
+	WHERE
+		w.date_deleted IS NULL AND ` + project.ReadableCondition + `
+		AND ` + archivedCondition + `
+	ORDER BY w.date_updated DESC, w.workspace_id DESC
+	LIMIT :top OFFSET :offset`
 
 	var wsCollection []Workspace
-	if err := database.NamedQuerySlice(ctx, str.logger, str.connection, query, queryParams, &wsCollection); err != nil {
+	connection := str.cluster.Reader(ctx)
+	if err := database.NamedQuerySlice(ctx, str.logger, connection, query, queryParams, &wsCollection); err != nil {
 		if err == database.ErrorNotFound {
 			return nil, database.ErrorNotFound
 		}
@@ -199,15 +442,64 @@ func (str Store) QueryWorkspaces(ctx context.Context, skip int32, top int32) ([]
 	return wsCollection, nil
 }
 
-// QueryWorkspaceByID looking for Workspace entity with wsId identifier.
-func (str Store) QueryWorkspaceByID(ctx context.Context, wsId string) (Workspace, error) {
+// QueryWorkspacesPage looking for a page of Workspace entities of projects readable by the claims subject using
+// keyset pagination with descending order by update date and identifier fields.
+// Archived workspaces and workspaces of archived projects are returned only if includeArchived is set.
+// If the page cursor is malformed, the method returns database.ErrorInvalidCursor.
+func (str Store) QueryWorkspacesPage(ctx context.Context, claims auth.Claims, includeArchived bool,
+	page PageRequest) (WorkspacePage, error) {
+	return str.QueryWorkspacesBySpec(ctx, claims, WorkspaceQuery{IncludeArchived: includeArchived}, page)
+}
+
+// QueryWorkspacesBySpec looking for a page of Workspace entities of projects readable by the claims subject which
+// satisfy filters of spec using keyset pagination with the order requested by spec.
+// If the page cursor is malformed or belongs to another order, the method returns database.ErrorInvalidCursor.
+// Can return validation or database errors.
+func (str Store) QueryWorkspacesBySpec(ctx context.Context, claims auth.Claims, spec WorkspaceQuery,
+	page PageRequest) (WorkspacePage, error) {
+	spec, cursor, err := workspaceQueryCursor(ctx, spec, page)
+	if err != nil {
+		return WorkspacePage{}, fmt.Errorf("error during search of Workspace entities: %w", err)
+	}
+
+	compiled := compileWorkspaceQuery(claims, spec, cursor, page.Top+1)
+
+	var wsCollection []Workspace
+	connection := str.cluster.Reader(ctx)
+	err = database.NamedQuerySlice(ctx, str.logger, connection, compiled.query, compiled.params, &wsCollection)
+	if err != nil {
+		return WorkspacePage{}, fmt.Errorf("error during search of Workspace entities: %w", err)
+	}
+
+	wsPage := WorkspacePage{Items: wsCollection}
+	if len(wsCollection) > int(page.Top) {
+		wsPage.Items = wsCollection[:page.Top]
+		wsPage.NextCursor = database.EncodeCursor(workspaceCursor(spec, wsPage.Items[page.Top-1]))
+	}
+
+	if page.WithTotal {
+		total, err := str.countRows(ctx, compiled.countQuery, compiled.params)
+		if err != nil {
+			return WorkspacePage{}, fmt.Errorf("error during count of Workspace entities: %w", err)
+		}
+		wsPage.Total = &total
+	}
+
+	return wsPage, nil
+}
+
+// QueryWorkspaceByID looking for Workspace entity with wsId identifier whose project is readable by the claims
+// subject. Workspace of a project which is not readable by the subject is reported with database.ErrorNotFound.
+func (str Store) QueryWorkspaceByID(ctx context.Context, claims auth.Claims, wsId string) (Workspace, error) {
 	if err := uuid.Validate(wsId); err != nil {
 		return Workspace{}, err
 	}
 
 	queryParams := struct {
+		project.Visibility
 		WorkspaceID string `db:"workspace_id"`
 	}{
+		Visibility:  project.NewVisibility(claims),
 		WorkspaceID: wsId,
 	}
 
@@ -225,14 +517,18 @@ func (str Store) QueryWorkspaceByID(ctx context.Context, wsId string) (Workspace
 		w.date_created,
 		w.created_by_user_id,
 		w.date_updated,
-		w.updated_by_user_id
+		w.updated_by_user_id,
+		w.date_archived,
+		w.date_deleted
 	FROM
 		WORKSPACE AS w
+		JOIN PROJECT AS p ON p.project_id = w.project_id
 	WHERE
-		p.workspace_id = :workspace_id`
+		w.workspace_id = :workspace_id AND w.date_deleted IS NULL AND ` + project.ReadableCondition
 
 	var wsData Workspace
-	if err := database.NamedQueryStruct(ctx, str.logger, str.connection, query, queryParams, &wsData); err != nil {
+	connection := str.cluster.Primary()
+	if err := database.NamedQueryStruct(ctx, str.logger, connection, query, queryParams, &wsData); err != nil {
 		if err == database.ErrorNotFound {
 			return Workspace{}, database.ErrorNotFound
 		}
@@ -243,28 +539,99 @@ func (str Store) QueryWorkspaceByID(ctx context.Context, wsId string) (Workspace
 	return wsData, nil
 }
 
-// QueryWorkspacesByProjectAndStem looking for all Workspace entities that belong to a specific Project and have a
-// specific Stem with descending order by update date field.
-// If stemId argument equals to nil. No Stem filter will be applied.
-func (str Store) QueryWorkspacesByProjectAndStem(ctx context.Context, projectId string, stemId *string) ([]Workspace,
-	error) {
+// checkChangeAllowed confirms that the claims subject can change Workspace entity with wsId identifier or its
+// entities, which is allowed to users who can write content of the workspace project, see project.WritableCondition.
+// If the change is not allowed, the method returns database.ErrorForbidden.
+func (str Store) checkChangeAllowed(ctx context.Context, claims auth.Claims, wsId string) error {
 	queryParams := struct {
-		ProjectId string `db:"project_id"`
-		StemId    string `db:"stem_id"`
+		project.Visibility
+		WorkspaceID string `db:"workspace_id"`
 	}{
-		ProjectId: projectId,
-		StemId:    "",
+		Visibility:  project.NewVisibility(claims),
+		WorkspaceID: wsId,
 	}
 
-	if err := uuid.Validate(projectId); err != nil {
-		return []Workspace{}, err
+	const query = `
+	SELECT
+		p.project_id
+	FROM
+		PROJECT AS p
+		JOIN WORKSPACE AS w ON w.project_id = p.project_id
+	WHERE
+		w.workspace_id = :workspace_id AND ` + project.ReadableCondition + ` AND ` + project.WritableCondition
+
+	var projectData struct {
+		ID string `db:"project_id"`
 	}
-	if stemId != nil {
-		if err := uuid.Validate(*stemId); err != nil {
-			return []Workspace{}, err
+	connection := str.cluster.Primary()
+	if err := database.NamedQueryStruct(ctx, str.logger, connection, query, queryParams, &projectData); err != nil {
+		if err == database.ErrorNotFound {
+			return database.ErrorForbidden
 		}
 
-		queryParams.StemId = *stemId
+		return fmt.Errorf("error during search of Project entity -> workspace_id={%q}: %w", wsId, err)
+	}
+
+	return nil
+}
+
+// checkWritableProject confirms that new workspaces can be placed into Project entity with projectId identifier.
+// It reports database.ErrorNotFound unless the project is readable by the claims subject and is not archived, and
+// database.ErrorForbidden unless the subject is an owner of the project or holds project.ReadWriteRole in it.
+func (str Store) checkWritableProject(ctx context.Context, transaction *sqlx.Tx, claims auth.Claims,
+	projectId string) error {
+	queryParams := struct {
+		project.Visibility
+		ProjectID string `db:"project_id"`
+	}{
+		Visibility: project.NewVisibility(claims),
+		ProjectID:  projectId,
+	}
+
+	const query = `
+	SELECT
+		p.project_id,
+		` + project.WritableCondition + ` AS writable
+	FROM
+		PROJECT AS p
+	WHERE
+		p.project_id = :project_id AND p.date_archived IS NULL AND ` + project.ReadableCondition
+
+	var projectData struct {
+		ID       string `db:"project_id"`
+		Writable bool   `db:"writable"`
+	}
+	if err := database.NamedQueryStruct(ctx, str.logger, transaction, query, queryParams, &projectData); err != nil {
+		return err
+	}
+
+	if !projectData.Writable {
+		return database.ErrorForbidden
+	}
+
+	return nil
+}
+
+// queryWorkspaceByID looking for Workspace entity with wsId identifier regardless of visibility of its project,
+// deleted workspace or workspace of deleted project is reported with database.ErrorNotFound.
+// It is used by mutations which perform their own permission checks.
+func (str Store) queryWorkspaceByID(ctx context.Context, wsId string) (Workspace, error) {
+	return str.queryWorkspace(ctx, wsId, false)
+}
+
+// queryWorkspace looking for Workspace entity with wsId identifier of not deleted project regardless of its
+// visibility. Deleted workspace is found only if includeDeleted is set.
+func (str Store) queryWorkspace(ctx context.Context, wsId string, includeDeleted bool) (Workspace, error) {
+	if err := uuid.Validate(wsId); err != nil {
+		return Workspace{}, err
+	}
+
+	queryParams := struct {
+		WorkspaceID    string `db:"workspace_id"`
+		IncludeDeleted bool   `db:"include_deleted"`
+	}{
+		WorkspaceID:    wsId,
+		IncludeDeleted: includeDeleted,
 	}
 
 	const query = `
@@ -281,15 +648,53 @@ func (str Store) QueryWorkspacesByProjectAndStem(ctx context.Context, projectId
 		w.date_created,
 		w.created_by_user_id,
 		w.date_updated,
-		w.updated_by_user_id
+		w.updated_by_user_id,
+		w.date_archived,
+		w.date_deleted
 	FROM
 		WORKSPACE AS w
+		JOIN PROJECT AS p ON p.project_id = w.project_id
 	WHERE
-		w.project_id = :project_id AND (:stem_id = "" OR w.stem_id = :stem_id)
-	ORDER BY w.date_updated DESC`
+		w.workspace_id = :workspace_id AND (:include_deleted OR w.date_deleted IS NULL) AND p.date_deleted IS NULL`
+
+	var wsData Workspace
+	connection := str.cluster.Primary()
+	if err := database.NamedQueryStruct(ctx, str.logger, connection, query, queryParams, &wsData); err != nil {
+		if err == database.ErrorNotFound {
+			return Workspace{}, database.ErrorNotFound
+		}
+
+		return Workspace{}, fmt.Errorf("error during search of Workspace entity -> id={%q}: %w", wsId, err)
+	}
+
+	return wsData, nil
+}
+
+// QueryWorkspacesByProjectAndStem looking for all Workspace entities that belong to a specific Project and have a
+// specific Stem with descending order by update date and identifier fields.
+// If stemId argument equals to nil. No Stem filter will be applied.
+// Workspaces of a project which is not readable by the claims subject are not returned.
+// Archived workspaces and workspaces of archived project are returned only if includeArchived is set.
+func (str Store) QueryWorkspacesByProjectAndStem(ctx context.Context, claims auth.Claims, projectId string,
+	stemId *string, includeArchived bool) ([]Workspace, error) {
+	if err := uuid.Validate(projectId); err != nil {
+		return []Workspace{}, err
+	}
+	spec := WorkspaceQuery{ProjectIDs: []string{projectId}, IncludeArchived: includeArchived}
+	if stemId != nil {
+		if err := uuid.Validate(*stemId); err != nil {
+			return []Workspace{}, err
+		}
+
+		spec.StemIDs = []string{*stemId}
+	}
+
+	compiled := compileWorkspaceQuery(claims, withDefaultSort(spec), nil, 0)
 
 	var wsCollection []Workspace
-	if err := database.NamedQuerySlice(ctx, str.logger, str.connection, query, queryParams, &wsCollection); err != nil {
+	connection := str.cluster.Reader(ctx)
+	err := database.NamedQuerySlice(ctx, str.logger, connection, compiled.query, compiled.params, &wsCollection)
+	if err != nil {
 		if err == database.ErrorNotFound {
 			return nil, database.ErrorNotFound
 		}
@@ -1 +1,400 @@
 package workspace
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/SKorolchuk/dpio-workspace/internal/pkg/infra/auth"
+	"github.com/SKorolchuk/dpio-workspace/internal/pkg/infra/database"
+	"github.com/SKorolchuk/dpio-workspace/internal/pkg/store/project"
+)
+
+const (
+	ownerID    = "92eded9e-979c-4e94-afc5-2333fcc920f6"
+	writerID   = "4f5a2c1e-6b3d-4e7f-8a9b-0c1d2e3f4a5b"
+	strangerID = "1a2b3c4d-5e6f-4a7b-8c9d-0e1f2a3b4c5d"
+	missingID  = "00000000-0000-4000-8000-000000000000"
+)
+
+var baseDate = time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// claimsOf returns claims of the user with userId identifier.
+func claimsOf(userId string) auth.Claims {
+	var claims auth.Claims
+	claims.Subject = userId
+
+	return claims
+}
+
+// testProjects holds projects of memory store tests, all of them are owned by ownerID.
+// Writer holds project.ReadWriteRole in the team project.
+type testProjects struct {
+	store    *project.MemoryStore
+	public   project.Project
+	team     project.Project
+	private  project.Project
+	archived project.Project
+}
+
+// newTestProjects creates MemoryStore of Project entities with a project of every collaboration type.
+func newTestProjects(t *testing.T) testProjects {
+	t.Helper()
+
+	ctx, claims := context.Background(), claimsOf(ownerID)
+	projects := testProjects{store: project.NewMemoryStore()}
+	create := func(name string, projectTypeId string) project.Project {
+		projectData, err := projects.store.CreateProject(ctx, claims,
+			project.NewProject{ProjectTypeID: projectTypeId, Name: name, Description: name}, baseDate)
+		if err != nil {
+			t.Fatalf("CreateProject() error = %v", err)
+		}
+
+		return projectData
+	}
+	projects.public = create("Public", project.PublicCollaborationType)
+	projects.team = create("Team", project.TeamCollaborationType)
+	projects.private = create("Private", project.PrivateCollaborationType)
+	projects.archived = create("Archived", project.PublicCollaborationType)
+
+	if err := projects.store.ArchiveProject(ctx, claims, projects.archived.ID, baseDate); err != nil {
+		t.Fatalf("ArchiveProject() error = %v", err)
+	}
+
+	groupData, err := projects.store.CreateGroup(ctx, claims, project.NewGroup{Name: "Writers"}, baseDate)
+	if err != nil {
+		t.Fatalf("CreateGroup() error = %v", err)
+	}
+	if _, err := projects.store.AddGroupUser(ctx, claims, groupData.ID, project.NewGroupUser{UserID: writerID},
+		baseDate); err != nil {
+		t.Fatalf("AddGroupUser() error = %v", err)
+	}
+	if _, err := projects.store.AssignGroupRole(ctx, claims, groupData.ID,
+		project.NewGroupRole{RoleID: project.ReadWriteRole}, baseDate); err != nil {
+		t.Fatalf("AssignGroupRole() error = %v", err)
+	}
+	if _, err := projects.store.GrantGroupAccess(ctx, claims, projects.team.ID,
+		project.NewGroupAccess{GroupID: groupData.ID}, baseDate); err != nil {
+		t.Fatalf("GrantGroupAccess() error = %v", err)
+	}
+
+	return projects
+}
+
+// newTestWorkspace describes Workspace entity of Sticker Pane stem in the project.
+func newTestWorkspace(projectId string, name string) NewWorkspace {
+	return NewWorkspace{
+		ProjectID:        projectId,
+		StemID:           StickerWorkspaceType,
+		Name:             name,
+		AssetAmountLimit: 10,
+		MaxX:             100,
+		MaxY:             100,
+		MaxZ:             1,
+	}
+}
+
+// mustCreateWorkspace adds Workspace entity created by the user at now to the store.
+func mustCreateWorkspace(t *testing.T, str *MemoryStore, userId string, ws NewWorkspace, now time.Time) Workspace {
+	t.Helper()
+
+	wsData, err := str.CreateWorkspace(context.Background(), claimsOf(userId), ws, now)
+	if err != nil {
+		t.Fatalf("CreateWorkspace() error = %v", err)
+	}
+
+	return wsData
+}
+
+func TestMemoryStoreCreateWorkspace(t *testing.T) {
+	projects := newTestProjects(t)
+	str := NewMemoryStore(projects.store)
+
+	unknownStem := newTestWorkspace(projects.public.ID, "Unknown stem")
+	unknownStem.StemID = missingID
+
+	tests := []struct {
+		name    string
+		userId  string
+		ws      NewWorkspace
+		wantErr error
+	}{
+		{name: "owner", userId: ownerID, ws: newTestWorkspace(projects.public.ID, "Owner")},
+		{name: "member with write role", userId: writerID, ws: newTestWorkspace(projects.team.ID, "Writer")},
+		{name: "reader of public project", userId: strangerID, ws: newTestWorkspace(projects.public.ID, "Reader"),
+			wantErr: database.ErrorForbidden},
+		{name: "hidden project", userId: writerID, ws: newTestWorkspace(projects.private.ID, "Hidden"),
+			wantErr: database.ErrorNotFound},
+		{name: "missing project", userId: ownerID, ws: newTestWorkspace(missingID, "Missing"),
+			wantErr: database.ErrorNotFound},
+		{name: "archived project", userId: ownerID, ws: newTestWorkspace(projects.archived.ID, "Archived"),
+			wantErr: database.ErrorNotFound},
+		{name: "unknown stem", userId: ownerID, ws: unknownStem, wantErr: database.ErrorInvalidReference},
+		{name: "taken name", userId: ownerID, ws: newTestWorkspace(projects.team.ID, "Owner"),
+			wantErr: database.ErrorConflict},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			wsData, err := str.CreateWorkspace(context.Background(), claimsOf(test.userId), test.ws, baseDate)
+			if !errors.Is(err, test.wantErr) {
+				t.Fatalf("CreateWorkspace() error = %v, want %v", err, test.wantErr)
+			}
+			if err == nil && (wsData.CreatedByUser != test.userId || wsData.ProjectID != test.ws.ProjectID) {
+				t.Errorf("CreateWorkspace() = %+v", wsData)
+			}
+		})
+	}
+}
+
+func TestMemoryStoreQueryWorkspaceByID(t *testing.T) {
+	projects := newTestProjects(t)
+	str := NewMemoryStore(projects.store)
+	public := mustCreateWorkspace(t, str, ownerID, newTestWorkspace(projects.public.ID, "Public"), baseDate)
+	private := mustCreateWorkspace(t, str, ownerID, newTestWorkspace(projects.private.ID, "Private"), baseDate)
+	deleted := mustCreateWorkspace(t, str, ownerID, newTestWorkspace(projects.public.ID, "Deleted"), baseDate)
+	if err := str.DeleteWorkspace(context.Background(), claimsOf(ownerID), deleted.ID, baseDate); err != nil {
+		t.Fatalf("DeleteWorkspace() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		userId  string
+		wsId    string
+		wantErr error
+	}{
+		{name: "missing workspace", userId: ownerID, wsId: missingID, wantErr: database.ErrorNotFound},
+		{name: "deleted workspace", userId: ownerID, wsId: deleted.ID, wantErr: database.ErrorNotFound},
+		{name: "workspace of hidden project", userId: strangerID, wsId: private.ID, wantErr: database.ErrorNotFound},
+		{name: "workspace of readable project", userId: strangerID, wsId: public.ID},
+		{name: "workspace of own project", userId: ownerID, wsId: private.ID},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			wsData, err := str.QueryWorkspaceByID(context.Background(), claimsOf(test.userId), test.wsId)
+			if !errors.Is(err, test.wantErr) {
+				t.Fatalf("QueryWorkspaceByID() error = %v, want %v", err, test.wantErr)
+			}
+			if err == nil && wsData.ID != test.wsId {
+				t.Errorf("QueryWorkspaceByID() id = %q, want %q", wsData.ID, test.wsId)
+			}
+		})
+	}
+}
+
+func TestMemoryStoreUpdateWorkspace(t *testing.T) {
+	projects := newTestProjects(t)
+	str := NewMemoryStore(projects.store)
+	byOwner := mustCreateWorkspace(t, str, ownerID, newTestWorkspace(projects.team.ID, "By owner"), baseDate)
+	byWriter := mustCreateWorkspace(t, str, writerID, newTestWorkspace(projects.team.ID, "By writer"), baseDate)
+	public := mustCreateWorkspace(t, str, ownerID, newTestWorkspace(projects.public.ID, "Public"), baseDate)
+
+	tests := []struct {
+		name    string
+		userId  string
+		wsId    string
+		wantErr error
+	}{
+		{name: "invalid identifier", userId: ownerID, wsId: "1", wantErr: database.ErrorInvalidIdentifier},
+		{name: "missing workspace", userId: ownerID, wsId: missingID, wantErr: database.ErrorNotFound},
+		{name: "workspace of another user in writable project", userId: writerID, wsId: byOwner.ID},
+		{name: "readable project without write role", userId: writerID, wsId: public.ID,
+			wantErr: database.ErrorForbidden},
+		{name: "workspace of a stranger", userId: strangerID, wsId: byWriter.ID, wantErr: database.ErrorForbidden},
+		{name: "own workspace", userId: writerID, wsId: byWriter.ID},
+		{name: "workspace in owned project", userId: ownerID, wsId: byWriter.ID},
+	}
+
+	for i, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			name, limit, max := fmt.Sprintf("Renamed %d", i), int32(10), int32(100)
+			ws := UpdateWorkspace{Name: &name, AssetAmountLimit: &limit, MaxX: &max, MaxY: &max, MaxZ: &limit}
+			err := str.UpdateWorkspace(context.Background(), claimsOf(test.userId), test.wsId, ws,
+				baseDate.Add(time.Hour))
+			if !errors.Is(err, test.wantErr) {
+				t.Errorf("UpdateWorkspace() error = %v, want %v", err, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestMemoryStoreFollowProjectDeletion(t *testing.T) {
+	projects := newTestProjects(t)
+	str := NewMemoryStore(projects.store)
+	projects.store.WithProjectDeletionHooks(str.FollowProjectDeletion)
+	ctx, claims := context.Background(), claimsOf(ownerID)
+
+	live := mustCreateWorkspace(t, str, ownerID, newTestWorkspace(projects.public.ID, "Live"), baseDate)
+	removed := mustCreateWorkspace(t, str, ownerID, newTestWorkspace(projects.public.ID, "Removed before"), baseDate)
+	other := mustCreateWorkspace(t, str, ownerID, newTestWorkspace(projects.team.ID, "Other project"), baseDate)
+	if err := str.DeleteWorkspace(ctx, claims, removed.ID, baseDate.Add(time.Hour)); err != nil {
+		t.Fatalf("DeleteWorkspace() error = %v", err)
+	}
+
+	isDeleted := func(wsId string) bool {
+		str.mutex.RLock()
+		defer str.mutex.RUnlock()
+
+		return str.workspaces[wsId].DateDeleted != nil
+	}
+
+	if err := projects.store.DeleteProject(ctx, claims, projects.public.ID, true,
+		baseDate.Add(2*time.Hour)); err != nil {
+		t.Fatalf("DeleteProject() error = %v", err)
+	}
+	if !isDeleted(live.ID) || !isDeleted(removed.ID) || isDeleted(other.ID) {
+		t.Errorf("DeleteProject() should mark only workspaces of the project as deleted")
+	}
+
+	if err := projects.store.RestoreProject(ctx, claims, projects.public.ID, baseDate.Add(3*time.Hour)); err != nil {
+		t.Fatalf("RestoreProject() error = %v", err)
+	}
+	if isDeleted(live.ID) || !isDeleted(removed.ID) {
+		t.Errorf("RestoreProject() should restore only workspaces deleted together with the project")
+	}
+}
+
+func TestMemoryStoreQueryWorkspacesBySpec(t *testing.T) {
+	projects := newTestProjects(t)
+	str := NewMemoryStore(projects.store)
+
+	var wsIds []string
+	for i, name := range []string{"Delta", "Alpha", "Charlie", "Bravo", "Echo"} {
+		wsData := mustCreateWorkspace(t, str, ownerID, newTestWorkspace(projects.public.ID, name),
+			baseDate.Add(time.Duration(i)*time.Hour))
+		wsIds = append(wsIds, wsData.ID)
+	}
+	mustCreateWorkspace(t, str, ownerID, newTestWorkspace(projects.private.ID, "Hidden"), baseDate)
+
+	tests := []struct {
+		name     string
+		spec     WorkspaceQuery
+		top      int32
+		wantIDs  []string
+		wantPage int
+	}{
+		{
+			name:     "default order is descending by update date",
+			top:      2,
+			wantIDs:  []string{wsIds[4], wsIds[3], wsIds[2], wsIds[1], wsIds[0]},
+			wantPage: 3,
+		},
+		{
+			name:     "ascending by creation date",
+			spec:     WorkspaceQuery{SortBy: SortByDateCreated, SortDirection: SortAscending},
+			top:      3,
+			wantIDs:  wsIds,
+			wantPage: 2,
+		},
+		{
+			name:     "descending by name",
+			spec:     WorkspaceQuery{SortBy: SortByName, SortDirection: SortDescending},
+			top:      4,
+			wantIDs:  []string{wsIds[4], wsIds[0], wsIds[2], wsIds[3], wsIds[1]},
+			wantPage: 2,
+		},
+		{
+			name:     "filtered by name prefix",
+			spec:     WorkspaceQuery{NamePrefix: "ch"},
+			top:      2,
+			wantIDs:  []string{wsIds[2]},
+			wantPage: 1,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var gotIds []string
+			page := PageRequest{Top: test.top, WithTotal: true}
+			pages := 0
+			for {
+				wsPage, err := str.QueryWorkspacesBySpec(context.Background(), claimsOf(strangerID), test.spec, page)
+				if err != nil {
+					t.Fatalf("QueryWorkspacesBySpec() error = %v", err)
+				}
+				pages++
+				if wsPage.Total == nil || *wsPage.Total != int64(len(test.wantIDs)) {
+					t.Errorf("QueryWorkspacesBySpec() total = %v, want %d", wsPage.Total, len(test.wantIDs))
+				}
+				for _, wsData := range wsPage.Items {
+					gotIds = append(gotIds, wsData.ID)
+				}
+				if wsPage.NextCursor == "" || pages > len(test.wantIDs) {
+					break
+				}
+				page.Cursor = wsPage.NextCursor
+			}
+
+			if pages != test.wantPage {
+				t.Errorf("QueryWorkspacesBySpec() returned %d pages, want %d", pages, test.wantPage)
+			}
+			if fmt.Sprint(gotIds) != fmt.Sprint(test.wantIDs) {
+				t.Errorf("QueryWorkspacesBySpec() = %v, want %v", gotIds, test.wantIDs)
+			}
+		})
+	}
+
+	t.Run("cursor of another order", func(t *testing.T) {
+		wsPage, err := str.QueryWorkspacesBySpec(context.Background(), claimsOf(strangerID), WorkspaceQuery{},
+			PageRequest{Top: 1})
+		if err != nil {
+			t.Fatalf("QueryWorkspacesBySpec() error = %v", err)
+		}
+
+		spec := WorkspaceQuery{SortBy: SortByName}
+		_, err = str.QueryWorkspacesBySpec(context.Background(), claimsOf(strangerID), spec,
+			PageRequest{Top: 1, Cursor: wsPage.NextCursor})
+		if !errors.Is(err, database.ErrorInvalidCursor) {
+			t.Errorf("QueryWorkspacesBySpec() error = %v, want %v", err, database.ErrorInvalidCursor)
+		}
+	})
+}
+
+func TestMemoryStorePurgeWorkspace(t *testing.T) {
+	projects := newTestProjects(t)
+	str := NewMemoryStore(projects.store)
+	ctx, claims := context.Background(), claimsOf(ownerID)
+
+	ws := newTestWorkspace(projects.team.ID, "Purged")
+	ws.MaxZ = 10
+	purged := mustCreateWorkspace(t, str, ownerID, ws, baseDate)
+	ws.Name = "Kept"
+	kept := mustCreateWorkspace(t, str, ownerID, ws, baseDate)
+	for _, placement := range []struct {
+		wsId     string
+		assetRef string
+	}{
+		{wsId: purged.ID, assetRef: "unique"},
+		{wsId: purged.ID, assetRef: "unique"},
+		{wsId: purged.ID, assetRef: "shared"},
+		{wsId: kept.ID, assetRef: "shared"},
+	} {
+		asset := newTestAsset(placement.wsId)
+		asset.AssetRefID = placement.assetRef
+		if _, err := str.CreateAsset(ctx, claims, asset, baseDate); err != nil {
+			t.Fatalf("CreateAsset() error = %v", err)
+		}
+	}
+
+	if _, err := str.PurgeWorkspace(ctx, claimsOf(strangerID), purged.ID); !errors.Is(err, database.ErrorForbidden) {
+		t.Errorf("PurgeWorkspace() by a stranger error = %v, want %v", err, database.ErrorForbidden)
+	}
+
+	assetRefs, err := str.PurgeWorkspace(ctx, claims, purged.ID)
+	if err != nil {
+		t.Fatalf("PurgeWorkspace() error = %v", err)
+	}
+	if len(assetRefs) != 1 || assetRefs[0] != "unique" {
+		t.Errorf("PurgeWorkspace() = %v, want only the reference which is not used anymore", assetRefs)
+	}
+	if len(str.workspaceAssets(kept.ID)) != 1 || len(str.workspaceAssets(purged.ID)) != 0 {
+		t.Errorf("PurgeWorkspace() should remove only assets of the purged workspace")
+	}
+
+	if _, err := str.PurgeWorkspace(ctx, claims, purged.ID); !errors.Is(err, database.ErrorNotFound) {
+		t.Errorf("PurgeWorkspace() of purged workspace error = %v, want %v", err, database.ErrorNotFound)
+	}
+}